@@ -0,0 +1,99 @@
+// tss-recover rebuilds the full private key from t encrypted key share backups, for offline disaster recovery only.
+//
+//	tss-recover [-format wif|hex|ed25519] [-testnet] [-kits a.kit,b.kit] party1.backup party3.backup ...
+//
+// every input is a backup written by backup.Export, plaintext KeyStep3Data files are refused.
+// a backup is opened with its kits if enough are given, otherwise its passphrase is read from stdin.
+// the ed25519 format is an expanded secret key, not an RFC 8032 seed, wallets expecting a 32 bytes seed can't import it.
+// every share is checked against the share public keys and the recovered key against the public key,
+// nothing is printed unless the recovered key matches. the output is the whole key, run it on an offline machine
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/key/backup"
+)
+
+func main() {
+	format := flag.String("format", FormatHex, "output format: wif, hex or ed25519 (expanded key, not a seed)")
+	testnet := flag.Bool("testnet", false, "wif for bitcoin testnet")
+	kitFiles := flag.String("kits", "", "comma separated recovery kit files of the encrypted backups")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] backupfile...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var kits []string
+	if *kitFiles != "" {
+		kits = strings.Split(*kitFiles, ",")
+	}
+	if err := run(*format, *testnet, kits, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "tss-recover:", err)
+		os.Exit(1)
+	}
+}
+
+func run(format string, testnet bool, kitFiles, paths []string) error {
+	kits := make([]*backup.Kit, len(kitFiles))
+	for i, path := range kitFiles {
+		kit, err := loadKit(path)
+		if err != nil {
+			return err
+		}
+		kits[i] = kit
+	}
+	stdin := bufio.NewReader(os.Stdin)
+	passphrase := func(b *backup.Backup) ([]byte, error) {
+		fmt.Fprintf(os.Stderr, "passphrase for the backup of party %d: ", b.PartyId)
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return nil, err
+		}
+		return []byte(strings.TrimRight(line, "\r\n")), nil
+	}
+
+	keys := make([]*tss.KeyStep3Data, len(paths))
+	for i, path := range paths {
+		key, err := loadKey(path, kits, passphrase)
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+	}
+	privateKey, err := reconstruct(keys)
+	if err != nil {
+		return err
+	}
+	curve := curves.GetCurveName(keys[0].PublicKey.Curve)
+	out, err := encode(format, privateKey, curve, testnet)
+	if err != nil {
+		return err
+	}
+	g, err := group.FromCurve(keys[0].PublicKey.Curve)
+	if err != nil {
+		return err
+	}
+	publicKey, err := g.PointFromAffine(keys[0].PublicKey.X, keys[0].PublicKey.Y)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "curve: %s\npublic key: %s\n", curve, hex.EncodeToString(publicKey.Bytes()))
+	if format == FormatEd25519 {
+		fmt.Fprintln(os.Stderr, "note: 64 bytes expanded secret key scalar||prefix, not an RFC 8032 seed, wallets expecting a 32 bytes seed can't import it")
+	}
+	fmt.Println(out)
+	return nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/okx/threshold-lib/crypto/base58"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/key/backup"
+)
+
+// output formats of the private key
+const (
+	FormatWIF     = "wif"     // bitcoin WIF, compressed public key, secp256k1 only
+	FormatHex     = "hex"     // 32 bytes big-endian scalar, little-endian for ed25519
+	FormatEd25519 = "ed25519" // 64 bytes expanded secret key scalar||prefix, not an RFC 8032 seed, ed25519 only
+)
+
+// loadKey read an encrypted backup of a key share, plaintext KeyStep3Data files are refused,
+// a backup is opened with its recovery kits if enough of them are given, otherwise with the passphrase
+func loadKey(path string, kits []*backup.Kit, passphrase func(b *backup.Backup) ([]byte, error)) (*tss.KeyStep3Data, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bytes, &fields); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if _, ok := fields["Ciphertext"]; !ok {
+		return nil, fmt.Errorf("%s: not an encrypted backup, export the key share with backup.Export first", path)
+	}
+
+	b := &backup.Backup{}
+	if err := json.Unmarshal(bytes, b); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := b.Verify(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	var own []*backup.Kit
+	for _, kit := range kits {
+		if kit.BackupId == b.Checksum {
+			own = append(own, kit)
+		}
+	}
+	var key *tss.KeyStep3Data
+	if b.Kits != nil && len(own) >= b.Kits.Threshold {
+		key, err = b.OpenWithKits(own, nil)
+	} else {
+		var secret []byte
+		secret, err = passphrase(b)
+		if err == nil {
+			key, err = b.Open(secret, nil)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return key, nil
+}
+
+// loadKit read a recovery kit file
+func loadKit(path string) (*backup.Kit, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	kit := &backup.Kit{}
+	if err := json.Unmarshal(bytes, kit); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return kit, nil
+}
+
+// reconstruct check every share against the share public keys, recover the private key from all of them
+// and check it against the public key, fewer shares than the threshold or a share of another key fail the last check
+func reconstruct(keys []*tss.KeyStep3Data) (*big.Int, error) {
+	if len(keys) < 2 {
+		return nil, fmt.Errorf("at least 2 key shares are needed")
+	}
+	first := keys[0]
+	if first == nil || first.PublicKey == nil || first.SharePubKeyMap == nil {
+		return nil, fmt.Errorf("key share is incomplete")
+	}
+	g, err := group.FromCurve(first.PublicKey.Curve)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int]bool, len(keys))
+	shares := make([]*vss.Share, len(keys))
+	for i, key := range keys {
+		if key == nil || key.ShareI == nil || key.PublicKey == nil {
+			return nil, fmt.Errorf("key share %d is incomplete", i+1)
+		}
+		if seen[key.Id] {
+			return nil, fmt.Errorf("duplicate key share of party %d", key.Id)
+		}
+		seen[key.Id] = true
+		// all shares are of the same key
+		if !key.PublicKey.Equals(first.PublicKey) || len(key.SharePubKeyMap) != len(first.SharePubKeyMap) {
+			return nil, fmt.Errorf("key share of party %d belongs to another key", key.Id)
+		}
+		for id, X := range first.SharePubKeyMap {
+			if X == nil || !X.Equals(key.SharePubKeyMap[id]) {
+				return nil, fmt.Errorf("key share of party %d belongs to another key", key.Id)
+			}
+		}
+		// older key files may hold shares not reduced modulo the order
+		shareI := g.ScalarFromBigInt(key.ShareI)
+		if key.ShareI.Sign() < 0 || shareI.IsZero() {
+			return nil, fmt.Errorf("key share of party %d is out of range", key.Id)
+		}
+		X, ok := first.SharePubKeyMap[key.Id]
+		if !ok || !curves.ScalarToPoint(X.Curve, shareI.BigInt()).Equals(X) {
+			return nil, fmt.Errorf("key share of party %d does not match its share public key", key.Id)
+		}
+		shares[i] = &vss.Share{Id: big.NewInt(int64(key.Id)), Y: shareI.BigInt()}
+	}
+
+	privateKey := vss.RecoverSecret(first.PublicKey.Curve, shares)
+	if privateKey.Sign() == 0 || !curves.ScalarToPoint(first.PublicKey.Curve, privateKey).Equals(first.PublicKey) {
+		return nil, fmt.Errorf("recovered key does not match the public key, more key shares are needed")
+	}
+	return privateKey, nil
+}
+
+// encode private key in the output format
+func encode(format string, privateKey *big.Int, curve string, testnet bool) (string, error) {
+	switch format {
+	case FormatWIF:
+		if curve != curves.Secp256k1 {
+			return "", fmt.Errorf("wif is for secp256k1 keys")
+		}
+		version := byte(0x80)
+		if testnet {
+			version = 0xef
+		}
+		payload := append([]byte{version}, privateKey.FillBytes(make([]byte, 32))...)
+		return base58.CheckEncode(append(payload, 0x01)), nil
+	case FormatHex:
+		key := privateKey.FillBytes(make([]byte, 32))
+		if curve == curves.Ed25519 {
+			reverse(key)
+		}
+		return hex.EncodeToString(key), nil
+	case FormatEd25519:
+		if curve != curves.Ed25519 {
+			return "", fmt.Errorf("ed25519 format is for ed25519 keys")
+		}
+		// RFC 8032 5.1.5 expanded key, not a seed: the shares are of the scalar, no seed hashes to it.
+		// the prefix is made up from the scalar, any fixed prefix gives valid signatures
+		scalar := privateKey.FillBytes(make([]byte, 32))
+		reverse(scalar)
+		prefix := sha512.Sum512(append([]byte("threshold-lib ed25519 prefix"), scalar...))
+		return hex.EncodeToString(append(scalar, prefix[:32]...)), nil
+	}
+	return "", fmt.Errorf("unknown format %s", format)
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
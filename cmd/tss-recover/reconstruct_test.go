@@ -0,0 +1,180 @@
+package main
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/key/backup"
+	"github.com/okx/threshold-lib/tss/key/dealer"
+	"github.com/stretchr/testify/require"
+)
+
+// importKeys import privateKey and run the mandatory refresh, key shares indexed by id
+func importKeys(t *testing.T, curve elliptic.Curve, privateKey *big.Int, threshold, total int) map[int]*tss.KeyStep3Data {
+	data, err := dealer.Import(curve, privateKey, threshold, total, nil)
+	require.NoError(t, err)
+	sessionId := tss.SessionId("refresh imported key")
+	infos := make(map[int]*dealer.Refresh, total)
+	for id, key := range data.Keys {
+		infos[id], err = key.Refresh(sessionId, data.Verifiers, curves.ScalarToPoint(curve, privateKey))
+		require.NoError(t, err)
+	}
+	msgs := make(map[int]map[int]*tss.Message, total)
+	for id, info := range infos {
+		msgs[id], err = info.DKGStep1()
+		require.NoError(t, err)
+	}
+	next := make(map[int]map[int]*tss.Message, total)
+	for id, info := range infos {
+		next[id], err = info.DKGStep2(collect(msgs, id))
+		require.NoError(t, err)
+	}
+	keys := make(map[int]*tss.KeyStep3Data, total)
+	for id, info := range infos {
+		keys[id], err = info.DKGStep3(collect(next, id))
+		require.NoError(t, err)
+	}
+	return keys
+}
+
+func collect(msgs map[int]map[int]*tss.Message, id int) []*tss.Message {
+	var in []*tss.Message
+	for _, out := range msgs {
+		if msg, ok := out[id]; ok {
+			in = append(in, msg)
+		}
+	}
+	return in
+}
+
+func TestReconstruct(t *testing.T) {
+	curve := secp256k1.S256()
+	keys := importKeys(t, curve, big.NewInt(1), 3, 4)
+
+	privateKey, err := reconstruct([]*tss.KeyStep3Data{keys[4], keys[1], keys[2]})
+	require.NoError(t, err)
+	require.Equal(t, 0, privateKey.Cmp(big.NewInt(1)))
+	wif, err := encode(FormatWIF, privateKey, curves.Secp256k1, false)
+	require.NoError(t, err)
+	require.Equal(t, "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", wif)
+	wif, _ = encode(FormatWIF, privateKey, curves.Secp256k1, true)
+	require.Equal(t, "cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA", wif)
+	out, _ := encode(FormatHex, privateKey, curves.Secp256k1, false)
+	require.Equal(t, "0000000000000000000000000000000000000000000000000000000000000001", out)
+	_, err = encode(FormatEd25519, privateKey, curves.Secp256k1, false)
+	require.Error(t, err)
+
+	// below threshold
+	_, err = reconstruct([]*tss.KeyStep3Data{keys[1], keys[2]})
+	require.Error(t, err)
+	// duplicate share
+	_, err = reconstruct([]*tss.KeyStep3Data{keys[1], keys[2], keys[1]})
+	require.Error(t, err)
+	// corrupted share
+	bad := *keys[3]
+	bad.ShareI = new(big.Int).Add(bad.ShareI, big.NewInt(1))
+	_, err = reconstruct([]*tss.KeyStep3Data{keys[1], keys[2], &bad})
+	require.Error(t, err)
+	// share of another key
+	other := importKeys(t, curve, big.NewInt(2), 3, 4)
+	_, err = reconstruct([]*tss.KeyStep3Data{keys[1], keys[2], other[3]})
+	require.Error(t, err)
+}
+
+func TestReconstructEd25519(t *testing.T) {
+	// key files written by the distributed test, 2-of-3, party3 is from another keygen run
+	var keys []*tss.KeyStep3Data
+	for i := 1; i <= 3; i++ {
+		bytes, err := os.ReadFile(fmt.Sprintf("../../distributed-test/go-client/test_key_party%d.key", i))
+		require.NoError(t, err)
+		key := &tss.KeyStep3Data{}
+		require.NoError(t, json.Unmarshal(bytes, key))
+		keys = append(keys, key)
+	}
+	_, err := reconstruct([]*tss.KeyStep3Data{keys[0], keys[2]})
+	require.Error(t, err)
+	keys = keys[:2]
+	privateKey, err := reconstruct(keys)
+	require.NoError(t, err)
+
+	out, err := encode(FormatEd25519, privateKey, curves.Ed25519, false)
+	require.NoError(t, err)
+	expanded, _ := hex.DecodeString(out)
+	require.Equal(t, 64, len(expanded))
+	// first half is the little-endian scalar of the public key
+	g := group.Ed25519()
+	scalar, err := g.ScalarFromBytes(expanded[:32])
+	require.NoError(t, err)
+	publicKey, _ := g.PointFromAffine(keys[0].PublicKey.X, keys[0].PublicKey.Y)
+	require.True(t, g.Generator().ScalarMult(scalar).Equal(publicKey))
+	hexOut, _ := encode(FormatHex, privateKey, curves.Ed25519, false)
+	require.Equal(t, hex.EncodeToString(expanded[:32]), hexOut)
+
+	_, err = encode(FormatWIF, privateKey, curves.Ed25519, false)
+	require.Error(t, err)
+	_, err = loadKey("missing.key", nil, nil)
+	require.Error(t, err)
+	// plaintext key files are refused
+	_, err = loadKey("../../distributed-test/go-client/test_key_party1.key", nil, nil)
+	require.Error(t, err)
+}
+
+func TestEncryptedBackups(t *testing.T) {
+	keys := importKeys(t, secp256k1.S256(), big.NewInt(1), 3, 4)
+	dir := t.TempDir()
+	write := func(name string, v interface{}) string {
+		bytes, err := json.Marshal(v)
+		require.NoError(t, err)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, bytes, 0600))
+		return path
+	}
+
+	// party1 and party4 with a passphrase, party2 with kits
+	b1, _, err := backup.Export(keys[1], nil, []byte("party1"))
+	require.NoError(t, err)
+	b2, kits, err := backup.Export(keys[2], nil, []byte("party2"), backup.KitParams{Threshold: 2, Total: 3})
+	require.NoError(t, err)
+	b4, _, err := backup.Export(keys[4], nil, []byte("party4"))
+	require.NoError(t, err)
+	paths := []string{write("party1.backup", b1), write("party2.backup", b2), write("party4.backup", b4)}
+	kitList := []*backup.Kit{}
+	for _, i := range []int{0, 2} {
+		kit, err := loadKit(write(fmt.Sprintf("kit%d", i), kits[i]))
+		require.NoError(t, err)
+		kitList = append(kitList, kit)
+	}
+	passphrase := func(b *backup.Backup) ([]byte, error) {
+		return []byte(fmt.Sprintf("party%d", b.PartyId)), nil
+	}
+
+	var loaded []*tss.KeyStep3Data
+	for _, path := range paths {
+		key, err := loadKey(path, kitList, passphrase)
+		require.NoError(t, err)
+		loaded = append(loaded, key)
+	}
+	privateKey, err := reconstruct(loaded)
+	require.NoError(t, err)
+	require.Equal(t, 0, privateKey.Cmp(big.NewInt(1)))
+
+	// wrong passphrase, one kit isn't enough for party2
+	wrong := func(b *backup.Backup) ([]byte, error) { return []byte("wrong"), nil }
+	_, err = loadKey(paths[0], nil, wrong)
+	require.Error(t, err)
+	_, err = loadKey(paths[1], kitList[:1], wrong)
+	require.Error(t, err)
+	// plaintext share of party3
+	_, err = loadKey(write("party3.key", keys[3]), nil, passphrase)
+	require.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package base58
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// bitcoin alphabet base58 and base58check
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var radix = big.NewInt(58)
+
+// Encode every leading zero byte becomes '1'
+func Encode(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Decode every leading '1' becomes a zero byte
+func Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		index := bytes.IndexByte([]byte(alphabet), s[i])
+		if index < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", s[i])
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(index)))
+	}
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// CheckEncode payload || sha256(sha256(payload))[:4]
+func CheckEncode(payload []byte) string {
+	data := append(append([]byte{}, payload...), checksum(payload)...)
+	return Encode(data)
+}
+
+// CheckDecode verify and strip the 4 bytes checksum
+func CheckDecode(s string) ([]byte, error) {
+	data, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, fmt.Errorf("base58check data too short")
+	}
+	payload := data[:len(data)-4]
+	if !bytes.Equal(checksum(payload), data[len(data)-4:]) {
+		return nil, fmt.Errorf("base58check checksum mismatch")
+	}
+	return payload, nil
+}
+
+func checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
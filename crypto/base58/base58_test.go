@@ -0,0 +1,40 @@
+package base58
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	vectors := map[string]string{
+		"":                         "",
+		"48656c6c6f20576f726c6421": "2NEpo7TZRRrLZSi2U",
+		"00000000":                 "1111",
+		"0000287fb4cd":             "11233QC4",
+	}
+	for data, expected := range vectors {
+		b, _ := hex.DecodeString(data)
+		require.Equal(t, expected, Encode(b))
+		decoded, err := Decode(expected)
+		require.NoError(t, err)
+		require.Equal(t, data, hex.EncodeToString(decoded))
+	}
+	_, err := Decode("0OIl")
+	require.Error(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	// version 0x00 || hash160 of the secp256k1 generator
+	payload, _ := hex.DecodeString("00751e76e8199196d454941c45d1b3a323f1433bd6")
+	s := CheckEncode(payload)
+	require.Equal(t, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", s)
+
+	decoded, err := CheckDecode(s)
+	require.NoError(t, err)
+	require.Equal(t, payload, decoded)
+
+	_, err = CheckDecode("1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ")
+	require.Error(t, err)
+}
@@ -2,7 +2,10 @@ package curves
 
 import (
 	"crypto/elliptic"
+	"fmt"
+	"math/big"
 	"reflect"
+	"sync"
 
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/decred/dcrd/dcrec/secp256k1/v2"
@@ -11,28 +14,82 @@ import (
 const (
 	Secp256k1 string = "secp256k1"
 	Ed25519   string = "ed25519"
+	P256      string = "p256"
 )
 
-var curveMap map[string]elliptic.Curve
+var (
+	curveMap  map[string]elliptic.Curve
+	curveLock sync.RWMutex
+)
 
-// only support ecdsa、ed25519
+// ecdsa: secp256k1、p256, eddsa: ed25519
 func init() {
 	curveMap = map[string]elliptic.Curve{
 		Secp256k1: secp256k1.S256(),
 		Ed25519:   edwards.Edwards(),
+		P256:      elliptic.P256(),
+	}
+}
+
+// Register add a curve to the registry, so that points on it can be serialized
+func Register(curveName string, curve elliptic.Curve) error {
+	if curveName == "" || curve == nil {
+		return fmt.Errorf("curve name or curve is empty")
 	}
+	curveLock.Lock()
+	defer curveLock.Unlock()
+	if _, ok := curveMap[curveName]; ok {
+		return fmt.Errorf("curve %s already registered", curveName)
+	}
+	curveMap[curveName] = curve
+	return nil
 }
 
 func GetCurveByName(curveName string) (elliptic.Curve, bool) {
+	curveLock.RLock()
+	defer curveLock.RUnlock()
 	val, ok := curveMap[curveName]
 	return val, ok
 }
 
 func GetCurveName(curve elliptic.Curve) string {
+	if curve == nil {
+		return ""
+	}
+	curveLock.RLock()
+	defer curveLock.RUnlock()
+	for name, e := range curveMap {
+		if curve == e {
+			return name
+		}
+	}
+	// nist curves share the CurveParams type, compare by name
 	for name, e := range curveMap {
-		if reflect.TypeOf(curve) == reflect.TypeOf(e) {
+		if reflect.TypeOf(curve) == reflect.TypeOf(e) && curve.Params().Name == e.Params().Name {
 			return name
 		}
 	}
 	return ""
 }
+
+// LiftX y of x on the short weierstrass curve y^2 = x^3 + a*x + b, a = 0 for secp256k1 and -3 for nist curves
+func LiftX(curve elliptic.Curve, x *big.Int) (*big.Int, error) {
+	if GetCurveName(curve) == Ed25519 {
+		return nil, fmt.Errorf("ed25519 is not a weierstrass curve")
+	}
+	params := curve.Params()
+	if x.Sign() < 0 || x.Cmp(params.P) >= 0 {
+		return nil, fmt.Errorf("x is out of range")
+	}
+	y2 := new(big.Int).Exp(x, big.NewInt(3), params.P)
+	if GetCurveName(curve) != Secp256k1 {
+		y2.Sub(y2, new(big.Int).Mul(big.NewInt(3), x))
+	}
+	y2.Add(y2, params.B)
+	y2.Mod(y2, params.P)
+	y := new(big.Int).ModSqrt(y2, params.P)
+	if y == nil {
+		return nil, fmt.Errorf("x is not on curve")
+	}
+	return y, nil
+}
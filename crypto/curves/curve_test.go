@@ -1,14 +1,17 @@
 package curves
 
 import (
+	"crypto/elliptic"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/decred/dcrd/dcrec/secp256k1/v2"
 	"github.com/okx/threshold-lib/crypto"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCurve(t *testing.T) {
@@ -61,3 +64,35 @@ func TestPubKeyToPoint(t *testing.T) {
 		fmt.Println(point2)
 	}
 }
+
+func TestCurveRegistry(t *testing.T) {
+	p256, ok := GetCurveByName(P256)
+	require.True(t, ok)
+	require.Equal(t, P256, GetCurveName(elliptic.P256()))
+	require.Equal(t, Secp256k1, GetCurveName(secp256k1.S256()))
+	require.Error(t, Register(P256, elliptic.P384()))
+	require.NoError(t, Register("p384", elliptic.P384()))
+	require.Equal(t, "p384", GetCurveName(elliptic.P384()))
+
+	// json round trip keeps the curve
+	point := ScalarToPoint(p256, big.NewInt(7))
+	bytes, err := json.Marshal(point)
+	require.NoError(t, err)
+	decoded := &ECPoint{}
+	require.NoError(t, json.Unmarshal(bytes, decoded))
+	require.True(t, point.Equals(decoded))
+	require.Equal(t, P256, GetCurveName(decoded.Curve))
+
+	// LiftX finds Y up to sign
+	for _, curve := range []elliptic.Curve{secp256k1.S256(), p256} {
+		point := ScalarToPoint(curve, crypto.RandomNum(curve.Params().N))
+		y, err := LiftX(curve, point.X)
+		require.NoError(t, err)
+		if y.Cmp(point.Y) != 0 {
+			y.Sub(curve.Params().P, y)
+		}
+		require.Equal(t, 0, y.Cmp(point.Y))
+	}
+	_, err = LiftX(edwards.Edwards(), big.NewInt(1))
+	require.Error(t, err)
+}
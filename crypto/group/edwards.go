@@ -0,0 +1,197 @@
+package group
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+)
+
+// edwardsGroup prime order subgroup of edwards25519, -x^2 + y^2 = 1 + d*x^2*y^2,
+// the identity (0, 1) is an ordinary affine point
+type edwardsGroup struct {
+	curve elliptic.Curve
+	field *scalarField
+	d     *big.Int
+}
+
+type edwardsPoint struct {
+	g    *edwardsGroup
+	x, y *big.Int
+}
+
+func newEdwardsGroup(curve elliptic.Curve) (*edwardsGroup, error) {
+	params := curve.Params()
+	field, err := newScalarField(params.N, true)
+	if err != nil {
+		return nil, err
+	}
+	// d = -121665/121666
+	p := params.P
+	d := new(big.Int).ModInverse(big.NewInt(121666), p)
+	d.Mul(d, big.NewInt(-121665))
+	d.Mod(d, p)
+	return &edwardsGroup{curve: curve, field: field, d: d}, nil
+}
+
+func (g *edwardsGroup) Name() string {
+	return curves.Ed25519
+}
+
+func (g *edwardsGroup) Curve() elliptic.Curve {
+	return g.curve
+}
+
+func (g *edwardsGroup) Order() *big.Int {
+	return new(big.Int).Set(g.field.order)
+}
+
+func (g *edwardsGroup) Identity() Point {
+	return &edwardsPoint{g: g, x: big.NewInt(0), y: big.NewInt(1)}
+}
+
+func (g *edwardsGroup) Generator() Point {
+	params := g.curve.Params()
+	return &edwardsPoint{g: g, x: params.Gx, y: params.Gy}
+}
+
+// PointFromBytes RFC 8032 encoding, little endian y with the sign of x in the top bit
+func (g *edwardsGroup) PointFromBytes(b []byte) (Point, error) {
+	if len(b) != 32 {
+		return nil, fmt.Errorf("invalid ed25519 point")
+	}
+	p := g.curve.Params().P
+	buf := append([]byte{}, b...)
+	sign := uint(buf[31] >> 7)
+	buf[31] &= 0x7f
+	reverse(buf)
+	y := new(big.Int).SetBytes(buf)
+	if y.Cmp(p) >= 0 {
+		return nil, fmt.Errorf("ed25519 point is not canonical")
+	}
+	// x^2 = (y^2 - 1) / (d*y^2 + 1)
+	y2 := new(big.Int).Mul(y, y)
+	u := new(big.Int).Sub(y2, big.NewInt(1))
+	v := new(big.Int).Mul(g.d, y2)
+	v.Add(v, big.NewInt(1))
+	v.ModInverse(v.Mod(v, p), p)
+	x2 := u.Mul(u, v)
+	x := new(big.Int).ModSqrt(x2.Mod(x2, p), p)
+	if x == nil {
+		return nil, fmt.Errorf("ed25519 point is not on curve")
+	}
+	if x.Sign() == 0 && sign == 1 {
+		return nil, fmt.Errorf("ed25519 point is not canonical")
+	}
+	if x.Bit(0) != sign {
+		x.Sub(p, x)
+	}
+	return g.PointFromAffine(x, y)
+}
+
+// PointFromAffine the point must be in the prime order subgroup
+func (g *edwardsGroup) PointFromAffine(x, y *big.Int) (Point, error) {
+	if x == nil || y == nil {
+		return nil, fmt.Errorf("point is nil")
+	}
+	if !g.curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("point is not on curve")
+	}
+	// n*P = O <=> (n-1)*P = -P, n-1 is below the order so no implementation reduces it away
+	params := g.curve.Params()
+	nx, ny := g.curve.ScalarMult(x, y, new(big.Int).Sub(params.N, big.NewInt(1)).Bytes())
+	negX := new(big.Int).Sub(params.P, x)
+	if nx.Cmp(negX.Mod(negX, params.P)) != 0 || ny.Cmp(y) != 0 {
+		return nil, fmt.Errorf("point is not in the prime order subgroup")
+	}
+	return &edwardsPoint{g: g, x: new(big.Int).Set(x), y: new(big.Int).Set(y)}, nil
+}
+
+func (g *edwardsGroup) NewScalar() Scalar {
+	return g.field.newScalar(g, limbs{})
+}
+
+func (g *edwardsGroup) RandomScalar() (Scalar, error) {
+	return g.field.random(g)
+}
+
+func (g *edwardsGroup) ScalarFromBigInt(x *big.Int) Scalar {
+	return g.field.fromBigInt(g, x)
+}
+
+func (g *edwardsGroup) ScalarFromBytes(b []byte) (Scalar, error) {
+	return g.field.fromBytes(g, b)
+}
+
+func (g *edwardsGroup) ScalarFromSecret(x *big.Int) (Scalar, error) {
+	return g.field.fromSecret(g, x)
+}
+
+func (g *edwardsGroup) ScalarFromUniformBytes(b []byte) (Scalar, error) {
+	return g.field.fromUniformBytes(g, b)
+}
+
+func (p *edwardsPoint) other(q Point) *edwardsPoint {
+	o, ok := q.(*edwardsPoint)
+	if !ok || !sameGroup(o.g, p.g) {
+		panic(fmt.Errorf("group: points of different groups"))
+	}
+	return o
+}
+
+func (p *edwardsPoint) Group() Group {
+	return p.g
+}
+
+func (p *edwardsPoint) Add(q Point) Point {
+	o := p.other(q)
+	x, y := p.g.curve.Add(p.x, p.y, o.x, o.y)
+	return &edwardsPoint{g: p.g, x: x, y: y}
+}
+
+func (p *edwardsPoint) Sub(q Point) Point {
+	return p.Add(p.other(q).Negate())
+}
+
+func (p *edwardsPoint) Negate() Point {
+	x := new(big.Int).Sub(p.g.curve.Params().P, p.x)
+	x.Mod(x, p.g.curve.Params().P)
+	return &edwardsPoint{g: p.g, x: x, y: p.y}
+}
+
+func (p *edwardsPoint) ScalarMult(k Scalar) Point {
+	s := toScalar(p.g, k)
+	if s.IsZero() {
+		return p.g.Identity()
+	}
+	x, y := p.g.curve.ScalarMult(p.x, p.y, s.bigEndian())
+	return &edwardsPoint{g: p.g, x: x, y: y}
+}
+
+func (p *edwardsPoint) IsIdentity() bool {
+	return p.x.Sign() == 0 && p.y.Cmp(big.NewInt(1)) == 0
+}
+
+func (p *edwardsPoint) Equal(q Point) bool {
+	o, ok := q.(*edwardsPoint)
+	if !ok || !sameGroup(o.g, p.g) {
+		return false
+	}
+	return p.x.Cmp(o.x) == 0 && p.y.Cmp(o.y) == 0
+}
+
+func (p *edwardsPoint) Bytes() []byte {
+	out := p.y.FillBytes(make([]byte, 32))
+	reverse(out)
+	out[31] |= byte(p.x.Bit(0) << 7)
+	return out
+}
+
+func (p *edwardsPoint) Affine() (*big.Int, *big.Int) {
+	return new(big.Int).Set(p.x), new(big.Int).Set(p.y)
+}
+
+func (p *edwardsPoint) ECPoint() (*curves.ECPoint, error) {
+	return &curves.ECPoint{Curve: p.g.curve, X: new(big.Int).Set(p.x), Y: new(big.Int).Set(p.y)}, nil
+}
@@ -0,0 +1,257 @@
+package group
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// maxLimbs 64 bit limbs of the largest supported order, p521 has a 521 bits order
+const maxLimbs = 9
+
+type limbs [maxLimbs]uint64
+
+// scalarField arithmetic modulo the odd group order n in montgomery form,
+// every operation runs the same instructions whatever the values, only the order is public
+type scalarField struct {
+	n      limbs
+	size   int    // number of limbs used
+	nInv   uint64 // -n^-1 mod 2^64
+	one    limbs  // R mod n, R = 2^(64*size)
+	r2     limbs  // R^2 mod n
+	r3     limbs  // R^3 mod n
+	order  *big.Int
+	length int  // encoding length in bytes
+	le     bool // little endian encoding, ed25519
+}
+
+func newScalarField(order *big.Int, littleEndian bool) (*scalarField, error) {
+	if order == nil || order.Sign() <= 0 || order.Bit(0) == 0 {
+		return nil, fmt.Errorf("group order must be odd")
+	}
+	size := (order.BitLen() + 63) / 64
+	if size > maxLimbs {
+		return nil, fmt.Errorf("group order is too large")
+	}
+	f := &scalarField{
+		size:   size,
+		order:  new(big.Int).Set(order),
+		length: (order.BitLen() + 7) / 8,
+		le:     littleEndian,
+	}
+	f.n = f.fromBig(order)
+	// newton iteration, inv = n^-1 mod 2^64
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.n[0]*inv
+	}
+	f.nInv = -inv
+	R := new(big.Int).Lsh(big.NewInt(1), uint(64*size))
+	f.one = f.fromBig(new(big.Int).Mod(R, order))
+	f.r2 = f.fromBig(new(big.Int).Exp(R, big.NewInt(2), order))
+	f.r3 = f.fromBig(new(big.Int).Exp(R, big.NewInt(3), order))
+	return f, nil
+}
+
+// fromBig limbs of x, x < R
+func (f *scalarField) fromBig(x *big.Int) limbs {
+	return loadLimbs(x.FillBytes(make([]byte, 8*f.size)), f.size)
+}
+
+// mul montgomery product a*b/R mod n, CIOS, a < R and b < n
+func (f *scalarField) mul(a, b *limbs) limbs {
+	var t [maxLimbs + 2]uint64
+	s := f.size
+	for i := 0; i < s; i++ {
+		var c uint64
+		for j := 0; j < s; j++ {
+			hi, lo := bits.Mul64(a[j], b[i])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		var cc uint64
+		t[s], cc = bits.Add64(t[s], c, 0)
+		t[s+1] = cc
+
+		m := t[0] * f.nInv
+		hi, lo := bits.Mul64(m, f.n[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < s; j++ {
+			hi, lo = bits.Mul64(m, f.n[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[s-1], cc = bits.Add64(t[s], c, 0)
+		t[s] = t[s+1] + cc
+	}
+	var out limbs
+	copy(out[:s], t[:s])
+	return f.reduceOnce(&out, t[s])
+}
+
+// reduceOnce a - n when carry*R + a >= n, a < 2n
+func (f *scalarField) reduceOnce(a *limbs, carry uint64) limbs {
+	var d limbs
+	var borrow uint64
+	for i := 0; i < f.size; i++ {
+		d[i], borrow = bits.Sub64(a[i], f.n[i], borrow)
+	}
+	// keep d when the subtraction didn't borrow or the value overflowed R
+	mask := -((borrow ^ 1) | carry)
+	var out limbs
+	for i := 0; i < f.size; i++ {
+		out[i] = (d[i] & mask) | (a[i] &^ mask)
+	}
+	return out
+}
+
+func (f *scalarField) add(a, b *limbs) limbs {
+	var sum limbs
+	var carry uint64
+	for i := 0; i < f.size; i++ {
+		sum[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+	return f.reduceOnce(&sum, carry)
+}
+
+func (f *scalarField) sub(a, b *limbs) limbs {
+	var d limbs
+	var borrow uint64
+	for i := 0; i < f.size; i++ {
+		d[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+	// add n back when a < b
+	mask := -borrow
+	var carry uint64
+	for i := 0; i < f.size; i++ {
+		d[i], carry = bits.Add64(d[i], f.n[i]&mask, carry)
+	}
+	return d
+}
+
+// exp a^e, e is public
+func (f *scalarField) exp(a *limbs, e *big.Int) limbs {
+	r := f.one
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		r = f.mul(&r, &r)
+		if e.Bit(i) == 1 {
+			r = f.mul(&r, a)
+		}
+	}
+	return r
+}
+
+// toMont a*R mod n for any a < R
+func (f *scalarField) toMont(a *limbs) limbs {
+	return f.mul(a, &f.r2)
+}
+
+func (f *scalarField) fromMont(a *limbs) limbs {
+	one := limbs{1}
+	return f.mul(a, &one)
+}
+
+// reduceWide (hi*R + lo)*R mod n, montgomery form of a 2*size limbs value
+func (f *scalarField) reduceWide(lo, hi *limbs) limbs {
+	l := f.mul(lo, &f.r2)
+	h := f.mul(hi, &f.r3)
+	return f.add(&l, &h)
+}
+
+func (f *scalarField) isZero(a *limbs) bool {
+	var acc uint64
+	for i := 0; i < f.size; i++ {
+		acc |= a[i]
+	}
+	return acc == 0
+}
+
+func (f *scalarField) equal(a, b *limbs) bool {
+	var acc uint64
+	for i := 0; i < f.size; i++ {
+		acc |= a[i] ^ b[i]
+	}
+	return acc == 0
+}
+
+// encode canonical fixed width encoding of a value in normal form
+func (f *scalarField) encode(a *limbs) []byte {
+	out := f.bigEndian(a)
+	if f.le {
+		reverse(out)
+	}
+	return out
+}
+
+// bigEndian fixed width big endian bytes of a value in normal form
+func (f *scalarField) bigEndian(a *limbs) []byte {
+	buf := make([]byte, 8*f.size)
+	for i := 0; i < f.size; i++ {
+		for j := 0; j < 8; j++ {
+			buf[len(buf)-1-8*i-j] = byte(a[i] >> (8 * j))
+		}
+	}
+	return buf[len(buf)-f.length:]
+}
+
+// decode limbs of a fixed width encoding, the value may be >= n
+func (f *scalarField) decode(b []byte) (limbs, error) {
+	if len(b) != f.length {
+		return limbs{}, fmt.Errorf("scalar must be %d bytes", f.length)
+	}
+	buf := make([]byte, 8*f.size)
+	copy(buf[len(buf)-f.length:], b)
+	if f.le {
+		reverse(buf[len(buf)-f.length:])
+	}
+	return loadLimbs(buf, f.size), nil
+}
+
+// decodeWide montgomery form of a value up to 2*size limbs
+func (f *scalarField) decodeWide(b []byte) (limbs, error) {
+	if len(b) == 0 || len(b) > 16*f.size {
+		return limbs{}, fmt.Errorf("uniform bytes must be at most %d bytes", 16*f.size)
+	}
+	buf := make([]byte, 16*f.size)
+	copy(buf[len(buf)-len(b):], b)
+	if f.le {
+		reverse(buf[len(buf)-len(b):])
+	}
+	hi := loadLimbs(buf[:8*f.size], f.size)
+	lo := loadLimbs(buf[8*f.size:], f.size)
+	return f.reduceWide(&lo, &hi), nil
+}
+
+// loadLimbs big endian buf of 8*size bytes
+func loadLimbs(buf []byte, size int) limbs {
+	var out limbs
+	for i := 0; i < size; i++ {
+		for j := 0; j < 8; j++ {
+			out[i] |= uint64(buf[len(buf)-1-8*i-j]) << (8 * j)
+		}
+	}
+	return out
+}
+
+// less a < n
+func (f *scalarField) less(a *limbs) bool {
+	var borrow uint64
+	for i := 0; i < f.size; i++ {
+		_, borrow = bits.Sub64(a[i], f.n[i], borrow)
+	}
+	return borrow == 1
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
@@ -0,0 +1,165 @@
+package group
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+)
+
+// Group prime order group of an elliptic curve, points and scalars are immutable values
+type Group interface {
+	// Name registry name of the curve, see curves.GetCurveName
+	Name() string
+	Curve() elliptic.Curve
+	Order() *big.Int
+
+	// Identity neutral element, a real point on every curve
+	Identity() Point
+	Generator() Point
+	// PointFromBytes canonical compressed encoding, SEC1 or RFC 8032
+	PointFromBytes(b []byte) (Point, error)
+	// PointFromAffine (0, 0) is the identity of weierstrass curves, as crypto/elliptic
+	PointFromAffine(x, y *big.Int) (Point, error)
+
+	// NewScalar zero
+	NewScalar() Scalar
+	RandomScalar() (Scalar, error)
+	// ScalarFromBigInt x mod n, public values only, big.Int is not constant-time
+	ScalarFromBigInt(x *big.Int) Scalar
+	// ScalarFromBytes canonical fixed width encoding, the value must be less than the order
+	ScalarFromBytes(b []byte) (Scalar, error)
+	// ScalarFromSecret x in [0, n) through its fixed width encoding and ScalarFromBytes, no big.Int reduction,
+	// for keys, shares and nonces
+	ScalarFromSecret(x *big.Int) (Scalar, error)
+	// ScalarFromUniformBytes wide reduction of twice the scalar length, for hashes and randomness
+	ScalarFromUniformBytes(b []byte) (Scalar, error)
+}
+
+// Scalar integer modulo the group order, arithmetic is constant-time
+type Scalar interface {
+	Group() Group
+	Add(b Scalar) Scalar
+	Sub(b Scalar) Scalar
+	Mul(b Scalar) Scalar
+	Negate() Scalar
+	// Invert error for zero
+	Invert() (Scalar, error)
+	IsZero() bool
+	Equal(b Scalar) bool
+	// Bytes big endian, little endian on ed25519
+	Bytes() []byte
+	BigInt() *big.Int
+}
+
+// Point group element, the identity is a value like any other
+type Point interface {
+	Group() Group
+	Add(q Point) Point
+	Sub(q Point) Point
+	Negate() Point
+	ScalarMult(k Scalar) Point
+	IsIdentity() bool
+	Equal(q Point) bool
+	// Bytes canonical compressed encoding, 0x00 is the identity of weierstrass curves
+	Bytes() []byte
+	// Affine coordinates, (0, 0) for the identity of weierstrass curves
+	Affine() (x, y *big.Int)
+	// ECPoint legacy point type, error for the identity of weierstrass curves which has no affine form
+	ECPoint() (*curves.ECPoint, error)
+}
+
+var (
+	// groups by curve name, unregistered curves by the curve itself, so every instance of a registered curve,
+	// e.g. each edwards.Edwards() call, maps to the same group
+	groups    = make(map[interface{}]Group)
+	groupLock sync.Mutex
+)
+
+// Secp256k1 group of secp256k1
+func Secp256k1() Group {
+	return mustByName(curves.Secp256k1)
+}
+
+// P256 group of NIST P-256
+func P256() Group {
+	return mustByName(curves.P256)
+}
+
+// Ed25519 prime order subgroup of edwards25519
+func Ed25519() Group {
+	return mustByName(curves.Ed25519)
+}
+
+// ByName group of a registered curve
+func ByName(name string) (Group, error) {
+	curve, ok := curves.GetCurveByName(name)
+	if !ok {
+		return nil, fmt.Errorf("curve %s is not registered", name)
+	}
+	return FromCurve(curve)
+}
+
+// FromCurve group of the curve, ed25519 or any short weierstrass curve with a = 0 (secp256k1) or a = -3
+func FromCurve(curve elliptic.Curve) (Group, error) {
+	if curve == nil {
+		return nil, fmt.Errorf("curve is nil")
+	}
+	name := curves.GetCurveName(curve)
+	var key interface{} = curve
+	if name != "" {
+		key = name
+	}
+	groupLock.Lock()
+	defer groupLock.Unlock()
+	if g, ok := groups[key]; ok {
+		return g, nil
+	}
+	var (
+		g   Group
+		err error
+	)
+	if name == curves.Ed25519 {
+		g, err = newEdwardsGroup(curve)
+	} else {
+		g, err = newWeierstrassGroup(curve)
+	}
+	if err != nil {
+		return nil, err
+	}
+	groups[key] = g
+	return g, nil
+}
+
+// sameGroup groups of registered curves are the same by name and order, others by identity
+func sameGroup(a, b Group) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil || a.Name() == "" {
+		return false
+	}
+	return a.Name() == b.Name() && a.Order().Cmp(b.Order()) == 0
+}
+
+// FromECPoint group element of a legacy point, the curve must be supported by FromCurve
+func FromECPoint(p *curves.ECPoint) (Point, error) {
+	if p == nil || p.Curve == nil || p.X == nil || p.Y == nil {
+		return nil, fmt.Errorf("point is nil")
+	}
+	g, err := FromCurve(p.Curve)
+	if err != nil {
+		return nil, err
+	}
+	return g.PointFromAffine(p.X, p.Y)
+}
+
+func mustByName(name string) Group {
+	g, err := ByName(name)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
@@ -0,0 +1,202 @@
+package group
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/stretchr/testify/require"
+)
+
+func allGroups() []Group {
+	return []Group{Secp256k1(), P256(), Ed25519()}
+}
+
+func TestScalar(t *testing.T) {
+	for _, g := range allGroups() {
+		fmt.Println("group:", g.Name())
+		n := g.Order()
+		for i := 0; i < 50; i++ {
+			a, b := crypto.RandomNum(n), crypto.RandomNum(n)
+			if i == 0 {
+				a = new(big.Int).Sub(n, big.NewInt(1))
+			}
+			sa, sb := g.ScalarFromBigInt(a), g.ScalarFromBigInt(b)
+			require.Equal(t, 0, sa.BigInt().Cmp(a))
+			require.Equal(t, 0, sa.Add(sb).BigInt().Cmp(new(big.Int).Mod(new(big.Int).Add(a, b), n)))
+			require.Equal(t, 0, sa.Sub(sb).BigInt().Cmp(new(big.Int).Mod(new(big.Int).Sub(a, b), n)))
+			require.Equal(t, 0, sa.Mul(sb).BigInt().Cmp(new(big.Int).Mod(new(big.Int).Mul(a, b), n)))
+			require.Equal(t, 0, sa.Negate().BigInt().Cmp(new(big.Int).Mod(new(big.Int).Neg(a), n)))
+			inv, err := sa.Invert()
+			require.NoError(t, err)
+			require.Equal(t, 0, inv.BigInt().Cmp(new(big.Int).ModInverse(a, n)))
+
+			decoded, err := g.ScalarFromBytes(sa.Bytes())
+			require.NoError(t, err)
+			require.True(t, decoded.Equal(sa))
+			secret, err := g.ScalarFromSecret(a)
+			require.NoError(t, err)
+			require.True(t, secret.Equal(sa))
+		}
+		require.True(t, g.NewScalar().IsZero())
+		require.True(t, g.ScalarFromBigInt(n).IsZero())
+		require.True(t, g.ScalarFromBigInt(big.NewInt(-1)).Equal(g.ScalarFromBigInt(new(big.Int).Sub(n, big.NewInt(1)))))
+		_, err := g.NewScalar().Invert()
+		require.Error(t, err)
+
+		// order itself is not a canonical encoding
+		nBytes := g.ScalarFromBigInt(big.NewInt(1)).Bytes()
+		copy(nBytes, n.FillBytes(make([]byte, len(nBytes))))
+		if g.Name() == curves.Ed25519 {
+			reverse(nBytes)
+		}
+		_, err = g.ScalarFromBytes(nBytes)
+		require.Error(t, err)
+		// secrets are never reduced
+		_, err = g.ScalarFromSecret(n)
+		require.Error(t, err)
+		_, err = g.ScalarFromSecret(big.NewInt(-1))
+		require.Error(t, err)
+		zero, err := g.ScalarFromSecret(big.NewInt(0))
+		require.NoError(t, err)
+		require.True(t, zero.IsZero())
+
+		// wide reduction
+		wide := make([]byte, 64)
+		for i := range wide {
+			wide[i] = 0xff
+		}
+		s, err := g.ScalarFromUniformBytes(wide)
+		require.NoError(t, err)
+		expected := new(big.Int).Mod(new(big.Int).SetBytes(wide), n)
+		require.Equal(t, 0, s.BigInt().Cmp(expected))
+
+		r1, err := g.RandomScalar()
+		require.NoError(t, err)
+		r2, err := g.RandomScalar()
+		require.NoError(t, err)
+		require.False(t, r1.Equal(r2))
+	}
+}
+
+func TestPoint(t *testing.T) {
+	for _, g := range allGroups() {
+		fmt.Println("group:", g.Name())
+		O := g.Identity()
+		G := g.Generator()
+		require.True(t, O.IsIdentity())
+		require.False(t, G.IsIdentity())
+
+		// identity is neutral, P - P is the identity
+		a, _ := g.RandomScalar()
+		P := G.ScalarMult(a)
+		require.True(t, P.Add(O).Equal(P))
+		require.True(t, O.Add(P).Equal(P))
+		require.True(t, P.Sub(P).IsIdentity())
+		require.True(t, P.Add(P.Negate()).IsIdentity())
+		require.True(t, G.ScalarMult(g.NewScalar()).IsIdentity())
+		require.True(t, O.ScalarMult(a).IsIdentity())
+		require.True(t, O.Negate().IsIdentity())
+
+		// (a+b)G = aG + bG, doubling
+		b, _ := g.RandomScalar()
+		require.True(t, G.ScalarMult(a.Add(b)).Equal(P.Add(G.ScalarMult(b))))
+		two := g.ScalarFromBigInt(big.NewInt(2))
+		require.True(t, P.Add(P).Equal(P.ScalarMult(two)))
+
+		// same as the legacy point
+		legacy := curves.ScalarToPoint(g.Curve(), a.BigInt())
+		ecPoint, err := P.ECPoint()
+		require.NoError(t, err)
+		require.True(t, legacy.Equals(ecPoint))
+		fromLegacy, err := FromECPoint(legacy)
+		require.NoError(t, err)
+		require.True(t, fromLegacy.Equal(P))
+
+		// canonical encoding round trip, identity included
+		for _, point := range []Point{O, G, P} {
+			decoded, err := g.PointFromBytes(point.Bytes())
+			require.NoError(t, err)
+			require.True(t, decoded.Equal(point))
+		}
+		x, y := O.Affine()
+		decoded, err := g.PointFromAffine(x, y)
+		require.NoError(t, err)
+		require.True(t, decoded.IsIdentity())
+	}
+
+	// SEC1 and RFC 8032 generator encodings
+	require.Equal(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", hex.EncodeToString(Secp256k1().Generator().Bytes()))
+	require.Equal(t, "036b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296", hex.EncodeToString(P256().Generator().Bytes()))
+	require.Equal(t, "5866666666666666666666666666666666666666666666666666666666666666", hex.EncodeToString(Ed25519().Generator().Bytes()))
+	require.Equal(t, "00", hex.EncodeToString(Secp256k1().Identity().Bytes()))
+	require.Equal(t, "0100000000000000000000000000000000000000000000000000000000000000", hex.EncodeToString(Ed25519().Identity().Bytes()))
+
+	// identity has no legacy affine form on weierstrass curves
+	_, err := Secp256k1().Identity().ECPoint()
+	require.Error(t, err)
+
+	// off curve, small order and wrong length encodings
+	_, err = Secp256k1().PointFromAffine(big.NewInt(1), big.NewInt(1))
+	require.Error(t, err)
+	smallOrder, _ := hex.DecodeString("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	_, err = Ed25519().PointFromBytes(smallOrder)
+	require.Error(t, err)
+	_, err = P256().PointFromBytes(Secp256k1().Generator().Bytes()[:10])
+	require.Error(t, err)
+}
+
+func TestFromCurve(t *testing.T) {
+	g, err := FromCurve(elliptic.P256())
+	require.NoError(t, err)
+	require.Equal(t, P256(), g)
+	require.Equal(t, curves.P256, g.Name())
+
+	// unregistered nist curve, larger order
+	g, err = FromCurve(elliptic.P521())
+	require.NoError(t, err)
+	k, _ := g.RandomScalar()
+	P := g.Generator().ScalarMult(k)
+	decoded, err := g.PointFromBytes(P.Bytes())
+	require.NoError(t, err)
+	require.True(t, decoded.Equal(P))
+	inv, err := k.Invert()
+	require.NoError(t, err)
+	require.True(t, P.ScalarMult(inv).Equal(g.Generator()))
+
+	// every edwards.Edwards() call is a new curve instance, all map to one group and mix freely
+	g, err = FromCurve(edwards.Edwards())
+	require.NoError(t, err)
+	require.True(t, g == Ed25519())
+	x := Ed25519().ScalarFromBigInt(big.NewInt(5))
+	P, err = FromECPoint(curves.ScalarToPoint(edwards.Edwards(), big.NewInt(3)))
+	require.NoError(t, err)
+	require.True(t, P.ScalarMult(x).Equal(Ed25519().Generator().ScalarMult(Ed25519().ScalarFromBigInt(big.NewInt(15)))))
+	require.True(t, sameGroup(g, Ed25519()))
+	require.False(t, sameGroup(g, Secp256k1()))
+
+	_, err = ByName("unknown")
+	require.Error(t, err)
+}
+
+func TestHashToPoint(t *testing.T) {
+	for _, g := range allGroups() {
+		H := HashToPoint(g, []byte("TestHashToPoint"))
+		fmt.Println(g.Name(), hex.EncodeToString(H.Bytes()))
+		require.False(t, H.IsIdentity())
+		require.False(t, H.Equal(g.Generator()))
+		require.True(t, H.Equal(HashToPoint(g, []byte("TestHashToPoint"))))
+		require.False(t, H.Equal(HashToPoint(g, []byte("TestHashToPoint2"))))
+
+		// in the prime order subgroup
+		decoded, err := g.PointFromBytes(H.Bytes())
+		require.NoError(t, err)
+		require.True(t, decoded.Equal(H))
+		require.True(t, H.ScalarMult(g.ScalarFromBigInt(big.NewInt(-1))).Equal(H.Negate()))
+	}
+}
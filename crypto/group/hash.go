@@ -0,0 +1,37 @@
+package group
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// HashToPoint point with unknown discrete logarithm to the generator, try-and-increment over sha256(domain, counter),
+// for nothing up my sleeve generators like the second pedersen base H, not constant-time
+func HashToPoint(g Group, domain []byte) Point {
+	length := len(g.Generator().Bytes())
+	_, weierstrass := g.(*weierstrassGroup)
+	for counter := uint32(0); ; counter++ {
+		var buf []byte
+		for block := uint32(0); len(buf) < length; block++ {
+			var ctr [8]byte
+			binary.BigEndian.PutUint32(ctr[:4], counter)
+			binary.BigEndian.PutUint32(ctr[4:], block)
+			h := sha256.New()
+			h.Write(domain)
+			h.Write(ctr[:])
+			buf = h.Sum(buf)
+		}
+		buf = buf[:length]
+		if weierstrass {
+			// compressed point with even y, x below 2^bitSize
+			buf[0] = 2
+			if bits := g.Curve().Params().BitSize % 8; bits != 0 {
+				buf[1] &= byte(1)<<bits - 1
+			}
+		}
+		point, err := g.PointFromBytes(buf)
+		if err == nil && !point.IsIdentity() {
+			return point
+		}
+	}
+}
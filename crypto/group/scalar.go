@@ -0,0 +1,143 @@
+package group
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// scalar value in montgomery form
+type scalar struct {
+	g Group
+	f *scalarField
+	v limbs
+}
+
+func (f *scalarField) newScalar(g Group, v limbs) *scalar {
+	return &scalar{g: g, f: f, v: v}
+}
+
+func (f *scalarField) random(g Group) (Scalar, error) {
+	buf := make([]byte, 16*f.size)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	hi := loadLimbs(buf[:8*f.size], f.size)
+	lo := loadLimbs(buf[8*f.size:], f.size)
+	return f.newScalar(g, f.reduceWide(&lo, &hi)), nil
+}
+
+func (f *scalarField) fromBigInt(g Group, x *big.Int) Scalar {
+	v := f.fromBig(new(big.Int).Mod(x, f.order))
+	return f.newScalar(g, f.toMont(&v))
+}
+
+func (f *scalarField) fromBytes(g Group, b []byte) (Scalar, error) {
+	v, err := f.decode(b)
+	if err != nil {
+		return nil, err
+	}
+	if !f.less(&v) {
+		return nil, fmt.Errorf("scalar is not canonical")
+	}
+	return f.newScalar(g, f.toMont(&v)), nil
+}
+
+// fromSecret fixed width encoding of x, only its limbs count is visible to big.Int
+func (f *scalarField) fromSecret(g Group, x *big.Int) (Scalar, error) {
+	if x == nil || x.Sign() < 0 || x.BitLen() > 8*f.length {
+		return nil, fmt.Errorf("secret scalar out of range")
+	}
+	b := x.FillBytes(make([]byte, f.length))
+	if f.le {
+		reverse(b)
+	}
+	return f.fromBytes(g, b)
+}
+
+func (f *scalarField) fromUniformBytes(g Group, b []byte) (Scalar, error) {
+	v, err := f.decodeWide(b)
+	if err != nil {
+		return nil, err
+	}
+	return f.newScalar(g, v), nil
+}
+
+// other scalar of the same group, mixing groups is a programming error
+func (s *scalar) other(b Scalar) *scalar {
+	o, ok := b.(*scalar)
+	if !ok || !sameGroup(o.g, s.g) {
+		panic(fmt.Errorf("group: scalars of different groups"))
+	}
+	return o
+}
+
+func (s *scalar) Group() Group {
+	return s.g
+}
+
+func (s *scalar) Add(b Scalar) Scalar {
+	return s.f.newScalar(s.g, s.f.add(&s.v, &s.other(b).v))
+}
+
+func (s *scalar) Sub(b Scalar) Scalar {
+	return s.f.newScalar(s.g, s.f.sub(&s.v, &s.other(b).v))
+}
+
+func (s *scalar) Mul(b Scalar) Scalar {
+	return s.f.newScalar(s.g, s.f.mul(&s.v, &s.other(b).v))
+}
+
+func (s *scalar) Negate() Scalar {
+	var zero limbs
+	return s.f.newScalar(s.g, s.f.sub(&zero, &s.v))
+}
+
+// Invert s^(n-2), fermat's little theorem, the exponent is public
+func (s *scalar) Invert() (Scalar, error) {
+	if s.IsZero() {
+		return nil, fmt.Errorf("zero has no inverse")
+	}
+	e := new(big.Int).Sub(s.f.order, big.NewInt(2))
+	return s.f.newScalar(s.g, s.f.exp(&s.v, e)), nil
+}
+
+func (s *scalar) IsZero() bool {
+	return s.f.isZero(&s.v)
+}
+
+func (s *scalar) Equal(b Scalar) bool {
+	o, ok := b.(*scalar)
+	if !ok || !sameGroup(o.g, s.g) {
+		return false
+	}
+	return s.f.equal(&s.v, &o.v)
+}
+
+func (s *scalar) Bytes() []byte {
+	v := s.f.fromMont(&s.v)
+	return s.f.encode(&v)
+}
+
+// bigEndian bytes for crypto/elliptic scalar multiplication
+func (s *scalar) bigEndian() []byte {
+	v := s.f.fromMont(&s.v)
+	return s.f.bigEndian(&v)
+}
+
+func (s *scalar) BigInt() *big.Int {
+	return new(big.Int).SetBytes(s.bigEndian())
+}
+
+func (s *scalar) String() string {
+	return s.BigInt().String()
+}
+
+// toScalar scalar of the group g
+func toScalar(g Group, k Scalar) *scalar {
+	s, ok := k.(*scalar)
+	if !ok || !sameGroup(s.g, g) {
+		panic(fmt.Errorf("group: scalar of a different group"))
+	}
+	return s
+}
@@ -0,0 +1,205 @@
+package group
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+)
+
+// weierstrassGroup secp256k1, p256 and other short weierstrass curves of crypto/elliptic,
+// point arithmetic is done by the curve implementation
+type weierstrassGroup struct {
+	name     string
+	curve    elliptic.Curve
+	field    *scalarField
+	coordLen int
+}
+
+// weierstrassPoint affine point, inf marks the point at infinity
+type weierstrassPoint struct {
+	g    *weierstrassGroup
+	x, y *big.Int
+	inf  bool
+}
+
+func newWeierstrassGroup(curve elliptic.Curve) (*weierstrassGroup, error) {
+	params := curve.Params()
+	field, err := newScalarField(params.N, false)
+	if err != nil {
+		return nil, err
+	}
+	return &weierstrassGroup{
+		name:     curves.GetCurveName(curve),
+		curve:    curve,
+		field:    field,
+		coordLen: (params.BitSize + 7) / 8,
+	}, nil
+}
+
+func (g *weierstrassGroup) Name() string {
+	return g.name
+}
+
+func (g *weierstrassGroup) Curve() elliptic.Curve {
+	return g.curve
+}
+
+func (g *weierstrassGroup) Order() *big.Int {
+	return new(big.Int).Set(g.field.order)
+}
+
+func (g *weierstrassGroup) Identity() Point {
+	return &weierstrassPoint{g: g, inf: true}
+}
+
+func (g *weierstrassGroup) Generator() Point {
+	params := g.curve.Params()
+	return &weierstrassPoint{g: g, x: params.Gx, y: params.Gy}
+}
+
+// PointFromBytes SEC1 compressed point, a single 0x00 byte is the identity
+func (g *weierstrassGroup) PointFromBytes(b []byte) (Point, error) {
+	if len(b) == 1 && b[0] == 0 {
+		return g.Identity(), nil
+	}
+	if len(b) != 1+g.coordLen || (b[0] != 2 && b[0] != 3) {
+		return nil, fmt.Errorf("invalid compressed point")
+	}
+	x := new(big.Int).SetBytes(b[1:])
+	y, err := curves.LiftX(g.curve, x)
+	if err != nil {
+		return nil, err
+	}
+	if y.Bit(0) != uint(b[0]&1) {
+		y.Sub(g.curve.Params().P, y)
+	}
+	return g.PointFromAffine(x, y)
+}
+
+func (g *weierstrassGroup) PointFromAffine(x, y *big.Int) (Point, error) {
+	if x == nil || y == nil {
+		return nil, fmt.Errorf("point is nil")
+	}
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return g.Identity(), nil
+	}
+	if !g.curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("point is not on curve")
+	}
+	return &weierstrassPoint{g: g, x: new(big.Int).Set(x), y: new(big.Int).Set(y)}, nil
+}
+
+func (g *weierstrassGroup) NewScalar() Scalar {
+	return g.field.newScalar(g, limbs{})
+}
+
+func (g *weierstrassGroup) RandomScalar() (Scalar, error) {
+	return g.field.random(g)
+}
+
+func (g *weierstrassGroup) ScalarFromBigInt(x *big.Int) Scalar {
+	return g.field.fromBigInt(g, x)
+}
+
+func (g *weierstrassGroup) ScalarFromBytes(b []byte) (Scalar, error) {
+	return g.field.fromBytes(g, b)
+}
+
+func (g *weierstrassGroup) ScalarFromSecret(x *big.Int) (Scalar, error) {
+	return g.field.fromSecret(g, x)
+}
+
+func (g *weierstrassGroup) ScalarFromUniformBytes(b []byte) (Scalar, error) {
+	return g.field.fromUniformBytes(g, b)
+}
+
+func (p *weierstrassPoint) other(q Point) *weierstrassPoint {
+	o, ok := q.(*weierstrassPoint)
+	if !ok || !sameGroup(o.g, p.g) {
+		panic(fmt.Errorf("group: points of different groups"))
+	}
+	return o
+}
+
+func (p *weierstrassPoint) affine(x, y *big.Int) Point {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return p.g.Identity()
+	}
+	return &weierstrassPoint{g: p.g, x: x, y: y}
+}
+
+func (p *weierstrassPoint) Group() Group {
+	return p.g
+}
+
+func (p *weierstrassPoint) Add(q Point) Point {
+	o := p.other(q)
+	if p.inf {
+		return o
+	}
+	if o.inf {
+		return p
+	}
+	return p.affine(p.g.curve.Add(p.x, p.y, o.x, o.y))
+}
+
+func (p *weierstrassPoint) Sub(q Point) Point {
+	return p.Add(p.other(q).Negate())
+}
+
+func (p *weierstrassPoint) Negate() Point {
+	if p.inf {
+		return p
+	}
+	y := new(big.Int).Sub(p.g.curve.Params().P, p.y)
+	return &weierstrassPoint{g: p.g, x: p.x, y: y}
+}
+
+func (p *weierstrassPoint) ScalarMult(k Scalar) Point {
+	s := toScalar(p.g, k)
+	if p.inf || s.IsZero() {
+		return p.g.Identity()
+	}
+	return p.affine(p.g.curve.ScalarMult(p.x, p.y, s.bigEndian()))
+}
+
+func (p *weierstrassPoint) IsIdentity() bool {
+	return p.inf
+}
+
+func (p *weierstrassPoint) Equal(q Point) bool {
+	o, ok := q.(*weierstrassPoint)
+	if !ok || !sameGroup(o.g, p.g) {
+		return false
+	}
+	if p.inf || o.inf {
+		return p.inf == o.inf
+	}
+	return p.x.Cmp(o.x) == 0 && p.y.Cmp(o.y) == 0
+}
+
+func (p *weierstrassPoint) Bytes() []byte {
+	if p.inf {
+		return []byte{0}
+	}
+	out := make([]byte, 1+p.g.coordLen)
+	out[0] = byte(2 + p.y.Bit(0))
+	p.x.FillBytes(out[1:])
+	return out
+}
+
+func (p *weierstrassPoint) Affine() (*big.Int, *big.Int) {
+	if p.inf {
+		return new(big.Int), new(big.Int)
+	}
+	return new(big.Int).Set(p.x), new(big.Int).Set(p.y)
+}
+
+func (p *weierstrassPoint) ECPoint() (*curves.ECPoint, error) {
+	if p.inf {
+		return nil, fmt.Errorf("identity has no affine coordinates")
+	}
+	return &curves.ECPoint{Curve: p.g.curve, X: new(big.Int).Set(p.x), Y: new(big.Int).Set(p.y)}, nil
+}
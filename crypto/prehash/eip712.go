@@ -0,0 +1,261 @@
+package prehash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TypedData EIP-712 typed data as sent to eth_signTypedData_v4
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedDataDigest keccak256(0x19 0x01 || hashStruct(domain) || hashStruct(message)) of the typed data json
+func TypedDataDigest(data []byte) ([]byte, error) {
+	var typedData TypedData
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&typedData); err != nil {
+		return nil, fmt.Errorf("invalid typed data: %v", err)
+	}
+	return typedData.Digest()
+}
+
+// Digest EIP-712 signing digest
+func (typedData *TypedData) Digest() ([]byte, error) {
+	if _, ok := typedData.Types["EIP712Domain"]; !ok {
+		return nil, fmt.Errorf("typed data misses EIP712Domain type")
+	}
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain)
+	if err != nil {
+		return nil, err
+	}
+	if typedData.PrimaryType == "EIP712Domain" {
+		return keccak256([]byte{0x19, 0x01}, domainSeparator), nil
+	}
+	hashStruct, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return nil, err
+	}
+	return keccak256([]byte{0x19, 0x01}, domainSeparator, hashStruct), nil
+}
+
+// HashStruct keccak256(typeHash || encodeData(data))
+func (typedData *TypedData) HashStruct(primaryType string, data map[string]interface{}) ([]byte, error) {
+	encoded, err := typedData.encodeData(primaryType, data)
+	if err != nil {
+		return nil, err
+	}
+	return keccak256(encoded), nil
+}
+
+// EncodeType primary type followed by the referenced struct types in alphabetical order,
+// e.g. Mail(Person from,Person to,string contents)Person(string name,address wallet)
+func (typedData *TypedData) EncodeType(primaryType string) (string, error) {
+	deps := make(map[string]bool)
+	if err := typedData.dependencies(primaryType, deps); err != nil {
+		return "", err
+	}
+	delete(deps, primaryType)
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range append([]string{primaryType}, names...) {
+		builder.WriteString(name)
+		builder.WriteString("(")
+		for i, field := range typedData.Types[name] {
+			if i > 0 {
+				builder.WriteString(",")
+			}
+			builder.WriteString(field.Type)
+			builder.WriteString(" ")
+			builder.WriteString(field.Name)
+		}
+		builder.WriteString(")")
+	}
+	return builder.String(), nil
+}
+
+func (typedData *TypedData) dependencies(typeName string, deps map[string]bool) error {
+	if deps[typeName] {
+		return nil
+	}
+	fields, ok := typedData.Types[typeName]
+	if !ok {
+		return fmt.Errorf("undefined type %s", typeName)
+	}
+	deps[typeName] = true
+	for _, field := range fields {
+		base := baseType(field.Type)
+		if _, ok := typedData.Types[base]; ok {
+			if err := typedData.dependencies(base, deps); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (typedData *TypedData) encodeData(typeName string, data map[string]interface{}) ([]byte, error) {
+	encodedType, err := typedData.EncodeType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	encoded := keccak256([]byte(encodedType))
+	for _, field := range typedData.Types[typeName] {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s misses field %s", typeName, field.Name)
+		}
+		word, err := typedData.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", typeName, field.Name, err)
+		}
+		encoded = append(encoded, word...)
+	}
+	return encoded, nil
+}
+
+// encodeValue 32 bytes encoding of one field, dynamic values, arrays and structs are hashed
+func (typedData *TypedData) encodeValue(typeName string, value interface{}) ([]byte, error) {
+	if strings.HasSuffix(typeName, "]") {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("array expected")
+		}
+		itemType := typeName[:strings.LastIndex(typeName, "[")]
+		var encoded []byte
+		for _, item := range items {
+			word, err := typedData.encodeValue(itemType, item)
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, word...)
+		}
+		return keccak256(encoded), nil
+	}
+	if _, ok := typedData.Types[typeName]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("object expected")
+		}
+		return typedData.HashStruct(typeName, data)
+	}
+
+	switch {
+	case typeName == "string":
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("string expected")
+		}
+		return keccak256([]byte(str)), nil
+	case typeName == "bytes":
+		data, err := hexValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return keccak256(data), nil
+	case typeName == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("bool expected")
+		}
+		word := make([]byte, 32)
+		if b {
+			word[31] = 1
+		}
+		return word, nil
+	case typeName == "address":
+		data, err := hexValue(value)
+		if err != nil || len(data) != 20 {
+			return nil, fmt.Errorf("20 bytes address expected")
+		}
+		return append(make([]byte, 12), data...), nil
+	case strings.HasPrefix(typeName, "bytes"):
+		size, err := strconv.Atoi(typeName[len("bytes"):])
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("unsupported type %s", typeName)
+		}
+		data, err := hexValue(value)
+		if err != nil || len(data) != size {
+			return nil, fmt.Errorf("%d bytes expected", size)
+		}
+		return append(data, make([]byte, 32-size)...), nil
+	case strings.HasPrefix(typeName, "uint"), strings.HasPrefix(typeName, "int"):
+		return encodeInteger(typeName, value)
+	}
+	return nil, fmt.Errorf("unsupported type %s", typeName)
+}
+
+// encodeInteger uintN and intN from a json number, a decimal or a 0x hex string, negative values in two's complement
+func encodeInteger(typeName string, value interface{}) ([]byte, error) {
+	signed := strings.HasPrefix(typeName, "int")
+	bits := 256
+	if suffix := strings.TrimPrefix(strings.TrimPrefix(typeName, "u"), "int"); suffix != "" {
+		var err error
+		if bits, err = strconv.Atoi(suffix); err != nil || bits < 8 || bits > 256 || bits%8 != 0 {
+			return nil, fmt.Errorf("unsupported type %s", typeName)
+		}
+	}
+	var text string
+	switch v := value.(type) {
+	case json.Number:
+		text = v.String()
+	case string:
+		text = v
+	default:
+		return nil, fmt.Errorf("integer expected")
+	}
+	n, ok := new(big.Int).SetString(text, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %s", text)
+	}
+	min, max := big.NewInt(0), new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	if signed {
+		max.Rsh(max, 1)
+		min.Neg(max)
+	}
+	if n.Cmp(min) < 0 || n.Cmp(max) >= 0 {
+		return nil, fmt.Errorf("integer %s overflows %s", text, typeName)
+	}
+	if n.Sign() < 0 {
+		n.Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	word := make([]byte, 32)
+	n.FillBytes(word)
+	return word, nil
+}
+
+func hexValue(value interface{}) ([]byte, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("hex string expected")
+	}
+	return hex.DecodeString(strings.TrimPrefix(str, "0x"))
+}
+
+// baseType struct name of an array type, e.g. Person[][2] -> Person
+func baseType(typeName string) string {
+	if i := strings.Index(typeName, "["); i >= 0 {
+		return typeName[:i]
+	}
+	return typeName
+}
@@ -0,0 +1,132 @@
+package prehash
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Mode how the message is hashed before signing
+type Mode string
+
+const (
+	// ecdsa modes, the signed value is a 32 bytes digest
+	Raw          Mode = "raw"           // message is already the 32 bytes digest
+	SHA256       Mode = "sha256"        // sha256(message)
+	DoubleSHA256 Mode = "double_sha256" // sha256(sha256(message)), bitcoin
+	Keccak256    Mode = "keccak256"     // keccak256(message), ethereum transactions
+	EIP191       Mode = "eip191"        // ethereum personal_sign message
+	EIP712       Mode = "eip712"        // ethereum typed data, message is the typed data json
+
+	// eddsa modes of RFC 8032
+	Ed25519    Mode = "ed25519"    // PureEdDSA, message is signed as is
+	Ed25519ph  Mode = "ed25519ph"  // sha512(message) is signed, optional context
+	Ed25519ctx Mode = "ed25519ctx" // message is signed with a non empty context
+)
+
+// ParseMode check name is a supported mode
+func ParseMode(name string) (Mode, error) {
+	mode := Mode(name)
+	switch mode {
+	case Raw, SHA256, DoubleSHA256, Keccak256, EIP191, EIP712, Ed25519, Ed25519ph, Ed25519ctx:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unsupported hash mode %q", name)
+}
+
+// IsEd25519 mode is one of the eddsa modes
+func (mode Mode) IsEd25519() bool {
+	return mode == Ed25519 || mode == Ed25519ph || mode == Ed25519ctx
+}
+
+// Digest 32 bytes digest of message for ecdsa signing
+func Digest(mode Mode, message []byte) ([]byte, error) {
+	switch mode {
+	case Raw:
+		if len(message) != 32 {
+			return nil, fmt.Errorf("raw digest must be 32 bytes")
+		}
+		return message, nil
+	case SHA256:
+		digest := sha256.Sum256(message)
+		return digest[:], nil
+	case DoubleSHA256:
+		first := sha256.Sum256(message)
+		digest := sha256.Sum256(first[:])
+		return digest[:], nil
+	case Keccak256:
+		return keccak256(message), nil
+	case EIP191:
+		prefix := "\x19Ethereum Signed Message:\n" + strconv.Itoa(len(message))
+		return keccak256([]byte(prefix), message), nil
+	case EIP712:
+		return TypedDataDigest(message)
+	}
+	return nil, fmt.Errorf("hash mode %q is not an ecdsa mode", mode)
+}
+
+// DigestHex hex encoded message in, hex encoded digest out, as taken by the signing APIs
+func DigestHex(mode Mode, message string) (string, error) {
+	bytes, err := hex.DecodeString(message)
+	if err != nil {
+		return "", err
+	}
+	digest, err := Digest(mode, bytes)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest), nil
+}
+
+// Ed25519Options RFC 8032 variant of an eddsa signature, Context is at most 255 bytes,
+// required by Ed25519ctx, optional for Ed25519ph and must be empty for Ed25519
+type Ed25519Options struct {
+	Mode    Mode
+	Context []byte
+}
+
+// Ed25519Message dom2 prefix and message M' hashed by the challenge SHA512(dom2 || R || A || M')
+func Ed25519Message(options *Ed25519Options, message []byte) ([]byte, []byte, error) {
+	if options == nil {
+		return nil, message, nil
+	}
+	if len(options.Context) > 255 {
+		return nil, nil, fmt.Errorf("context is longer than 255 bytes")
+	}
+	switch options.Mode {
+	case Ed25519, "":
+		if len(options.Context) != 0 {
+			return nil, nil, fmt.Errorf("context is not supported by ed25519, use ed25519ctx")
+		}
+		return nil, message, nil
+	case Ed25519ph:
+		digest := sha512.Sum512(message)
+		return dom2(1, options.Context), digest[:], nil
+	case Ed25519ctx:
+		if len(options.Context) == 0 {
+			return nil, nil, fmt.Errorf("ed25519ctx requires a context")
+		}
+		return dom2(0, options.Context), message, nil
+	}
+	return nil, nil, fmt.Errorf("hash mode %q is not an eddsa mode", options.Mode)
+}
+
+// dom2 "SigEd25519 no Ed25519 collisions" || phflag || len(context) || context
+func dom2(phflag byte, context []byte) []byte {
+	dom := []byte("SigEd25519 no Ed25519 collisions")
+	dom = append(dom, phflag, byte(len(context)))
+	return append(dom, context...)
+}
+
+// keccak256 legacy keccak-256 of data as used by ethereum
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,114 @@
+package prehash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const mail = `{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "version", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "verifyingContract", "type": "address"}
+    ],
+    "Person": [
+      {"name": "name", "type": "string"},
+      {"name": "wallet", "type": "address"}
+    ],
+    "Mail": [
+      {"name": "from", "type": "Person"},
+      {"name": "to", "type": "Person"},
+      {"name": "contents", "type": "string"}
+    ]
+  },
+  "primaryType": "Mail",
+  "domain": {
+    "name": "Ether Mail",
+    "version": "1",
+    "chainId": 1,
+    "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+  },
+  "message": {
+    "from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+    "to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+    "contents": "Hello, Bob!"
+  }
+}`
+
+func TestDigest(t *testing.T) {
+	hello := []byte("hello")
+	sha := sha256.Sum256(hello)
+	vectors := []struct {
+		mode     Mode
+		message  []byte
+		expected string
+	}{
+		{Raw, sha[:], hex.EncodeToString(sha[:])},
+		{SHA256, hello, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{DoubleSHA256, hello, "9595c9df90075148eb06860365df33584b75bff782a510c6cd4883a419833d50"},
+		{Keccak256, hello, "1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8"},
+		{EIP191, hello, "50b2c43fd39106bafbba0da34fc430e1f91e3c96ea2acee2bc34119f92b37750"},
+		{EIP712, []byte(mail), "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"},
+	}
+	for _, v := range vectors {
+		digest, err := Digest(v.mode, v.message)
+		require.NoError(t, err)
+		require.Equal(t, v.expected, hex.EncodeToString(digest), v.mode)
+		fmt.Println(v.mode, hex.EncodeToString(digest))
+	}
+
+	_, err := Digest(Raw, hello)
+	require.Error(t, err)
+	_, err = Digest(Ed25519ph, hello)
+	require.Error(t, err)
+	_, err = ParseMode("md5")
+	require.Error(t, err)
+}
+
+func TestTypedData(t *testing.T) {
+	typedData := &TypedData{
+		Types: map[string][]TypedDataField{
+			"Person": {{Name: "name", Type: "string"}, {Name: "wallet", Type: "address"}},
+			"Mail":   {{Name: "from", Type: "Person"}, {Name: "to", Type: "Person"}, {Name: "contents", Type: "string"}},
+		},
+	}
+	encodeType, err := typedData.EncodeType("Mail")
+	require.NoError(t, err)
+	require.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", encodeType)
+
+	// integer range and two's complement
+	word, err := encodeInteger("int8", "-1")
+	require.NoError(t, err)
+	require.Equal(t, "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", hex.EncodeToString(word))
+	_, err = encodeInteger("int8", "128")
+	require.Error(t, err)
+	_, err = encodeInteger("uint8", "-1")
+	require.Error(t, err)
+	word, err = encodeInteger("uint256", "0x10")
+	require.NoError(t, err)
+	require.Equal(t, byte(16), word[31])
+}
+
+func TestEd25519Message(t *testing.T) {
+	message := []byte("hello")
+	dom, msg, err := Ed25519Message(nil, message)
+	require.NoError(t, err)
+	require.Nil(t, dom)
+	require.Equal(t, message, msg)
+
+	dom, msg, err = Ed25519Message(&Ed25519Options{Mode: Ed25519ph}, message)
+	require.NoError(t, err)
+	require.Equal(t, 64, len(msg))
+	require.Equal(t, byte(1), dom[32])
+
+	_, _, err = Ed25519Message(&Ed25519Options{Mode: Ed25519ctx}, message)
+	require.Error(t, err)
+	_, _, err = Ed25519Message(&Ed25519Options{Mode: Ed25519, Context: []byte("ctx")}, message)
+	require.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package schnorr
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+)
+
+// DleqProof Chaum-Pedersen proof that X = x*G and D = x*H share the same x
+type DleqProof struct {
+	R1 *curves.ECPoint // r*G
+	R2 *curves.ECPoint // r*H
+	S  *big.Int
+}
+
+// ProveDleqWithId s = r + hx
+func ProveDleqWithId(sessionId, x *big.Int, X, H, D *curves.ECPoint) (*DleqProof, error) {
+	if sessionId == nil || x == nil || X == nil || H == nil || D == nil {
+		return nil, fmt.Errorf("dleq prove parameters error")
+	}
+	g, points, err := dleqPoints(X, H, D)
+	if err != nil {
+		return nil, err
+	}
+	xs, err := g.ScalarFromSecret(x)
+	if err != nil {
+		return nil, err
+	}
+	r, err := g.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	R1p := g.Generator().ScalarMult(r)
+	R2p := points[1].ScalarMult(r)
+	R1, err := R1p.ECPoint()
+	if err != nil {
+		return nil, err
+	}
+	R2, err := R2p.ECPoint()
+	if err != nil {
+		return nil, err
+	}
+
+	h := dleqChallenge(g, sessionId, append(points, R1p, R2p)...)
+	s := r.Add(h.Mul(xs))
+	return &DleqProof{R1: R1, R2: R2, S: s.BigInt()}, nil
+}
+
+// VerifyDleqWithId s*G = R1 + h*X, s*H = R2 + h*D
+func VerifyDleqWithId(sessionId *big.Int, pf *DleqProof, X, H, D *curves.ECPoint) bool {
+	if sessionId == nil || pf == nil || pf.R1 == nil || pf.R2 == nil || pf.S == nil {
+		return false
+	}
+	if X == nil || H == nil || D == nil {
+		return false
+	}
+	g, points, err := dleqPoints(X, H, D, pf.R1, pf.R2)
+	if err != nil {
+		return false
+	}
+	S, err := scalarOf(g, pf.S)
+	if err != nil {
+		return false
+	}
+	Xp, Hp, Dp, R1p, R2p := points[0], points[1], points[2], points[3], points[4]
+	h := dleqChallenge(g, sessionId, points...)
+
+	if !g.Generator().ScalarMult(S).Equal(R1p.Add(Xp.ScalarMult(h))) {
+		return false
+	}
+	return Hp.ScalarMult(S).Equal(R2p.Add(Dp.ScalarMult(h)))
+}
+
+// dleqPoints group elements of X and the other points on the same curve
+func dleqPoints(X *curves.ECPoint, others ...*curves.ECPoint) (group.Group, []group.Point, error) {
+	g, Xp, err := toPoint(X)
+	if err != nil {
+		return nil, nil, err
+	}
+	points := []group.Point{Xp}
+	for _, P := range others {
+		point, err := pointOf(g, P)
+		if err != nil {
+			return nil, nil, err
+		}
+		points = append(points, point)
+	}
+	return g, points, nil
+}
+
+// dleqChallenge sha256(sessionId, G, X, H, D, R1, R2)
+func dleqChallenge(g group.Group, sessionId *big.Int, points ...group.Point) group.Scalar {
+	Gx, Gy := g.Generator().Affine()
+	input := []*big.Int{sessionId, Gx, Gy}
+	for _, point := range points {
+		x, y := point.Affine()
+		input = append(input, x, y)
+	}
+	return g.ScalarFromBigInt(crypto.SHA256Int(input...))
+}
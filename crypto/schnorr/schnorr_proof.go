@@ -6,6 +6,7 @@ import (
 
 	"github.com/okx/threshold-lib/crypto"
 	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
 )
 
 type Proof struct {
@@ -15,81 +16,125 @@ type Proof struct {
 
 // Prove schnorr s = r + hx
 func Prove(x *big.Int, X *curves.ECPoint) (*Proof, error) {
-	if x == nil || X == nil {
-		return nil, fmt.Errorf("schnorr prove parameters error")
-	}
-	q := X.Curve.Params().N
-
-	r := crypto.RandomNum(q)
-	R := curves.ScalarToPoint(X.Curve, r)
-
-	h := crypto.SHA256Int(X.X, X.Y, R.X, R.Y)
-	h = new(big.Int).Mod(h, q)
-
-	s := new(big.Int).Mul(h, x)
-	s = new(big.Int).Mod(new(big.Int).Add(r, s), q)
-	return &Proof{R: R, S: s}, nil
+	return prove(nil, x, X)
 }
 
 // Verify s*G = R + h*X
 func Verify(pf *Proof, X *curves.ECPoint) bool {
-	if pf == nil || pf.R == nil || pf.S == nil {
-		return false
-	}
-	if !pf.R.IsOnCurve() || !X.IsOnCurve() {
-		return false
+	return verify(nil, pf, X)
+}
+
+// ProveWithId schnorr s = r + hx
+func ProveWithId(sessionId, x *big.Int, X *curves.ECPoint) (*Proof, error) {
+	if sessionId == nil {
+		return nil, fmt.Errorf("schnorr prove parameters error")
 	}
-	q := X.Curve.Params().N
-	h := crypto.SHA256Int(X.X, X.Y, pf.R.X, pf.R.Y)
-	h = new(big.Int).Mod(h, q)
+	return prove(sessionId, x, X)
+}
 
-	SG := curves.ScalarToPoint(X.Curve, pf.S)
-	Xh := X.ScalarMult(h)
-	RXh, err := pf.R.Add(Xh)
-	if err != nil {
+// VerifyWithId s*G = R + h*X
+func VerifyWithId(sessionId *big.Int, pf *Proof, X *curves.ECPoint) bool {
+	if sessionId == nil {
 		return false
 	}
-	return RXh.X.Cmp(SG.X) == 0 && RXh.Y.Cmp(SG.Y) == 0
+	return verify(sessionId, pf, X)
 }
 
-// ProveWithId schnorr s = r + hx
-func ProveWithId(sessionId, x *big.Int, X *curves.ECPoint) (*Proof, error) {
+func prove(sessionId, x *big.Int, X *curves.ECPoint) (*Proof, error) {
 	if x == nil || X == nil {
 		return nil, fmt.Errorf("schnorr prove parameters error")
 	}
-	q := X.Curve.Params().N
-
-	r := crypto.RandomNum(q)
-	R := curves.ScalarToPoint(X.Curve, r)
-	G := curves.ScalarToPoint(X.Curve, big.NewInt(1))
-
-	h := crypto.SHA256Int(sessionId, G.X, G.Y, X.X, X.Y, R.X, R.Y)
-	h = new(big.Int).Mod(h, q)
-
-	s := new(big.Int).Mul(h, x)
-	s = new(big.Int).Mod(new(big.Int).Add(r, s), q)
-	return &Proof{R: R, S: s}, nil
+	g, Xp, err := toPoint(X)
+	if err != nil {
+		return nil, err
+	}
+	xs, err := g.ScalarFromSecret(x)
+	if err != nil {
+		return nil, err
+	}
+	r, err := g.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	Rp := g.Generator().ScalarMult(r)
+	R, err := Rp.ECPoint()
+	if err != nil {
+		return nil, err
+	}
+	h := challenge(g, sessionId, Xp, Rp)
+	s := r.Add(h.Mul(xs))
+	return &Proof{R: R, S: s.BigInt()}, nil
 }
 
-// VerifyWithId s*G = R + h*X
-func VerifyWithId(sessionId *big.Int, pf *Proof, X *curves.ECPoint) bool {
-	if pf == nil || pf.R == nil || pf.S == nil {
+func verify(sessionId *big.Int, pf *Proof, X *curves.ECPoint) bool {
+	if pf == nil || pf.R == nil || pf.S == nil || X == nil {
+		return false
+	}
+	g, Xp, err := toPoint(X)
+	if err != nil {
 		return false
 	}
-	if !pf.R.IsOnCurve() || !X.IsOnCurve() {
+	Rp, err := pointOf(g, pf.R)
+	if err != nil {
 		return false
 	}
-	q := X.Curve.Params().N
-	G := curves.ScalarToPoint(X.Curve, big.NewInt(1))
+	S, err := scalarOf(g, pf.S)
+	if err != nil {
+		return false
+	}
+	h := challenge(g, sessionId, Xp, Rp)
+	return g.Generator().ScalarMult(S).Equal(Rp.Add(Xp.ScalarMult(h)))
+}
+
+// challenge h = sha256(X, R) without session, sha256(sessionId, G, X, R) with session
+func challenge(g group.Group, sessionId *big.Int, X, R group.Point) group.Scalar {
+	Xx, Xy := X.Affine()
+	Rx, Ry := R.Affine()
+	var h *big.Int
+	if sessionId == nil {
+		h = crypto.SHA256Int(Xx, Xy, Rx, Ry)
+	} else {
+		Gx, Gy := g.Generator().Affine()
+		h = crypto.SHA256Int(sessionId, Gx, Gy, Xx, Xy, Rx, Ry)
+	}
+	return g.ScalarFromBigInt(h)
+}
 
-	h := crypto.SHA256Int(sessionId, G.X, G.Y, X.X, X.Y, pf.R.X, pf.R.Y)
-	h = new(big.Int).Mod(h, q)
+// toPoint group and group element of X
+func toPoint(X *curves.ECPoint) (group.Group, group.Point, error) {
+	if X == nil || X.Curve == nil {
+		return nil, nil, fmt.Errorf("point is nil")
+	}
+	g, err := group.FromCurve(X.Curve)
+	if err != nil {
+		return nil, nil, err
+	}
+	Xp, err := pointOf(g, X)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, Xp, nil
+}
 
-	SG := curves.ScalarToPoint(X.Curve, pf.S)
-	Xh := X.ScalarMult(h)
-	RXh, err := pf.R.Add(Xh)
+// pointOf group element of P, the identity is rejected, it proves nothing
+func pointOf(g group.Group, P *curves.ECPoint) (group.Point, error) {
+	if P == nil || P.Curve == nil || curves.GetCurveName(P.Curve) != g.Name() {
+		return nil, fmt.Errorf("point is not on %s", g.Name())
+	}
+	point, err := g.PointFromAffine(P.X, P.Y)
 	if err != nil {
-		return false
+		return nil, err
+	}
+	if point.IsIdentity() {
+		return nil, fmt.Errorf("point is the identity")
+	}
+	return point, nil
+}
+
+// scalarOf s must be reduced, a response s + n is rejected
+func scalarOf(g group.Group, s *big.Int) (group.Scalar, error) {
+	if s.Sign() < 0 || s.Cmp(g.Order()) >= 0 {
+		return nil, fmt.Errorf("scalar out of range")
 	}
-	return RXh.X.Cmp(SG.X) == 0 && RXh.Y.Cmp(SG.Y) == 0
+	return g.ScalarFromBigInt(s), nil
 }
@@ -38,4 +38,46 @@ func TestProofFaulty(t *testing.T) {
 	if res {
 		t.Fatal("result should be false")
 	}
-}
\ No newline at end of file
+}
+func TestDleqProof(t *testing.T) {
+	curve := secp256k1.S256()
+	sessionId := big.NewInt(7)
+	x := crypto.RandomNum(curve.N)
+	X := curves.ScalarToPoint(curve, x)
+	H := curves.ScalarToPoint(curve, crypto.RandomNum(curve.N))
+	D := H.ScalarMult(x)
+	proof, err := ProveDleqWithId(sessionId, x, X, H, D)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyDleqWithId(sessionId, proof, X, H, D) {
+		t.Fatal("result should be true")
+	}
+	if VerifyDleqWithId(big.NewInt(8), proof, X, H, D) {
+		t.Fatal("other session should be false")
+	}
+	if VerifyDleqWithId(sessionId, proof, X, H, H.ScalarMult(big.NewInt(2))) {
+		t.Fatal("other D should be false")
+	}
+}
+
+func TestProofIdentity(t *testing.T) {
+	curve := secp256k1.S256()
+	// 0*G is the identity, nothing to prove
+	_, err := ProveWithId(big.NewInt(7), big.NewInt(0), &curves.ECPoint{Curve: curve, X: big.NewInt(0), Y: big.NewInt(0)})
+	if err == nil {
+		t.Fatal("identity should be rejected")
+	}
+
+	x := crypto.RandomNum(curve.N)
+	X := curves.ScalarToPoint(curve, x)
+	proof, err := ProveWithId(big.NewInt(7), x, X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// s + n is the same scalar but not canonical
+	proof.S = new(big.Int).Add(proof.S, curve.N)
+	if VerifyWithId(big.NewInt(7), proof, X) {
+		t.Fatal("non canonical s should be false")
+	}
+}
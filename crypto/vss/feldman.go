@@ -6,6 +6,7 @@ import (
 	"math/big"
 
 	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
 )
 
 //  verifiable secret sharing scheme
@@ -13,6 +14,7 @@ type Feldman struct {
 	threshold int // power of polynomial add one
 	limit     int //
 	curve     elliptic.Curve
+	group     group.Group
 }
 
 // NewFeldman
@@ -23,43 +25,111 @@ func NewFeldman(threshold, limit int, curve elliptic.Curve) (*Feldman, error) {
 	if limit < threshold {
 		return nil, fmt.Errorf("NewFeldman error, limit less than threshold")
 	}
-	return &Feldman{threshold, limit, curve}, nil
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+	return &Feldman{threshold, limit, curve, g}, nil
 }
 
 // Evaluate return verifiers and shares
 func (fm *Feldman) Evaluate(secret *big.Int) ([]*curves.ECPoint, []*Share, error) {
-	poly, err := InitPolynomial(fm.curve, secret, fm.threshold-1)
+	ids := make([]int, fm.limit)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return fm.EvaluateAt(secret, ids)
+}
+
+// EvaluateAt return verifiers and shares for the given participant ids, len(ids) <= limit
+// a zero secret has the identity as first verifier which has no affine form, use Deal instead
+func (fm *Feldman) EvaluateAt(secret *big.Int, ids []int) ([]*curves.ECPoint, []*Share, error) {
+	if secret == nil {
+		return nil, nil, fmt.Errorf("EvaluateAt error, secret is nil")
+	}
+	s, err := fm.group.ScalarFromSecret(secret)
 	if err != nil {
 		return nil, nil, err
 	}
-	shares := make([]*Share, fm.limit)
-	for i := 1; i <= fm.limit; i++ {
-		shares[i-1] = poly.EvaluatePolynomial(big.NewInt(int64(i)))
+	points, shares, err := fm.Deal(s, ids)
+	if err != nil {
+		return nil, nil, err
 	}
-	verifiers := make([]*curves.ECPoint, len(poly.Coefficients))
-	for i, c := range poly.Coefficients {
-		verifiers[i] = curves.ScalarToPoint(fm.curve, c)
+	verifiers := make([]*curves.ECPoint, len(points))
+	for i, point := range points {
+		verifiers[i], err = point.ECPoint()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return verifiers, shares, nil
+}
+
+// Deal verifiers [a0*G, a1*G, ...] and shares for the given participant ids, a zero secret gives the identity a0*G
+func (fm *Feldman) Deal(secret group.Scalar, ids []int) ([]group.Point, []*Share, error) {
+	if len(ids) > fm.limit {
+		return nil, nil, fmt.Errorf("EvaluateAt error, ids more than limit")
+	}
+	if secret == nil || secret.Group() != fm.group {
+		return nil, nil, fmt.Errorf("Deal error, secret is not a scalar of %s", fm.group.Name())
+	}
+	poly, err := newPolynomial(fm.group, secret, fm.threshold-1)
+	if err != nil {
+		return nil, nil, err
+	}
+	shares := make([]*Share, len(ids))
+	for i, id := range ids {
+		if id <= 0 {
+			return nil, nil, fmt.Errorf("EvaluateAt error, invalid id %d", id)
+		}
+		shares[i] = poly.EvaluatePolynomial(big.NewInt(int64(id)))
+	}
+	G := fm.group.Generator()
+	verifiers := make([]group.Point, len(poly.coefficients))
+	for i, c := range poly.coefficients {
+		verifiers[i] = G.ScalarMult(c)
 	}
 	return verifiers, shares, nil
 }
 
 // Verify check feldman verifiable secret sharing
 func (fm *Feldman) Verify(share *Share, verifiers []*curves.ECPoint) (bool, error) {
+	points := make([]group.Point, len(verifiers))
+	for i, verifier := range verifiers {
+		if verifier == nil || curves.GetCurveName(verifier.Curve) != fm.group.Name() {
+			return false, fmt.Errorf("feldman verifier is not on %s", fm.group.Name())
+		}
+		point, err := fm.group.PointFromAffine(verifier.X, verifier.Y)
+		if err != nil {
+			return false, err
+		}
+		points[i] = point
+	}
+	return fm.VerifyPoints(share, points)
+}
+
+// VerifyPoints share.Y*G = v0 + id*v1 + ... + id^(t-1)*v(t-1), share.Y must be reduced
+func (fm *Feldman) VerifyPoints(share *Share, verifiers []group.Point) (bool, error) {
 	if len(verifiers) < fm.threshold {
 		return false, fmt.Errorf("feldman verify number error")
 	}
-	lhs := curves.ScalarToPoint(fm.curve, share.Y)
+	if share == nil || share.Id == nil || share.Y == nil {
+		return false, fmt.Errorf("feldman share is nil")
+	}
+	y, err := fm.group.ScalarFromSecret(share.Y)
+	if err != nil {
+		return false, err
+	}
+	lhs := fm.group.Generator().ScalarMult(y)
 
-	var err error
-	x := big.NewInt(1)
-	rhs := verifiers[0]
-	for j := 1; j < len(verifiers); j++ {
-		x = new(big.Int).Mul(x, share.Id)
-		c := verifiers[j].ScalarMult(x)
-		rhs, err = rhs.Add(c)
-		if err != nil {
-			return false, err
+	// horner, ((v(t-1)*id + v(t-2))*id + ...)*id + v0
+	id := fm.group.ScalarFromBigInt(share.Id)
+	rhs := fm.group.Identity()
+	for j := len(verifiers) - 1; j >= 0; j-- {
+		if verifiers[j] == nil || verifiers[j].Group() != fm.group {
+			return false, fmt.Errorf("feldman verifier is not on %s", fm.group.Name())
 		}
+		rhs = rhs.ScalarMult(id).Add(verifiers[j])
 	}
-	return lhs.Equals(rhs), nil
+	return lhs.Equal(rhs), nil
 }
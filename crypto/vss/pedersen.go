@@ -0,0 +1,114 @@
+package vss
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/group"
+)
+
+// Pedersen verifiable secret sharing, the commitments a_k*G + b_k*H hide the secret, unlike feldman verifiers
+type Pedersen struct {
+	threshold int // power of polynomial add one
+	limit     int
+	group     group.Group
+	H         group.Point // second generator, nobody knows log_G(H)
+}
+
+// PedersenDeal one dealing, Verifiers a_k*G are kept by the dealer until the commitments are accepted
+type PedersenDeal struct {
+	Commitments []group.Point // a_k*G + b_k*H
+	Verifiers   []group.Point // a_k*G
+	Shares      []*Share      // f(id)
+	Blinds      []*Share      // f'(id)
+}
+
+// NewPedersen H must be independent of G, e.g. group.HashToPoint of a public domain
+func NewPedersen(threshold, limit int, curve elliptic.Curve, H group.Point) (*Pedersen, error) {
+	if threshold < 2 {
+		return nil, fmt.Errorf("threshold least than 2")
+	}
+	if limit < threshold {
+		return nil, fmt.Errorf("NewPedersen error, limit less than threshold")
+	}
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+	if H == nil || H.Group() != g || H.IsIdentity() || H.Equal(g.Generator()) {
+		return nil, fmt.Errorf("NewPedersen error, invalid H")
+	}
+	return &Pedersen{threshold: threshold, limit: limit, group: g, H: H}, nil
+}
+
+// Deal share secret with f(z) = secret + a_1*z + ..., blinded by a random f'(z)
+func (pd *Pedersen) Deal(secret group.Scalar, ids []int) (*PedersenDeal, error) {
+	if len(ids) > pd.limit {
+		return nil, fmt.Errorf("Deal error, ids more than limit")
+	}
+	if secret == nil || secret.Group() != pd.group {
+		return nil, fmt.Errorf("Deal error, secret is not a scalar of %s", pd.group.Name())
+	}
+	blind, err := pd.group.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	f, err := newPolynomial(pd.group, secret, pd.threshold-1)
+	if err != nil {
+		return nil, err
+	}
+	fb, err := newPolynomial(pd.group, blind, pd.threshold-1)
+	if err != nil {
+		return nil, err
+	}
+	deal := &PedersenDeal{
+		Commitments: make([]group.Point, pd.threshold),
+		Verifiers:   make([]group.Point, pd.threshold),
+		Shares:      make([]*Share, len(ids)),
+		Blinds:      make([]*Share, len(ids)),
+	}
+	G := pd.group.Generator()
+	for k := 0; k < pd.threshold; k++ {
+		deal.Verifiers[k] = G.ScalarMult(f.coefficients[k])
+		deal.Commitments[k] = deal.Verifiers[k].Add(pd.H.ScalarMult(fb.coefficients[k]))
+	}
+	for i, id := range ids {
+		if id <= 0 {
+			return nil, fmt.Errorf("Deal error, invalid id %d", id)
+		}
+		deal.Shares[i] = f.EvaluatePolynomial(big.NewInt(int64(id)))
+		deal.Blinds[i] = fb.EvaluatePolynomial(big.NewInt(int64(id)))
+	}
+	return deal, nil
+}
+
+// Verify share.Y*G + blind.Y*H = C0 + id*C1 + ... + id^(t-1)*C(t-1), share.Y and blind.Y must be reduced
+func (pd *Pedersen) Verify(share, blind *Share, commitments []group.Point) (bool, error) {
+	if len(commitments) != pd.threshold {
+		return false, fmt.Errorf("pedersen verify number error")
+	}
+	if share == nil || blind == nil || share.Id == nil || share.Y == nil || blind.Id == nil || blind.Y == nil || share.Id.Cmp(blind.Id) != 0 {
+		return false, fmt.Errorf("pedersen share is nil")
+	}
+	g := pd.group
+	y, err := g.ScalarFromSecret(share.Y)
+	if err != nil {
+		return false, err
+	}
+	b, err := g.ScalarFromSecret(blind.Y)
+	if err != nil {
+		return false, err
+	}
+	lhs := g.Generator().ScalarMult(y).Add(pd.H.ScalarMult(b))
+
+	id := g.ScalarFromBigInt(share.Id)
+	rhs := g.Identity()
+	for j := len(commitments) - 1; j >= 0; j-- {
+		if commitments[j] == nil || commitments[j].Group() != g {
+			return false, fmt.Errorf("pedersen commitment is not on %s", g.Name())
+		}
+		rhs = rhs.ScalarMult(id).Add(commitments[j])
+	}
+	return lhs.Equal(rhs), nil
+}
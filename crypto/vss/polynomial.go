@@ -2,14 +2,18 @@ package vss
 
 import (
 	"crypto/elliptic"
-	"crypto/rand"
 	"fmt"
 	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/group"
 )
 
 type Polynomial struct {
 	Coefficients []*big.Int // polynomial coefficient, eg: [a0, a1, a2 ...]
 	QMod         *big.Int
+
+	group        group.Group
+	coefficients []group.Scalar
 }
 
 // secret share
@@ -20,22 +24,46 @@ type Share struct {
 
 // InitPolynomial init Coefficients [a0, a1....at] t=degree
 func InitPolynomial(curve elliptic.Curve, secret *big.Int, degree int) (*Polynomial, error) {
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("secret is nil")
+	}
+	s, err := g.ScalarFromSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	return newPolynomial(g, s, degree)
+}
+
+// newPolynomial random coefficients, the leading one is nonzero so the degree is exact
+func newPolynomial(g group.Group, secret group.Scalar, degree int) (*Polynomial, error) {
 	if degree < 1 {
 		return nil, fmt.Errorf("degree must be at least 1")
 	}
-	q := curve.Params().N
-	Coefficients := make([]*big.Int, degree+1)
-	Coefficients[0] = secret
+	coefficients := make([]group.Scalar, degree+1)
+	coefficients[0] = secret
 	for i := 1; i <= degree; i++ {
-		r, err := rand.Prime(rand.Reader, q.BitLen())
+		r, err := g.RandomScalar()
 		if err != nil {
 			return nil, err
 		}
-		Coefficients[i] = r // random generation coefficient
+		if i == degree && r.IsZero() {
+			return nil, fmt.Errorf("leading coefficient is zero")
+		}
+		coefficients[i] = r // random generation coefficient
+	}
+	Coefficients := make([]*big.Int, len(coefficients))
+	for i, c := range coefficients {
+		Coefficients[i] = c.BigInt()
 	}
 	return &Polynomial{
 		Coefficients: Coefficients,
-		QMod:         q,
+		QMod:         g.Order(),
+		group:        g,
+		coefficients: coefficients,
 	}, nil
 }
 
@@ -43,51 +71,85 @@ func InitPolynomial(curve elliptic.Curve, secret *big.Int, degree int) (*Polynom
 // EvaluatePolynomial(x):
 // 		returns a + bx + cx^2 + dx^3
 func (p *Polynomial) EvaluatePolynomial(x *big.Int) *Share {
-	result := new(big.Int).Set(p.Coefficients[0])
-	tmp := big.NewInt(1)
-	for i := 1; i <= len(p.Coefficients)-1; i++ {
-		tmp = new(big.Int).Mul(tmp, x)
-		aiXi := new(big.Int).Mul(p.Coefficients[i], tmp)
-		result = result.Add(result, aiXi)
+	// horner, ((d*x + c)*x + b)*x + a
+	xs := p.group.ScalarFromBigInt(x)
+	result := p.group.NewScalar()
+	for i := len(p.coefficients) - 1; i >= 0; i-- {
+		result = result.Mul(xs).Add(p.coefficients[i])
 	}
-	result = new(big.Int).Mod(result, p.QMod)
 	return &Share{
 		Id: x,
-		Y:  result,
+		Y:  result.BigInt(),
 	}
 }
 
 // RecoverSecret recover secret key
 func RecoverSecret(curve elliptic.Curve, pointList []*Share) *big.Int {
-	q := curve.Params().N
 	xList := make([]*big.Int, len(pointList))
 	for i, point := range pointList {
 		xList[i] = point.Id
 	}
-	secret := big.NewInt(0)
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		q := curve.Params().N
+		secret := big.NewInt(0)
+		for _, point := range pointList {
+			secret.Add(secret, lagrangianMod(q, point.Id, point.Y, big.NewInt(0), xList))
+		}
+		return secret.Mod(secret, q)
+	}
+	secret := g.NewScalar()
 	for _, point := range pointList {
-		wi := CalLagrangian(curve, point.Id, point.Y, xList)
-		secret = secret.Add(secret, wi)
+		secret = secret.Add(lagrangian(g, point.Id, g.ScalarFromBigInt(point.Y), g.NewScalar(), xList))
 	}
-	secret = new(big.Int).Mod(secret, q)
-	return secret
+	return secret.BigInt()
 }
 
 // CalLagrangian lagrangian interpolation wi, x = sum(wi)
 func CalLagrangian(curve elliptic.Curve, x, y *big.Int, xList []*big.Int) *big.Int {
-	q := curve.Params().N
-	wi := new(big.Int).SetBytes(y.Bytes())
-	// wi = y*mul(xj/(xj-xi))
-	for i := 0; i < len(xList); i++ {
-		xj := xList[i]
-		if x.Cmp(xj) == 0 {
+	return CalLagrangianAt(curve, x, y, big.NewInt(0), xList)
+}
+
+// CalLagrangianAt lagrangian interpolation wi at point at, f(at) = sum(wi)
+func CalLagrangianAt(curve elliptic.Curve, x, y, at *big.Int, xList []*big.Int) *big.Int {
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		return lagrangianMod(curve.Params().N, x, y, at, xList)
+	}
+	return lagrangian(g, x, g.ScalarFromBigInt(y), g.ScalarFromBigInt(at), xList).BigInt()
+}
+
+// lagrangian wi = y*mul((at-xj)/(xi-xj)), ids equal to x modulo the order are skipped
+func lagrangian(g group.Group, x *big.Int, y, at group.Scalar, xList []*big.Int) group.Scalar {
+	xi := g.ScalarFromBigInt(x)
+	wi := y
+	for _, id := range xList {
+		xj := g.ScalarFromBigInt(id)
+		if xj.Equal(xi) {
+			continue
+		}
+		// xi - xj is nonzero, the inverse exists
+		coef, _ := xi.Sub(xj).Invert()
+		wi = wi.Mul(at.Sub(xj).Mul(coef))
+	}
+	return wi
+}
+
+// lagrangianMod lagrangian with big.Int arithmetic mod q, for curves outside the group registry
+func lagrangianMod(q, x, y, at *big.Int, xList []*big.Int) *big.Int {
+	xi := new(big.Int).Mod(x, q)
+	wi := new(big.Int).Mod(y, q)
+	for _, id := range xList {
+		xj := new(big.Int).Mod(id, q)
+		if xj.Cmp(xi) == 0 {
 			continue
 		}
-		coef := new(big.Int).Sub(xj, x)
-		coef.ModInverse(coef, q)
-		coef.Mul(xj, coef)
+		// q is prime and xi - xj is nonzero, the inverse exists
+		coef := new(big.Int).Sub(xi, xj)
+		coef.ModInverse(coef.Mod(coef, q), q)
+		coef.Mul(coef, new(big.Int).Sub(at, xj))
 		wi.Mul(wi, coef)
+		wi.Mod(wi, q)
 	}
-	wi = new(big.Int).Mod(wi, q)
 	return wi
 }
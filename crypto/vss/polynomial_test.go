@@ -1,11 +1,14 @@
 package vss
 
 import (
+	"crypto/elliptic"
 	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPoly(t *testing.T) {
@@ -54,3 +57,100 @@ func TestFeldman(t *testing.T) {
 	w23 := CalLagrangian(curve, big.NewInt(int64(3)), shares[2].Y, []*big.Int{big.NewInt(int64(1)), big.NewInt(int64(3))})
 	fmt.Println(new(big.Int).Mod(new(big.Int).Add(w21, w23), curve.N))
 }
+
+func TestFeldmanZeroSecret(t *testing.T) {
+	for _, g := range []group.Group{group.Secp256k1(), group.Ed25519()} {
+		feldman, err := NewFeldman(2, 3, g.Curve())
+		require.NoError(t, err)
+		// zero secret deals the real identity as a0*G
+		verifiers, shares, err := feldman.Deal(g.NewScalar(), []int{1, 2, 3})
+		require.NoError(t, err)
+		require.True(t, verifiers[0].IsIdentity())
+		for _, share := range shares {
+			ok, err := feldman.VerifyPoints(share, verifiers)
+			require.NoError(t, err)
+			require.True(t, ok)
+		}
+		require.Equal(t, 0, RecoverSecret(g.Curve(), shares[:2]).Sign())
+
+		// legacy weierstrass points have no identity, ed25519 has (0, 1)
+		_, _, err = feldman.EvaluateAt(big.NewInt(0), []int{1, 2, 3})
+		require.Equal(t, g == group.Secp256k1(), err != nil)
+
+		wrong := &Share{Id: shares[0].Id, Y: new(big.Int).Add(shares[0].Y, big.NewInt(1))}
+		ok, err := feldman.VerifyPoints(wrong, verifiers)
+		require.NoError(t, err)
+		require.False(t, ok)
+	}
+}
+
+func TestPedersen(t *testing.T) {
+	for _, g := range []group.Group{group.Secp256k1(), group.Ed25519()} {
+		H := group.HashToPoint(g, []byte("TestPedersen"))
+		pedersen, err := NewPedersen(3, 5, g.Curve(), H)
+		require.NoError(t, err)
+		secret, _ := g.RandomScalar()
+		deal, err := pedersen.Deal(secret, []int{1, 2, 3, 4, 5})
+		require.NoError(t, err)
+		require.True(t, deal.Verifiers[0].Equal(g.Generator().ScalarMult(secret)))
+		for i := range deal.Shares {
+			ok, err := pedersen.Verify(deal.Shares[i], deal.Blinds[i], deal.Commitments)
+			require.NoError(t, err)
+			require.True(t, ok)
+		}
+		require.Equal(t, 0, RecoverSecret(g.Curve(), deal.Shares[2:]).Cmp(secret.BigInt()))
+
+		// shares are also feldman shares of the verifiers
+		feldman, _ := NewFeldman(3, 5, g.Curve())
+		ok, err := feldman.VerifyPoints(deal.Shares[0], deal.Verifiers)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		wrong := &Share{Id: deal.Blinds[0].Id, Y: new(big.Int).Add(deal.Blinds[0].Y, big.NewInt(1))}
+		ok, err = pedersen.Verify(deal.Shares[0], wrong, deal.Commitments)
+		require.NoError(t, err)
+		require.False(t, ok)
+		_, err = pedersen.Verify(deal.Shares[0], deal.Blinds[1], deal.Commitments)
+		require.Error(t, err)
+
+		_, err = NewPedersen(3, 5, g.Curve(), g.Generator())
+		require.Error(t, err)
+	}
+}
+
+func TestCalLagrangianAt(t *testing.T) {
+	curve := secp256k1.S256()
+	polynomial, _ := InitPolynomial(curve, big.NewInt(123456), 2)
+	xList := []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(5)}
+	at := big.NewInt(4)
+	sum := new(big.Int)
+	for _, x := range xList {
+		sum.Add(sum, CalLagrangianAt(curve, x, polynomial.EvaluatePolynomial(x).Y, at, xList))
+	}
+	require.Equal(t, 0, sum.Mod(sum, curve.N).Cmp(polynomial.EvaluatePolynomial(at).Y))
+}
+
+func TestLagrangianUnregisteredCurve(t *testing.T) {
+	// order 2^607 - 1 is beyond the group limbs, interpolation falls back to big.Int arithmetic mod N
+	n := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 607), big.NewInt(1))
+	curve := &elliptic.CurveParams{Name: "order-607", P: n, N: n, BitSize: 607}
+	_, err := group.FromCurve(curve)
+	require.Error(t, err)
+	// f(x) = 123456 + 7x - x^2
+	f := func(x int64) *big.Int {
+		y := big.NewInt(123456 + 7*x - x*x)
+		return y.Mod(y, n)
+	}
+	xList := []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(5)}
+	shares := make([]*Share, len(xList))
+	for i, x := range xList {
+		shares[i] = &Share{Id: x, Y: f(x.Int64())}
+	}
+	require.Equal(t, 0, RecoverSecret(curve, shares).Cmp(big.NewInt(123456)))
+
+	at := new(big.Int)
+	for _, share := range shares {
+		at.Add(at, CalLagrangianAt(curve, share.Id, share.Y, big.NewInt(4), xList))
+	}
+	require.Equal(t, 0, at.Mod(at, n).Cmp(f(4)))
+}
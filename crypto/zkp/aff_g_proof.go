@@ -34,6 +34,8 @@ var (
 // y is committed in elliptic curve group instead of Paillier group
 func PaillierAffineProve(pedersen *pedersen.PedersenParameters, st *AffGStatement, wit *AffGWitness) *AffGProof {
 	N2 := new(big.Int).Mul(st.N, st.N)
+	// group of the statement, secp256k1 or p256
+	curve := st.X.Curve
 
 	// sample viaribles
 	rangeL0Epsilon := new(big.Int).Lsh(one, uint(L0_Aff_G+Epsilon_Aff_G))
@@ -77,7 +79,7 @@ func PaillierAffineProve(pedersen *pedersen.PedersenParameters, st *AffGStatemen
 
 	// compute challenge e
 	e := crypto.SHA256Int(st.N, st.C, st.D, st.X.X, st.Y.X, A, Bx.X, By.X, E, S, F, T)
-	e = new(big.Int).Mod(e, curve.N)
+	e = new(big.Int).Mod(e, curve.Params().N)
 
 	// compute Z1, Z2, Z3, Z4, W
 	// Z1 = alpha + e * x
@@ -99,9 +101,17 @@ func PaillierAffineProve(pedersen *pedersen.PedersenParameters, st *AffGStatemen
 }
 
 func PaillierAffineVerify(pedersen *pedersen.PedersenParameters, proof *AffGProof, st *AffGStatement) bool {
+	if st.X == nil || st.Y == nil || st.X.Curve == nil || proof.Bx == nil || proof.By == nil {
+		return false
+	}
 	N2 := new(big.Int).Mul(st.N, st.N)
+	curve := st.X.Curve
+	name := curves.GetCurveName(curve)
+	if curves.GetCurveName(st.Y.Curve) != name || curves.GetCurveName(proof.Bx.Curve) != name || curves.GetCurveName(proof.By.Curve) != name {
+		return false
+	}
 	e := crypto.SHA256Int(st.N, st.C, st.D, st.X.X, st.Y.X, proof.A, proof.Bx.X, proof.By.X, proof.E, proof.S, proof.F, proof.T)
-	e = new(big.Int).Mod(e, curve.N)
+	e = new(big.Int).Mod(e, curve.Params().N)
 
 	// check A
 	// C^Z1 * ((1+N)^Z2 * w^N) = A * D^e mod N2
@@ -1,10 +1,12 @@
 package zkp
 
 import (
+	"crypto/elliptic"
 	"fmt"
 	"math/big"
 	"testing"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
 	"github.com/okx/threshold-lib/crypto"
 	"github.com/okx/threshold-lib/crypto/curves"
 	"github.com/okx/threshold-lib/crypto/pedersen"
@@ -31,51 +33,53 @@ func TestAffGProof(t *testing.T) {
 	N := new(big.Int).Mul(p, q)
 	N2 := new(big.Int).Mul(N, N)
 
-	rangeL0 := new(big.Int).Lsh(one, uint(L0_Aff_G))
-	rangeL1 := new(big.Int).Lsh(one, uint(L1_Aff_G))
+	for _, curve := range []elliptic.Curve{secp256k1.S256(), elliptic.P256()} {
+		fmt.Println("curve:", curve.Params().Name)
+		rangeL0 := new(big.Int).Lsh(one, uint(L0_Aff_G))
+		rangeL1 := new(big.Int).Lsh(one, uint(L1_Aff_G))
 
-	x := crypto.RandomNum(rangeL0)
-	y := crypto.RandomNum(rangeL1)
-	rho := crypto.RandomNum(N)
+		x := crypto.RandomNum(rangeL0)
+		y := crypto.RandomNum(rangeL1)
+		rho := crypto.RandomNum(N)
 
-	witness := &AffGWitness{
-		X:   x,
-		Y:   y,
-		Rho: rho,
-	}
+		witness := &AffGWitness{
+			X:   x,
+			Y:   y,
+			Rho: rho,
+		}
 
-	// C is an ciphertext of an Paillier encryption of a secret
-	C := crypto.RandomNum(N2)
+		// C is an ciphertext of an Paillier encryption of a secret
+		C := crypto.RandomNum(N2)
 
-	// D = C^x * (1+N)^y * rho^N mod N^2
-	D := new(big.Int).Exp(C, x, N2)
-	D = new(big.Int).Mod(new(big.Int).Mul(D, new(big.Int).Exp(new(big.Int).Add(one, N), y, N2)), N2)
-	D = new(big.Int).Mod(new(big.Int).Mul(D, new(big.Int).Exp(rho, N, N2)), N2)
+		// D = C^x * (1+N)^y * rho^N mod N^2
+		D := new(big.Int).Exp(C, x, N2)
+		D = new(big.Int).Mod(new(big.Int).Mul(D, new(big.Int).Exp(new(big.Int).Add(one, N), y, N2)), N2)
+		D = new(big.Int).Mod(new(big.Int).Mul(D, new(big.Int).Exp(rho, N, N2)), N2)
 
-	X := curves.ScalarToPoint(curve, x)
-	Y := curves.ScalarToPoint(curve, y)
+		X := curves.ScalarToPoint(curve, x)
+		Y := curves.ScalarToPoint(curve, y)
 
-	st := &AffGStatement{
-		N: N,
-		C: C,
-		D: D,
-		X: X,
-		Y: Y,
-	}
+		st := &AffGStatement{
+			N: N,
+			C: C,
+			D: D,
+			X: X,
+			Y: Y,
+		}
 
-	proof := PaillierAffineProve(pesersen, st, witness)
-	verify := PaillierAffineVerify(pesersen, proof, st)
+		proof := PaillierAffineProve(pesersen, st, witness)
+		verify := PaillierAffineVerify(pesersen, proof, st)
 
-	fmt.Println("PaillierAffineProof of honest prover:", verify)
+		fmt.Println("PaillierAffineProof of honest prover:", verify)
 
-	x = crypto.RandomNum(N)
-	witness = &AffGWitness{
-		X:   x,
-		Y:   y,
-		Rho: rho,
+		x = crypto.RandomNum(N)
+		witness = &AffGWitness{
+			X:   x,
+			Y:   y,
+			Rho: rho,
+		}
+		proof = PaillierAffineProve(pesersen, st, witness)
+		verify = PaillierAffineVerify(pesersen, proof, st)
+		fmt.Println("PaillierAffineProof of malicious prover:", verify)
 	}
-	proof = PaillierAffineProve(pesersen, st, witness)
-	verify = PaillierAffineVerify(pesersen, proof, st)
-	fmt.Println("PaillierAffineProof of malicious prover:", verify)
-
 }
@@ -2,8 +2,6 @@ package zkp
 
 import (
 	"math/big"
-
-	"github.com/decred/dcrd/dcrec/secp256k1/v2"
 )
 
 var (
@@ -18,5 +16,3 @@ type SecurityParameter struct {
 	Q_bitlen uint
 	Epsilon  uint
 }
-
-var curve = secp256k1.S256()
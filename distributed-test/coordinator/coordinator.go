@@ -1,6 +1,8 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -306,8 +308,13 @@ func (s *CoordinatorServer) handleCreateSession(client *Client, msg *Message) {
 		return
 	}
 	
-	// 创建新会话
-	sessionID := fmt.Sprintf("session_%d", time.Now().Unix())
+	// 创建新会话，客户端用会话ID绑定所有协议消息，必须随机且不能重复
+	sessionID, err := newSessionID()
+	if err != nil {
+		s.mu.Unlock()
+		log.Printf("❌ 生成会话ID失败: %v", err)
+		return
+	}
 
 	session := &Session{
 		ID:           sessionID,
@@ -788,4 +795,13 @@ func (s *CoordinatorServer) handleRefreshComplete(client *Client, msg *Message)
 			s.sendToClient(participantID, response)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// newSessionID 随机会话ID
+func newSessionID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "session_" + hex.EncodeToString(bytes), nil
+}
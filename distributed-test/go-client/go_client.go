@@ -66,9 +66,6 @@ func NewDistributedGoClient(clientID, serverURL string, partyID, threshold, tota
 	// 使用secp256k1曲线
 	curve := edwards.Edwards()
 
-	// 创建DKG设置
-	dkgSetup := dkg.NewSetUp(partyID, totalParties, curve)
-
 	return &DistributedGoClient{
 		ClientID:       clientID,
 		PartyID:        partyID,
@@ -76,7 +73,6 @@ func NewDistributedGoClient(clientID, serverURL string, partyID, threshold, tota
 		TotalParties:   totalParties,
 		ServerURL:      serverURL,
 		keygenDone:     make(chan bool, 1),
-		dkgSetup:       dkgSetup,
 		curve:          curve,
 		round1Messages: make(map[int]*tss.Message),
 		round2Messages: make(map[int]*tss.Message),
@@ -256,8 +252,16 @@ func (c *DistributedGoClient) handleSessionCreated(msg *Message) {
 	c.sessionJoined = true // 设置标志，防止重复处理
 	log.Printf("✅ Go客户端 %s 会话已创建: %s，等待协调服务器开始信号", c.ClientID, c.sessionID)
 
-	// 不重新初始化DKG设置，保持原有状态
-	// 只清空之前的消息
+	// 协调服务器分配的会话ID各方一致，绑定到DKG的所有承诺和证明
+	sessionId := tss.SessionId(msg.SessionID)
+	if sessionId == nil {
+		log.Printf("❌ Go客户端 %s 会话ID为空", c.ClientID)
+		c.keygenDone <- false
+		return
+	}
+	c.dkgSetup = dkg.NewSetUp(sessionId, c.PartyID, c.Threshold, c.TotalParties, c.curve)
+
+	// 清空之前的消息
 	c.round1Messages = make(map[int]*tss.Message)
 	c.round2Messages = make(map[int]*tss.Message)
 
@@ -267,6 +271,11 @@ func (c *DistributedGoClient) handleSessionCreated(msg *Message) {
 
 // handleStartKeygen 处理开始密钥生成信号
 func (c *DistributedGoClient) handleStartKeygen(msg *Message) {
+	if c.dkgSetup == nil {
+		log.Printf("❌ Go客户端 %s 尚未加入会话", c.ClientID)
+		c.keygenDone <- false
+		return
+	}
 	log.Printf("🚀 Go客户端 %s 收到开始密钥生成信号，开始第一轮", c.ClientID)
 	c.performKeygenRound1()
 }
@@ -628,8 +637,13 @@ func (c *DistributedGoClient) handleStartRefresh(msg *Message) {
 	}
 
 	// 创建刷新设置
-	devoteList := [2]int{c.PartyID, c.TotalParties}
-	refreshInfo := reshare.NewRefresh(c.PartyID, c.TotalParties, devoteList, c.finalKeyData.ShareI, c.finalKeyData.PublicKey)
+	devoteList := []int{c.PartyID, c.TotalParties}
+	sessionId := tss.SessionId(msg.SessionID)
+	if sessionId == nil {
+		log.Printf("❌ Go客户端 %s 刷新会话ID为空", c.ClientID)
+		return
+	}
+	refreshInfo := reshare.NewRefresh(sessionId, c.PartyID, c.Threshold, c.TotalParties, devoteList, c.finalKeyData.ShareI, c.finalKeyData.PublicKey)
 	c.refreshSetup = refreshInfo
 
 	// 执行第一轮
@@ -196,8 +196,13 @@ func (c *RefreshClient) handleStartRefresh(msg *Message) {
 	}
 
 	// 创建刷新设置
-	devoteList := [2]int{c.Threshold, c.TotalParties}
-	refreshInfo := reshare.NewRefresh(c.PartyID, c.TotalParties, devoteList, c.originalKeyData.ShareI, c.originalKeyData.PublicKey)
+	devoteList := []int{c.Threshold, c.TotalParties}
+	sessionId := tss.SessionId(msg.SessionID)
+	if sessionId == nil {
+		log.Printf("❌ 刷新客户端 %s 会话ID为空", c.ClientID)
+		return
+	}
+	refreshInfo := reshare.NewRefresh(sessionId, c.PartyID, c.Threshold, c.TotalParties, devoteList, c.originalKeyData.ShareI, c.originalKeyData.PublicKey)
 	c.refreshSetup = refreshInfo
 
 	// 执行第一轮
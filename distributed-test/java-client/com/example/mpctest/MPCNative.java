@@ -24,9 +24,10 @@ public class MPCNative {
      * @param partyID 当前方ID
      * @param threshold 阈值
      * @param totalParties 总参与方数量
+     * @param sessionId 会话ID，各方一致且每次协议唯一（如协调方生成的UUID），不能为空
      * @return 会话句柄指针
      */
-    public static native long keygenInit(int curve, int partyID, int threshold, int totalParties);
+    public static native long keygenInit(int curve, int partyID, int threshold, int totalParties, String sessionId);
     
     /**
      * 密钥生成第一轮
@@ -66,9 +67,10 @@ public class MPCNative {
      * @param threshold 阈值
      * @param devoteList 参与方列表
      * @param keyData 现有密钥数据
+     * @param sessionId 会话ID，各方一致且每次协议唯一，不能为空
      * @return 会话句柄指针
      */
-    public static native long refreshInit(int curve, int partyID, int threshold, int[] devoteList, byte[] keyData);
+    public static native long refreshInit(int curve, int partyID, int threshold, int[] devoteList, byte[] keyData, String sessionId);
     
     /**
      * 密钥刷新第一轮
@@ -108,9 +110,10 @@ public class MPCNative {
      * @param partList 参与方列表
      * @param keyData 密钥数据
      * @param message 待签名消息
+     * @param sessionId 会话ID，各方一致且每次签名唯一，不能为空
      * @return 会话句柄指针
      */
-    public static native long ed25519SignInit(int partyID, int threshold, int[] partList, byte[] keyData, byte[] message);
+    public static native long ed25519SignInit(int partyID, int threshold, int[] partList, byte[] keyData, byte[] message, String sessionId);
     
     /**
      * Ed25519签名第一轮
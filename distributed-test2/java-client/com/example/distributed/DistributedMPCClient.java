@@ -179,7 +179,7 @@ public class DistributedMPCClient {
         
         // 初始化密钥生成但不立即开始
         try {
-            keygenHandle = MPCNative.keygenInit(0, partyId, threshold, totalParties); // 0 = SECP256K1
+            keygenHandle = MPCNative.keygenInit(0, partyId, threshold, totalParties, sessionId); // 0 = SECP256K1
             if (keygenHandle == 0) {
                 throw new RuntimeException("密钥生成初始化失败");
             }
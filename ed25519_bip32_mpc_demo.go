@@ -48,12 +48,13 @@ func main() {
 
 // performEd25519MPCDKG 执行Ed25519 MPC分布式密钥生成
 func performEd25519MPCDKG() (*tss.KeyStep3Data, *tss.KeyStep3Data, *tss.KeyStep3Data) {
+	sessionId := tss.SessionId("performEd25519MPCDKG")
 	fmt.Println("执行3方Ed25519 MPC DKG密钥生成...")
 
 	// 初始化3个参与者，使用Edwards曲线
-	setUp1 := dkg.NewSetUp(1, 3, curve)
-	setUp2 := dkg.NewSetUp(2, 3, curve)
-	setUp3 := dkg.NewSetUp(3, 3, curve)
+	setUp1 := dkg.NewSetUp(sessionId, 1, 2, 3, curve)
+	setUp2 := dkg.NewSetUp(sessionId, 2, 2, 3, curve)
+	setUp3 := dkg.NewSetUp(sessionId, 3, 2, 3, curve)
 
 	// DKG第一轮
 	msgs1_1, _ := setUp1.DKGStep1()
@@ -181,6 +182,7 @@ func demonstrateBIP32Derivation(masterTssKey1, masterTssKey2, masterTssKey3 *bip
 
 // demonstrateMPCSigningWithDerivedKeys 使用派生密钥进行MPC签名
 func demonstrateMPCSigningWithDerivedKeys(p1Data, p2Data, p3Data *tss.KeyStep3Data, masterTssKey1, masterTssKey2 *bip32.Ed25519TssKey) {
+	sessionId := tss.SessionId("demonstrateMPCSigningWithDerivedKeys")
 	fmt.Println("使用派生密钥进行MPC签名...")
 
 	// 派生用于签名的子密钥 m/0/1
@@ -218,8 +220,8 @@ func demonstrateMPCSigningWithDerivedKeys(p1Data, p2Data, p3Data *tss.KeyStep3Da
 
 	// 初始化签名参与者（使用调整后的密钥份额）
 	partList := []int{1, 2}
-	p1 := sign.NewEd25519Sign(1, 2, partList, adjustedShare1, publicKey, messageHex)
-	p2 := sign.NewEd25519Sign(2, 2, partList, adjustedShare2, publicKey, messageHex)
+	p1 := sign.NewEd25519Sign(sessionId, 1, 2, partList, adjustedShare1, publicKey, messageHex)
+	p2 := sign.NewEd25519Sign(sessionId, 2, 2, partList, adjustedShare2, publicKey, messageHex)
 
 	// 执行MPC签名协议
 	// 签名第一步
@@ -328,6 +330,7 @@ func demonstrateMultiPathKeyManagement(masterTssKey1, masterTssKey2 *bip32.Ed255
 
 // performQuickSignTest 执行快速签名测试
 func performQuickSignTest(child1, child2 *bip32.Ed25519TssKey, p1Data, p2Data *tss.KeyStep3Data, message string) bool {
+	sessionId := tss.SessionId("performQuickSignTest")
 	// 计算调整后的密钥份额
 	adjustedShare1 := new(big.Int).Add(p1Data.ShareI, child1.PrivateKeyOffset())
 	adjustedShare1 = new(big.Int).Mod(adjustedShare1, curve.Params().N)
@@ -346,8 +349,8 @@ func performQuickSignTest(child1, child2 *bip32.Ed25519TssKey, p1Data, p2Data *t
 
 	// 初始化签名参与者
 	partList := []int{1, 2}
-	p1 := sign.NewEd25519Sign(1, 2, partList, adjustedShare1, publicKey, messageHex)
-	p2 := sign.NewEd25519Sign(2, 2, partList, adjustedShare2, publicKey, messageHex)
+	p1 := sign.NewEd25519Sign(sessionId, 1, 2, partList, adjustedShare1, publicKey, messageHex)
+	p2 := sign.NewEd25519Sign(sessionId, 2, 2, partList, adjustedShare2, publicKey, messageHex)
 
 	// 执行简化的签名流程
 	p1Step1, err := p1.SignStep1()
@@ -66,12 +66,13 @@ func main() {
 
 // performEd25519DKG 执行Ed25519 DKG分布式密钥生成
 func performEd25519DKG() (*tss.KeyStep3Data, *tss.KeyStep3Data, *tss.KeyStep3Data) {
+	sessionId := tss.SessionId("performEd25519DKG")
 	fmt.Println("执行3方Ed25519 DKG密钥生成...")
 
 	// 初始化3个参与者，使用Edwards曲线
-	setUp1 := dkg.NewSetUp(1, 3, curve)
-	setUp2 := dkg.NewSetUp(2, 3, curve)
-	setUp3 := dkg.NewSetUp(3, 3, curve)
+	setUp1 := dkg.NewSetUp(sessionId, 1, 2, 3, curve)
+	setUp2 := dkg.NewSetUp(sessionId, 2, 2, 3, curve)
+	setUp3 := dkg.NewSetUp(sessionId, 3, 2, 3, curve)
 
 	// DKG第一轮
 	msgs1_1, _ := setUp1.DKGStep1()
@@ -124,6 +125,7 @@ func performEd25519DKG() (*tss.KeyStep3Data, *tss.KeyStep3Data, *tss.KeyStep3Dat
 
 // performEd25519ThresholdSign 执行Ed25519 threshold签名
 func performEd25519ThresholdSign(pData1, pData2 *tss.KeyStep3Data, message string, partList []int) (*big.Int, *big.Int) {
+	sessionId := tss.SessionId("performEd25519ThresholdSign")
 	fmt.Printf("对消息进行Ed25519 threshold签名: %s\n", message)
 	fmt.Printf("参与者: %v\n", partList)
 
@@ -138,8 +140,8 @@ func performEd25519ThresholdSign(pData1, pData2 *tss.KeyStep3Data, message strin
 	publicKey := edwards.NewPublicKey(pData1.PublicKey.X, pData1.PublicKey.Y)
 
 	// 初始化签名参与者
-	p1 := sign.NewEd25519Sign(partList[0], 2, partList, pData1.ShareI, publicKey, messageHex)
-	p2 := sign.NewEd25519Sign(partList[1], 2, partList, pData2.ShareI, publicKey, messageHex)
+	p1 := sign.NewEd25519Sign(sessionId, partList[0], 2, partList, pData1.ShareI, publicKey, messageHex)
+	p2 := sign.NewEd25519Sign(sessionId, partList[1], 2, partList, pData2.ShareI, publicKey, messageHex)
 
 	// 签名第一步
 	p1Step1, err := p1.SignStep1()
@@ -287,14 +289,15 @@ func performEd25519SimpleSignVerify(p1Data, p2Data, p3Data *tss.KeyStep3Data, me
 
 // performEd25519Reshare 执行Ed25519密钥刷新
 func performEd25519Reshare(p1Data, p2Data, p3Data *tss.KeyStep3Data) {
+	sessionId := tss.SessionId("performEd25519Reshare")
 	fmt.Println("执行Ed25519密钥刷新(Reshare)...")
 
 	// 假设参与者1和3参与刷新，参与者2的密钥丢失
-	devoteList := [2]int{1, 3}
+	devoteList := []int{1, 3}
 
-	refresh1 := reshare.NewRefresh(1, 3, devoteList, p1Data.ShareI, p1Data.PublicKey)
-	refresh2 := reshare.NewRefresh(2, 3, devoteList, nil, p2Data.PublicKey) // 参与者2密钥丢失，传入nil
-	refresh3 := reshare.NewRefresh(3, 3, devoteList, p3Data.ShareI, p3Data.PublicKey)
+	refresh1 := reshare.NewRefresh(sessionId, 1, 2, 3, devoteList, p1Data.ShareI, p1Data.PublicKey)
+	refresh2 := reshare.NewRefresh(sessionId, 2, 2, 3, devoteList, nil, p2Data.PublicKey) // 参与者2密钥丢失，传入nil
+	refresh3 := reshare.NewRefresh(sessionId, 3, 2, 3, devoteList, p3Data.ShareI, p3Data.PublicKey)
 
 	// Reshare第一轮
 	msgs1_1, _ := refresh1.DKGStep1()
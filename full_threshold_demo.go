@@ -66,12 +66,13 @@ func main() {
 
 // performDKG 执行DKG分布式密钥生成
 func performDKG() (*tss.KeyStep3Data, *tss.KeyStep3Data, *tss.KeyStep3Data) {
+	sessionId := tss.SessionId("performDKG")
 	fmt.Println("执行3方DKG密钥生成...")
 
 	// 初始化3个参与者
-	setUp1 := dkg.NewSetUp(1, 3, curve)
-	setUp2 := dkg.NewSetUp(2, 3, curve)
-	setUp3 := dkg.NewSetUp(3, 3, curve)
+	setUp1 := dkg.NewSetUp(sessionId, 1, 2, 3, curve)
+	setUp2 := dkg.NewSetUp(sessionId, 2, 2, 3, curve)
+	setUp3 := dkg.NewSetUp(sessionId, 3, 2, 3, curve)
 
 	// DKG第一轮
 	msgs1_1, _ := setUp1.DKGStep1()
@@ -310,14 +311,15 @@ func performKeyRecovery(p1Data, p2Data, p3Data *tss.KeyStep3Data) {
 
 // performReshare 执行密钥刷新
 func performReshare(p1Data, p2Data, p3Data *tss.KeyStep3Data) {
+	sessionId := tss.SessionId("performReshare")
 	fmt.Println("执行密钥刷新(Reshare)...")
 
 	// 假设参与者1和3参与刷新，参与者2的密钥丢失
-	devoteList := [2]int{1, 3}
+	devoteList := []int{1, 3}
 
-	refresh1 := reshare.NewRefresh(1, 3, devoteList, p1Data.ShareI, p1Data.PublicKey)
-	refresh2 := reshare.NewRefresh(2, 3, devoteList, nil, p2Data.PublicKey) // 参与者2密钥丢失，传入nil
-	refresh3 := reshare.NewRefresh(3, 3, devoteList, p3Data.ShareI, p3Data.PublicKey)
+	refresh1 := reshare.NewRefresh(sessionId, 1, 2, 3, devoteList, p1Data.ShareI, p1Data.PublicKey)
+	refresh2 := reshare.NewRefresh(sessionId, 2, 2, 3, devoteList, nil, p2Data.PublicKey) // 参与者2密钥丢失，传入nil
+	refresh3 := reshare.NewRefresh(sessionId, 3, 2, 3, devoteList, p3Data.ShareI, p3Data.PublicKey)
 
 	// Reshare第一轮
 	msgs1_1, _ := refresh1.DKGStep1()
@@ -7,11 +7,15 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"syscall"
 
 	"github.com/gin-gonic/gin"
+	"github.com/okx/threshold-lib/tss/ecdsa/keygen"
 	"mpc-server/internal/config"
 	"mpc-server/internal/handlers"
+	"mpc-server/internal/keystore"
 	"mpc-server/internal/mpc"
 	"mpc-server/internal/peer"
 	"mpc-server/internal/websocket"
@@ -79,8 +83,19 @@ func setupServerComponents(serverID string) (*websocket.Hub, *handlers.Handler,
 		return nil, nil, nil, fmt.Errorf("failed to create temp handler: %v", err)
 	}
 
+	keyStore, err := setupKeyStore(serverID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pool, err := setupPool(serverID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// 使用peerClient和wsHub创建MPCManager
-	mpcManager := mpc.NewMPCManager(serverID, tempHandler.GetPeerClient(), wsHub)
+	mpcManager := mpc.NewMPCManager(serverID, tempHandler.GetPeerClient(), wsHub, keyStore)
+	mpcManager.SetPool(pool)
 
 	// 设置mpcManager到handler中
 	handler := tempHandler
@@ -89,6 +104,73 @@ func setupServerComponents(serverID string) (*websocket.Hub, *handlers.Handler,
 	return wsHub, handler, mpcManager, nil
 }
 
+// setupKeyStore 根据环境变量创建加密文件密钥存储，未设置口令时启动失败，
+// MPC_KEYSTORE=memory 显式选择内存存储，重启后密钥丢失，仅用于测试和演示
+func setupKeyStore(serverID string) (keystore.KeyStore, error) {
+	if memoryKeyStore() {
+		log.Printf("WARNING: MPC_KEYSTORE=memory, key shares are kept in memory only and lost on restart")
+		return keystore.NewMemoryKeyStore(), nil
+	}
+	passphrase := os.Getenv("MPC_KEYSTORE_PASSPHRASE")
+	if passphrase == "" {
+		return nil, fmt.Errorf("MPC_KEYSTORE_PASSPHRASE is not set, set it to persist key shares or set MPC_KEYSTORE=memory to keep them in memory only")
+	}
+	dir := os.Getenv("MPC_KEYSTORE_DIR")
+	if dir == "" {
+		dir = filepath.Join("data", "keys", serverID)
+	}
+	keyStore, err := keystore.NewFileKeyStore(dir, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key store: %v", err)
+	}
+	log.Printf("Key store: %s", dir)
+	return keyStore, nil
+}
+
+// setupPool 根据环境变量创建Paillier密钥和预参数池，后台预生成安全素数，MPC_POOL_SIZE为0时不启用。
+// 池中含有秘密素数，使用密钥存储的口令加密持久化，MPC_KEYSTORE=memory 时只保存在内存中
+func setupPool(serverID string) (*keygen.Pool, error) {
+	size := 2
+	if value := os.Getenv("MPC_POOL_SIZE"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid MPC_POOL_SIZE: %s", value)
+		}
+		size = n
+	}
+	if size == 0 {
+		log.Printf("Paillier pool disabled")
+		return nil, nil
+	}
+	var store keygen.PoolStore
+	dir := "memory"
+	if !memoryKeyStore() {
+		dir = os.Getenv("MPC_POOL_DIR")
+		if dir == "" {
+			dir = filepath.Join("data", "pool", serverID)
+		}
+		poolStore, err := keystore.NewPoolStore(dir, os.Getenv("MPC_KEYSTORE_PASSPHRASE"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create paillier pool store: %v", err)
+		}
+		store = poolStore
+	}
+	pool, err := keygen.NewPool(store, size, func(err error) {
+		log.Printf("Paillier pool: %v", err)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create paillier pool: %v", err)
+	}
+	paillierKeys, preParams := pool.Len()
+	log.Printf("Paillier pool: %s, size %d, ready %d/%d", dir, size, paillierKeys, preParams)
+	return pool, nil
+}
+
+// memoryKeyStore MPC_KEYSTORE=memory 时密钥分片和Paillier池不落盘
+func memoryKeyStore() bool {
+	return os.Getenv("MPC_KEYSTORE") == "memory"
+}
+
 // setupRouter 设置Gin路由
 func setupRouter(serverID string, serverConfig *config.ServerConfig, handler *handlers.Handler, wsHub *websocket.Hub) *gin.Engine {
 	// 设置Gin路由
@@ -121,6 +203,9 @@ func setupRouter(serverID string, serverConfig *config.ServerConfig, handler *ha
 			api.GET("/sessions", handler.ListSessions)
 			api.GET("/sessions/:sessionId", handler.GetSessionStatus)
 
+			// 密钥地址
+			api.GET("/keys/:keyId/address", handler.GetKeyAddress)
+
 			// MPC操作
 			api.POST("/keygen", handler.InitKeygen)
 			api.POST("/reshare", handler.InitReshare)
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"log"
 	"mpc-server/internal/config"
+	"mpc-server/internal/keystore"
 	"mpc-server/internal/mpc"
 	"mpc-server/internal/peer"
 	"mpc-server/internal/protocol"
@@ -51,8 +54,11 @@ type ReshareResponse struct {
 
 // SignRequest 签名请求
 type SignRequest struct {
-	SessionID string   `json:"session_id" binding:"required"`
+	SessionID string   `json:"session_id"` // keygen会话ID，与key_id二选一
+	KeyID     string   `json:"key_id"`
 	Message   string   `json:"message" binding:"required"`
+	HashMode  string   `json:"hash_mode"` // raw/sha256/double_sha256/keccak256/eip191/eip712，默认sha256
+	Path      string   `json:"path"`      // 可选，bip32非硬化派生路径，如m/0/1
 	Signers   []string `json:"signers" binding:"required"`
 }
 
@@ -228,6 +234,15 @@ func (h *Handler) InitSign(c *gin.Context) {
 		return
 	}
 
+	if req.SessionID == "" && req.KeyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session_id or key_id is required"})
+		return
+	}
+	if _, err := mpc.ParseHashMode(req.HashMode); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// 检查服务器是否支持sign
 	if !h.config.HasCapability("sign") {
 		c.JSON(http.StatusForbidden, gin.H{
@@ -246,7 +261,10 @@ func (h *Handler) InitSign(c *gin.Context) {
 	// 创建sign初始化数据
 	initData := &protocol.SignInitData{
 		SessionID: req.SessionID,
+		KeyID:     req.KeyID,
 		Message:   req.Message,
+		HashMode:  req.HashMode,
+		Path:      req.Path,
 		Signers:   req.Signers,
 	}
 
@@ -314,6 +332,31 @@ func (h *Handler) ListSessions(c *gin.Context) {
 	})
 }
 
+// GetKeyAddress 按format返回密钥的链上地址，可选path做非硬化bip32派生
+func (h *Handler) GetKeyAddress(c *gin.Context) {
+	keyID := c.Param("keyId")
+	format := c.DefaultQuery("format", "eth")
+	path := c.Query("path")
+
+	addr, publicKey, err := h.mpcManager.KeyAddress(keyID, format, path)
+	if errors.Is(err, keystore.ErrKeyNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Key not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"key_id":     keyID,
+		"format":     format,
+		"path":       path,
+		"public_key": hex.EncodeToString(append(publicKey.X.Bytes(), publicKey.Y.Bytes()...)),
+		"address":    addr,
+	})
+}
+
 // broadcastToParticipants 向参与者广播消息
 func (h *Handler) broadcastToParticipants(sessionID string, participants []string, msgType protocol.MessageType, data interface{}) {
 	for _, participant := range participants {
@@ -365,6 +408,8 @@ func (h *Handler) HandleWebSocketMessage(client *ws.Client, messageBytes []byte)
 		h.handleSignInit(client, msg)
 	case protocol.MsgTypeSignRound:
 		h.handleSignRound(client, msg)
+	case protocol.MsgTypePreParams, protocol.MsgTypeKeygenP1Data:
+		h.handleTwoPartyKeygen(client, msg)
 	case protocol.MsgTypeHeartbeat:
 		h.handleHeartbeat(client, msg)
 	case protocol.MsgTypeSessionSync:
@@ -404,6 +449,22 @@ func (h *Handler) handleKeygenRound(client *ws.Client, msg *protocol.Message) {
 	h.mpcManager.ProcessKeygenRound(msg.SessionID, &roundData)
 }
 
+// handleTwoPartyKeygen 处理2方签名材料协商消息
+func (h *Handler) handleTwoPartyKeygen(client *ws.Client, msg *protocol.Message) {
+	var roundData protocol.KeygenRoundData
+	dataBytes, _ := json.Marshal(msg.Data)
+	if err := json.Unmarshal(dataBytes, &roundData); err != nil {
+		h.sendError(client, msg.SessionID, fmt.Sprintf("Invalid two-party keygen data: %v", err))
+		return
+	}
+
+	go func() {
+		if err := h.mpcManager.ProcessTwoPartyKeygen(msg.SessionID, msg.Type, &roundData); err != nil {
+			log.Printf("Two-party keygen failed for session %s: %v", msg.SessionID, err)
+		}
+	}()
+}
+
 // handleReshareInit 处理密钥重分享初始化
 func (h *Handler) handleReshareInit(client *ws.Client, msg *protocol.Message) {
 	if !h.config.HasCapability("reshare") {
@@ -175,11 +175,15 @@ func (h *Handler) handlePreParamsStep(session *mpc.Session, twoPartyData *TwoPar
 
 	if twoPartyData.IsInitiator {
 		// 作为发起者，生成预参数
-		preParams := keygen.GeneratePreParamsWithDlnProof()
+		preParams, err := h.mpcManager.PreParams()
+		if err != nil {
+			log.Printf("Failed to generate pre-params: %v", err)
+			return
+		}
 		twoPartyData.PreParams = preParams
 
 		// 生成Paillier密钥对
-		paiPrivate, _, err := paillier.NewKeyPair(8)
+		paiPrivate, err := h.mpcManager.PaillierKey()
 		if err != nil {
 			log.Printf("Failed to generate Paillier key pair: %v", err)
 			return
@@ -0,0 +1,256 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	fileVersion = 1
+	fileSuffix  = ".key"
+
+	// scrypt参数，每个文件使用独立的salt
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 16
+
+	// 文件头中的scrypt参数上限，内存128*N*R不超过1GB，与backup.KDFParams一致
+	maxScryptN      = 1 << 20
+	maxScryptMemory = 1 << 30
+	maxScryptP      = 16
+)
+
+// encryptedFile 磁盘上的加密文件格式，KeyID作为AES-GCM附加数据，防止文件被替换
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// FileKeyStore 本地文件密钥存储，每个KeyID一个文件，使用口令派生密钥AES-256-GCM加密
+type FileKeyStore struct {
+	dir        string
+	passphrase []byte
+	mu         sync.RWMutex
+}
+
+// NewFileKeyStore 创建文件密钥存储，目录不存在时自动创建
+func NewFileKeyStore(dir, passphrase string) (*FileKeyStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("key store directory is empty")
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("key store passphrase is empty")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create key store directory: %w", err)
+	}
+	return &FileKeyStore{
+		dir:        dir,
+		passphrase: []byte(passphrase),
+	}, nil
+}
+
+// Save 加密后原子写入文件
+func (s *FileKeyStore) Save(record *KeyRecord) error {
+	if record == nil {
+		return fmt.Errorf("key record is nil")
+	}
+	plaintext, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.write(record.KeyID, plaintext)
+}
+
+// Load 读取并解密，口令错误或文件被篡改时返回错误
+func (s *FileKeyStore) Load(keyID string) (*KeyRecord, error) {
+	plaintext, err := s.read(keyID)
+	if err != nil {
+		return nil, err
+	}
+	var record KeyRecord
+	if err := json.Unmarshal(plaintext, &record); err != nil {
+		return nil, fmt.Errorf("invalid key record %s: %w", keyID, err)
+	}
+	if record.KeyID != keyID {
+		return nil, fmt.Errorf("key record id mismatch: %s", keyID)
+	}
+	return &record, nil
+}
+
+// Delete 删除文件，不存在时返回ErrKeyNotFound
+func (s *FileKeyStore) Delete(keyID string) error {
+	if err := ValidateKeyID(keyID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := os.Remove(s.path(keyID))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrKeyNotFound
+	}
+	return err
+}
+
+// List 按KeyID排序返回
+func (s *FileKeyStore) List() ([]string, error) {
+	s.mu.RLock()
+	entries, err := os.ReadDir(s.dir)
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, fileSuffix))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// write 加密后原子写入id对应的文件，id作为附加数据
+func (s *FileKeyStore) write(id string, plaintext []byte) error {
+	if err := ValidateKeyID(id); err != nil {
+		return err
+	}
+	file, err := s.seal(id, plaintext)
+	if err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tmp, err := os.CreateTemp(s.dir, "."+id+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(bytes); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write key %s: %w", id, err)
+	}
+	return os.Rename(tmp.Name(), s.path(id))
+}
+
+// read 读取并解密id对应的文件，不存在时返回ErrKeyNotFound
+func (s *FileKeyStore) read(id string) ([]byte, error) {
+	if err := ValidateKeyID(id); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	bytes, err := os.ReadFile(s.path(id))
+	s.mu.RUnlock()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, err
+	}
+	var file encryptedFile
+	if err := json.Unmarshal(bytes, &file); err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", id, err)
+	}
+	return s.open(id, &file)
+}
+
+func (s *FileKeyStore) path(keyID string) string {
+	return filepath.Join(s.dir, keyID+fileSuffix)
+}
+
+func (s *FileKeyStore) seal(keyID string, plaintext []byte) (*encryptedFile, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := s.aead(salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &encryptedFile{
+		Version:    fileVersion,
+		KDF:        "scrypt",
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(keyID)),
+	}, nil
+}
+
+func (s *FileKeyStore) open(keyID string, file *encryptedFile) ([]byte, error) {
+	if file.Version != fileVersion || file.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported key file format %d/%s", file.Version, file.KDF)
+	}
+	if !validScryptParams(file.N, file.R, file.P) {
+		return nil, fmt.Errorf("invalid key file kdf parameters")
+	}
+	aead, err := s.aead(file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid key file nonce")
+	}
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt key %s, wrong passphrase or corrupted file", keyID)
+	}
+	return plaintext, nil
+}
+
+// validScryptParams N为2的幂，参数在派生密钥前检查，篡改的文件头不能耗尽内存
+func validScryptParams(n, r, p int) bool {
+	if n <= 1 || n > maxScryptN || n&(n-1) != 0 {
+		return false
+	}
+	return r > 0 && r <= maxScryptMemory/(128*n) && p > 0 && p <= maxScryptP
+}
+
+func (s *FileKeyStore) aead(salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key(s.passphrase, salt, n, r, p, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,58 @@
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"time"
+
+	"github.com/okx/threshold-lib/crypto/paillier"
+	"github.com/okx/threshold-lib/crypto/pedersen"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/ecdsa/keygen"
+)
+
+// ErrKeyNotFound 密钥不存在
+var ErrKeyNotFound = errors.New("key not found")
+
+// KeyRecord 持久化的密钥材料，按KeyID索引
+type KeyRecord struct {
+	KeyID        string            `json:"key_id"`
+	PublicKey    string            `json:"public_key"` // hex(X || Y)
+	Threshold    int               `json:"threshold"`
+	Participants []string          `json:"participants"`
+	KeyData      *tss.KeyStep3Data `json:"key_data"` // DKG/重分享得到的私钥分片
+
+	// 2方签名P1材料
+	PaiPriKey *paillier.PrivateKey         `json:"pai_pri_key,omitempty"`
+	E_x1      *big.Int                     `json:"e_x1,omitempty"`
+	Ped       *pedersen.PedersenParameters `json:"ped,omitempty"`
+	// 2方签名P2材料
+	P2SaveData *keygen.P2SaveData `json:"p2_save_data,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// KeyStore 密钥存储接口，服务重启后密钥不丢失
+type KeyStore interface {
+	// Save 新增或覆盖KeyID对应的记录
+	Save(record *KeyRecord) error
+	// Load 读取记录，不存在时返回ErrKeyNotFound
+	Load(keyID string) (*KeyRecord, error)
+	// Delete 删除记录
+	Delete(keyID string) error
+	// List 列出所有KeyID
+	List() ([]string, error)
+}
+
+var keyIDPattern = regexp.MustCompile(`^[0-9A-Za-z_-]{1,256}$`)
+
+// ValidateKeyID KeyID仅允许字母数字、下划线和短横线，防止路径穿越
+func ValidateKeyID(keyID string) error {
+	if !keyIDPattern.MatchString(keyID) {
+		return fmt.Errorf("invalid key id %q", keyID)
+	}
+	return nil
+}
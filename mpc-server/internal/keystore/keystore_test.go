@@ -0,0 +1,202 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/okx/threshold-lib/tss"
+)
+
+func testRecord(keyID string) *KeyRecord {
+	return &KeyRecord{
+		KeyID:        keyID,
+		PublicKey:    keyID,
+		Threshold:    2,
+		Participants: []string{"third-party", "enterprise", "mobile-app"},
+		KeyData: &tss.KeyStep3Data{
+			Id:        1,
+			ShareI:    big.NewInt(123456789),
+			ChainCode: "0a0b0c",
+		},
+		E_x1:      big.NewInt(987654321),
+		CreatedAt: time.Unix(1700000000, 0).UTC(),
+		UpdatedAt: time.Unix(1700000001, 0).UTC(),
+	}
+}
+
+func TestFileKeyStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileKeyStore(dir, "key-passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := testRecord("key-a")
+	if err := store.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// encrypted on disk
+	raw, err := os.ReadFile(filepath.Join(dir, "key-a"+fileSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("123456789")) || bytes.Contains(raw, []byte("third-party")) {
+		t.Fatal("key record stored in plaintext")
+	}
+
+	loaded, err := store.Load("key-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(record, loaded) {
+		t.Fatalf("round trip mismatch\n%+v\n%+v", record, loaded)
+	}
+
+	// a new store on the same directory, e.g. after a restart
+	restarted, err := NewFileKeyStore(dir, "key-passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err = restarted.Load("key-a"); err != nil || loaded.KeyData.ShareI.Cmp(record.KeyData.ShareI) != 0 {
+		t.Fatalf("reload after restart failed: %v", err)
+	}
+
+	if _, err := store.Load("key-missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestFileKeyStoreWrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileKeyStore(dir, "key-passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(testRecord("key-a")); err != nil {
+		t.Fatal(err)
+	}
+	wrong, err := NewFileKeyStore(dir, "wrong-passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrong.Load("key-a"); err == nil {
+		t.Fatal("wrong passphrase accepted")
+	}
+	if _, err := NewFileKeyStore(dir, ""); err == nil {
+		t.Fatal("empty passphrase accepted")
+	}
+}
+
+func TestFileKeyStoreTampered(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileKeyStore(dir, "key-passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(testRecord("key-a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(testRecord("key-b")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the key id is the associated data, a file moved to another key id must not decrypt
+	raw, err := os.ReadFile(filepath.Join(dir, "key-a"+fileSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "key-b"+fileSuffix), raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load("key-b"); err == nil {
+		t.Fatal("swapped key file accepted")
+	}
+
+	// a flipped ciphertext byte
+	i := bytes.Index(raw, []byte(`"ciphertext":"`)) + len(`"ciphertext":"`)
+	raw[i] ^= 0x01
+	if err := os.WriteFile(filepath.Join(dir, "key-a"+fileSuffix), raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load("key-a"); err == nil {
+		t.Fatal("tampered key file accepted")
+	}
+}
+
+// scrypt参数来自未认证的文件头，超限的参数在派生密钥前被拒绝
+func TestFileKeyStoreKDFLimits(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileKeyStore(dir, "key-passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(testRecord("key-a")); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "key-a"+fileSuffix)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, params := range [][3]int{{1 << 20, 1 << 20, 1}, {1 << 15, 8, 1 << 20}, {3 << 14, 8, 1}, {1 << 21, 1, 1}} {
+		var file encryptedFile
+		if err := json.Unmarshal(raw, &file); err != nil {
+			t.Fatal(err)
+		}
+		file.N, file.R, file.P = params[0], params[1], params[2]
+		crafted, err := json.Marshal(&file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, crafted, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Load("key-a"); err == nil || !strings.Contains(err.Error(), "kdf parameters") {
+			t.Fatalf("kdf parameters %v accepted: %v", params, err)
+		}
+	}
+}
+
+func TestKeyStoreListDelete(t *testing.T) {
+	fileStore, err := NewFileKeyStore(t.TempDir(), "key-passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]KeyStore{"file": fileStore, "memory": NewMemoryKeyStore()}
+	for name, store := range stores {
+		for _, keyID := range []string{"key-b", "key-a", "key-c"} {
+			if err := store.Save(testRecord(keyID)); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		}
+		if err := store.Save(testRecord("../escape")); err == nil {
+			t.Fatalf("%s: invalid key id accepted", name)
+		}
+		ids, err := store.List()
+		if err != nil || !reflect.DeepEqual(ids, []string{"key-a", "key-b", "key-c"}) {
+			t.Fatalf("%s: unexpected list %v %v", name, ids, err)
+		}
+
+		if err := store.Delete("key-b"); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := store.Delete("key-b"); !errors.Is(err, ErrKeyNotFound) {
+			t.Fatalf("%s: expected ErrKeyNotFound, got %v", name, err)
+		}
+		if _, err := store.Load("key-b"); !errors.Is(err, ErrKeyNotFound) {
+			t.Fatalf("%s: expected ErrKeyNotFound, got %v", name, err)
+		}
+		ids, err = store.List()
+		if err != nil || !reflect.DeepEqual(ids, []string{"key-a", "key-c"}) {
+			t.Fatalf("%s: unexpected list %v %v", name, ids, err)
+		}
+	}
+}
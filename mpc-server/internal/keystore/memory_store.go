@@ -0,0 +1,73 @@
+package keystore
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// MemoryKeyStore 内存密钥存储，仅用于测试和演示，重启后数据丢失
+type MemoryKeyStore struct {
+	records map[string][]byte
+	mu      sync.RWMutex
+}
+
+// NewMemoryKeyStore 创建内存密钥存储
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{
+		records: make(map[string][]byte),
+	}
+}
+
+// Save 保存记录副本
+func (s *MemoryKeyStore) Save(record *KeyRecord) error {
+	if err := ValidateKeyID(record.KeyID); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.KeyID] = bytes
+	return nil
+}
+
+// Load 返回记录副本
+func (s *MemoryKeyStore) Load(keyID string) (*KeyRecord, error) {
+	s.mu.RLock()
+	bytes, ok := s.records[keyID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	var record KeyRecord
+	if err := json.Unmarshal(bytes, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Delete 删除记录
+func (s *MemoryKeyStore) Delete(keyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.records[keyID]; !ok {
+		return ErrKeyNotFound
+	}
+	delete(s.records, keyID)
+	return nil
+}
+
+// List 按KeyID排序返回
+func (s *MemoryKeyStore) List() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]string, 0, len(s.records))
+	for id := range s.records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,51 @@
+package keystore
+
+import (
+	"errors"
+)
+
+// PoolStore keygen.PoolStore的实现，预生成的Paillier私钥和预参数含有秘密素数，
+// 与密钥存储使用同一口令加密保存，每项一个文件
+type PoolStore struct {
+	files *FileKeyStore
+}
+
+// NewPoolStore 创建加密的池存储，目录不能与密钥存储相同
+func NewPoolStore(dir, passphrase string) (*PoolStore, error) {
+	files, err := NewFileKeyStore(dir, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &PoolStore{files: files}, nil
+}
+
+// Save 加密保存一项
+func (s *PoolStore) Save(name string, data []byte) error {
+	return s.files.write(name, data)
+}
+
+// Load 读取并解密所有项，口令错误或文件被篡改时返回错误
+func (s *PoolStore) Load() (map[string][]byte, error) {
+	names, err := s.files.List()
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string][]byte, len(names))
+	for _, name := range names {
+		data, err := s.files.read(name)
+		if err != nil {
+			return nil, err
+		}
+		items[name] = data
+	}
+	return items, nil
+}
+
+// Remove 删除一项，不存在时不报错
+func (s *PoolStore) Remove(name string) error {
+	err := s.files.Delete(name)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package keystore
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPoolStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewPoolStore(dir, "pool-passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte(`{"P":"secret prime"}`)
+	if err := store.Save("paillier-0123abcd", secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save("../escape", secret); err == nil {
+		t.Fatal("invalid name accepted")
+	}
+
+	// encrypted on disk
+	raw, err := os.ReadFile(filepath.Join(dir, "paillier-0123abcd"+fileSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("secret prime")) {
+		t.Fatal("pool item stored in plaintext")
+	}
+
+	items, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || !bytes.Equal(items["paillier-0123abcd"], secret) {
+		t.Fatalf("unexpected items %v", items)
+	}
+
+	wrong, err := NewPoolStore(dir, "wrong-passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrong.Load(); err == nil {
+		t.Fatal("wrong passphrase accepted")
+	}
+
+	if err := store.Remove("paillier-0123abcd"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Remove("paillier-0123abcd"); err != nil {
+		t.Fatal(err)
+	}
+	items, err = store.Load()
+	if err != nil || len(items) != 0 {
+		t.Fatalf("unexpected items %v %v", items, err)
+	}
+}
@@ -0,0 +1,58 @@
+package mpc
+
+import (
+	"fmt"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/tss/address"
+	"github.com/okx/threshold-lib/tss/key/bip32"
+)
+
+// KeyAddress 按format生成KeyID对应公钥的链上地址，path非空时先做非硬化bip32派生，如m/0/1
+func (m *MPCManager) KeyAddress(keyID, format, path string) (string, *curves.ECPoint, error) {
+	record, err := m.keyStore.Load(keyID)
+	if err != nil {
+		return "", nil, err
+	}
+	if record.KeyData == nil || record.KeyData.PublicKey == nil {
+		return "", nil, fmt.Errorf("key %s has no public key", keyID)
+	}
+	publicKey := record.KeyData.PublicKey
+	if path != "" {
+		if publicKey, err = derivePublicKey(publicKey, record.KeyData.ChainCode, path); err != nil {
+			return "", nil, err
+		}
+	}
+	addr, err := address.Encode(address.Format(format), publicKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return addr, publicKey, nil
+}
+
+// derivePublicKey 只用公钥和chaincode派生，硬化派生需要各方参与，这里不支持
+func derivePublicKey(publicKey *curves.ECPoint, chainCode, path string) (*curves.ECPoint, error) {
+	if chainCode == "" {
+		return nil, fmt.Errorf("key has no chaincode")
+	}
+	if curves.GetCurveName(publicKey.Curve) == curves.Ed25519 {
+		tssKey, err := bip32.NewEd25519TssKey(nil, publicKey, chainCode)
+		if err != nil {
+			return nil, err
+		}
+		child, err := tssKey.DerivePath(path)
+		if err != nil {
+			return nil, err
+		}
+		return child.PublicKey(), nil
+	}
+	tssKey, err := bip32.NewTssKey(nil, publicKey, chainCode)
+	if err != nil {
+		return nil, err
+	}
+	child, err := tssKey.DerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return child.PublicKey(), nil
+}
@@ -0,0 +1,102 @@
+package mpc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/okx/threshold-lib/crypto/paillier"
+	"github.com/okx/threshold-lib/crypto/pedersen"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/ecdsa/keygen"
+	"github.com/okx/threshold-lib/tss/key/bip32"
+	"mpc-server/internal/keystore"
+)
+
+// keyIDOf KeyID为公钥hex(X || Y)，与会话中的public_key一致
+func keyIDOf(keyData *tss.KeyStep3Data) string {
+	return hex.EncodeToString(append(keyData.PublicKey.X.Bytes(), keyData.PublicKey.Y.Bytes()...))
+}
+
+// saveKeyShare 保存DKG或重分享得到的私钥分片，分片变化时清除已有的2方签名材料
+func (m *MPCManager) saveKeyShare(keyData *tss.KeyStep3Data, threshold int, participants []string) (string, error) {
+	keyID := keyIDOf(keyData)
+	now := time.Now()
+	record, err := m.keyStore.Load(keyID)
+	if errors.Is(err, keystore.ErrKeyNotFound) {
+		record = &keystore.KeyRecord{
+			KeyID:     keyID,
+			PublicKey: keyID,
+			CreatedAt: now,
+		}
+	} else if err != nil {
+		return "", err
+	}
+	// 重分享不产生chaincode，沿用原值
+	if keyData.ChainCode == "" && record.KeyData != nil {
+		keyData.ChainCode = record.KeyData.ChainCode
+	}
+	// 分片变化后旧的2方签名材料失效，由新一轮2方keygen重新生成
+	if record.KeyData == nil || record.KeyData.ShareI == nil || record.KeyData.ShareI.Cmp(keyData.ShareI) != 0 {
+		record.PaiPriKey = nil
+		record.E_x1 = nil
+		record.Ped = nil
+		record.P2SaveData = nil
+	}
+	record.KeyData = keyData
+	record.Threshold = threshold
+	record.Participants = participants
+	record.UpdatedAt = now
+	if err := m.keyStore.Save(record); err != nil {
+		return "", err
+	}
+	return keyID, nil
+}
+
+// deriveTssKey 按path做非硬化派生，shareI为nil时只派生公钥
+func deriveTssKey(shareI *big.Int, keyData *tss.KeyStep3Data, path string) (*bip32.TssKey, error) {
+	if keyData.ChainCode == "" {
+		return nil, fmt.Errorf("key has no chaincode")
+	}
+	tssKey, err := bip32.NewTssKey(shareI, keyData.PublicKey, keyData.ChainCode)
+	if err != nil {
+		return nil, err
+	}
+	return tssKey.DerivePath(path)
+}
+
+// StoreP1KeyMaterial 保存2方签名P1的Paillier私钥、E(x1)和Pedersen参数
+func (m *MPCManager) StoreP1KeyMaterial(keyID string, paiPriKey *paillier.PrivateKey, E_x1 *big.Int, ped *pedersen.PedersenParameters) error {
+	record, err := m.keyStore.Load(keyID)
+	if err != nil {
+		return fmt.Errorf("failed to load key %s: %w", keyID, err)
+	}
+	record.PaiPriKey = paiPriKey
+	record.E_x1 = E_x1
+	record.Ped = ped
+	record.UpdatedAt = time.Now()
+	return m.keyStore.Save(record)
+}
+
+// StoreP2SaveData 保存2方签名P2的keygen结果
+func (m *MPCManager) StoreP2SaveData(keyID string, p2SaveData *keygen.P2SaveData) error {
+	record, err := m.keyStore.Load(keyID)
+	if err != nil {
+		return fmt.Errorf("failed to load key %s: %w", keyID, err)
+	}
+	record.P2SaveData = p2SaveData
+	record.UpdatedAt = time.Now()
+	return m.keyStore.Save(record)
+}
+
+// LoadKey 按KeyID读取密钥记录
+func (m *MPCManager) LoadKey(keyID string) (*keystore.KeyRecord, error) {
+	return m.keyStore.Load(keyID)
+}
+
+// ListKeys 列出本服务器保存的所有KeyID
+func (m *MPCManager) ListKeys() ([]string, error) {
+	return m.keyStore.List()
+}
@@ -0,0 +1,131 @@
+package mpc
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/paillier"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/ecdsa/keygen"
+	"mpc-server/internal/keystore"
+)
+
+const testChainCode = "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"
+
+func testKeyData(shareI int64) *tss.KeyStep3Data {
+	curve := secp256k1.S256()
+	return &tss.KeyStep3Data{
+		Id:        1,
+		ShareI:    big.NewInt(shareI),
+		PublicKey: curves.ScalarToPoint(curve, big.NewInt(42)),
+		ChainCode: testChainCode,
+	}
+}
+
+func TestSaveKeyShare(t *testing.T) {
+	m := NewMPCManager("enterprise", nil, nil, keystore.NewMemoryKeyStore())
+	participants := []string{"third-party", "enterprise", "mobile-app"}
+
+	keyID, err := m.saveKeyShare(testKeyData(7), 2, participants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != keyIDOf(testKeyData(7)) {
+		t.Fatalf("unexpected key id %s", keyID)
+	}
+	record, err := m.LoadKey(keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.KeyData.ShareI.Int64() != 7 || record.Threshold != 2 || len(record.Participants) != 3 || record.CreatedAt.IsZero() {
+		t.Fatalf("unexpected record %+v", record)
+	}
+
+	// 2方签名材料
+	if err := m.StoreP1KeyMaterial(keyID, &paillier.PrivateKey{}, big.NewInt(99), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.StoreP2SaveData(keyID, &keygen.P2SaveData{From: 2, To: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 分片不变时保留2方签名材料
+	if _, err := m.saveKeyShare(testKeyData(7), 2, participants); err != nil {
+		t.Fatal(err)
+	}
+	record, err = m.LoadKey(keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.E_x1 == nil || record.E_x1.Int64() != 99 || record.P2SaveData == nil || record.P2SaveData.From != 2 {
+		t.Fatal("2-party material lost without share change")
+	}
+
+	// 重分享：新分片没有chaincode，沿用原值，旧分片对应的2方签名材料清除
+	reshared := testKeyData(8)
+	reshared.ChainCode = ""
+	if _, err := m.saveKeyShare(reshared, 2, participants[:2]); err != nil {
+		t.Fatal(err)
+	}
+	record, err = m.LoadKey(keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.KeyData.ShareI.Int64() != 8 || record.KeyData.ChainCode != testChainCode || len(record.Participants) != 2 {
+		t.Fatalf("unexpected reshared record %+v", record.KeyData)
+	}
+	if record.PaiPriKey != nil || record.E_x1 != nil || record.Ped != nil || record.P2SaveData != nil {
+		t.Fatal("2-party material of the old share kept by reshare")
+	}
+
+	ids, err := m.ListKeys()
+	if err != nil || len(ids) != 1 || ids[0] != keyID {
+		t.Fatalf("unexpected keys %v %v", ids, err)
+	}
+}
+
+func TestStoreKeyMaterialMissingKey(t *testing.T) {
+	m := NewMPCManager("enterprise", nil, nil, keystore.NewMemoryKeyStore())
+	if err := m.StoreP1KeyMaterial("key-missing", &paillier.PrivateKey{}, big.NewInt(1), nil); !errors.Is(err, keystore.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	if err := m.StoreP2SaveData("key-missing", &keygen.P2SaveData{}); !errors.Is(err, keystore.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestDeriveTssKey(t *testing.T) {
+	keyData := testKeyData(7)
+	child, err := deriveTssKey(keyData.ShareI, keyData, "m/0/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 只派生公钥时结果一致
+	public, err := deriveTssKey(nil, keyData, "m/0/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !child.PublicKey().Equals(public.PublicKey()) {
+		t.Fatal("public derivation mismatch")
+	}
+	if child.PublicKey().Equals(keyData.PublicKey) {
+		t.Fatal("child key equals the parent key")
+	}
+
+	keyData.ChainCode = ""
+	if _, err := deriveTssKey(keyData.ShareI, keyData, "m/0/1"); err == nil {
+		t.Fatal("key without chaincode derived")
+	}
+}
+
+func TestNewMPCManagerNilKeyStore(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("nil key store accepted")
+		}
+	}()
+	NewMPCManager("enterprise", nil, nil, nil)
+}
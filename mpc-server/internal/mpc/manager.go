@@ -3,23 +3,23 @@ package mpc
 import (
 	"crypto/ecdsa"
 	"crypto/rand"
-	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/decred/dcrd/dcrec/secp256k1/v2"
 	"github.com/okx/threshold-lib/crypto"
 	"github.com/okx/threshold-lib/crypto/curves"
-	"github.com/okx/threshold-lib/crypto/paillier"
-	"github.com/okx/threshold-lib/crypto/pedersen"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/crypto/zkp"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/ecdsa/keygen"
 	"github.com/okx/threshold-lib/tss/ecdsa/sign"
+	"github.com/okx/threshold-lib/tss/key/bip32"
 	"github.com/okx/threshold-lib/tss/key/dkg"
 	"github.com/okx/threshold-lib/tss/key/reshare"
 	"log"
 	"math/big"
+	"mpc-server/internal/keystore"
 	"mpc-server/internal/protocol"
 	"sync"
 	"time"
@@ -64,6 +64,8 @@ type MPCManager struct {
 	sessions   map[string]*Session
 	peerClient PeerClient   // 添加peer客户端接口
 	wsHub      WebSocketHub // 添加WebSocket Hub接口
+	keyStore   keystore.KeyStore
+	pool       *keygen.Pool // Paillier密钥和预参数池，可为nil
 	mu         sync.RWMutex
 }
 
@@ -78,13 +80,17 @@ type WebSocketHub interface {
 	BroadcastToAll(message []byte)
 }
 
-// NewMPCManager 创建新的MPC管理器
-func NewMPCManager(serverID string, peerClient PeerClient, wsHub WebSocketHub) *MPCManager {
+// NewMPCManager 创建新的MPC管理器，keyStore不能为nil，内存存储需显式传入keystore.NewMemoryKeyStore()
+func NewMPCManager(serverID string, peerClient PeerClient, wsHub WebSocketHub, keyStore keystore.KeyStore) *MPCManager {
+	if keyStore == nil {
+		panic(fmt.Errorf("NewMPCManager keyStore is nil"))
+	}
 	return &MPCManager{
 		serverID:   serverID,
 		sessions:   make(map[string]*Session),
 		peerClient: peerClient,
 		wsHub:      wsHub,
+		keyStore:   keyStore,
 	}
 }
 
@@ -288,6 +294,7 @@ func (m *MPCManager) StartReshareProtocol(sessionID string) error {
 		err := m.performRealReshare(sessionID, threshold, participants)
 		if err != nil {
 			log.Printf("Reshare failed for session %s: %v", sessionID, err)
+			m.recordFailure(session, err)
 			m.UpdateSessionStatus(sessionID, StatusFailed)
 			return
 		}
@@ -328,6 +335,7 @@ func (m *MPCManager) StartSignProtocol(sessionID string) error {
 		err := m.performRealECDSASign(sessionID, threshold, participants)
 		if err != nil {
 			log.Printf("ECDSA sign failed for session %s: %v", sessionID, err)
+			m.recordFailure(session, err)
 			m.UpdateSessionStatus(sessionID, StatusFailed)
 			return
 		}
@@ -387,12 +395,37 @@ func (m *MPCManager) ProcessReshareRound(sessionID string, data *protocol.Reshar
 	return nil, nil
 }
 
-// ProcessSignInit 处理签名初始化
+// ProcessSignInit 处理签名初始化，优先按KeyID从密钥存储加载
 func (m *MPCManager) ProcessSignInit(sessionID string, data *protocol.SignInitData) error {
-	// 检查原会话是否存在
-	originalSession, err := m.GetSession(data.SessionID)
+	hashMode, err := ParseHashMode(data.HashMode)
 	if err != nil {
-		return fmt.Errorf("original session %s not found", data.SessionID)
+		return err
+	}
+	if _, err := messageDigest(data.Message, hashMode); err != nil {
+		return err
+	}
+	if data.Path != "" {
+		if _, err := bip32.ParsePath(data.Path); err != nil {
+			return err
+		}
+	}
+	keyID := data.KeyID
+	if keyID == "" {
+		// 兼容按keygen会话ID发起签名
+		originalSession, err := m.GetSession(data.SessionID)
+		if err != nil {
+			return fmt.Errorf("original session %s not found", data.SessionID)
+		}
+		originalSession.mu.RLock()
+		keyID, _ = originalSession.Data["key_id"].(string)
+		originalSession.mu.RUnlock()
+		if keyID == "" {
+			return fmt.Errorf("no key ID found in session %s", data.SessionID)
+		}
+	}
+	record, err := m.keyStore.Load(keyID)
+	if err != nil {
+		return fmt.Errorf("failed to load key %s: %w", keyID, err)
 	}
 
 	session, err := m.GetSession(sessionID)
@@ -403,19 +436,17 @@ func (m *MPCManager) ProcessSignInit(sessionID string, data *protocol.SignInitDa
 	session.mu.Lock()
 	defer session.mu.Unlock()
 
-	// 复制密钥数据
-	originalSession.mu.RLock()
 	session.Data["original_session"] = data.SessionID
-	session.Data["public_key"] = originalSession.Data["public_key"]
-	session.Data["private_share"] = originalSession.Data["private_share"]
-	originalSession.mu.RUnlock()
-
+	session.Data["key_id"] = keyID
+	session.Data["public_key"] = record.PublicKey
 	session.Data["message"] = data.Message
+	session.Data["hash_mode"] = string(hashMode)
+	session.Data["path"] = data.Path
 	session.Data["signers"] = data.Signers
 	session.Status = StatusRunning
 	session.UpdatedAt = time.Now()
 
-	log.Printf("Sign session %s initialized for message: %s", sessionID, data.Message)
+	log.Printf("Sign session %s initialized with key %s for message: %s", sessionID, keyID, data.Message)
 	return nil
 }
 
@@ -667,34 +698,38 @@ func (m *MPCManager) processSignRound3(session *Session, data *protocol.SignRoun
 	affineProof.S = new(big.Int).SetBytes(mustDecodeHex(affineProofData.S))
 
 	// P1 Step3 - 计算最终签名
-	r, s, err := signCtx.P1.Step3(E_k2_h_xr, affineProof)
+	sig, err := signCtx.P1.Step3Signature(E_k2_h_xr, affineProof)
+	if err != nil {
+		err = fmt.Errorf("P1 step3 failed: %w", err)
+		m.recordFailure(session, err)
+		return nil, err
+	}
+	result, err := newSignResult(sig)
 	if err != nil {
-		return nil, fmt.Errorf("P1 step3 failed: %v", err)
+		m.recordFailure(session, err)
+		return nil, err
 	}
 
 	// 保存签名结果
-	session.Data["signature_r"] = hex.EncodeToString(r.Bytes())
-	session.Data["signature_s"] = hex.EncodeToString(s.Bytes())
+	saveSignResult(session, result)
 	session.Status = StatusCompleted
 
-	log.Printf("ECDSA signature completed for session %s: r=%s, s=%s",
-		session.ID, hex.EncodeToString(r.Bytes()), hex.EncodeToString(s.Bytes()))
+	log.Printf("ECDSA signature completed for session %s: r=%s, s=%s, v=%d",
+		session.ID, result.R, result.S, sig.V)
 
 	// 广播签名完成
 	resultData := &protocol.SignRound3Data{
-		R: stringPtr(hex.EncodeToString(r.Bytes())),
-		S: stringPtr(hex.EncodeToString(s.Bytes())),
+		R: stringPtr(result.R),
+		S: stringPtr(result.S),
+		V: result.V,
 	}
 
 	m.broadcastSignMessage(session.ID, 3, resultData, session.Participants)
 
 	// 发送签名完成通知
-	m.notifySignComplete(session.ID, r, s)
+	m.notifySignComplete(session.ID, result)
 
-	return &protocol.SignResultData{
-		Success:   true,
-		Signature: fmt.Sprintf("%s%s", hex.EncodeToString(r.Bytes()), hex.EncodeToString(s.Bytes())),
-	}, nil
+	return result, nil
 }
 
 // ListSessions 列出所有会话
@@ -846,8 +881,16 @@ func (m *MPCManager) initSetup(sessionID string, threshold int, participants []s
 	// 当前服务器的参与者ID（从1开始）
 	participantID := serverIndex + 1
 
-	// 为当前服务器创建SetupInfo
-	setUp := dkg.NewSetUp(participantID, total, curve)
+	// 门限需满足 2 <= t <= n
+	if threshold < 2 || threshold > total {
+		return fmt.Errorf("invalid threshold %d for %d participants", threshold, total)
+	}
+
+	// 为当前服务器创建SetupInfo，各服务器共享同一会话ID，绑定到所有承诺和证明
+	if sessionID == "" {
+		return fmt.Errorf("empty session id")
+	}
+	setUp := dkg.NewSetUp(tss.SessionId(sessionID), participantID, threshold, total, curve)
 
 	session, err := m.GetSession(sessionID)
 	if err != nil {
@@ -865,6 +908,17 @@ func (m *MPCManager) initSetup(sessionID string, threshold int, participants []s
 	return nil
 }
 
+// recordFailure 记录失败原因，可识别中止时记录作恶参与者，调用方可将其排除后重试
+// 参与者ID从1开始对应Participants下标，2方签名中P1为Participants[0]
+func (m *MPCManager) recordFailure(session *Session, err error) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.Data["error"] = err.Error()
+	if culprit, ok := tss.Culprit(err); ok && culprit > 0 && culprit <= len(session.Participants) {
+		session.Data["blamed_participant"] = session.Participants[culprit-1]
+	}
+}
+
 // broadcastDKGMessages 广播DKG消息给其他参与者
 func (m *MPCManager) broadcastDKGMessages(sessionID string, round int, messages map[int]*tss.Message, participants []string, messageType protocol.MessageType) error {
 	for participantID, message := range messages {
@@ -941,6 +995,7 @@ func (m *MPCManager) ProcessDKGRoundMessage(sessionID string, round int, fromPar
 		err := m.ProcessRound(sessionID, round)
 		if err != nil {
 			log.Printf("Failed to process DKG round %d for session %s: %v", round+1, sessionID, err)
+			m.recordFailure(session, err)
 			session.mu.Lock()
 			session.Status = StatusFailed
 			session.mu.Unlock()
@@ -976,7 +1031,7 @@ func (m *MPCManager) ProcessRound(sessionID string, currentRound int) error {
 		// 执行DKG第一轮
 		round1Messages, err := setUp.DKGStep1()
 		if err != nil {
-			return fmt.Errorf("DKG step 1 failed for participant %d: %v", participantID, err)
+			return fmt.Errorf("DKG step 1 failed for participant %d: %w", participantID, err)
 		}
 
 		// 保存第一轮消息到会话
@@ -1009,7 +1064,7 @@ func (m *MPCManager) ProcessRound(sessionID string, currentRound int) error {
 		// 执行DKG第二轮
 		round2Messages, err := setUp.DKGStep2(round2Inputs)
 		if err != nil {
-			return fmt.Errorf("DKG step 2 failed: %v", err)
+			return fmt.Errorf("DKG step 2 failed: %w", err)
 		}
 
 		session.mu.Lock()
@@ -1038,14 +1093,21 @@ func (m *MPCManager) ProcessRound(sessionID string, currentRound int) error {
 		// 执行DKG第三轮 - 完成密钥生成
 		keyData, err := setUp.DKGStep3(round3Inputs)
 		if err != nil {
-			return fmt.Errorf("DKG step 3 failed: %v", err)
+			return fmt.Errorf("DKG step 3 failed: %w", err)
 		}
 
 		// 保存结果
 		pubKeyHex := hex.EncodeToString(append(keyData.PublicKey.X.Bytes(), keyData.PublicKey.Y.Bytes()...))
 		privateShareHex := hex.EncodeToString(keyData.ShareI.Bytes())
 
+		// 持久化密钥分片，KeyID即公钥
+		keyID, err := m.saveKeyShare(keyData, session.Threshold, participants)
+		if err != nil {
+			return fmt.Errorf("failed to persist key share: %w", err)
+		}
+
 		session.mu.Lock()
+		session.Data["key_id"] = keyID
 		session.Data["public_key"] = pubKeyHex
 		session.Data["private_share"] = privateShareHex
 		session.Data["participant_id"] = keyData.Id
@@ -1057,6 +1119,12 @@ func (m *MPCManager) ProcessRound(sessionID string, currentRound int) error {
 		log.Printf("DKG completed successfully for session %s, participant %d, public key: %s...",
 			sessionID, participantID, pubKeyHex[:40])
 
+		// 协商2方签名材料，失败不影响DKG结果，记录原因
+		if err := m.startTwoPartyKeygen(session); err != nil {
+			log.Printf("Two-party keygen failed for session %s: %v", sessionID, err)
+			m.recordFailure(session, err)
+		}
+
 	default:
 		return fmt.Errorf("invalid DKG round: %d", currentRound+1)
 	}
@@ -1115,21 +1183,26 @@ func (m *MPCManager) performRealECDSASign(sessionID string, threshold int, parti
 		Y:     pubKeyY,
 	}
 
-	// 计算消息哈希并转换为十六进制字符串
-	hash := sha256.Sum256([]byte(message))
-	messageHex := hex.EncodeToString(hash[:])
+	// 按会话的hash_mode计算消息摘要
+	hashMode := sessionHashMode(session)
+	messageHex, err := messageDigest(message, hashMode)
+	if err != nil {
+		return err
+	}
 
-	log.Printf("ECDSA Sign: message=%s, hash=%s...", message, messageHex[:16])
+	log.Printf("ECDSA Sign: message=%s, %s=%s...", message, hashMode, messageHex[:16])
 
-	paiPriKey, paiPubKey, err := paillier.NewKeyPair()
+	paiPriKey, err := m.PaillierKey()
 	if err != nil {
 		return fmt.Errorf("failed to generate Paillier key: %v", err)
 	}
+	paiPubKey := &paiPriKey.PublicKey
 
-	pedParams, err := pedersen.NewPedersenParameters()
+	preParams, err := m.PreParams()
 	if err != nil {
 		return fmt.Errorf("failed to generate Pedersen parameters: %v", err)
 	}
+	pedParams := preParams.PedersonParameters()
 
 	// 模拟加密的x1份额（在实际实现中应该从DKG阶段获得）
 	x1 := crypto.RandomNum(secp256k1.S256().N)
@@ -1155,7 +1228,7 @@ func (m *MPCManager) performRealECDSASign(sessionID string, threshold int, parti
 	// Step 1: P1生成承诺
 	cmtC, err := p1.Step1()
 	if err != nil {
-		return fmt.Errorf("P1 Step1 failed: %v", err)
+		return fmt.Errorf("P1 Step1 failed: %w", err)
 	}
 
 	log.Printf("ECDSA Sign Round 2 starting for session %s", sessionID)
@@ -1163,13 +1236,13 @@ func (m *MPCManager) performRealECDSASign(sessionID string, threshold int, parti
 	// Step 1: P2处理承诺并生成证明
 	p2Proof, R2, err := p2.Step1(cmtC)
 	if err != nil {
-		return fmt.Errorf("P2 Step1 failed: %v", err)
+		return fmt.Errorf("P2 Step1 failed: %w", err)
 	}
 
 	// Step 2: P1处理P2的证明
 	p1Proof, cmtD, err := p1.Step2(p2Proof, R2)
 	if err != nil {
-		return fmt.Errorf("P1 Step2 failed: %v", err)
+		return fmt.Errorf("P1 Step2 failed: %w", err)
 	}
 
 	log.Printf("ECDSA Sign Round 3 starting for session %s", sessionID)
@@ -1177,21 +1250,23 @@ func (m *MPCManager) performRealECDSASign(sessionID string, threshold int, parti
 	// Step 2: P2处理P1的证明并生成加密数据
 	E_k2_h_xr, affGProof, err := p2.Step2(cmtD, p1Proof)
 	if err != nil {
-		return fmt.Errorf("P2 Step2 failed: %v", err)
+		return fmt.Errorf("P2 Step2 failed: %w", err)
 	}
 
 	// Step 3: P1完成签名
-	r, s, err := p1.Step3(E_k2_h_xr, affGProof)
+	sig, err := p1.Step3Signature(E_k2_h_xr, affGProof)
 	if err != nil {
-		return fmt.Errorf("P1 Step3 failed: %v", err)
+		return fmt.Errorf("P1 Step3 failed: %w", err)
+	}
+	result, err := newSignResult(sig)
+	if err != nil {
+		return err
 	}
 
 	// 保存签名结果
 	session.mu.Lock()
-	signatureHex := hex.EncodeToString(append(r.Bytes(), s.Bytes()...))
-	session.Data["signature"] = signatureHex
-	session.Data["signature_r"] = hex.EncodeToString(r.Bytes())
-	session.Data["signature_s"] = hex.EncodeToString(s.Bytes())
+	signatureHex := result.Signature
+	saveSignResult(session, result)
 	session.Status = StatusCompleted
 	session.UpdatedAt = time.Now()
 	session.mu.Unlock()
@@ -1267,20 +1342,23 @@ func (m *MPCManager) performRealReshare(sessionID string, threshold int, partici
 	}
 
 	total := len(participants)
+	if threshold < 2 || threshold > total {
+		return fmt.Errorf("invalid threshold %d for %d participants", threshold, total)
+	}
 
 	// 为了演示，我们假设参与者1和2进行重分享
 	// 在实际实现中，这应该由协议参数决定
-	devoteList := [2]int{1, 2}
+	devoteList := []int{1, 2}
 
 	log.Printf("Reshare Round 1 starting for session %s", sessionID)
 
 	// 创建重分享参与者
-	refreshInfo := reshare.NewRefresh(participantID, total, devoteList, privateShare, publicKeyPoint)
+	refreshInfo := reshare.NewRefresh(tss.SessionId(sessionID), participantID, threshold, total, devoteList, privateShare, publicKeyPoint)
 
 	// 第一轮：生成重分享数据
 	round1Messages, err := refreshInfo.DKGStep1()
 	if err != nil {
-		return fmt.Errorf("reshare DKG step 1 failed: %v", err)
+		return fmt.Errorf("reshare DKG step 1 failed: %w", err)
 	}
 
 	log.Printf("Reshare Round 2 starting for session %s", sessionID)
@@ -1294,12 +1372,12 @@ func (m *MPCManager) performRealReshare(sessionID string, threshold int, partici
 		if i != participantID {
 			// 模拟其他参与者的私钥份额
 			otherPrivateShare := new(big.Int).Add(privateShare, big.NewInt(int64(i)))
-			otherRefresh := reshare.NewRefresh(i, total, devoteList, otherPrivateShare, publicKeyPoint)
+			otherRefresh := reshare.NewRefresh(tss.SessionId(sessionID), i, threshold, total, devoteList, otherPrivateShare, publicKeyPoint)
 			otherRefreshInfos = append(otherRefreshInfos, otherRefresh)
 
 			otherMsgs, err := otherRefresh.DKGStep1()
 			if err != nil {
-				return fmt.Errorf("reshare DKG step 1 failed for participant %d: %v", i, err)
+				return fmt.Errorf("reshare DKG step 1 failed for participant %d: %w", i, err)
 			}
 			otherRound1Messages = append(otherRound1Messages, otherMsgs)
 		}
@@ -1316,7 +1394,7 @@ func (m *MPCManager) performRealReshare(sessionID string, threshold int, partici
 	// 第二轮：处理重分享消息
 	_, err = refreshInfo.DKGStep2(round2Input)
 	if err != nil {
-		return fmt.Errorf("reshare DKG step 2 failed: %v", err)
+		return fmt.Errorf("reshare DKG step 2 failed: %w", err)
 	}
 
 	log.Printf("Reshare Round 3 starting for session %s", sessionID)
@@ -1343,7 +1421,7 @@ func (m *MPCManager) performRealReshare(sessionID string, threshold int, partici
 
 		otherMsgs, err := otherRefresh.DKGStep2(otherRound2Input)
 		if err != nil {
-			return fmt.Errorf("reshare DKG step 2 failed for participant %d: %v", otherRefresh.DeviceNumber, err)
+			return fmt.Errorf("reshare DKG step 2 failed for participant %d: %w", otherRefresh.DeviceNumber, err)
 		}
 		otherRound2Messages = append(otherRound2Messages, otherMsgs)
 	}
@@ -1359,7 +1437,7 @@ func (m *MPCManager) performRealReshare(sessionID string, threshold int, partici
 	// 第三轮：完成重分享
 	newKeyData, err := refreshInfo.DKGStep3(round3Input)
 	if err != nil {
-		return fmt.Errorf("reshare DKG step 3 failed: %v", err)
+		return fmt.Errorf("reshare DKG step 3 failed: %w", err)
 	}
 
 	// 验证新的公钥与原公钥一致
@@ -1367,6 +1445,14 @@ func (m *MPCManager) performRealReshare(sessionID string, threshold int, partici
 		return fmt.Errorf("public key changed after reshare")
 	}
 
+	// 持久化新的私钥份额，覆盖原有记录，旧的2方签名材料随之清除
+	session.mu.Lock()
+	delete(session.Data, "two_party_ready")
+	session.mu.Unlock()
+	if _, err := m.saveKeyShare(newKeyData, threshold, participants); err != nil {
+		return fmt.Errorf("failed to persist key share: %w", err)
+	}
+
 	// 保存新的私钥份额
 	session.mu.Lock()
 	newPrivateShareHex := hex.EncodeToString(newKeyData.ShareI.Bytes())
@@ -1378,6 +1464,12 @@ func (m *MPCManager) performRealReshare(sessionID string, threshold int, partici
 	session.mu.Unlock()
 
 	log.Printf("Reshare completed successfully for session %s, new private share generated", sessionID)
+
+	// 按新分片重新协商2方签名材料
+	if err := m.startTwoPartyKeygen(session); err != nil {
+		log.Printf("Two-party keygen failed for session %s: %v", sessionID, err)
+		m.recordFailure(session, err)
+	}
 	return nil
 }
 
@@ -1424,14 +1516,10 @@ func (m *MPCManager) notifySessionComplete(sessionID string, status SessionStatu
 		}
 	case TypeSign:
 		if status == StatusCompleted {
-			if signature, ok := session.Data["signature"].(string); ok {
-				sessionData["signature"] = signature
-			}
-			if signatureR, ok := session.Data["signature_r"].(string); ok {
-				sessionData["signature_r"] = signatureR
-			}
-			if signatureS, ok := session.Data["signature_s"].(string); ok {
-				sessionData["signature_s"] = signatureS
+			for _, key := range signatureKeys {
+				if value, ok := session.Data[key]; ok {
+					sessionData[key] = value
+				}
 			}
 		}
 	}
@@ -1471,6 +1559,9 @@ func (m *MPCManager) notifySessionComplete(sessionID string, status SessionStatu
 		if errorMsg, ok := session.Data["error"].(string); ok {
 			notification["error"] = errorMsg
 		}
+		if culprit, ok := session.Data["blamed_participant"].(string); ok {
+			notification["blamed_participant"] = culprit
+		}
 	}
 
 	// 序列化消息
@@ -1546,49 +1637,18 @@ func (m *MPCManager) getSignContext(session *Session) (*SignContext, error) {
 	return nil, fmt.Errorf("sign context not found for session %s", session.ID)
 }
 
-// createSignContext 创建签名上下文
+// createSignContext 创建签名上下文，按KeyID从密钥存储加载密钥材料
 func (m *MPCManager) createSignContext(session *Session) (*SignContext, error) {
-	// 获取keygen会话的数据
-	keygenSessionID, exists := session.Data["keygen_session_id"]
-	if !exists {
-		return nil, fmt.Errorf("no keygen session ID found for sign session %s", session.ID)
+	keyID, ok := session.Data["key_id"].(string)
+	if !ok || keyID == "" {
+		return nil, fmt.Errorf("no key ID found for sign session %s", session.ID)
 	}
-
-	keygenSession, err := m.GetSession(keygenSessionID.(string))
+	record, err := m.keyStore.Load(keyID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get keygen session %s: %v", keygenSessionID, err)
-	}
-
-	keygenSession.mu.RLock()
-	defer keygenSession.mu.RUnlock()
-
-	// 获取必要的keygen数据
-	privateShareData, exists := keygenSession.Data["private_share"]
-	if !exists {
-		return nil, fmt.Errorf("no private share found in keygen session %s", keygenSessionID)
-	}
-	privateShareHex := privateShareData.(string)
-
-	publicKeyData, exists := keygenSession.Data["public_key"]
-	if !exists {
-		return nil, fmt.Errorf("no public key found in keygen session %s", keygenSessionID)
-	}
-	publicKeyHex := publicKeyData.(string)
-
-	// 获取Paillier密钥和Pedersen参数（这些应该在keygen后处理中生成）
-	paiPrivateData, exists := keygenSession.Data["paillier_private_key"]
-	if !exists {
-		return nil, fmt.Errorf("no Paillier private key found in keygen session %s", keygenSessionID)
-	}
-
-	E_x1Data, exists := keygenSession.Data["E_x1"]
-	if !exists {
-		return nil, fmt.Errorf("no E_x1 found in keygen session %s", keygenSessionID)
+		return nil, fmt.Errorf("failed to load key %s: %w", keyID, err)
 	}
-
-	pedParamsData, exists := keygenSession.Data["pedersen_params"]
-	if !exists {
-		return nil, fmt.Errorf("no Pedersen parameters found in keygen session %s", keygenSessionID)
+	if record.KeyData == nil || record.KeyData.PublicKey == nil {
+		return nil, fmt.Errorf("no key share found for key %s", keyID)
 	}
 
 	// 获取签名消息
@@ -1596,50 +1656,51 @@ func (m *MPCManager) createSignContext(session *Session) (*SignContext, error) {
 	if !exists {
 		return nil, fmt.Errorf("no message found in sign session %s", session.ID)
 	}
-	messageHex := messageData.(string)
-
-	// 重构数据
-	privateShareBytes, err := hex.DecodeString(privateShareHex)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode private share: %v", err)
-	}
-	privateShare := new(big.Int).SetBytes(privateShareBytes)
-
-	publicKeyBytes, err := hex.DecodeString(publicKeyHex)
+	messageHex, err := messageDigest(messageData.(string), sessionHashMode(session))
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode public key: %v", err)
+		return nil, fmt.Errorf("invalid message in sign session %s: %w", session.ID, err)
 	}
 
-	// 重构公钥
-	pubKeyX := new(big.Int).SetBytes(publicKeyBytes[:32])
-	pubKeyY := new(big.Int).SetBytes(publicKeyBytes[32:])
 	publicKey := &ecdsa.PublicKey{
 		Curve: secp256k1.S256(),
-		X:     pubKeyX,
-		Y:     pubKeyY,
+		X:     record.KeyData.PublicKey.X,
+		Y:     record.KeyData.PublicKey.Y,
 	}
 
-	// 创建签名上下文
+	// 创建签名上下文，指定path时对子密钥签名，P2把派生偏移量加到x2上
 	signCtx := &SignContext{}
+	path, _ := session.Data["path"].(string)
 
 	if m.isP1(session) {
-		// 重构P1需要的数据
-		paiPrivate := paiPrivateData.(*paillier.PrivateKey)
-		E_x1 := E_x1Data.(*big.Int)
-		pedParams := pedParamsData.(*pedersen.PedersenParameters)
-
-		signCtx.P1 = sign.NewP1(publicKey, messageHex, paiPrivate, E_x1, pedParams)
+		if record.PaiPriKey == nil || record.E_x1 == nil || record.Ped == nil {
+			return nil, fmt.Errorf("no P1 key material found for key %s", keyID)
+		}
+		if path == "" {
+			signCtx.P1 = sign.NewP1(publicKey, messageHex, record.PaiPriKey, record.E_x1, record.Ped)
+		} else {
+			tssKey, err := deriveTssKey(nil, record.KeyData, path)
+			if err != nil {
+				return nil, err
+			}
+			signCtx.P1 = sign.NewP1WithTssKey(tssKey, messageHex, record.PaiPriKey, record.E_x1, record.Ped)
+		}
 	} else {
-		// 重构P2需要的数据
-		E_x1 := E_x1Data.(*big.Int)
-		paiPubData, exists := keygenSession.Data["paillier_public_key"]
-		if !exists {
-			return nil, fmt.Errorf("no Paillier public key found in keygen session %s", keygenSessionID)
+		p2SaveData := record.P2SaveData
+		if p2SaveData == nil {
+			return nil, fmt.Errorf("no P2 key material found for key %s", keyID)
 		}
-		paiPub := paiPubData.(*paillier.PublicKey)
-		pedParams := pedParamsData.(*pedersen.PedersenParameters)
-
-		signCtx.P2 = sign.NewP2(privateShare, E_x1, publicKey, paiPub, messageHex, pedParams)
+		if path == "" {
+			signCtx.P2 = sign.NewP2(p2SaveData.X2, p2SaveData.E_x1, publicKey, p2SaveData.PaiPubKey, messageHex, p2SaveData.Ped1)
+		} else {
+			tssKey, err := deriveTssKey(p2SaveData.X2, record.KeyData, path)
+			if err != nil {
+				return nil, err
+			}
+			signCtx.P2 = sign.NewP2WithTssKey(tssKey, p2SaveData.E_x1, p2SaveData.PaiPubKey, messageHex, p2SaveData.Ped1)
+		}
+	}
+	if signCtx.P1 == nil && signCtx.P2 == nil {
+		return nil, fmt.Errorf("invalid message in sign session %s", session.ID)
 	}
 
 	// 保存签名上下文到会话
@@ -1703,16 +1764,17 @@ func (m *MPCManager) broadcastSignMessage(sessionID string, round int, data inte
 }
 
 // notifySignComplete 通知签名完成
-func (m *MPCManager) notifySignComplete(sessionID string, r, s *big.Int) {
-	signature := fmt.Sprintf("%s%s", hex.EncodeToString(r.Bytes()), hex.EncodeToString(s.Bytes()))
-
+func (m *MPCManager) notifySignComplete(sessionID string, result *protocol.SignResultData) {
 	notification := map[string]interface{}{
-		"type":        "sign_complete",
-		"session_id":  sessionID,
-		"signature":   signature,
-		"signature_r": hex.EncodeToString(r.Bytes()),
-		"signature_s": hex.EncodeToString(s.Bytes()),
-		"timestamp":   time.Now().Format(time.RFC3339),
+		"type":              "sign_complete",
+		"session_id":        sessionID,
+		"signature":         result.Signature,
+		"signature_r":       result.R,
+		"signature_s":       result.S,
+		"signature_v":       *result.V,
+		"signature_compact": result.Compact,
+		"signature_der":     result.DER,
+		"timestamp":         time.Now().Format(time.RFC3339),
 	}
 
 	message := protocol.NewMessage(
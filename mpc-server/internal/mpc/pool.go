@@ -0,0 +1,28 @@
+package mpc
+
+import (
+	"github.com/okx/threshold-lib/crypto/paillier"
+	"github.com/okx/threshold-lib/tss/ecdsa/keygen"
+)
+
+// SetPool 设置Paillier密钥和预参数池
+func (m *MPCManager) SetPool(pool *keygen.Pool) {
+	m.pool = pool
+}
+
+// PaillierKey 从池中取出Paillier私钥，未设置池或池为空时现场生成
+func (m *MPCManager) PaillierKey() (*paillier.PrivateKey, error) {
+	if m.pool == nil {
+		paiPriKey, _, err := paillier.NewKeyPair(8)
+		return paiPriKey, err
+	}
+	return m.pool.PaillierKey()
+}
+
+// PreParams 从池中取出带Dln证明的预参数，未设置池或池为空时现场生成
+func (m *MPCManager) PreParams() (*keygen.PreParamsWithDlnProof, error) {
+	if m.pool == nil {
+		return keygen.GeneratePreParamsWithDlnProof(), nil
+	}
+	return m.pool.PreParams()
+}
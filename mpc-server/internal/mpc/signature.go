@@ -0,0 +1,88 @@
+package mpc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/okx/threshold-lib/crypto/prehash"
+	"github.com/okx/threshold-lib/tss/ecdsa/sign"
+	"mpc-server/internal/protocol"
+)
+
+// DefaultHashMode 未指定hash_mode时对消息做SHA-256
+const DefaultHashMode = prehash.SHA256
+
+// ParseHashMode 解析签名请求的hash_mode，为空时使用默认值，只支持ECDSA模式
+func ParseHashMode(name string) (prehash.Mode, error) {
+	if name == "" {
+		return DefaultHashMode, nil
+	}
+	mode, err := prehash.ParseMode(name)
+	if err != nil {
+		return "", err
+	}
+	if mode.IsEd25519() {
+		return "", fmt.Errorf("hash mode %s is not supported by ecdsa sign", name)
+	}
+	return mode, nil
+}
+
+// messageDigest 计算待签名摘要的hex编码，raw模式下消息为hex编码的32字节摘要，
+// 其他模式下以0x开头的消息按hex解码，否则按UTF-8文本处理
+func messageDigest(message string, mode prehash.Mode) (string, error) {
+	var data []byte
+	if mode == prehash.Raw || strings.HasPrefix(message, "0x") {
+		var err error
+		if data, err = hex.DecodeString(strings.TrimPrefix(message, "0x")); err != nil {
+			return "", fmt.Errorf("invalid hex message: %v", err)
+		}
+	} else {
+		data = []byte(message)
+	}
+	digest, err := prehash.Digest(mode, data)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest), nil
+}
+
+// sessionHashMode 会话的hash_mode，旧会话没有该字段时使用默认值
+func sessionHashMode(session *Session) prehash.Mode {
+	if mode, ok := session.Data["hash_mode"].(string); ok && mode != "" {
+		return prehash.Mode(mode)
+	}
+	return DefaultHashMode
+}
+
+// signatureKeys 会话中保存签名结果的字段
+var signatureKeys = []string{"signature", "signature_r", "signature_s", "signature_v", "signature_compact", "signature_der"}
+
+// newSignResult 由签名生成定长r/s、64字节r||s、65字节r||s||v和DER编码
+func newSignResult(sig *sign.Signature) (*protocol.SignResultData, error) {
+	der, err := sig.DER()
+	if err != nil {
+		return nil, err
+	}
+	bytes := sig.Bytes()
+	v := int(sig.V)
+	return &protocol.SignResultData{
+		Success:   true,
+		Signature: hex.EncodeToString(bytes),
+		R:         hex.EncodeToString(bytes[:32]),
+		S:         hex.EncodeToString(bytes[32:]),
+		V:         &v,
+		Compact:   hex.EncodeToString(sig.Compact()),
+		DER:       hex.EncodeToString(der),
+	}, nil
+}
+
+// saveSignResult 保存签名结果到会话数据, 调用方持有会话锁
+func saveSignResult(session *Session, result *protocol.SignResultData) {
+	session.Data["signature"] = result.Signature
+	session.Data["signature_r"] = result.R
+	session.Data["signature_s"] = result.S
+	session.Data["signature_v"] = *result.V
+	session.Data["signature_compact"] = result.Compact
+	session.Data["signature_der"] = result.DER
+}
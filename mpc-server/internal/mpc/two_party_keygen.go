@@ -0,0 +1,210 @@
+package mpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/okx/threshold-lib/crypto/pedersen"
+	"github.com/okx/threshold-lib/crypto/zkp"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/ecdsa/keygen"
+	"mpc-server/internal/keystore"
+	"mpc-server/internal/protocol"
+)
+
+// 2方签名密钥材料：DKG或重分享完成后，参与者列表前两位按keygen.P1/keygen.P2协商，
+// P1(Participants[0], id 1)保存Paillier私钥、E(x1)和Pedersen参数，P2(Participants[1], id 2)保存P2SaveData。
+// P2 --pre_params--> P1 --keygen_p1_data--> P2
+
+const (
+	twoPartyP1 = 1
+	twoPartyP2 = 2
+)
+
+// twoPartyPreParams P2的Pedersen参数和Dln证明
+type twoPartyPreParams struct {
+	Ped      *pedersen.PedersenParameters
+	DlnProof *zkp.DlnProof
+}
+
+// startTwoPartyKeygen 密钥分片保存后调用，P2发送预参数，P1处理分片保存前已收到的预参数
+func (m *MPCManager) startTwoPartyKeygen(session *Session) error {
+	participants := session.Participants
+	if len(participants) < 2 {
+		return nil
+	}
+	session.mu.Lock()
+	session.Data["two_party_ready"] = true
+	session.mu.Unlock()
+	switch m.serverID {
+	case participants[twoPartyP2-1]:
+		preParams, err := m.PreParams()
+		if err != nil {
+			return err
+		}
+		bytes, err := json.Marshal(&twoPartyPreParams{Ped: preParams.PedersonParameters(), DlnProof: preParams.Proof})
+		if err != nil {
+			return err
+		}
+		session.mu.Lock()
+		session.Data["two_party_pre_params"] = preParams
+		session.mu.Unlock()
+		msg := &tss.Message{From: twoPartyP2, To: twoPartyP1, Data: string(bytes)}
+		return m.sendTwoPartyMessage(session, protocol.MsgTypePreParams, participants[twoPartyP1-1], msg)
+	case participants[twoPartyP1-1]:
+		session.mu.Lock()
+		pending, ok := session.Data["pending_pre_params"].(*tss.Message)
+		delete(session.Data, "pending_pre_params")
+		session.mu.Unlock()
+		if ok {
+			return m.processTwoPartyPreParams(session, pending)
+		}
+	}
+	return nil
+}
+
+// ProcessTwoPartyKeygen 处理2方密钥材料协商消息，data.From为发送方服务器ID
+func (m *MPCManager) ProcessTwoPartyKeygen(sessionID string, msgType protocol.MessageType, data *protocol.KeygenRoundData) error {
+	session, err := m.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	var msg tss.Message
+	bytes, err := json.Marshal(data.Data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(bytes, &msg); err != nil {
+		return fmt.Errorf("invalid two-party keygen message: %w", err)
+	}
+	participants := session.Participants
+	if len(participants) < 2 {
+		return fmt.Errorf("session %s has no two-party signers", sessionID)
+	}
+
+	switch msgType {
+	case protocol.MsgTypePreParams:
+		if participants[twoPartyP1-1] != m.serverID || data.From != participants[twoPartyP2-1] {
+			return fmt.Errorf("unexpected pre-params from %s", data.From)
+		}
+		// 本地分片尚未保存时先缓存，保存后由startTwoPartyKeygen处理
+		session.mu.Lock()
+		saved, _ := session.Data["two_party_ready"].(bool)
+		if !saved {
+			session.Data["pending_pre_params"] = &msg
+		}
+		session.mu.Unlock()
+		if !saved {
+			return nil
+		}
+		err = m.processTwoPartyPreParams(session, &msg)
+	case protocol.MsgTypeKeygenP1Data:
+		if participants[twoPartyP2-1] != m.serverID || data.From != participants[twoPartyP1-1] {
+			return fmt.Errorf("unexpected P1 data from %s", data.From)
+		}
+		err = m.processTwoPartyP1Data(session, &msg)
+	default:
+		return fmt.Errorf("unknown two-party keygen message type %s", msgType)
+	}
+	if err != nil {
+		m.recordFailure(session, err)
+	}
+	return err
+}
+
+// processTwoPartyPreParams P1：验证P2的Dln证明，加密x1发给P2，保存P1材料
+func (m *MPCManager) processTwoPartyPreParams(session *Session, msg *tss.Message) error {
+	if msg.From != twoPartyP2 || msg.To != twoPartyP1 {
+		return fmt.Errorf("pre-params message mismatch")
+	}
+	var content twoPartyPreParams
+	if err := json.Unmarshal([]byte(msg.Data), &content); err != nil {
+		return fmt.Errorf("invalid pre-params: %w", err)
+	}
+	if content.Ped == nil || content.DlnProof == nil {
+		return fmt.Errorf("invalid pre-params")
+	}
+	keyID, record, err := m.sessionKey(session)
+	if err != nil {
+		return err
+	}
+	paiPriKey, err := m.PaillierKey()
+	if err != nil {
+		return err
+	}
+	preParams, err := m.PreParams()
+	if err != nil {
+		return err
+	}
+	p1Msg, E_x1, err := keygen.P1(record.KeyData.ShareI, paiPriKey, twoPartyP1, twoPartyP2, preParams,
+		content.Ped, content.DlnProof, record.KeyData.PublicKey.Curve)
+	if err != nil {
+		return fmt.Errorf("two-party keygen P1 failed: %w", err)
+	}
+	if err := m.StoreP1KeyMaterial(keyID, paiPriKey, E_x1, preParams.PedersonParameters()); err != nil {
+		return err
+	}
+	log.Printf("Stored P1 key material for key %s", keyID)
+	return m.sendTwoPartyMessage(session, protocol.MsgTypeKeygenP1Data, session.Participants[twoPartyP2-1], p1Msg)
+}
+
+// processTwoPartyP1Data P2：验证P1的证明，保存P2SaveData
+func (m *MPCManager) processTwoPartyP1Data(session *Session, msg *tss.Message) error {
+	session.mu.RLock()
+	preParams, ok := session.Data["two_party_pre_params"].(*keygen.PreParamsWithDlnProof)
+	session.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no pre-params sent in session %s", session.ID)
+	}
+	keyID, record, err := m.sessionKey(session)
+	if err != nil {
+		return err
+	}
+	p2SaveData, err := keygen.P2(record.KeyData.ShareI, record.KeyData.PublicKey, msg, twoPartyP1, twoPartyP2, preParams.PedersonParameters())
+	if err != nil {
+		return fmt.Errorf("two-party keygen P2 failed: %w", err)
+	}
+	if err := m.StoreP2SaveData(keyID, p2SaveData); err != nil {
+		return err
+	}
+	session.mu.Lock()
+	delete(session.Data, "two_party_pre_params")
+	session.mu.Unlock()
+	log.Printf("Stored P2 key material for key %s", keyID)
+	return nil
+}
+
+// sessionKey 会话保存的密钥分片
+func (m *MPCManager) sessionKey(session *Session) (string, *keystore.KeyRecord, error) {
+	session.mu.RLock()
+	keyID, ok := session.Data["key_id"].(string)
+	session.mu.RUnlock()
+	if !ok || keyID == "" {
+		return "", nil, fmt.Errorf("no key ID found for session %s", session.ID)
+	}
+	record, err := m.keyStore.Load(keyID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load key %s: %w", keyID, err)
+	}
+	if record.KeyData == nil || record.KeyData.ShareI == nil || record.KeyData.PublicKey == nil {
+		return "", nil, fmt.Errorf("no key share found for key %s", keyID)
+	}
+	return keyID, record, nil
+}
+
+// sendTwoPartyMessage 发送给另一方，格式与DKG轮次消息相同
+func (m *MPCManager) sendTwoPartyMessage(session *Session, msgType protocol.MessageType, to string, msg *tss.Message) error {
+	if m.peerClient == nil {
+		return fmt.Errorf("no peer client")
+	}
+	message := protocol.NewMessage(msgType, session.ID, m.serverID, to, protocol.KeygenRoundData{
+		Data: msg,
+		From: m.serverID,
+	})
+	bytes, err := message.ToJSON()
+	if err != nil {
+		return err
+	}
+	return m.peerClient.SendToPeer(to, bytes)
+}
@@ -0,0 +1,98 @@
+package mpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/tss"
+	"mpc-server/internal/keystore"
+	"mpc-server/internal/protocol"
+)
+
+// testPeers 直接把消息交给对方的MPCManager
+type testPeers map[string]*MPCManager
+
+func (p testPeers) SendToPeer(peerID string, message []byte) error {
+	msg, err := protocol.FromJSON(message)
+	if err != nil {
+		return err
+	}
+	var data protocol.KeygenRoundData
+	bytes, _ := json.Marshal(msg.Data)
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return err
+	}
+	return p[peerID].ProcessTwoPartyKeygen(msg.SessionID, msg.Type, &data)
+}
+
+func TestTwoPartyKeygen(t *testing.T) {
+	if testing.Short() {
+		t.Skip("pre params generation is slow")
+	}
+	curve := secp256k1.S256()
+	// f(x) = 42 + 5x
+	publicKey := curves.ScalarToPoint(curve, big.NewInt(42))
+	participants := []string{"third-party", "enterprise"}
+	peers := testPeers{}
+	sessions := make(map[string]*Session)
+	keyIDs := make(map[string]string)
+	for i, id := range participants {
+		m := NewMPCManager(id, peers, nil, keystore.NewMemoryKeyStore())
+		peers[id] = m
+		keyID, err := m.saveKeyShare(&tss.KeyStep3Data{
+			Id:        i + 1,
+			ShareI:    big.NewInt(42 + 5*int64(i+1)),
+			PublicKey: publicKey,
+			ChainCode: testChainCode,
+		}, 2, participants)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyIDs[id] = keyID
+		sessions[id] = &Session{
+			ID:           "keygen-1",
+			Type:         TypeKeygen,
+			Participants: participants,
+			Threshold:    2,
+			Data:         map[string]interface{}{"key_id": keyID},
+		}
+		if err := m.SetSession(sessions[id]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// P2先完成DKG，预参数在P1保存分片前到达，先缓存
+	if err := peers["enterprise"].startTwoPartyKeygen(sessions["enterprise"]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sessions["third-party"].Data["pending_pre_params"]; !ok {
+		t.Fatal("early pre-params not buffered")
+	}
+	if err := peers["third-party"].startTwoPartyKeygen(sessions["third-party"]); err != nil {
+		t.Fatal(err)
+	}
+
+	p1, err := peers["third-party"].LoadKey(keyIDs["third-party"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p1.PaiPriKey == nil || p1.E_x1 == nil || p1.Ped == nil {
+		t.Fatal("P1 key material not stored")
+	}
+	p2, err := peers["enterprise"].LoadKey(keyIDs["enterprise"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p2.P2SaveData == nil || p2.P2SaveData.E_x1.Cmp(p1.E_x1) != 0 {
+		t.Fatal("P2 save data not stored")
+	}
+
+	// 非P1/P2的发送方被拒绝
+	data := &protocol.KeygenRoundData{From: "mobile-app", Data: &tss.Message{From: 2, To: 1}}
+	if err := peers["third-party"].ProcessTwoPartyKeygen("keygen-1", protocol.MsgTypePreParams, data); err == nil {
+		t.Fatal("pre-params from a stranger accepted")
+	}
+}
@@ -92,8 +92,11 @@ type ReshareResultData struct {
 
 // SignInitData 签名初始化数据
 type SignInitData struct {
-	SessionID string   `json:"session_id"`
+	SessionID string   `json:"session_id,omitempty"` // keygen会话ID，未指定KeyID时用于查找KeyID
+	KeyID     string   `json:"key_id,omitempty"`     // 密钥ID，服务重启后仍可签名
 	Message   string   `json:"message"`
+	HashMode  string   `json:"hash_mode,omitempty"` // 消息哈希模式，默认sha256
+	Path      string   `json:"path,omitempty"`      // bip32非硬化派生路径，为空时用根密钥签名
 	Signers   []string `json:"signers"`
 }
 
@@ -130,6 +133,7 @@ type SignRound3Data struct {
 	// P1计算的最终签名
 	R *string `json:"r,omitempty"`
 	S *string `json:"s,omitempty"`
+	V *int    `json:"v,omitempty"`
 }
 
 // 辅助数据结构
@@ -160,10 +164,16 @@ type AffineProofData struct {
 	S string `json:"s"`
 }
 
-// SignResultData 签名结果数据
+// SignResultData 签名结果数据, r/s为32字节定长hex, signature为64字节r||s,
+// compact为65字节r||s||v (v为0或1), der为ASN.1 DER编码
 type SignResultData struct {
 	Success   bool   `json:"success"`
 	Signature string `json:"signature,omitempty"`
+	R         string `json:"r,omitempty"`
+	S         string `json:"s,omitempty"`
+	V         *int   `json:"v,omitempty"`
+	Compact   string `json:"compact,omitempty"`
+	DER       string `json:"der,omitempty"`
 	Error     string `json:"error,omitempty"`
 }
 
@@ -33,6 +33,11 @@ start_server() {
     sleep 1
 }
 
+# 演示脚本，未设置口令时显式使用内存密钥存储，重启后密钥丢失
+if [ -z "$MPC_KEYSTORE_PASSPHRASE" ]; then
+    export MPC_KEYSTORE=${MPC_KEYSTORE:-memory}
+fi
+
 # 创建日志目录
 mkdir -p logs
 
@@ -4,6 +4,11 @@
 
 echo "=== 启动MPC服务器集群 ==="
 
+# 演示脚本，未设置口令时显式使用内存密钥存储，重启后密钥丢失
+if [ -z "$MPC_KEYSTORE_PASSPHRASE" ]; then
+    export MPC_KEYSTORE=${MPC_KEYSTORE:-memory}
+fi
+
 # 创建日志目录
 mkdir -p logs
 
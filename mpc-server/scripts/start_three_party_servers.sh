@@ -5,6 +5,11 @@
 
 echo "启动三方MPC服务器演示..."
 
+# 演示脚本，未设置口令时显式使用内存密钥存储，重启后密钥丢失
+if [ -z "$MPC_KEYSTORE_PASSPHRASE" ]; then
+    export MPC_KEYSTORE=${MPC_KEYSTORE:-memory}
+fi
+
 # 检查是否已有服务器在运行
 check_port() {
     local port=$1
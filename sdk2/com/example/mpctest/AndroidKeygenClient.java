@@ -2,6 +2,7 @@ package com.example.mpctest;
 
 import android.content.Context;
 import android.util.Log;
+import java.util.UUID;
 import java.util.concurrent.CompletableFuture;
 import java.util.concurrent.ExecutorService;
 import java.util.concurrent.Executors;
@@ -58,12 +59,13 @@ public class AndroidKeygenClient {
     /**
      * 异步执行密钥生成
      * @param curve 曲线类型
+     * @param sessionId 协调方分发的会话ID，各方一致且每次协议唯一
      * @param callback 回调接口
      */
-    public void generateKeyAsync(int curve, KeygenCallback callback) {
+    public void generateKeyAsync(int curve, String sessionId, KeygenCallback callback) {
         executor.submit(() -> {
             try {
-                generateKey(curve, callback);
+                generateKey(curve, sessionId, callback);
             } catch (Exception e) {
                 Log.e(TAG, "密钥生成异常", e);
                 callback.onError("密钥生成过程中发生异常: " + e.getMessage());
@@ -74,16 +76,17 @@ public class AndroidKeygenClient {
     /**
      * 同步执行密钥生成（主要用于测试）
      * @param curve 曲线类型
+     * @param sessionId 会话ID
      * @param callback 回调接口
      */
-    private void generateKey(int curve, KeygenCallback callback) {
+    private void generateKey(int curve, String sessionId, KeygenCallback callback) {
         Log.i(TAG, "🔐 开始Android MPC密钥生成");
         callback.onProgress("开始密钥生成...");
         
         try {
             // 步骤1：初始化
             callback.onProgress("初始化密钥生成会话...");
-            sessionHandle = MPCNative.keygenInit(curve, myRole, THRESHOLD, TOTAL_PARTIES);
+            sessionHandle = MPCNative.keygenInit(curve, myRole, THRESHOLD, TOTAL_PARTIES, sessionId);
             if (sessionHandle == 0) {
                 String error = MPCNative.getErrorString(-1);
                 throw new RuntimeException("初始化失败: " + error);
@@ -230,7 +233,7 @@ public class AndroidKeygenClient {
         
         AndroidKeygenClient client = new AndroidKeygenClient(context, ROLE_ANDROID);
         
-        client.generateKeyAsync(CURVE_ED25519, new KeygenCallback() {
+        client.generateKeyAsync(CURVE_ED25519, UUID.randomUUID().toString(), new KeygenCallback() {
             @Override
             public void onProgress(String message) {
                 Log.i("AndroidKeygenClient", "进度: " + message);
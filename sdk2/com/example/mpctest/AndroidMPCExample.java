@@ -1,6 +1,7 @@
 package com.example.mpctest;
 
 import java.util.Arrays;
+import java.util.UUID;
 
 /**
  * Android MPC客户端示例
@@ -29,13 +30,14 @@ public class AndroidMPCExample {
     /**
      * 执行三方密钥生成
      * @param curve 曲线类型
+     * @param sessionId 协调方分发的会话ID，各方一致且每次协议唯一
      * @return 是否成功
      */
-    public boolean performKeyGeneration(int curve) {
+    public boolean performKeyGeneration(int curve, String sessionId) {
         System.out.println("开始三方密钥生成，当前方ID: " + myPartyID);
         
         // 1. 初始化密钥生成
-        long handle = MPCNative.keygenInit(curve, myPartyID, THRESHOLD, TOTAL_PARTIES);
+        long handle = MPCNative.keygenInit(curve, myPartyID, THRESHOLD, TOTAL_PARTIES, sessionId);
         if (handle == 0) {
             System.err.println("密钥生成初始化失败");
             return false;
@@ -83,9 +85,10 @@ public class AndroidMPCExample {
     /**
      * 执行密钥刷新
      * @param curve 曲线类型
+     * @param sessionId 协调方分发的会话ID，各方一致且每次协议唯一
      * @return 是否成功
      */
-    public boolean performKeyRefresh(int curve) {
+    public boolean performKeyRefresh(int curve, String sessionId) {
         if (keyData == null) {
             System.err.println("没有可用的密钥数据");
             return false;
@@ -97,7 +100,7 @@ public class AndroidMPCExample {
         int[] devoteList = {PARTY_SERVER, PARTY_THIRD_PARTY, PARTY_ANDROID};
         
         // 1. 初始化密钥刷新
-        long handle = MPCNative.refreshInit(curve, myPartyID, THRESHOLD, devoteList, keyData);
+        long handle = MPCNative.refreshInit(curve, myPartyID, THRESHOLD, devoteList, keyData, sessionId);
         if (handle == 0) {
             System.err.println("密钥刷新初始化失败");
             return false;
@@ -148,9 +151,10 @@ public class AndroidMPCExample {
     /**
      * 执行Ed25519两方签名
      * @param message 待签名消息
+     * @param sessionId 协调方分发的会话ID，各方一致且每次签名唯一
      * @return 签名结果 [r, s]
      */
-    public String[] performEd25519Signing(byte[] message) {
+    public String[] performEd25519Signing(byte[] message, String sessionId) {
         if (keyData == null) {
             System.err.println("没有可用的密钥数据");
             return null;
@@ -162,7 +166,7 @@ public class AndroidMPCExample {
         int[] partList = {PARTY_SERVER, PARTY_ANDROID};
         
         // 1. 初始化签名
-        long handle = MPCNative.ed25519SignInit(myPartyID, THRESHOLD, partList, keyData, message);
+        long handle = MPCNative.ed25519SignInit(myPartyID, THRESHOLD, partList, keyData, message, sessionId);
         if (handle == 0) {
             System.err.println("Ed25519签名初始化失败");
             return null;
@@ -285,7 +289,7 @@ public class AndroidMPCExample {
         try {
             // 1. 执行三方密钥生成
             System.out.println("=== 开始三方密钥生成 ===");
-            boolean keygenSuccess = client.performKeyGeneration(CURVE_ED25519);
+            boolean keygenSuccess = client.performKeyGeneration(CURVE_ED25519, UUID.randomUUID().toString());
             if (!keygenSuccess) {
                 System.err.println("密钥生成失败");
                 return;
@@ -293,7 +297,7 @@ public class AndroidMPCExample {
             
             // 2. 执行密钥刷新
             System.out.println("\n=== 开始密钥刷新 ===");
-            boolean refreshSuccess = client.performKeyRefresh(CURVE_ED25519);
+            boolean refreshSuccess = client.performKeyRefresh(CURVE_ED25519, UUID.randomUUID().toString());
             if (!refreshSuccess) {
                 System.err.println("密钥刷新失败");
                 return;
@@ -302,7 +306,7 @@ public class AndroidMPCExample {
             // 3. 执行Ed25519签名
             System.out.println("\n=== 开始Ed25519签名 ===");
             String message = "Hello, MPC World!";
-            String[] signature = client.performEd25519Signing(message.getBytes());
+            String[] signature = client.performEd25519Signing(message.getBytes(), UUID.randomUUID().toString());
             if (signature == null) {
                 System.err.println("Ed25519签名失败");
                 return;
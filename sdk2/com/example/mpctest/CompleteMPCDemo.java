@@ -1,6 +1,7 @@
 package com.example.mpctest;
 
 import java.util.Arrays;
+import java.util.UUID;
 
 /**
  * 完整的MPC演示程序
@@ -52,9 +53,10 @@ public class CompleteMPCDemo {
         byte[][] keys = new byte[TOTAL_PARTIES][];
         
         try {
-            // 初始化所有参与方
+            // 初始化所有参与方，会话ID各方共享
+            String sessionId = UUID.randomUUID().toString();
             for (int i = 0; i < TOTAL_PARTIES; i++) {
-                handles[i] = MPCNative.keygenInit(SECP256K1, i + 1, THRESHOLD, TOTAL_PARTIES);
+                handles[i] = MPCNative.keygenInit(SECP256K1, i + 1, THRESHOLD, TOTAL_PARTIES, sessionId);
                 if (handles[i] == 0) {
                     throw new RuntimeException("参与方 " + (i + 1) + " 初始化失败");
                 }
@@ -224,9 +226,10 @@ public class CompleteMPCDemo {
         byte[][] keys = new byte[TOTAL_PARTIES][];
         
         try {
-            // 初始化所有参与方
+            // 初始化所有参与方，会话ID各方共享
+            String sessionId = UUID.randomUUID().toString();
             for (int i = 0; i < TOTAL_PARTIES; i++) {
-                handles[i] = MPCNative.keygenInit(ED25519, i + 1, THRESHOLD, TOTAL_PARTIES);
+                handles[i] = MPCNative.keygenInit(ED25519, i + 1, THRESHOLD, TOTAL_PARTIES, sessionId);
                 if (handles[i] == 0) {
                     throw new RuntimeException("Ed25519参与方 " + (i + 1) + " 初始化失败");
                 }
@@ -294,9 +297,10 @@ public class CompleteMPCDemo {
         long p1Handle = 0, p2Handle = 0;
         
         try {
-            // 初始化签名
-            p1Handle = MPCNative.ed25519SignInit(1, THRESHOLD, partList, p1Key, messageBytes);
-            p2Handle = MPCNative.ed25519SignInit(2, THRESHOLD, partList, p2Key, messageBytes);
+            // 初始化签名，会话ID各方共享
+            String sessionId = UUID.randomUUID().toString();
+            p1Handle = MPCNative.ed25519SignInit(1, THRESHOLD, partList, p1Key, messageBytes, sessionId);
+            p2Handle = MPCNative.ed25519SignInit(2, THRESHOLD, partList, p2Key, messageBytes, sessionId);
             
             if (p1Handle == 0 || p2Handle == 0) {
                 throw new RuntimeException("Ed25519签名初始化失败");
@@ -365,9 +369,10 @@ public class CompleteMPCDemo {
         byte[][] newKeys = new byte[TOTAL_PARTIES][];
         
         try {
-            // 初始化刷新
+            // 初始化刷新，会话ID各方共享
+            String sessionId = UUID.randomUUID().toString();
             for (int i = 0; i < TOTAL_PARTIES; i++) {
-                handles[i] = MPCNative.refreshInit(SECP256K1, i + 1, THRESHOLD, devoteList, originalKeys[i]);
+                handles[i] = MPCNative.refreshInit(SECP256K1, i + 1, THRESHOLD, devoteList, originalKeys[i], sessionId);
                 if (handles[i] == 0) {
                     throw new RuntimeException("参与方 " + (i + 1) + " 刷新初始化失败");
                 }
@@ -2,6 +2,7 @@ package com.example.mpctest;
 
 import java.util.ArrayList;
 import java.util.List;
+import java.util.UUID;
 import java.util.concurrent.CompletableFuture;
 import java.util.concurrent.ExecutorService;
 import java.util.concurrent.Executors;
@@ -94,9 +95,10 @@ public class KeygenDemo {
     private boolean initializeParties(int curve) {
         Log.i(TAG, "📋 第一步：初始化参与方");
         
+        String sessionId = UUID.randomUUID().toString(); // 各方共享的会话ID
         for (int i = 0; i < 3; i++) {
             int partyId = i + 1;
-            handles[i] = MPCNative.keygenInit(curve, partyId, THRESHOLD, TOTAL_PARTIES);
+            handles[i] = MPCNative.keygenInit(curve, partyId, THRESHOLD, TOTAL_PARTIES, sessionId);
             
             if (handles[i] == 0) {
                 String error = MPCNative.getErrorString(-1);
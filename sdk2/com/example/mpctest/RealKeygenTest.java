@@ -1,5 +1,7 @@
 package com.example.mpctest;
 
+import java.util.UUID;
+
 /**
  * 真正的MPC密钥生成测试 - 直接调用C库
  * 基于test_corrected_keygen.c的逻辑
@@ -35,8 +37,9 @@ public class RealKeygenTest {
             
             // 初始化三个参与方
             long[] handles = new long[3];
+            String sessionId = UUID.randomUUID().toString(); // 各方共享的会话ID
             for (int i = 0; i < 3; i++) {
-                handles[i] = MPCNative.keygenInit(curve, i + 1, 2, 3);
+                handles[i] = MPCNative.keygenInit(curve, i + 1, 2, 3, sessionId);
                 if (handles[i] == 0) {
                     throw new RuntimeException("参与方 " + (i + 1) + " 初始化失败");
                 }
@@ -1,5 +1,7 @@
 package com.example.mpctest;
 
+import java.util.UUID;
+
 /**
  * 简单的MPC密钥生成测试
  * 基于test_corrected_keygen.c的逻辑
@@ -57,9 +59,10 @@ public class SimpleKeygenTest {
         try {
             // 步骤1：初始化三个参与方
             System.out.println("📋 步骤1：初始化参与方");
+            String sessionId = UUID.randomUUID().toString(); // 各方共享的会话ID
             for (int i = 0; i < 3; i++) {
                 int partyId = i + 1;
-                handles[i] = MPCNative.keygenInit(curve, partyId, THRESHOLD, TOTAL_PARTIES);
+                handles[i] = MPCNative.keygenInit(curve, partyId, THRESHOLD, TOTAL_PARTIES, sessionId);
                 
                 if (handles[i] == 0) {
                     System.err.println("❌ 参与方" + partyId + "初始化失败");
@@ -98,12 +98,12 @@ typedef struct { void *data; GoInt len; GoInt cap; } GoSlice;
 extern "C" {
 #endif
 
-extern int go_keygen_init(GoInt curve, GoInt partyID, GoInt threshold, GoInt totalParties, void** handle);
+extern int go_keygen_init(GoInt curve, GoInt partyID, GoInt threshold, GoInt totalParties, char* session_id, int session_id_len, void** handle);
 extern int go_keygen_round1(void* handle, char** outData, int* outLen);
 extern int go_keygen_round2(void* handle, char* inData, int inLen, char** outData, int* outLen);
 extern int go_keygen_round3(void* handle, char* inData, int inLen, char** keyData, int* keyLen);
 extern void go_keygen_destroy(void* handle);
-extern int go_refresh_init(GoInt curve, GoInt partyID, GoInt threshold, int* devoteList, int devoteCount, char* keyData, int keyLen, void** handle);
+extern int go_refresh_init(GoInt curve, GoInt partyID, GoInt threshold, int* devoteList, int devoteCount, char* keyData, int keyLen, char* session_id, int session_id_len, void** handle);
 extern int go_refresh_round1(void* handle, char** outData, int* outLen);
 extern int go_refresh_round2(void* handle, char* inData, int inLen, char** outData, int* outLen);
 extern int go_refresh_round3(void* handle, char* inData, int inLen, char** keyData, int* keyLen);
@@ -116,7 +116,8 @@ extern int go_ecdsa_sign_p1_step2(void* handle, char* proofData, int proofLen, c
 extern int go_ecdsa_sign_p2_step2(void* handle, char* cmtDData, int cmtDLen, char* p1ProofData, int p1ProofLen, char** ekData, int* ekLen, char** affineProofData, int* affineProofLen);
 extern int go_ecdsa_sign_p1_step3(void* handle, char* ekData, int ekLen, char* affineProofData, int affineProofLen, char** rData, int* rLen, char** sData, int* sLen);
 extern void go_ecdsa_sign_destroy(void* handle);
-extern int go_ed25519_sign_init(int party_id, int threshold, int* part_list, int part_count, char* key_data, int key_len, char* message, int message_len, void** handle);
+extern int go_ed25519_sign_init(int party_id, int threshold, int* part_list, int part_count, char* key_data, int key_len, char* message, int message_len, char* session_id, int session_id_len, void** handle);
+extern int go_ed25519_sign_init_with_mode(int party_id, int threshold, int* part_list, int part_count, char* key_data, int key_len, char* message, int message_len, char* mode, int mode_len, char* context, int context_len, char* session_id, int session_id_len, void** handle);
 extern int go_ed25519_sign_round1(void* handle, char** out_data, int* out_len);
 extern int go_ed25519_sign_round2(void* handle, char* in_data, int in_len, char** out_data, int* out_len);
 extern int go_ed25519_sign_round3(void* handle, char* in_data, int in_len, char** sig_r, char** sig_s);
@@ -126,6 +127,10 @@ extern void mpc_string_free(char* str);
 extern void* mpc_message_array_alloc(int count);
 extern void mpc_message_array_free(void* messages);
 extern char* mpc_get_error_string(int error_code);
+extern int go_pool_init(int size);
+extern void go_pool_close();
+extern int go_message_digest(char* mode, int mode_len, char* message, int message_len, char** digest, int* digest_len);
+extern int go_address(int curve, char* format, int format_len, char* public_key, int public_key_len, char** out, int* out_len);
 extern int go_ecdsa_keygen_generate_p2_params(char** out_data, int* out_len);
 extern int go_ecdsa_keygen_p1(char* key_data, int key_len, int peer_id, char* p2_params, int p2_params_len, char** out_data, int* out_len, char** message_data, int* message_len);
 extern int go_ecdsa_keygen_p2(char* key_data, int key_len, int p1_id, char* p1_message, int p1_msg_len, char* p2_params, int p2_params_len, char** out_data, int* out_len);
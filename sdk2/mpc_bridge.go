@@ -40,9 +40,11 @@ import (
 	"github.com/okx/threshold-lib/crypto/curves"
 	"github.com/okx/threshold-lib/crypto/paillier"
 	"github.com/okx/threshold-lib/crypto/pedersen"
+	"github.com/okx/threshold-lib/crypto/prehash"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/crypto/zkp"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/address"
 	"github.com/okx/threshold-lib/tss/ecdsa/keygen"
 	"github.com/okx/threshold-lib/tss/ecdsa/sign"
 	ed25519Sign "github.com/okx/threshold-lib/tss/ed25519/sign"
@@ -181,16 +183,36 @@ func convertMessagesFromC(cArray *C.MPCMessageArray) []*tss.Message {
 // 密钥生成相关函数
 // ================================
 
-//export go_keygen_init
-func go_keygen_init(curve int, partyID int, threshold int, totalParties int, handle *unsafe.Pointer) C.int {
-	var curveType elliptic.Curve
-	if curve == 0 {
-		curveType = secp256k1.S256()
-	} else {
-		curveType = edwards.Edwards()
+// curveByType C接口的曲线类型，0为secp256k1，2为P-256，其余为Ed25519
+func curveByType(curve int) elliptic.Curve {
+	switch curve {
+	case 0:
+		return secp256k1.S256()
+	case 2:
+		return elliptic.P256()
+	default:
+		return edwards.Edwards()
+	}
+}
+
+// sessionIdFromC 调用方传入的会话ID，各方一致且每次协议唯一（如协调方生成的uuid），为空时返回nil
+func sessionIdFromC(sessionId *C.char, sessionIdLen C.int) *big.Int {
+	if sessionId == nil || sessionIdLen <= 0 {
+		return nil
 	}
+	return tss.SessionId(C.GoStringN(sessionId, sessionIdLen))
+}
+
+//export go_keygen_init
+func go_keygen_init(curve int, partyID int, threshold int, totalParties int, session_id *C.char, session_id_len C.int, handle *unsafe.Pointer) C.int {
+	curveType := curveByType(curve)
 
-	setUp := dkg.NewSetUp(partyID, totalParties, curveType)
+	// 会话ID绑定到所有承诺和证明，为空时拒绝
+	sessionId := sessionIdFromC(session_id, session_id_len)
+	if sessionId == nil {
+		return -8
+	}
+	setUp := dkg.NewSetUp(sessionId, partyID, threshold, totalParties, curveType)
 	sessionID := addSession(unsafe.Pointer(setUp), "keygen")
 	*handle = unsafe.Pointer(uintptr(sessionID))
 
@@ -311,29 +333,29 @@ func go_keygen_destroy(handle unsafe.Pointer) {
 // ================================
 
 //export go_refresh_init
-func go_refresh_init(curve int, partyID int, threshold int, devoteList *C.int, devoteCount C.int, keyData *C.char, keyLen C.int, handle *unsafe.Pointer) C.int {
+func go_refresh_init(curve int, partyID int, threshold int, devoteList *C.int, devoteCount C.int, keyData *C.char, keyLen C.int,
+	session_id *C.char, session_id_len C.int, handle *unsafe.Pointer) C.int {
+	sessionId := sessionIdFromC(session_id, session_id_len)
+	if sessionId == nil {
+		return -8
+	}
+
 	// 转换参数
 	goPartyID := int(partyID)
 	goDevoteCount := int(devoteCount)
 
-	// 转换devoteList - NewRefresh需要[2]int类型
+	// 转换devoteList，贡献者数量不少于门限
+	if goDevoteCount < 2 || goDevoteCount < threshold {
+		return -1
+	}
 	devoteSlice := (*[1 << 30]C.int)(unsafe.Pointer(devoteList))[:goDevoteCount:goDevoteCount]
-	var goDevoteList [2]int
-	for i := 0; i < goDevoteCount && i < 2; i++ {
+	goDevoteList := make([]int, goDevoteCount)
+	for i := 0; i < goDevoteCount; i++ {
 		goDevoteList[i] = int(devoteSlice[i])
 	}
-	// 如果只有一个元素，第二个元素设为0
-	if goDevoteCount == 1 {
-		goDevoteList[1] = 0
-	}
 
 	// 确定曲线类型
-	var curveType elliptic.Curve
-	if curve == 0 {
-		curveType = secp256k1.S256()
-	} else {
-		curveType = edwards.Edwards()
-	}
+	curveType := curveByType(curve)
 
 	// 解析密钥数据 - 必须成功解析真实的keygen数据
 	goKeyData := C.GoStringN(keyData, keyLen)
@@ -355,7 +377,10 @@ func go_refresh_init(curve int, partyID int, threshold int, devoteList *C.int, d
 
 	// 创建refresh实例 (假设总共3个参与方)
 	totalParties := 3
-	refresh := reshare.NewRefresh(goPartyID, totalParties, goDevoteList, saveData.ShareI, saveData.PublicKey)
+	if threshold < 2 || threshold > totalParties {
+		return -1
+	}
+	refresh := reshare.NewRefresh(sessionId, goPartyID, threshold, totalParties, goDevoteList, saveData.ShareI, saveData.PublicKey)
 	if refresh == nil {
 		return -5
 	}
@@ -526,10 +551,9 @@ func go_ecdsa_sign_init_p1_complex(partyID C.int, peerID C.int, keyData *C.char,
 	// 解析消息
 	goMessage := C.GoStringN(message, messageLen)
 
-	// 创建公钥 - 使用secp256k1曲线
-	curve := secp256k1.S256()
+	// 创建公钥 - 使用密钥所在的曲线，secp256k1或P-256
 	pubKey := &ecdsa.PublicKey{
-		Curve: curve,
+		Curve: ecdsaSignData.KeyStep3Data.PublicKey.Curve,
 		X:     ecdsaSignData.KeyStep3Data.PublicKey.X,
 		Y:     ecdsaSignData.KeyStep3Data.PublicKey.Y,
 	}
@@ -576,10 +600,9 @@ func go_ecdsa_sign_init_p2_complex(partyID C.int, peerID C.int, keyData *C.char,
 	// 解析消息
 	goMessage := C.GoStringN(message, messageLen)
 
-	// 创建公钥 - 使用secp256k1曲线
-	curve := secp256k1.S256()
+	// 创建公钥 - 使用密钥所在的曲线，secp256k1或P-256
 	pubKey := &ecdsa.PublicKey{
-		Curve: curve,
+		Curve: ecdsaSignData.KeyStep3Data.PublicKey.Curve,
 		X:     ecdsaSignData.KeyStep3Data.PublicKey.X,
 		Y:     ecdsaSignData.KeyStep3Data.PublicKey.Y,
 	}
@@ -866,7 +889,31 @@ func go_ecdsa_sign_destroy(handle unsafe.Pointer) {
 // ================================
 
 //export go_ed25519_sign_init
-func go_ed25519_sign_init(party_id C.int, threshold C.int, part_list *C.int, part_count C.int, key_data *C.char, key_len C.int, message *C.char, message_len C.int, handle *unsafe.Pointer) C.int {
+func go_ed25519_sign_init(party_id C.int, threshold C.int, part_list *C.int, part_count C.int, key_data *C.char, key_len C.int, message *C.char, message_len C.int,
+	session_id *C.char, session_id_len C.int, handle *unsafe.Pointer) C.int {
+	return ed25519SignInit(party_id, threshold, part_list, part_count, key_data, key_len, message, message_len, nil, session_id, session_id_len, handle)
+}
+
+// go_ed25519_sign_init_with_mode mode为ed25519/ed25519ph/ed25519ctx，context为RFC 8032上下文
+//
+//export go_ed25519_sign_init_with_mode
+func go_ed25519_sign_init_with_mode(party_id C.int, threshold C.int, part_list *C.int, part_count C.int, key_data *C.char, key_len C.int, message *C.char, message_len C.int,
+	mode *C.char, mode_len C.int, context *C.char, context_len C.int, session_id *C.char, session_id_len C.int, handle *unsafe.Pointer) C.int {
+	hashMode, err := prehash.ParseMode(C.GoStringN(mode, mode_len))
+	if err != nil || !hashMode.IsEd25519() {
+		return -7 // 不支持的哈希模式
+	}
+	options := &prehash.Ed25519Options{Mode: hashMode, Context: []byte(C.GoStringN(context, context_len))}
+	return ed25519SignInit(party_id, threshold, part_list, part_count, key_data, key_len, message, message_len, options, session_id, session_id_len, handle)
+}
+
+func ed25519SignInit(party_id C.int, threshold C.int, part_list *C.int, part_count C.int, key_data *C.char, key_len C.int, message *C.char, message_len C.int,
+	options *prehash.Ed25519Options, session_id *C.char, session_id_len C.int, handle *unsafe.Pointer) C.int {
+	sessionId := sessionIdFromC(session_id, session_id_len)
+	if sessionId == nil {
+		return -8 // 会话ID为空
+	}
+
 	// Convert C parameters to Go
 	partyID := int(party_id)
 	thresh := int(threshold)
@@ -898,7 +945,7 @@ func go_ed25519_sign_init(party_id C.int, threshold C.int, part_list *C.int, par
 	publicKey := edwards.NewPublicKey(keyStep3Data.PublicKey.X, keyStep3Data.PublicKey.Y)
 
 	// Create Ed25519 sign instance
-	ed25519SignInstance := ed25519Sign.NewEd25519Sign(partyID, thresh, partList, keyStep3Data.ShareI, publicKey, messageStr)
+	ed25519SignInstance := ed25519Sign.NewEd25519Sign(sessionId, partyID, thresh, partList, keyStep3Data.ShareI, publicKey, messageStr, options)
 	if ed25519SignInstance == nil {
 		return -6
 	}
@@ -1103,6 +1150,8 @@ func mpc_get_error_string(error_code C.int) *C.char {
 		return C.CString("Network error")
 	case -5:
 		return C.CString("Timeout error")
+	case -8:
+		return C.CString("Empty session id")
 	default:
 		return C.CString("Unknown error")
 	}
@@ -1112,10 +1161,73 @@ func mpc_get_error_string(error_code C.int) *C.char {
 // ECDSA Keygen 相关函数
 // ================================
 
+var (
+	paramsPool      *keygen.Pool
+	paramsPoolMutex sync.RWMutex
+)
+
+// go_pool_init 启动后台预生成Paillier私钥和预参数的池，池中含有秘密素数，只保存在内存中
+//
+//export go_pool_init
+func go_pool_init(size C.int) C.int {
+	paramsPoolMutex.Lock()
+	defer paramsPoolMutex.Unlock()
+
+	if paramsPool != nil {
+		paramsPool.Close()
+		paramsPool = nil
+	}
+	pool, err := keygen.NewPool(nil, int(size), func(err error) {
+		log.Printf("paillier pool: %v", err)
+	})
+	if err != nil {
+		return -1 // 参数错误
+	}
+	paramsPool = pool
+	return 0
+}
+
+//export go_pool_close
+func go_pool_close() {
+	paramsPoolMutex.Lock()
+	defer paramsPoolMutex.Unlock()
+
+	if paramsPool != nil {
+		paramsPool.Close()
+		paramsPool = nil
+	}
+}
+
+// 从池中取Paillier私钥，未初始化池时现场生成
+func poolPaillierKey() (*paillier.PrivateKey, error) {
+	paramsPoolMutex.RLock()
+	defer paramsPoolMutex.RUnlock()
+
+	if paramsPool == nil {
+		paiPrivateKey, _, err := paillier.NewKeyPair(8)
+		return paiPrivateKey, err
+	}
+	return paramsPool.PaillierKey()
+}
+
+// 从池中取预参数，未初始化池时现场生成
+func poolPreParams() (*keygen.PreParamsWithDlnProof, error) {
+	paramsPoolMutex.RLock()
+	defer paramsPoolMutex.RUnlock()
+
+	if paramsPool == nil {
+		return keygen.GeneratePreParamsWithDlnProof(), nil
+	}
+	return paramsPool.PreParams()
+}
+
 //export go_ecdsa_keygen_generate_p2_params
 func go_ecdsa_keygen_generate_p2_params(out_data **C.char, out_len *C.int) C.int {
 	// P2生成自己的预参数和证明
-	p2PreParamsAndProof := keygen.GeneratePreParamsWithDlnProof()
+	p2PreParamsAndProof, err := poolPreParams()
+	if err != nil {
+		return -3 // 预参数生成失败
+	}
 
 	// 序列化P2预参数
 	p2ParamsData, err := json.Marshal(p2PreParamsAndProof)
@@ -1138,7 +1250,7 @@ func go_ecdsa_keygen_p1(key_data *C.char, key_len C.int, peer_id C.int, p2_param
 	// 解析DKG密钥数据
 	var keyStep3Data tss.KeyStep3Data
 	err := json.Unmarshal([]byte(keyDataStr), &keyStep3Data)
-	if err != nil {
+	if err != nil || keyStep3Data.PublicKey == nil {
 		return -1 // DKG密钥解析错误
 	}
 
@@ -1153,16 +1265,19 @@ func go_ecdsa_keygen_p1(key_data *C.char, key_len C.int, peer_id C.int, p2_param
 	share1 := keyStep3Data.ShareI
 
 	// 生成Paillier密钥对
-	paiPrivateKey, _, err := paillier.NewKeyPair(8)
+	paiPrivateKey, err := poolPaillierKey()
 	if err != nil {
 		return -3 // Paillier密钥生成失败
 	}
 
 	// P1生成自己的预参数和证明
-	p1PreParamsAndProof := keygen.GeneratePreParamsWithDlnProof()
+	p1PreParamsAndProof, err := poolPreParams()
+	if err != nil {
+		return -3 // 预参数生成失败
+	}
 
 	// 执行P1 keygen，使用P2的预参数
-	message, e_x1, err := keygen.P1(share1, paiPrivateKey, keyStep3Data.Id, int(peer_id), p1PreParamsAndProof, p2PreParamsAndProof.PedersonParameters(), p2PreParamsAndProof.Proof)
+	message, e_x1, err := keygen.P1(share1, paiPrivateKey, keyStep3Data.Id, int(peer_id), p1PreParamsAndProof, p2PreParamsAndProof.PedersonParameters(), p2PreParamsAndProof.Proof, keyStep3Data.PublicKey.Curve)
 	if err != nil {
 		return -4 // P1 keygen执行失败
 	}
@@ -1259,3 +1374,45 @@ func go_ecdsa_keygen_p2(key_data *C.char, key_len C.int, p1_id C.int, p1_message
 func main() {
 	// CGO库不需要main函数，但Go要求有
 }
+
+// go_message_digest 按mode计算ECDSA待签名摘要，message为hex编码，输出hex编码的32字节摘要，
+// 可直接传给go_ecdsa_sign_init_p1_complex/go_ecdsa_sign_init_p2_complex
+//
+//export go_message_digest
+func go_message_digest(mode *C.char, mode_len C.int, message *C.char, message_len C.int, digest **C.char, digest_len *C.int) C.int {
+	hashMode, err := prehash.ParseMode(C.GoStringN(mode, mode_len))
+	if err != nil || hashMode.IsEd25519() {
+		return -1 // 不支持的哈希模式
+	}
+	digestHex, err := prehash.DigestHex(hashMode, C.GoStringN(message, message_len))
+	if err != nil {
+		return -2 // 消息格式错误
+	}
+	*digest = C.CString(digestHex)
+	*digest_len = C.int(len(digestHex))
+	return 0
+}
+
+// go_address 由公钥生成链上地址，curve为0时public_key为secp256k1压缩或非压缩hex，否则为32字节Ed25519公钥hex，
+// format见address.Formats，如eth、btc_p2wpkh、sol
+//
+//export go_address
+func go_address(curve C.int, format *C.char, format_len C.int, public_key *C.char, public_key_len C.int, out **C.char, out_len *C.int) C.int {
+	var point *curves.ECPoint
+	var err error
+	if curve == 0 {
+		point, err = curves.EcdsaPubKeyToPoint(C.GoStringN(public_key, public_key_len))
+	} else {
+		point, err = curves.Ed25519PubKeyToPoint(C.GoStringN(public_key, public_key_len))
+	}
+	if err != nil {
+		return -1 // 公钥格式错误
+	}
+	addr, err := address.Encode(address.Format(C.GoStringN(format, format_len)), point)
+	if err != nil {
+		return -2 // 不支持的地址格式或曲线不匹配
+	}
+	*out = C.CString(addr)
+	*out_len = C.int(len(addr))
+	return 0
+}
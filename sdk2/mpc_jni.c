@@ -3,13 +3,30 @@
 #include <stdlib.h>
 #include "libmpc.h"
 
+// 会话ID由协调方生成并分发给各方，每次协议唯一，为空时init返回失败
+static char* getSessionId(JNIEnv *env, jstring sessionId, int* sessionIdLen) {
+    *sessionIdLen = 0;
+    if (sessionId == NULL) {
+        return NULL;
+    }
+    const char* utf = (*env)->GetStringUTFChars(env, sessionId, NULL);
+    char* copy = strdup(utf);
+    (*env)->ReleaseStringUTFChars(env, sessionId, utf);
+    *sessionIdLen = strlen(copy);
+    return copy;
+}
+
 // ==================== 密钥生成 (Key Generation) ====================
 
 JNIEXPORT jlong JNICALL
 Java_com_example_mpctest_MPCNative_keygenInit(JNIEnv *env, jclass clazz, 
-                                               jint curve, jint partyID, jint threshold, jint totalParties) {
+                                               jint curve, jint partyID, jint threshold, jint totalParties,
+                                               jstring sessionId) {
+    int sessionIdLen = 0;
+    char* sessionIdChars = getSessionId(env, sessionId, &sessionIdLen);
     void* handle = NULL;
-    int result = go_keygen_init(curve, partyID, threshold, totalParties, &handle);
+    int result = go_keygen_init(curve, partyID, threshold, totalParties, sessionIdChars, sessionIdLen, &handle);
+    free(sessionIdChars);
     if (result != 0) {
         return 0; // 返回NULL指针表示失败
     }
@@ -95,7 +112,9 @@ Java_com_example_mpctest_MPCNative_keygenDestroy(JNIEnv *env, jclass clazz, jlon
 JNIEXPORT jlong JNICALL
 Java_com_example_mpctest_MPCNative_refreshInit(JNIEnv *env, jclass clazz, 
                                                 jint curve, jint partyID, jint threshold, 
-                                                jintArray devoteList, jbyteArray keyData) {
+                                                jintArray devoteList, jbyteArray keyData, jstring sessionId) {
+    int sessionIdLen = 0;
+    char* sessionIdChars = getSessionId(env, sessionId, &sessionIdLen);
     jint* devoteArray = (*env)->GetIntArrayElements(env, devoteList, NULL);
     jsize devoteCount = (*env)->GetArrayLength(env, devoteList);
     
@@ -104,8 +123,9 @@ Java_com_example_mpctest_MPCNative_refreshInit(JNIEnv *env, jclass clazz,
     
     void* handle = NULL;
     int result = go_refresh_init(curve, partyID, threshold, (int*)devoteArray, devoteCount, 
-                                (char*)keyBytes, keyLen, &handle);
+                                (char*)keyBytes, keyLen, sessionIdChars, sessionIdLen, &handle);
     
+    free(sessionIdChars);
     (*env)->ReleaseIntArrayElements(env, devoteList, devoteArray, JNI_ABORT);
     (*env)->ReleaseByteArrayElements(env, keyData, keyBytes, JNI_ABORT);
     
@@ -193,7 +213,9 @@ Java_com_example_mpctest_MPCNative_refreshDestroy(JNIEnv *env, jclass clazz, jlo
 JNIEXPORT jlong JNICALL
 Java_com_example_mpctest_MPCNative_ed25519SignInit(JNIEnv *env, jclass clazz, 
                                                     jint partyID, jint threshold, jintArray partList,
-                                                    jbyteArray keyData, jbyteArray message) {
+                                                    jbyteArray keyData, jbyteArray message, jstring sessionId) {
+    int sessionIdLen = 0;
+    char* sessionIdChars = getSessionId(env, sessionId, &sessionIdLen);
     jint* partArray = (*env)->GetIntArrayElements(env, partList, NULL);
     jsize partCount = (*env)->GetArrayLength(env, partList);
     
@@ -205,8 +227,10 @@ Java_com_example_mpctest_MPCNative_ed25519SignInit(JNIEnv *env, jclass clazz,
     
     void* handle = NULL;
     int result = go_ed25519_sign_init(partyID, threshold, (int*)partArray, partCount,
-                                     (char*)keyBytes, keyLen, (char*)msgBytes, msgLen, &handle);
+                                     (char*)keyBytes, keyLen, (char*)msgBytes, msgLen,
+                                     sessionIdChars, sessionIdLen, &handle);
     
+    free(sessionIdChars);
     (*env)->ReleaseIntArrayElements(env, partList, partArray, JNI_ABORT);
     (*env)->ReleaseByteArrayElements(env, keyData, keyBytes, JNI_ABORT);
     (*env)->ReleaseByteArrayElements(env, message, msgBytes, JNI_ABORT);
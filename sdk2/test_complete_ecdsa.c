@@ -1,6 +1,8 @@
 #include <stdio.h>
 #include <stdlib.h>
 #include <string.h>
+#include <time.h>
+#include <unistd.h>
 #include "libmpc.h"
 
 // 将字符串转换为十六进制字符串
@@ -154,9 +156,12 @@ int main() {
     
     // DKG初始化
     printf("1. DKG初始化...\n");
+    // 会话ID各方共享，每次协议唯一
+    char session_id[64];
+    snprintf(session_id, sizeof(session_id), "keygen-%ld-%d", (long)time(NULL), getpid());
     for (int i = 0; i < 3; i++) {
         int party_id = i + 1;
-        int result = go_keygen_init(curve, party_id, threshold, total_parties, &handles[i]);
+        int result = go_keygen_init(curve, party_id, threshold, total_parties, session_id, strlen(session_id), &handles[i]);
         if (result != 0) {
             printf("❌ 参与方%d DKG初始化失败: %d\n", party_id, result);
             return 1;
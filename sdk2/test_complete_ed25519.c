@@ -1,6 +1,7 @@
 #include <stdio.h>
 #include <stdlib.h>
 #include <string.h>
+#include <time.h>
 #include <unistd.h>
 #include "libmpc.h"
 
@@ -115,21 +116,24 @@ int main() {
     
     // 初始化DKG参与方
     print_step("1. 初始化DKG参与方...");
-    result = go_keygen_init(1, 1, 2, 3, &dkg1_handle);
+    // 会话ID各方共享，每次协议唯一
+    char session_id[64];
+    snprintf(session_id, sizeof(session_id), "keygen-%ld-%d", (long)time(NULL), getpid());
+    result = go_keygen_init(1, 1, 2, 3, session_id, strlen(session_id), &dkg1_handle);
     if (result != 0) {
         printf("   ❌ 参与方1 DKG初始化失败，错误码: %d\n", result);
         goto cleanup;
     }
     printf("   ✅ 参与方1 DKG初始化成功\n");
     
-    result = go_keygen_init(1, 2, 2, 3, &dkg2_handle);
+    result = go_keygen_init(1, 2, 2, 3, session_id, strlen(session_id), &dkg2_handle);
     if (result != 0) {
         printf("   ❌ 参与方2 DKG初始化失败，错误码: %d\n", result);
         goto cleanup;
     }
     printf("   ✅ 参与方2 DKG初始化成功\n");
     
-    result = go_keygen_init(1, 3, 2, 3, &dkg3_handle);
+    result = go_keygen_init(1, 3, 2, 3, session_id, strlen(session_id), &dkg3_handle);
     if (result != 0) {
         printf("   ❌ 参与方3 DKG初始化失败，错误码: %d\n", result);
         goto cleanup;
@@ -256,7 +260,11 @@ int main() {
     // 初始化Ed25519签名
     print_step("1. 初始化P1签名...");
     int part_list[] = {1, 2};
-    result = go_ed25519_sign_init(1, 2, part_list, 2, p1_key_data, p1_key_len, hex_message, strlen(hex_message), &ed25519_p1_handle);
+    // 会话ID各方共享，每次协议唯一
+    char sign_session_id[64];
+    snprintf(sign_session_id, sizeof(sign_session_id), "ed25519-sign-%ld-%d", (long)time(NULL), getpid());
+    result = go_ed25519_sign_init(1, 2, part_list, 2, p1_key_data, p1_key_len, hex_message, strlen(hex_message),
+                                  sign_session_id, strlen(sign_session_id), &ed25519_p1_handle);
     if (result != 0) {
         printf("   ❌ P1签名初始化失败，错误码: %d\n", result);
         goto cleanup;
@@ -264,7 +272,8 @@ int main() {
     printf("   ✅ P1签名初始化成功\n");
     
     print_step("2. 初始化P2签名...");
-    result = go_ed25519_sign_init(2, 2, part_list, 2, p2_key_data, p2_key_len, hex_message, strlen(hex_message), &ed25519_p2_handle);
+    result = go_ed25519_sign_init(2, 2, part_list, 2, p2_key_data, p2_key_len, hex_message, strlen(hex_message),
+                                  sign_session_id, strlen(sign_session_id), &ed25519_p2_handle);
     if (result != 0) {
         printf("   ❌ P2签名初始化失败，错误码: %d\n", result);
         goto cleanup;
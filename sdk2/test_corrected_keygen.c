@@ -1,6 +1,8 @@
 #include <stdio.h>
 #include <stdlib.h>
 #include <string.h>
+#include <time.h>
+#include <unistd.h>
 
 // 颜色输出宏
 #define RESET   "\033[0m"
@@ -12,7 +14,7 @@
 #define CYAN    "\033[36m"
 
 // 声明外部Go函数
-extern int go_keygen_init(int curve, int party_id, int threshold, int total_parties, void** handle);
+extern int go_keygen_init(int curve, int party_id, int threshold, int total_parties, char* session_id, int session_id_len, void** handle);
 extern int go_keygen_round1(void* handle, char** out_data, int* out_len);
 extern int go_keygen_round2(void* handle, const char* in_data, int in_len, char** out_data, int* out_len);
 extern int go_keygen_round3(void* handle, const char* in_data, int in_len, char** key_data, int* key_len);
@@ -96,9 +98,12 @@ int test_corrected_keygen() {
     
     // 第一步：初始化参与方
     printf(BLUE "📋 第一步：初始化参与方\n" RESET);
+    // 会话ID各方共享，每次协议唯一
+    char session_id[64];
+    snprintf(session_id, sizeof(session_id), "keygen-%ld-%d", (long)time(NULL), getpid());
     for (int i = 0; i < 3; i++) {
         int party_id = i + 1;
-        int ret = go_keygen_init(curve, party_id, threshold, total_parties, &handles[i]);
+        int ret = go_keygen_init(curve, party_id, threshold, total_parties, session_id, strlen(session_id), &handles[i]);
         if (ret != 0) {
             printf(RED "❌ 参与方%d初始化失败，错误码: %d\n" RESET, party_id, ret);
             return -1;
@@ -1,6 +1,7 @@
 #include <stdio.h>
 #include <stdlib.h>
 #include <string.h>
+#include <time.h>
 #include <unistd.h>
 #include "libmpc.h"
 
@@ -123,9 +124,12 @@ int generate_real_keys(PartyKeyData* party_keys) {
     
     // 初始化keygen会话
     printf(YELLOW "   🚀 初始化keygen会话\n" RESET);
+    // 会话ID各方共享，每次协议唯一
+    char session_id[64];
+    snprintf(session_id, sizeof(session_id), "keygen-%ld-%d", (long)time(NULL), getpid());
     for (int i = 0; i < TOTAL_PARTIES; i++) {
         int party_id = i + 1;
-        int ret = go_keygen_init(curve, party_id, THRESHOLD, TOTAL_PARTIES, &handles[i]);
+        int ret = go_keygen_init(curve, party_id, THRESHOLD, TOTAL_PARTIES, session_id, strlen(session_id), &handles[i]);
         if (ret != 0) {
             printf(RED "   ❌ 参与方%d keygen初始化失败，错误码: %d\n" RESET, party_id, ret);
             return -1;
@@ -227,6 +231,9 @@ int test_refresh() {
 
     // 步骤2: 初始化refresh会话
     printf(BOLD YELLOW "\n🚀 步骤2: 初始化refresh会话\n" RESET);
+    // 会话ID各方共享，每次协议唯一
+    char session_id[64];
+    snprintf(session_id, sizeof(session_id), "refresh-%ld-%d", (long)time(NULL), getpid());
     for (int i = 0; i < TOTAL_PARTIES; i++) {
         // devoteList参数 - 指定要刷新的参与方列表
         int devoteList[2] = {1, 2}; // 刷新参与方1和2
@@ -240,6 +247,8 @@ int test_refresh() {
             devoteCount,              // 刷新参与方数量
             party_keys[i].key_data,   // 真实的密钥数据
             party_keys[i].key_len,    // 密钥数据长度
+            session_id,               // 会话ID
+            strlen(session_id),       // 会话ID长度
             &handles[i]               // 输出会话句柄
         );
 
@@ -0,0 +1,262 @@
+package address
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/base58"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/tss/bip340"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
+)
+
+// Format address format name
+type Format string
+
+const (
+	BTCP2PKH         Format = "btc_p2pkh"
+	BTCP2WPKH        Format = "btc_p2wpkh"
+	BTCP2TR          Format = "btc_p2tr"
+	BTCTestnetP2PKH  Format = "btc_testnet_p2pkh"
+	BTCTestnetP2WPKH Format = "btc_testnet_p2wpkh"
+	BTCTestnetP2TR   Format = "btc_testnet_p2tr"
+	ETH              Format = "eth"
+	TRX              Format = "trx"
+	ATOM             Format = "atom"
+	SOL              Format = "sol"
+	APT              Format = "apt"
+	SUI              Format = "sui"
+)
+
+// BitcoinNetwork address prefixes of a bitcoin network
+type BitcoinNetwork struct {
+	PubKeyHashVersion byte
+	Bech32Hrp         string
+}
+
+var (
+	BitcoinMainnet = &BitcoinNetwork{PubKeyHashVersion: 0x00, Bech32Hrp: "bc"}
+	BitcoinTestnet = &BitcoinNetwork{PubKeyHashVersion: 0x6f, Bech32Hrp: "tb"}
+)
+
+// Formats all supported formats
+func Formats() []Format {
+	return []Format{BTCP2PKH, BTCP2WPKH, BTCP2TR, BTCTestnetP2PKH, BTCTestnetP2WPKH, BTCTestnetP2TR,
+		ETH, TRX, ATOM, SOL, APT, SUI}
+}
+
+// Encode address of the threshold publicKey (or a bip32 child publicKey) in the given format
+func Encode(format Format, publicKey *curves.ECPoint) (string, error) {
+	switch Format(strings.ToLower(string(format))) {
+	case BTCP2PKH:
+		return P2PKH(publicKey, BitcoinMainnet)
+	case BTCP2WPKH:
+		return P2WPKH(publicKey, BitcoinMainnet)
+	case BTCP2TR:
+		return P2TR(publicKey, BitcoinMainnet)
+	case BTCTestnetP2PKH:
+		return P2PKH(publicKey, BitcoinTestnet)
+	case BTCTestnetP2WPKH:
+		return P2WPKH(publicKey, BitcoinTestnet)
+	case BTCTestnetP2TR:
+		return P2TR(publicKey, BitcoinTestnet)
+	case ETH:
+		return Ethereum(publicKey)
+	case TRX:
+		return Tron(publicKey)
+	case ATOM:
+		return Cosmos(publicKey, "cosmos")
+	case SOL:
+		return Solana(publicKey)
+	case APT:
+		return Aptos(publicKey)
+	case SUI:
+		return Sui(publicKey)
+	default:
+		return "", fmt.Errorf("unsupported address format %s", format)
+	}
+}
+
+// P2PKH base58check(version || hash160(compressed publicKey))
+func P2PKH(publicKey *curves.ECPoint, network *BitcoinNetwork) (string, error) {
+	pub, err := secp256k1Compressed(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return base58.CheckEncode(append([]byte{network.PubKeyHashVersion}, hash160(pub)...)), nil
+}
+
+// P2WPKH segwit v0 bech32 address of hash160(compressed publicKey)
+func P2WPKH(publicKey *curves.ECPoint, network *BitcoinNetwork) (string, error) {
+	pub, err := secp256k1Compressed(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return segwitAddress(network.Bech32Hrp, 0, hash160(pub))
+}
+
+// P2TR BIP86 key path only taproot bech32m address, output key Q = P + hashTapTweak(P.x)*G
+func P2TR(publicKey *curves.ECPoint, network *BitcoinNetwork) (string, error) {
+	if err := checkCurve(publicKey, curves.Secp256k1); err != nil {
+		return "", err
+	}
+	Q, err := bip340.OutputKey(publicKey, nil)
+	if err != nil {
+		return "", err
+	}
+	program := make([]byte, 32)
+	Q.X.FillBytes(program)
+	return segwitAddress(network.Bech32Hrp, 1, program)
+}
+
+// Ethereum EIP-55 checksummed keccak256(X || Y)[12:]
+func Ethereum(publicKey *curves.ECPoint) (string, error) {
+	addr, err := ethereumBytes(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + checksumHex(addr), nil
+}
+
+// Tron base58check(0x41 || keccak256(X || Y)[12:])
+func Tron(publicKey *curves.ECPoint) (string, error) {
+	addr, err := ethereumBytes(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return base58.CheckEncode(append([]byte{0x41}, addr...)), nil
+}
+
+// Cosmos bech32 address of hash160(compressed publicKey) with the chain prefix
+func Cosmos(publicKey *curves.ECPoint, hrp string) (string, error) {
+	pub, err := secp256k1Compressed(publicKey)
+	if err != nil {
+		return "", err
+	}
+	if hrp == "" {
+		return "", fmt.Errorf("bech32 prefix is empty")
+	}
+	return bech32Encode(hrp, convertBits(hash160(pub)), bech32Const), nil
+}
+
+// Solana base58 of the 32 bytes ed25519 publicKey
+func Solana(publicKey *curves.ECPoint) (string, error) {
+	pub, err := ed25519Bytes(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(pub), nil
+}
+
+// Aptos single signer authentication key sha3-256(publicKey || 0x00)
+func Aptos(publicKey *curves.ECPoint) (string, error) {
+	pub, err := ed25519Bytes(publicKey)
+	if err != nil {
+		return "", err
+	}
+	digest := sha3.Sum256(append(pub, 0x00))
+	return "0x" + hex.EncodeToString(digest[:]), nil
+}
+
+// Sui blake2b-256(flag || publicKey), flag 0x00 ed25519, 0x01 secp256k1 compressed publicKey
+func Sui(publicKey *curves.ECPoint) (string, error) {
+	if publicKey == nil || publicKey.Curve == nil {
+		return "", fmt.Errorf("publicKey is nil")
+	}
+	var data []byte
+	switch curves.GetCurveName(publicKey.Curve) {
+	case curves.Ed25519:
+		pub, err := ed25519Bytes(publicKey)
+		if err != nil {
+			return "", err
+		}
+		data = append([]byte{0x00}, pub...)
+	case curves.Secp256k1:
+		pub, err := secp256k1Compressed(publicKey)
+		if err != nil {
+			return "", err
+		}
+		data = append([]byte{0x01}, pub...)
+	default:
+		return "", fmt.Errorf("unsupported curve")
+	}
+	digest := blake2b.Sum256(data)
+	return "0x" + hex.EncodeToString(digest[:]), nil
+}
+
+func checkCurve(publicKey *curves.ECPoint, name string) error {
+	if publicKey == nil || publicKey.Curve == nil || publicKey.X == nil || publicKey.Y == nil {
+		return fmt.Errorf("publicKey is nil")
+	}
+	if curves.GetCurveName(publicKey.Curve) != name {
+		return fmt.Errorf("publicKey must be on %s curve", name)
+	}
+	if !publicKey.IsOnCurve() {
+		return fmt.Errorf("publicKey is not on curve")
+	}
+	return nil
+}
+
+func secp256k1Compressed(publicKey *curves.ECPoint) ([]byte, error) {
+	if err := checkCurve(publicKey, curves.Secp256k1); err != nil {
+		return nil, err
+	}
+	return secp256k1.NewPublicKey(publicKey.X, publicKey.Y).SerializeCompressed(), nil
+}
+
+func ed25519Bytes(publicKey *curves.ECPoint) ([]byte, error) {
+	if err := checkCurve(publicKey, curves.Ed25519); err != nil {
+		return nil, err
+	}
+	return edwards.NewPublicKey(publicKey.X, publicKey.Y).Serialize(), nil
+}
+
+func ethereumBytes(publicKey *curves.ECPoint) ([]byte, error) {
+	if err := checkCurve(publicKey, curves.Secp256k1); err != nil {
+		return nil, err
+	}
+	pub := secp256k1.NewPublicKey(publicKey.X, publicKey.Y).SerializeUncompressed()
+	return keccak256(pub[1:])[12:], nil
+}
+
+// checksumHex EIP-55 mixed case hex, a letter is upper case when its keccak nibble >= 8
+func checksumHex(addr []byte) string {
+	lower := hex.EncodeToString(addr)
+	hash := keccak256([]byte(lower))
+	out := []byte(lower)
+	for i, c := range out {
+		if c < 'a' {
+			continue
+		}
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if nibble >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return string(out)
+}
+
+func hash160(data []byte) []byte {
+	digest := sha256.Sum256(data)
+	h := ripemd160.New()
+	h.Write(digest[:])
+	return h.Sum(nil)
+}
+
+// keccak256 legacy keccak-256 of data as used by ethereum
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
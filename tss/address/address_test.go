@@ -0,0 +1,119 @@
+package address
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/base58"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestSegwit(t *testing.T) {
+	// BIP173 and BIP350 vectors
+	program, _ := hex.DecodeString("751e76e8199196d454941c45d1b3a323f1433bd6")
+	addr, err := segwitAddress("bc", 0, program)
+	require.NoError(t, err)
+	require.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", addr)
+
+	program, _ = hex.DecodeString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	addr, err = segwitAddress("bc", 1, program)
+	require.NoError(t, err)
+	require.Equal(t, "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", addr)
+}
+
+func TestSecp256k1(t *testing.T) {
+	// private key 1
+	G := curves.ScalarToPoint(secp256k1.S256(), big.NewInt(1))
+
+	addr, err := Encode(BTCP2PKH, G)
+	require.NoError(t, err)
+	require.Equal(t, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", addr)
+
+	addr, err = Encode(BTCP2WPKH, G)
+	require.NoError(t, err)
+	require.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", addr)
+
+	addr, err = Encode(BTCTestnetP2WPKH, G)
+	require.NoError(t, err)
+	require.Equal(t, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", addr)
+
+	addr, err = Encode(ETH, G)
+	require.NoError(t, err)
+	require.Equal(t, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", addr)
+
+	addr, err = Encode(BTCP2TR, G)
+	require.NoError(t, err)
+	fmt.Println("p2tr: ", addr)
+	require.True(t, strings.HasPrefix(addr, "bc1p"))
+	require.Equal(t, 62, len(addr))
+
+	addr, err = Encode(TRX, G)
+	require.NoError(t, err)
+	require.Equal(t, "TMVQGm1qAQYVdetCeGRRkTWYYrLXuHK2HC", addr)
+
+	addr, err = Encode(ATOM, G)
+	require.NoError(t, err)
+	fmt.Println("cosmos: ", addr)
+	require.True(t, strings.HasPrefix(addr, "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k"))
+
+	// odd Y, P2TR uses the even Y internal key
+	P := curves.ScalarToPoint(secp256k1.S256(), big.NewInt(3))
+	negP := &curves.ECPoint{Curve: P.Curve, X: P.X, Y: new(big.Int).Sub(P.Curve.Params().P, P.Y)}
+	addr1, err := Encode(BTCP2TR, P)
+	require.NoError(t, err)
+	addr2, err := Encode(BTCP2TR, negP)
+	require.NoError(t, err)
+	require.Equal(t, addr1, addr2)
+
+	_, err = Encode(SOL, G)
+	require.Error(t, err)
+	_, err = Encode("doge", G)
+	require.Error(t, err)
+}
+
+func TestEIP55(t *testing.T) {
+	vectors := []string{
+		"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"fB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"dbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"D1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	}
+	for _, v := range vectors {
+		addr, _ := hex.DecodeString(v)
+		require.Equal(t, v, checksumHex(addr))
+	}
+}
+
+func TestEd25519(t *testing.T) {
+	// RFC8032 test 1 publicKey
+	pubBytes, _ := hex.DecodeString("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	pub, err := edwards.ParsePubKey(pubBytes)
+	require.NoError(t, err)
+	point := &curves.ECPoint{Curve: pub.Curve, X: pub.X, Y: pub.Y}
+
+	addr, err := Encode(SOL, point)
+	require.NoError(t, err)
+	fmt.Println("solana: ", addr)
+	require.Equal(t, base58.Encode(pubBytes), addr)
+
+	addr, err = Encode(APT, point)
+	require.NoError(t, err)
+	aptos := sha3.Sum256(append(pubBytes, 0))
+	require.Equal(t, "0x"+hex.EncodeToString(aptos[:]), addr)
+
+	addr, err = Encode(SUI, point)
+	require.NoError(t, err)
+	sui := blake2b.Sum256(append([]byte{0}, pubBytes...))
+	require.Equal(t, "0x"+hex.EncodeToString(sui[:]), addr)
+
+	_, err = Encode(ETH, point)
+	require.Error(t, err)
+}
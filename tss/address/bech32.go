@@ -0,0 +1,88 @@
+package address
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BIP173 bech32 and BIP350 bech32m
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HrpExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// bech32Encode data are 5 bits groups, checksum constant selects bech32 or bech32m
+func bech32Encode(hrp string, data []byte, constant uint32) string {
+	values := append(bech32HrpExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ constant
+	var builder strings.Builder
+	builder.WriteString(hrp)
+	builder.WriteByte('1')
+	for _, d := range data {
+		builder.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		builder.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return builder.String()
+}
+
+// convertBits regroup 8 bits bytes to 5 bits groups with padding
+func convertBits(data []byte) []byte {
+	var out []byte
+	acc, bits := uint32(0), uint(0)
+	for _, b := range data {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out = append(out, byte(acc>>bits)&31)
+		}
+	}
+	if bits > 0 {
+		out = append(out, byte(acc<<(5-bits))&31)
+	}
+	return out
+}
+
+// segwitAddress witness version 0 uses bech32, version 1 and above bech32m
+func segwitAddress(hrp string, version byte, program []byte) (string, error) {
+	if version > 16 || len(program) < 2 || len(program) > 40 {
+		return "", fmt.Errorf("invalid witness program")
+	}
+	constant := uint32(bech32Const)
+	if version > 0 {
+		constant = bech32mConst
+	}
+	return bech32Encode(hrp, append([]byte{version}, convertBits(program)...), constant), nil
+}
@@ -0,0 +1,221 @@
+package bip340
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/tss"
+)
+
+var (
+	curve = secp256k1.S256()
+)
+
+// Key threshold key normalized for BIP340, the group publicKey has even Y,
+// when the dkg publicKey has odd Y every share and share publicKey is negated
+type Key struct {
+	Id             int
+	shareI         *big.Int
+	PublicKey      *curves.ECPoint
+	SharePubKeyMap map[int]*curves.ECPoint
+}
+
+// NewKey normalize a secp256k1 dkg output, shareI is optional
+func NewKey(keyData *tss.KeyStep3Data) (*Key, error) {
+	if keyData == nil || keyData.PublicKey == nil || keyData.SharePubKeyMap == nil {
+		return nil, fmt.Errorf("parameter error")
+	}
+	if curves.GetCurveName(keyData.PublicKey.Curve) != curves.Secp256k1 {
+		return nil, fmt.Errorf("publicKey must be on secp256k1 curve")
+	}
+	var shareI *big.Int
+	if keyData.ShareI != nil {
+		shareI = new(big.Int).Mod(keyData.ShareI, curve.N)
+	}
+	key := &Key{
+		Id:             keyData.Id,
+		shareI:         shareI,
+		PublicKey:      keyData.PublicKey,
+		SharePubKeyMap: keyData.SharePubKeyMap,
+	}
+	return key.normalize(), nil
+}
+
+// ShareI normalized key share
+func (key *Key) ShareI() *big.Int {
+	return key.shareI
+}
+
+// XOnlyPublicKey 32 bytes x coordinate of the group publicKey
+func (key *Key) XOnlyPublicKey() []byte {
+	return xBytes(key.PublicKey)
+}
+
+// Tweak BIP341 taproot output key Q = P + t*G, t = hashTapTweak(P.x || merkleRoot),
+// merkleRoot is empty for key path only outputs, t is added to every share
+func (key *Key) Tweak(merkleRoot []byte) (*Key, error) {
+	t, err := tapTweak(key.PublicKey, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	tG := curves.ScalarToPoint(curve, t)
+	Q, err := key.PublicKey.Add(tG)
+	if err != nil {
+		return nil, err
+	}
+	sharePubKeyMap := make(map[int]*curves.ECPoint, len(key.SharePubKeyMap))
+	for id, Xi := range key.SharePubKeyMap {
+		if sharePubKeyMap[id], err = Xi.Add(tG); err != nil {
+			return nil, err
+		}
+	}
+	var shareI *big.Int
+	if key.shareI != nil {
+		shareI = new(big.Int).Mod(new(big.Int).Add(key.shareI, t), curve.N)
+	}
+	tweaked := &Key{
+		Id:             key.Id,
+		shareI:         shareI,
+		PublicKey:      Q,
+		SharePubKeyMap: sharePubKeyMap,
+	}
+	return tweaked.normalize(), nil
+}
+
+// OutputKey BIP341 taproot output key of an internal publicKey, the internal key is taken with even Y,
+// BIP86 key path only outputs have empty merkleRoot
+func OutputKey(publicKey *curves.ECPoint, merkleRoot []byte) (*curves.ECPoint, error) {
+	if publicKey == nil || curves.GetCurveName(publicKey.Curve) != curves.Secp256k1 {
+		return nil, fmt.Errorf("publicKey must be on secp256k1 curve")
+	}
+	P := publicKey
+	if P.Y.Bit(0) == 1 {
+		P = negate(P)
+	}
+	t, err := tapTweak(P, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	return P.Add(curves.ScalarToPoint(curve, t))
+}
+
+// tapTweak t = hashTapTweak(P.x || merkleRoot)
+func tapTweak(publicKey *curves.ECPoint, merkleRoot []byte) (*big.Int, error) {
+	if len(merkleRoot) != 0 && len(merkleRoot) != 32 {
+		return nil, fmt.Errorf("merkleRoot must be 32 bytes")
+	}
+	t := new(big.Int).SetBytes(taggedHash("TapTweak", xBytes(publicKey), merkleRoot))
+	if t.Cmp(curve.N) >= 0 {
+		return nil, fmt.Errorf("invalid tweak")
+	}
+	return t, nil
+}
+
+// normalize negate the key when publicKey has odd Y
+func (key *Key) normalize() *Key {
+	if key.PublicKey.Y.Bit(0) == 0 {
+		return key
+	}
+	sharePubKeyMap := make(map[int]*curves.ECPoint, len(key.SharePubKeyMap))
+	for id, Xi := range key.SharePubKeyMap {
+		sharePubKeyMap[id] = negate(Xi)
+	}
+	var shareI *big.Int
+	if key.shareI != nil {
+		shareI = new(big.Int).Mod(new(big.Int).Neg(key.shareI), curve.N)
+	}
+	return &Key{
+		Id:             key.Id,
+		shareI:         shareI,
+		PublicKey:      negate(key.PublicKey),
+		SharePubKeyMap: sharePubKeyMap,
+	}
+}
+
+// Verify BIP340 signature of a 32 bytes x-only publicKey
+func Verify(publicKey, message, signature []byte) bool {
+	if len(publicKey) != 32 || len(signature) != 64 {
+		return false
+	}
+	P, err := liftX(new(big.Int).SetBytes(publicKey))
+	if err != nil {
+		return false
+	}
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	if r.Cmp(curve.P) >= 0 || s.Cmp(curve.N) >= 0 {
+		return false
+	}
+	e := challenge(signature[:32], publicKey, message)
+	// R = s*G - e*P
+	sG := curves.ScalarToPoint(curve, s)
+	eP := P.ScalarMult(new(big.Int).Sub(curve.N, e))
+	if eP == nil {
+		return false
+	}
+	x, y := curve.Add(sG.X, sG.Y, eP.X, eP.Y)
+	if (x.Sign() == 0 && y.Sign() == 0) || y.Bit(0) == 1 {
+		return false
+	}
+	return x.Cmp(r) == 0
+}
+
+// challenge e = hashBIP0340/challenge(R.x || P.x || m) mod n
+func challenge(R, publicKey, message []byte) *big.Int {
+	e := new(big.Int).SetBytes(taggedHash("BIP0340/challenge", R, publicKey, message))
+	return e.Mod(e, curve.N)
+}
+
+// taggedHash SHA256(SHA256(tag) || SHA256(tag) || data)
+func taggedHash(tag string, data ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// liftX the point with x and even Y
+func liftX(x *big.Int) (*curves.ECPoint, error) {
+	p := curve.P
+	if x.Cmp(p) >= 0 {
+		return nil, fmt.Errorf("invalid x")
+	}
+	// y^2 = x^3 + 7
+	c := new(big.Int).Exp(x, big.NewInt(3), p)
+	c.Add(c, big.NewInt(7))
+	c.Mod(c, p)
+	y := new(big.Int).ModSqrt(c, p)
+	if y == nil {
+		return nil, fmt.Errorf("x is not on the curve")
+	}
+	if y.Bit(0) == 1 {
+		y.Sub(p, y)
+	}
+	return curves.NewECPoint(curve, x, y)
+}
+
+func negate(point *curves.ECPoint) *curves.ECPoint {
+	return &curves.ECPoint{
+		Curve: point.Curve,
+		X:     point.X,
+		Y:     new(big.Int).Mod(new(big.Int).Neg(point.Y), curve.P),
+	}
+}
+
+// xBytes 32 bytes big endian x coordinate
+func xBytes(point *curves.ECPoint) []byte {
+	return scalarBytes(point.X)
+}
+
+func scalarBytes(n *big.Int) []byte {
+	bytes := make([]byte, 32)
+	n.FillBytes(bytes)
+	return bytes
+}
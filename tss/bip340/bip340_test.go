@@ -0,0 +1,186 @@
+package bip340
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+	"github.com/okx/threshold-lib/tss/key/dkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	// BIP340 test vectors 0 and 1
+	vectors := []struct {
+		publicKey, message, signature string
+	}{
+		{
+			"F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0",
+		},
+		{
+			"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+			"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+			"6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A",
+		},
+	}
+	for _, v := range vectors {
+		publicKey, _ := hex.DecodeString(v.publicKey)
+		message, _ := hex.DecodeString(v.message)
+		signature, _ := hex.DecodeString(v.signature)
+		require.True(t, Verify(publicKey, message, signature))
+		signature[63] ^= 1
+		require.False(t, Verify(publicKey, message, signature))
+	}
+}
+
+func TestBip340Sign(t *testing.T) {
+	message := []byte("hello bip340")
+	// the dkg publicKey has odd Y about half of the time
+	for n := 0; n < 4; n++ {
+		keys := keyGen(t)
+		fmt.Println("publicKey odd Y:", keys[1].PublicKey.Y.Bit(0) == 1)
+		for _, partList := range [][]int{{1, 2}, {1, 3}, {2, 3}, {1, 2, 3}} {
+			for _, signature := range bip340Sign(t, keys, partList, message) {
+				require.True(t, Verify(keys[1].XOnlyPublicKey(), message, signature))
+			}
+		}
+	}
+}
+
+func TestTaproot(t *testing.T) {
+	keys := keyGen(t)
+	merkleRoot := make([]byte, 32)
+	merkleRoot[0] = 1
+	message := []byte("hello taproot")
+	for _, root := range [][]byte{nil, merkleRoot} {
+		tweaked := make(map[int]*Key, len(keys))
+		for id, key := range keys {
+			tk, err := key.Tweak(root)
+			require.NoError(t, err)
+			tweaked[id] = tk
+		}
+		require.NotEqual(t, keys[1].XOnlyPublicKey(), tweaked[1].XOnlyPublicKey())
+		Q, err := OutputKey(keys[1].PublicKey, root)
+		require.NoError(t, err)
+		require.Equal(t, xBytes(Q), tweaked[1].XOnlyPublicKey())
+		signatures := bip340Sign(t, tweaked, []int{1, 3}, message)
+		for _, signature := range signatures {
+			require.True(t, Verify(tweaked[1].XOnlyPublicKey(), message, signature))
+			require.False(t, Verify(keys[1].XOnlyPublicKey(), message, signature))
+		}
+		fmt.Println("taproot output key", hex.EncodeToString(tweaked[1].XOnlyPublicKey()), hex.EncodeToString(signatures[0]))
+	}
+	_, err := keys[1].Tweak([]byte{1})
+	require.Error(t, err)
+}
+
+func TestBip340Blame(t *testing.T) {
+	keys := keyGen(t)
+	message := hex.EncodeToString([]byte("hello bip340"))
+
+	nonces1, err := Preprocess(keys[1], 1)
+	require.NoError(t, err)
+	nonces2, err := Preprocess(keys[2], 1)
+	require.NoError(t, err)
+	p1 := NewBip340Sign(keys[1], []int{1, 2}, message, nonces1[0])
+	p2 := NewBip340Sign(keys[2], []int{1, 2}, message, nonces2[0])
+
+	p1Step1, err := p1.SignStep1()
+	require.NoError(t, err)
+	p2Step1, err := p2.SignStep1()
+	require.NoError(t, err)
+	_, err = p1.SignStep2([]*tss.Message{p2Step1[1]})
+	require.NoError(t, err)
+	_, err = p2.SignStep2([]*tss.Message{p1Step1[2]})
+	require.NoError(t, err)
+
+	// nonces can't be used twice
+	again := NewBip340Sign(keys[1], []int{1, 2}, message, nonces1[0])
+	_, err = again.SignStep1()
+	require.NoError(t, err)
+	_, err = again.SignStep2([]*tss.Message{p2Step1[1]})
+	require.Error(t, err)
+
+	// party 1 sends a wrong share
+	bytes, err := codec.JSON.Marshal(Step2Data{Zi: new(big.Int).Add(big.NewInt(1), p1.Share())})
+	require.NoError(t, err)
+	_, err = p2.SignStep3([]*tss.Message{{From: 1, To: 2, Data: string(bytes)}})
+	fmt.Println(err)
+	culprit, ok := tss.Culprit(err)
+	require.True(t, ok)
+	require.Equal(t, 1, culprit)
+}
+
+// bip340Sign run one signature among partList with fresh nonces
+func bip340Sign(t *testing.T, keys map[int]*Key, partList []int, message []byte) [][]byte {
+	signers := make(map[int]*Bip340Sign)
+	for _, id := range partList {
+		nonces, err := Preprocess(keys[id], 1)
+		require.NoError(t, err)
+		signers[id] = NewBip340Sign(keys[id], partList, hex.EncodeToString(message), nonces[0])
+		require.NotNil(t, signers[id])
+	}
+	step1 := make(map[int]map[int]*tss.Message)
+	for id, signer := range signers {
+		out, err := signer.SignStep1()
+		require.NoError(t, err)
+		step1[id] = out
+	}
+	step2 := make(map[int]map[int]*tss.Message)
+	for id, signer := range signers {
+		out, err := signer.SignStep2(receive(step1, id))
+		require.NoError(t, err)
+		step2[id] = out
+	}
+	var signatures [][]byte
+	for id, signer := range signers {
+		signature, err := signer.SignStep3(receive(step2, id))
+		require.NoError(t, err)
+		signatures = append(signatures, signature)
+	}
+	return signatures
+}
+
+func receive(msgs map[int]map[int]*tss.Message, to int) []*tss.Message {
+	var in []*tss.Message
+	for from, out := range msgs {
+		if from != to {
+			in = append(in, out[to])
+		}
+	}
+	return in
+}
+
+func keyGen(t *testing.T) map[int]*Key {
+	sessionId := tss.SessionId("keyGen")
+	setUps := map[int]*dkg.SetupInfo{}
+	for id := 1; id <= 3; id++ {
+		setUps[id] = dkg.NewSetUp(sessionId, id, 2, 3, curve)
+	}
+	step1 := make(map[int]map[int]*tss.Message)
+	for id, setUp := range setUps {
+		out, err := setUp.DKGStep1()
+		require.NoError(t, err)
+		step1[id] = out
+	}
+	step2 := make(map[int]map[int]*tss.Message)
+	for id, setUp := range setUps {
+		out, err := setUp.DKGStep2(receive(step1, id))
+		require.NoError(t, err)
+		step2[id] = out
+	}
+	keys := make(map[int]*Key)
+	for id, setUp := range setUps {
+		keyData, err := setUp.DKGStep3(receive(step2, id))
+		require.NoError(t, err)
+		keys[id], err = NewKey(keyData)
+		require.NoError(t, err)
+		require.Equal(t, uint(0), keys[id].PublicKey.Y.Bit(0))
+	}
+	return keys
+}
@@ -0,0 +1,71 @@
+package bip340
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/tss/frost"
+)
+
+// NonceCommitment public commitment of a nonce pair, Di = di*G, Ei = ei*G
+type NonceCommitment = frost.NonceCommitment
+
+// Nonces secret nonce pair, used for exactly one signature
+type Nonces = frost.Nonces
+
+type Step1Data = frost.Step1Data
+
+type Step2Data = frost.Step2Data
+
+// ciphersuite FROST over secp256k1 with BIP340 tagged hashes, the x-only publicKey is bound by the binding factors,
+// R is taken with even Y and the challenge is the BIP340 one
+var ciphersuite = &frost.Ciphersuite{
+	Group: group.Secp256k1(),
+	Hash: func(tag string, data ...[]byte) []byte {
+		return taggedHash("FROST/"+tag, data...)
+	},
+	EncodeKey: xOnly,
+	Challenge: func(R, publicKey group.Point, message []byte) group.Scalar {
+		e, _ := group.Secp256k1().ScalarFromUniformBytes(taggedHash("BIP0340/challenge", xOnly(R), xOnly(publicKey), message))
+		return e
+	},
+	EvenR: true,
+}
+
+// Preprocess generate count nonce pairs before the message is known,
+// publish the commitments to the other signers, keep the nonces secret
+func Preprocess(key *Key, count int) ([]*Nonces, error) {
+	if key == nil {
+		return nil, fmt.Errorf("parameter error")
+	}
+	return ciphersuite.Preprocess(key.Id, key.shareI, count)
+}
+
+// Aggregate verify every signature share zi and output the 64 bytes BIP340 signature R.x || z,
+// a wrong share is reported as tss.BlameError of the signer
+func Aggregate(key *Key, message []byte, commitments []*NonceCommitment, shares map[int]*big.Int) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("parameter error")
+	}
+	R, z, err := ciphersuite.Aggregate(key.PublicKey, key.SharePubKeyMap, message, commitments, shares)
+	if err != nil {
+		return nil, err
+	}
+	return signature(key, message, R, z)
+}
+
+// signature R.x || z, checked against the x-only publicKey of key
+func signature(key *Key, message []byte, R *curves.ECPoint, z *big.Int) ([]byte, error) {
+	signature := append(xBytes(R), scalarBytes(z)...)
+	if !Verify(key.XOnlyPublicKey(), message, signature) {
+		return nil, fmt.Errorf("signature verify fail, signers are fewer than threshold")
+	}
+	return signature, nil
+}
+
+// xOnly 32 bytes x of a SEC1 compressed point
+func xOnly(point group.Point) []byte {
+	return point.Bytes()[1:]
+}
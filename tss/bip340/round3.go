@@ -0,0 +1,14 @@
+package bip340
+
+import (
+	"github.com/okx/threshold-lib/tss"
+)
+
+// SignStep3 receive signature shares, aggregate the 64 bytes BIP340 signature
+func (sign *Bip340Sign) SignStep3(msgs []*tss.Message) ([]byte, error) {
+	R, z, err := sign.Signer.SignStep3(msgs)
+	if err != nil {
+		return nil, err
+	}
+	return signature(sign.key, sign.message, R, z)
+}
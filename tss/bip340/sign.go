@@ -0,0 +1,47 @@
+package bip340
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/okx/threshold-lib/tss/codec"
+	"github.com/okx/threshold-lib/tss/frost"
+)
+
+// Bip340Sign threshold schnorr signing among partList, one round to exchange nonce commitments,
+// one round to exchange signature shares, then every signer can aggregate the signature
+type Bip340Sign struct {
+	*frost.Signer
+
+	key     *Key
+	message []byte
+}
+
+// NewBip340Sign key from NewKey or Key.Tweak, nonces come from Preprocess and are consumed by this signature
+func NewBip340Sign(key *Key, partList []int, message string, nonces *Nonces) *Bip340Sign {
+	if key == nil {
+		return nil
+	}
+	bytes, err := hex.DecodeString(message)
+	if err != nil {
+		return nil
+	}
+	signer, err := frost.NewSigner(ciphersuite, key.Id, key.shareI, key.PublicKey, key.SharePubKeyMap, partList, bytes, nonces)
+	if err != nil {
+		return nil
+	}
+	return &Bip340Sign{
+		Signer:  signer,
+		key:     key,
+		message: bytes,
+	}
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (sign *Bip340Sign) SetCodec(c codec.Codec) *Bip340Sign {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
+	}
+	sign.Signer.SetCodec(c)
+	return sign
+}
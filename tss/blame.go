@@ -0,0 +1,47 @@
+package tss
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BlameError identifiable abort, the protocol failed because of participant From
+// caller can exclude the culprit and retry with the remaining participants
+type BlameError struct {
+	From     int    // misbehaving participant
+	Round    int    // protocol round where the check failed
+	Check    string // failing check
+	Evidence string // message data received from the culprit
+	Err      error  // underlying error, may be nil
+}
+
+// NewBlameError blame the sender of msg, message data is kept as evidence
+func NewBlameError(msg *Message, round int, check string, err error) *BlameError {
+	return &BlameError{
+		From:     msg.From,
+		Round:    round,
+		Check:    check,
+		Evidence: msg.Data,
+		Err:      err,
+	}
+}
+
+func (e *BlameError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("round %d: %s for participant %d: %v", e.Round, e.Check, e.From, e.Err)
+	}
+	return fmt.Sprintf("round %d: %s for participant %d", e.Round, e.Check, e.From)
+}
+
+func (e *BlameError) Unwrap() error {
+	return e.Err
+}
+
+// Culprit return the misbehaving participant if err is caused by a BlameError
+func Culprit(err error) (int, bool) {
+	var blame *BlameError
+	if errors.As(err, &blame) {
+		return blame.From, true
+	}
+	return 0, false
+}
@@ -0,0 +1,508 @@
+package codec
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+)
+
+const (
+	// Magic first byte of a binary payload, never the first byte of a JSON document
+	Magic byte = 0xb5
+	// Version binary schema version, bump on any incompatible layout change
+	Version byte = 1
+)
+
+// curve ids of the binary format, points are SEC1 / RFC 8032 compressed
+const (
+	curveSecp256k1 byte = 1
+	curveEd25519   byte = 2
+	curveP256      byte = 3
+)
+
+// p256CompressedLen SEC1 compressed p256 point
+const p256CompressedLen = 33
+
+var (
+	bigIntType  = reflect.TypeOf(big.Int{})
+	ecPointType = reflect.TypeOf(curves.ECPoint{})
+)
+
+// binaryCodec layout: Magic | Version | value
+//
+//	bool          1 byte, 0 or 1
+//	int           zigzag varint
+//	uint          varint
+//	string, bytes varint length | bytes
+//	big.Int       sign byte | varint length | big-endian magnitude without leading zero
+//	ECPoint       curve id | compressed point
+//	pointer       presence byte 0 or 1 | value
+//	struct        exported fields in declaration order
+//	slice         varint length | elements
+//	array         elements
+//	map           varint length | entries sorted by encoded key
+//
+// decoding rejects every non canonical input, so equal values always have equal encodings
+type binaryCodec struct{}
+
+func (binaryCodec) Name() string {
+	return "binary"
+}
+
+// IsBinary check whether data is produced by the Binary codec
+func IsBinary(data []byte) bool {
+	return len(data) > 0 && data[0] == Magic
+}
+
+func (binaryCodec) Marshal(v interface{}) ([]byte, error) {
+	// top level pointers are not encoded, same as the JSON codec
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Kind() == reflect.Ptr {
+		return nil, fmt.Errorf("codec: marshal nil value")
+	}
+	// make the value addressable
+	value := reflect.New(rv.Type()).Elem()
+	value.Set(rv)
+
+	e := &encoder{buf: []byte{Magic, Version}}
+	if err := e.encode(value); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+func (binaryCodec) Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("codec: unmarshal requires a non-nil pointer")
+	}
+	if len(data) < 2 || data[0] != Magic {
+		return fmt.Errorf("codec: not a binary payload")
+	}
+	if data[1] != Version {
+		return fmt.Errorf("codec: unsupported binary version %d", data[1])
+	}
+	value := rv.Elem()
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+	d := &decoder{buf: data[2:]}
+	if err := d.decode(value); err != nil {
+		return err
+	}
+	if len(d.buf) != 0 {
+		return fmt.Errorf("codec: %d trailing bytes", len(d.buf))
+	}
+	return nil
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) uvarint(x uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	e.buf = append(e.buf, tmp[:n]...)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.uvarint(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) encode(v reflect.Value) error {
+	t := v.Type()
+	switch t {
+	case bigIntType:
+		e.bigInt(v.Addr().Interface().(*big.Int))
+		return nil
+	case ecPointType:
+		return e.point(v.Addr().Interface().(*curves.ECPoint))
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, 1)
+		} else {
+			e.buf = append(e.buf, 0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x := v.Int()
+		e.uvarint(uint64(x<<1) ^ uint64(x>>63))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		e.uvarint(v.Uint())
+	case reflect.String:
+		e.bytes([]byte(v.String()))
+	case reflect.Ptr:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0)
+			return nil
+		}
+		e.buf = append(e.buf, 1)
+		return e.encode(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if skipField(t.Field(i)) {
+				continue
+			}
+			if err := e.encode(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			e.bytes(v.Bytes())
+			return nil
+		}
+		e.uvarint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		entries := make([][2][]byte, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := reflect.New(t.Key()).Elem()
+			key.Set(iter.Key())
+			value := reflect.New(t.Elem()).Elem()
+			value.Set(iter.Value())
+			ke, ve := &encoder{}, &encoder{}
+			if err := ke.encode(key); err != nil {
+				return err
+			}
+			if err := ve.encode(value); err != nil {
+				return err
+			}
+			entries = append(entries, [2][]byte{ke.buf, ve.buf})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i][0], entries[j][0]) < 0
+		})
+		e.uvarint(uint64(len(entries)))
+		for _, entry := range entries {
+			e.buf = append(e.buf, entry[0]...)
+			e.buf = append(e.buf, entry[1]...)
+		}
+	default:
+		return fmt.Errorf("codec: unsupported type %s", t)
+	}
+	return nil
+}
+
+func (e *encoder) bigInt(x *big.Int) {
+	if x.Sign() < 0 {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+	e.bytes(x.Bytes())
+}
+
+func (e *encoder) point(p *curves.ECPoint) error {
+	if p.Curve == nil || p.X == nil || p.Y == nil {
+		return fmt.Errorf("codec: incomplete ECPoint")
+	}
+	switch curves.GetCurveName(p.Curve) {
+	case curves.Secp256k1:
+		e.buf = append(e.buf, curveSecp256k1)
+		e.buf = append(e.buf, (&secp256k1.PublicKey{Curve: p.Curve, X: p.X, Y: p.Y}).SerializeCompressed()...)
+	case curves.Ed25519:
+		e.buf = append(e.buf, curveEd25519)
+		e.buf = append(e.buf, (&edwards.PublicKey{Curve: p.Curve, X: p.X, Y: p.Y}).SerializeCompressed()...)
+	case curves.P256:
+		e.buf = append(e.buf, curveP256)
+		e.buf = append(e.buf, elliptic.MarshalCompressed(p.Curve, p.X, p.Y)...)
+	default:
+		return fmt.Errorf("codec: curve is not supported")
+	}
+	return nil
+}
+
+type decoder struct {
+	buf []byte
+}
+
+func (d *decoder) byte() (byte, error) {
+	if len(d.buf) < 1 {
+		return 0, fmt.Errorf("codec: unexpected end of data")
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b, nil
+}
+
+// flag read a 0 or 1 byte
+func (d *decoder) flag() (bool, error) {
+	b, err := d.byte()
+	if err != nil {
+		return false, err
+	}
+	if b > 1 {
+		return false, fmt.Errorf("codec: invalid flag byte %d", b)
+	}
+	return b == 1, nil
+}
+
+func (d *decoder) uvarint() (uint64, error) {
+	x, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		return 0, fmt.Errorf("codec: invalid varint")
+	}
+	// reject overlong encodings
+	var tmp [binary.MaxVarintLen64]byte
+	if binary.PutUvarint(tmp[:], x) != n {
+		return 0, fmt.Errorf("codec: non canonical varint")
+	}
+	d.buf = d.buf[n:]
+	return x, nil
+}
+
+// length read a length prefix, bounded by the remaining data
+func (d *decoder) length() (int, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.buf)) {
+		return 0, fmt.Errorf("codec: length %d exceeds data", n)
+	}
+	return int(n), nil
+}
+
+func (d *decoder) bytes() ([]byte, error) {
+	n, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	b := d.buf[:n:n]
+	d.buf = d.buf[n:]
+	return b, nil
+}
+
+func (d *decoder) decode(v reflect.Value) error {
+	t := v.Type()
+	switch t {
+	case bigIntType:
+		return d.bigInt(v.Addr().Interface().(*big.Int))
+	case ecPointType:
+		return d.point(v.Addr().Interface().(*curves.ECPoint))
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := d.flag()
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		u, err := d.uvarint()
+		if err != nil {
+			return err
+		}
+		x := int64(u>>1) ^ -int64(u&1)
+		if v.OverflowInt(x) {
+			return fmt.Errorf("codec: %d overflows %s", x, t)
+		}
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x, err := d.uvarint()
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(x) {
+			return fmt.Errorf("codec: %d overflows %s", x, t)
+		}
+		v.SetUint(x)
+	case reflect.String:
+		b, err := d.bytes()
+		if err != nil {
+			return err
+		}
+		v.SetString(string(b))
+	case reflect.Ptr:
+		present, err := d.flag()
+		if err != nil {
+			return err
+		}
+		if !present {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		elem := reflect.New(t.Elem())
+		if err := d.decode(elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if skipField(t.Field(i)) {
+				continue
+			}
+			if err := d.decode(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b, err := d.bytes()
+			if err != nil {
+				return err
+			}
+			v.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		n, err := d.length()
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(t, n, n)
+		for i := 0; i < n; i++ {
+			if err := d.decode(slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := d.decode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		n, err := d.length()
+		if err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(t, n)
+		var prev []byte
+		for i := 0; i < n; i++ {
+			start := d.buf
+			key := reflect.New(t.Key()).Elem()
+			if err := d.decode(key); err != nil {
+				return err
+			}
+			encodedKey := start[:len(start)-len(d.buf)]
+			if i > 0 && bytes.Compare(prev, encodedKey) >= 0 {
+				return fmt.Errorf("codec: map keys are not sorted")
+			}
+			prev = encodedKey
+			value := reflect.New(t.Elem()).Elem()
+			if err := d.decode(value); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("codec: unsupported type %s", t)
+	}
+	return nil
+}
+
+func (d *decoder) bigInt(x *big.Int) error {
+	negative, err := d.flag()
+	if err != nil {
+		return err
+	}
+	b, err := d.bytes()
+	if err != nil {
+		return err
+	}
+	if len(b) > 0 && b[0] == 0 {
+		return fmt.Errorf("codec: big.Int has leading zero")
+	}
+	if negative && len(b) == 0 {
+		return fmt.Errorf("codec: negative zero")
+	}
+	x.SetBytes(b)
+	if negative {
+		x.Neg(x)
+	}
+	return nil
+}
+
+func (d *decoder) point(p *curves.ECPoint) error {
+	id, err := d.byte()
+	if err != nil {
+		return err
+	}
+	var encoded []byte
+	switch id {
+	case curveSecp256k1:
+		if len(d.buf) < secp256k1.PubKeyBytesLenCompressed {
+			return fmt.Errorf("codec: unexpected end of data")
+		}
+		encoded = d.buf[:secp256k1.PubKeyBytesLenCompressed]
+		key, err := secp256k1.ParsePubKey(encoded)
+		if err != nil {
+			return fmt.Errorf("codec: invalid secp256k1 point: %v", err)
+		}
+		p.Curve, p.X, p.Y = key.Curve, key.X, key.Y
+	case curveEd25519:
+		if len(d.buf) < edwards.PubKeyBytesLen {
+			return fmt.Errorf("codec: unexpected end of data")
+		}
+		encoded = d.buf[:edwards.PubKeyBytesLen]
+		key, err := edwards.ParsePubKey(encoded)
+		if err != nil {
+			return fmt.Errorf("codec: invalid ed25519 point: %v", err)
+		}
+		p.Curve, p.X, p.Y = key.Curve, key.X, key.Y
+	case curveP256:
+		if len(d.buf) < p256CompressedLen {
+			return fmt.Errorf("codec: unexpected end of data")
+		}
+		encoded = d.buf[:p256CompressedLen]
+		curve := elliptic.P256()
+		x, y := elliptic.UnmarshalCompressed(curve, encoded)
+		if x == nil {
+			return fmt.Errorf("codec: invalid p256 point")
+		}
+		p.Curve, p.X, p.Y = curve, x, y
+	default:
+		return fmt.Errorf("codec: unknown curve id %d", id)
+	}
+	d.buf = d.buf[len(encoded):]
+
+	e := &encoder{}
+	if err := e.point(p); err != nil {
+		return err
+	}
+	if !bytes.Equal(e.buf[1:], encoded) || !p.IsOnCurve() {
+		return fmt.Errorf("codec: non canonical point")
+	}
+	return nil
+}
+
+// skipField unexported fields and fields tagged json:"-" are not serialized, same as the JSON codec
+func skipField(f reflect.StructField) bool {
+	if f.PkgPath != "" {
+		return true
+	}
+	return f.Tag.Get("json") == "-"
+}
@@ -0,0 +1,41 @@
+package codec
+
+import (
+	"encoding/json"
+)
+
+// Codec serialization of round payloads and saved keys
+type Codec interface {
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	// JSON legacy format, big.Int as decimal number, ECPoint as {Curve, X, Y}
+	JSON Codec = jsonCodec{}
+	// Binary compact, versioned and canonical format
+	Binary Codec = binaryCodec{}
+)
+
+// Unmarshal decode data produced by any codec, the format is detected from the first byte
+func Unmarshal(data []byte, v interface{}) error {
+	if IsBinary(data) {
+		return Binary.Unmarshal(data, v)
+	}
+	return JSON.Unmarshal(data, v)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,144 @@
+package codec
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/schnorr"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/stretchr/testify/require"
+)
+
+type payload struct {
+	C        *commitment.Commitment
+	Witness  commitment.Witness
+	Share    *vss.Share
+	Proof    *schnorr.Proof
+	Id       int
+	Negative *big.Int
+	Label    string
+	Raw      []byte
+	PubKeys  map[int]*curves.ECPoint
+	Missing  *schnorr.Proof
+	internal int
+}
+
+func newPayload(t *testing.T) *payload {
+	curve := secp256k1.S256()
+	x := crypto.RandomNum(curve.N)
+	X := curves.ScalarToPoint(curve, x)
+	proof, err := schnorr.Prove(x, X)
+	require.NoError(t, err)
+	cmt := commitment.NewCommitment(X.X, X.Y)
+
+	pubKeys := make(map[int]*curves.ECPoint)
+	for i := 1; i <= 5; i++ {
+		pubKeys[i] = curves.ScalarToPoint(curve, big.NewInt(int64(i)))
+	}
+	pubKeys[-1] = curves.ScalarToPoint(edwards.Edwards(), x)
+	pubKeys[-2] = curves.ScalarToPoint(elliptic.P256(), x)
+	return &payload{
+		C:        &cmt.C,
+		Witness:  cmt.Msg,
+		Share:    &vss.Share{Id: big.NewInt(2), Y: x},
+		Proof:    proof,
+		Id:       -3,
+		Negative: big.NewInt(-1000),
+		Label:    "chaincode",
+		Raw:      []byte{0, 1, 2},
+		PubKeys:  pubKeys,
+		internal: 7,
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	data := newPayload(t)
+	bin, err := Binary.Marshal(data)
+	require.NoError(t, err)
+	js, err := JSON.Marshal(data)
+	require.NoError(t, err)
+	fmt.Println("binary", len(bin), "json", len(js))
+	require.True(t, len(bin) < len(js))
+	require.True(t, IsBinary(bin))
+	require.False(t, IsBinary(js))
+
+	var decoded payload
+	require.NoError(t, Unmarshal(bin, &decoded))
+	require.Equal(t, 0, (*data.C).Cmp(*decoded.C))
+	require.Equal(t, len(data.Witness), len(decoded.Witness))
+	require.Equal(t, 0, data.Share.Y.Cmp(decoded.Share.Y))
+	require.True(t, data.Proof.R.Equals(decoded.Proof.R))
+	require.Equal(t, data.Id, decoded.Id)
+	require.Equal(t, 0, data.Negative.Cmp(decoded.Negative))
+	require.Equal(t, data.Label, decoded.Label)
+	require.Equal(t, data.Raw, decoded.Raw)
+	require.Nil(t, decoded.Missing)
+	require.Equal(t, 0, decoded.internal)
+	for id, point := range data.PubKeys {
+		require.True(t, point.Equals(decoded.PubKeys[id]))
+		require.Equal(t, curves.GetCurveName(point.Curve), curves.GetCurveName(decoded.PubKeys[id].Curve))
+	}
+
+	// canonical, same value same bytes
+	again, err := Binary.Marshal(&decoded)
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(bin, again))
+
+	// json codec still works through Unmarshal
+	var fromJSON payload
+	require.NoError(t, Unmarshal(js, &fromJSON))
+	require.True(t, data.Proof.R.Equals(fromJSON.Proof.R))
+}
+
+func TestBinaryRejectNonCanonical(t *testing.T) {
+	type number struct {
+		X *big.Int
+	}
+	bin, err := Binary.Marshal(&number{X: big.NewInt(1)})
+	require.NoError(t, err)
+	fmt.Printf("%x\n", bin)
+	var n number
+	require.NoError(t, Binary.Unmarshal(bin, &n))
+
+	// trailing bytes
+	require.Error(t, Binary.Unmarshal(append(bin, 0), &n))
+	// unknown version
+	require.Error(t, Binary.Unmarshal(append([]byte{Magic, Version + 1}, bin[2:]...), &n))
+	// leading zero: presence, sign, length 2, 0x00 0x01
+	require.Error(t, Binary.Unmarshal([]byte{Magic, Version, 1, 0, 2, 0, 1}, &n))
+	// negative zero
+	require.Error(t, Binary.Unmarshal([]byte{Magic, Version, 1, 1, 0}, &n))
+	// invalid presence byte
+	require.Error(t, Binary.Unmarshal([]byte{Magic, Version, 2, 0, 1, 1}, &n))
+	// overlong varint
+	require.Error(t, Binary.Unmarshal([]byte{Magic, Version, 1, 0, 0x81, 0x00, 1}, &n))
+
+	// unsorted map keys
+	type keys struct {
+		M map[int]bool
+	}
+	bin, err = Binary.Marshal(&keys{M: map[int]bool{1: true, 2: false}})
+	require.NoError(t, err)
+	var k keys
+	require.NoError(t, Binary.Unmarshal(bin, &k))
+	swapped := []byte{Magic, Version, 2, bin[5], bin[6], bin[3], bin[4]}
+	require.Error(t, Binary.Unmarshal(swapped, &k))
+
+	// point not on the curve
+	point := curves.ScalarToPoint(secp256k1.S256(), big.NewInt(5))
+	bin, err = Binary.Marshal(point)
+	require.NoError(t, err)
+	bin[len(bin)-1] ^= 1
+	var p curves.ECPoint
+	err = Binary.Unmarshal(bin, &p)
+	fmt.Println(err)
+	require.Error(t, err)
+}
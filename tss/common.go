@@ -1,12 +1,17 @@
 package tss
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
 	"math/big"
 
 	"github.com/okx/threshold-lib/crypto/commitment"
 	"github.com/okx/threshold-lib/crypto/curves"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
 type Message struct {
@@ -15,6 +20,53 @@ type Message struct {
 	Data string
 }
 
+// MarshalBinary encode the message with the binary codec,
+// use it as transport format when Data is produced by codec.Binary
+func (m *Message) MarshalBinary() ([]byte, error) {
+	return codec.Binary.Marshal(m)
+}
+
+// UnmarshalBinary decode a message produced by MarshalBinary
+func (m *Message) UnmarshalBinary(data []byte) error {
+	return codec.Binary.Unmarshal(data, m)
+}
+
+// messageJSON JSON transport form of Message, Binary holds a Data produced by codec.Binary
+type messageJSON struct {
+	From   int
+	To     int
+	Data   string
+	Binary []byte `json:",omitempty"`
+}
+
+// MarshalJSON a binary Data is not valid UTF-8 and would be mangled as a JSON string,
+// it is carried base64 encoded in Binary instead, a JSON Data keeps the legacy form
+func (m Message) MarshalJSON() ([]byte, error) {
+	out := messageJSON{From: m.From, To: m.To}
+	if codec.IsBinary([]byte(m.Data)) {
+		out.Binary = []byte(m.Data)
+	} else {
+		out.Data = m.Data
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decode a message produced by MarshalJSON
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var in messageJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if len(in.Binary) > 0 {
+		if in.Data != "" || !codec.IsBinary(in.Binary) {
+			return fmt.Errorf("invalid binary message data")
+		}
+		in.Data = string(in.Binary)
+	}
+	m.From, m.To, m.Data = in.From, in.To, in.Data
+	return nil
+}
+
 type KeyStep1Data struct {
 	C *commitment.Commitment
 }
@@ -32,3 +84,28 @@ type KeyStep3Data struct {
 	ChainCode      string                  // chaincode for derivation, no longer change when update
 	SharePubKeyMap map[int]*curves.ECPoint //  ShareI*G map
 }
+
+// SessionId hash an identifier agreed by all participants before round 1,
+// e.g. a uuid generated by the coordinator, it must be unique for each protocol run.
+// An empty id returns nil, rejected by the protocol constructors
+func SessionId(id string) *big.Int {
+	if id == "" {
+		return nil
+	}
+	hash := sha256.New()
+	hash.Write([]byte("threshold-lib session"))
+	hash.Write([]byte(id))
+	return new(big.Int).SetBytes(hash.Sum(nil))
+}
+
+// BindId bind the session id to sender and receiver,
+// used as the first committed value and as Fiat-Shamir id, so messages can't be replayed into another session or party
+func BindId(sessionId *big.Int, from, to int) *big.Int {
+	var ids [16]byte
+	binary.BigEndian.PutUint64(ids[:8], uint64(from))
+	binary.BigEndian.PutUint64(ids[8:], uint64(to))
+	hash := sha256.New()
+	hash.Write(sessionId.Bytes())
+	hash.Write(ids[:])
+	return new(big.Int).SetBytes(hash.Sum(nil))
+}
@@ -2,6 +2,7 @@ package keygen
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/okx/threshold-lib/crypto/curves"
@@ -12,10 +13,27 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var (
+	paramsOnce      sync.Once
+	paramsPaiKey    *paillier.PrivateKey
+	paramsPreParams *PreParamsWithDlnProof
+)
+
+// testParams paillier key and pre params take minutes to generate, one set is shared by the tests
+func testParams(t *testing.T) (*paillier.PrivateKey, *PreParamsWithDlnProof) {
+	paramsOnce.Do(func() {
+		paramsPaiKey, _, _ = paillier.NewKeyPair(8)
+		paramsPreParams = GeneratePreParamsWithDlnProof()
+	})
+	require.NotNil(t, paramsPaiKey)
+	return paramsPaiKey, paramsPreParams
+}
+
 func TestKeyGen(t *testing.T) {
-	setUp1 := dkg.NewSetUp(1, 3, curve)
-	setUp2 := dkg.NewSetUp(2, 3, curve)
-	setUp3 := dkg.NewSetUp(3, 3, curve)
+	sessionId := tss.SessionId("TestKeyGen")
+	setUp1 := dkg.NewSetUp(sessionId, 1, 2, 3, curve)
+	setUp2 := dkg.NewSetUp(sessionId, 2, 2, 3, curve)
+	setUp3 := dkg.NewSetUp(sessionId, 3, 2, 3, curve)
 
 	msgs1_1, _ := setUp1.DKGStep1()
 	msgs2_1, _ := setUp2.DKGStep1()
@@ -44,9 +62,7 @@ func TestKeyGen(t *testing.T) {
 	fmt.Println("=========2/2 keygen==========")
 
 	// 1-->2   1--->3
-	paiPriKey, _, err := paillier.NewKeyPair(8)
-	require.NoError(t, err)
-	p1PreParamsAndProof := GeneratePreParamsWithDlnProof() // this step should be locally done by P1
+	paiPriKey, p1PreParamsAndProof := testParams(t) // this step should be locally done by P1
 
 	// this step should be locally done by P2. To save time, we assume both setup are the same.
 	p2PreParamsAndProof := &PreParamsWithDlnProof{
@@ -84,3 +100,73 @@ func TestKeyGen(t *testing.T) {
 	fmt.Println(tssKey.PublicKey())
 
 }
+
+// mapStore PoolStore in memory, stands in for an encrypted store
+type mapStore struct {
+	mu    sync.Mutex
+	items map[string][]byte
+}
+
+func (s *mapStore) Save(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[name] = data
+	return nil
+}
+
+func (s *mapStore) Load() (map[string][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := make(map[string][]byte, len(s.items))
+	for name, data := range s.items {
+		items[name] = data
+	}
+	return items, nil
+}
+
+func (s *mapStore) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.items, name)
+	return nil
+}
+
+func (s *mapStore) Has(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.items[name]
+	return ok
+}
+
+func TestPool(t *testing.T) {
+	store := &mapStore{items: make(map[string][]byte)}
+	paiPriKey, preParams := testParams(t)
+	// persisted by a previous run
+	require.NoError(t, (&Pool{store: store}).save(paillierName(paiPriKey), paiPriKey))
+	require.NoError(t, (&Pool{store: store}).save(preParamsName(preParams), preParams))
+
+	pool, err := NewPool(store, 1, func(err error) { t.Log(err) })
+	require.NoError(t, err)
+	defer pool.Close()
+	keys, params := pool.Len()
+	require.Equal(t, 1, keys)
+	require.Equal(t, 1, params)
+
+	key, err := pool.PaillierKey()
+	require.NoError(t, err)
+	require.Equal(t, 0, key.N.Cmp(paiPriKey.N))
+	params2, err := pool.PreParams()
+	require.NoError(t, err)
+	require.Equal(t, 0, params2.Params.NTildei.Cmp(preParams.Params.NTildei))
+	require.True(t, params2.Verify())
+
+	// handed out only once
+	require.False(t, store.Has(paillierName(key)))
+	require.False(t, store.Has(preParamsName(params2)))
+	fmt.Println("pool", key.N.BitLen(), params2.Params.NTildei.BitLen())
+
+	// a corrupted item fails at startup
+	corrupted := &mapStore{items: map[string][]byte{paillierName(paiPriKey): []byte("{}")}}
+	_, err = NewPool(corrupted, 1, nil)
+	require.Error(t, err)
+}
@@ -1,7 +1,7 @@
 package keygen
 
 import (
-	"encoding/json"
+	"crypto/elliptic"
 	"fmt"
 	"math/big"
 
@@ -14,12 +14,11 @@ import (
 	"github.com/okx/threshold-lib/crypto/vss"
 	"github.com/okx/threshold-lib/crypto/zkp"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
-var (
-	curve = secp256k1.S256()
-	G     = curves.ScalarToPoint(curve, big.NewInt(1))
-)
+// curve default curve of 2-party ecdsa
+var curve = secp256k1.S256()
 
 type PreParams struct {
 	NTildei     *big.Int
@@ -104,7 +103,23 @@ type P1Data struct {
 
 // P1 after dkg, prepare for 2-party signature, P1 send encrypt x1 to P2
 // RPC: paillier key pair generation is time-consuming, generated in advance, encrypted storage?
-func P1(share1 *big.Int, paiPriKey *paillier.PrivateKey, from, to int, preParamsAndProof *PreParamsWithDlnProof, p2_ped *pedersen.PedersenParameters, p2_dlnproof *zkp.DlnProof) (*tss.Message, *big.Int, error) {
+// ecCurve is the curve of the dkg key, secp256k1 by default
+func P1(share1 *big.Int, paiPriKey *paillier.PrivateKey, from, to int, preParamsAndProof *PreParamsWithDlnProof, p2_ped *pedersen.PedersenParameters, p2_dlnproof *zkp.DlnProof, ecCurve ...elliptic.Curve) (*tss.Message, *big.Int, error) {
+	return P1WithCodec(codec.JSON, share1, paiPriKey, from, to, preParamsAndProof, p2_ped, p2_dlnproof, ecCurve...)
+}
+
+// P1WithCodec same as P1, the message payload is encoded with c
+func P1WithCodec(c codec.Codec, share1 *big.Int, paiPriKey *paillier.PrivateKey, from, to int, preParamsAndProof *PreParamsWithDlnProof, p2_ped *pedersen.PedersenParameters, p2_dlnproof *zkp.DlnProof, ecCurve ...elliptic.Curve) (*tss.Message, *big.Int, error) {
+	if c == nil {
+		return nil, nil, fmt.Errorf("codec is nil")
+	}
+	var curve elliptic.Curve = curve
+	if len(ecCurve) > 0 && ecCurve[0] != nil {
+		curve = ecCurve[0]
+	}
+	if curves.GetCurveName(curve) == "" || curves.GetCurveName(curve) == curves.Ed25519 {
+		return nil, nil, fmt.Errorf("unsupported ecdsa curve")
+	}
 	if !zkp.DlnVerify(p2_dlnproof, p2_ped.T, p2_ped.S, p2_ped.Ntilde) {
 		return nil, nil, fmt.Errorf("fail to verify dln proof for p2 pederson parameters. ")
 	}
@@ -129,6 +144,7 @@ func P1(share1 *big.Int, paiPriKey *paillier.PrivateKey, from, to int, preParams
 	}
 	// PDLwSlackStatement
 	q_bitlen := uint(X1.Curve.Params().N.BitLen())
+	G := curves.ScalarToPoint(curve, big.NewInt(1))
 	X1RangeProof := zkp.NewGroupElementPaillierEncryptionRangeProof(
 		paiPriKey.N, E_x1, x1, r, q_bitlen, X1, G, p2_ped, security_params,
 	)
@@ -155,7 +171,7 @@ func P1(share1 *big.Int, paiPriKey *paillier.PrivateKey, from, to int, preParams
 		X1RangeProof:       X1RangeProof,
 	}
 
-	bytes, err := json.Marshal(p1Data)
+	bytes, err := c.Marshal(p1Data)
 	if err != nil {
 		return nil, nil, err
 	}
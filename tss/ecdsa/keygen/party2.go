@@ -1,7 +1,6 @@
 package keygen
 
 import (
-	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/okx/threshold-lib/crypto/vss"
 	"github.com/okx/threshold-lib/crypto/zkp"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
 type P2SaveData struct {
@@ -25,15 +25,23 @@ type P2SaveData struct {
 }
 
 // P2 after dkg, prepare for 2-party signature, P2 receives encrypt x1 and paillier public key from P1
+// the curve is taken from publicKey
 func P2(share2 *big.Int, publicKey *curves.ECPoint, msg *tss.Message, from, to int, ped2 *pedersen.PedersenParameters) (*P2SaveData, error) {
+	if publicKey == nil || publicKey.Curve == nil {
+		return nil, fmt.Errorf("publicKey is nil")
+	}
+	curve := publicKey.Curve
 	if msg.From != from || msg.To != to {
 		return nil, fmt.Errorf("message mismatch")
 	}
 	p1Data := &P1Data{}
-	err := json.Unmarshal([]byte(msg.Data), p1Data)
+	err := codec.Unmarshal([]byte(msg.Data), p1Data)
 	if err != nil {
 		return nil, err
 	}
+	if p1Data.X1 == nil || curves.GetCurveName(p1Data.X1.Curve) != curves.GetCurveName(curve) {
+		return nil, fmt.Errorf("X1 is not on the publicKey curve")
+	}
 	// lagrangian interpolation x2, x = x1 + x2
 	x2 := vss.CalLagrangian(curve, big.NewInt(int64(to)), share2, []*big.Int{big.NewInt(int64(from)), big.NewInt(int64(to))})
 	X2 := curves.ScalarToPoint(curve, x2)
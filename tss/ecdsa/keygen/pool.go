@@ -0,0 +1,213 @@
+package keygen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/okx/threshold-lib/crypto/paillier"
+)
+
+const (
+	paillierPrefix  = "paillier-"
+	preParamsPrefix = "preparams-"
+)
+
+// PoolStore persists pool items until they are handed out. The items hold secret primes,
+// implementations must encrypt them, names are letters, digits and '-'
+type PoolStore interface {
+	// Save store data under name
+	Save(name string, data []byte) error
+	// Load all stored items by name
+	Load() (map[string][]byte, error)
+	// Remove delete name, a missing name is not an error
+	Remove(name string) error
+}
+
+// Pool pre-generate paillier keys and PreParamsWithDlnProof in background,
+// safe prime generation takes seconds to minutes, the pool hands them out instantly.
+// Every item is handed out only once. When store is set, items are persisted there until handed out.
+type Pool struct {
+	store        PoolStore
+	onError      func(error)
+	paillierKeys chan *paillier.PrivateKey
+	preParams    chan *PreParamsWithDlnProof
+
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewPool create a pool keeping size items of each kind, store is optional, nil keeps items in memory only.
+// onError receives the errors of background generation and persistence, generation goes on after an error
+func NewPool(store PoolStore, size int, onError func(error)) (*Pool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("pool size must be positive")
+	}
+	pool := &Pool{
+		store:        store,
+		onError:      onError,
+		paillierKeys: make(chan *paillier.PrivateKey, size),
+		preParams:    make(chan *PreParamsWithDlnProof, size),
+		quit:         make(chan struct{}),
+	}
+	if store != nil {
+		if err := pool.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	go pool.fill(func() error {
+		key, _, err := paillier.NewKeyPair()
+		if err != nil {
+			return err
+		}
+		// not persisted, still usable until the process exits
+		pool.report(pool.save(paillierName(key), key))
+		select {
+		case pool.paillierKeys <- key:
+			return nil
+		case <-pool.quit:
+			return pool.remove(paillierName(key))
+		}
+	})
+	go pool.fill(func() error {
+		params := GeneratePreParamsWithDlnProof()
+		pool.report(pool.save(preParamsName(params), params))
+		select {
+		case pool.preParams <- params:
+			return nil
+		case <-pool.quit:
+			return pool.remove(preParamsName(params))
+		}
+	})
+	return pool, nil
+}
+
+// PaillierKey take a pre-generated paillier key, generate one if the pool is empty
+func (pool *Pool) PaillierKey() (*paillier.PrivateKey, error) {
+	select {
+	case key := <-pool.paillierKeys:
+		if err := pool.remove(paillierName(key)); err != nil {
+			return nil, err
+		}
+		return key, nil
+	default:
+		key, _, err := paillier.NewKeyPair()
+		return key, err
+	}
+}
+
+// PreParams take a pre-generated PreParamsWithDlnProof, generate one if the pool is empty
+func (pool *Pool) PreParams() (*PreParamsWithDlnProof, error) {
+	select {
+	case params := <-pool.preParams:
+		if err := pool.remove(preParamsName(params)); err != nil {
+			return nil, err
+		}
+		return params, nil
+	default:
+		return GeneratePreParamsWithDlnProof(), nil
+	}
+}
+
+// Len number of ready paillier keys and pre-params
+func (pool *Pool) Len() (int, int) {
+	return len(pool.paillierKeys), len(pool.preParams)
+}
+
+// Close stop background generation without waiting for the item in progress, ready items stay persisted
+func (pool *Pool) Close() {
+	pool.closeOnce.Do(func() {
+		close(pool.quit)
+	})
+}
+
+// fill run generate until the pool is closed, generate blocks while the pool is full
+func (pool *Pool) fill(generate func() error) {
+	for {
+		select {
+		case <-pool.quit:
+			return
+		default:
+		}
+		pool.report(generate())
+	}
+}
+
+// report pass a non nil error to onError
+func (pool *Pool) report(err error) {
+	if err != nil && pool.onError != nil {
+		pool.onError(err)
+	}
+}
+
+// load persisted items, at most size of each kind
+func (pool *Pool) load() error {
+	items, err := pool.store.Load()
+	if err != nil {
+		return err
+	}
+	for name, bytes := range items {
+		switch {
+		case strings.HasPrefix(name, paillierPrefix) && len(pool.paillierKeys) < cap(pool.paillierKeys):
+			key := new(paillier.PrivateKey)
+			if err := json.Unmarshal(bytes, key); err != nil || !validPaillierKey(key) || paillierName(key) != name {
+				return fmt.Errorf("invalid pool item %s", name)
+			}
+			pool.paillierKeys <- key
+		case strings.HasPrefix(name, preParamsPrefix) && len(pool.preParams) < cap(pool.preParams):
+			params := new(PreParamsWithDlnProof)
+			if err := json.Unmarshal(bytes, params); err != nil || !validPreParams(params) || preParamsName(params) != name {
+				return fmt.Errorf("invalid pool item %s", name)
+			}
+			pool.preParams <- params
+		}
+	}
+	return nil
+}
+
+func (pool *Pool) save(name string, v interface{}) error {
+	if pool.store == nil {
+		return nil
+	}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return pool.store.Save(name, bytes)
+}
+
+func (pool *Pool) remove(name string) error {
+	if pool.store == nil {
+		return nil
+	}
+	return pool.store.Remove(name)
+}
+
+func validPaillierKey(key *paillier.PrivateKey) bool {
+	return key.N != nil && key.P != nil && key.Q != nil && key.Lambda != nil && key.Phi != nil &&
+		new(big.Int).Mul(key.P, key.Q).Cmp(key.N) == 0
+}
+
+func validPreParams(params *PreParamsWithDlnProof) bool {
+	p := params.Params
+	return p != nil && params.Proof != nil && p.NTildei != nil && p.H1i != nil && p.H2i != nil &&
+		p.Alpha != nil && p.Beta != nil && p.P != nil && p.Q != nil && params.Verify()
+}
+
+func paillierName(key *paillier.PrivateKey) string {
+	return paillierPrefix + fingerprint(key.N)
+}
+
+func preParamsName(params *PreParamsWithDlnProof) string {
+	return preParamsPrefix + fingerprint(params.Params.NTildei)
+}
+
+func fingerprint(n *big.Int) string {
+	hash := sha256.Sum256(n.Bytes())
+	return hex.EncodeToString(hash[:16])
+}
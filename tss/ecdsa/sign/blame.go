@@ -0,0 +1,24 @@
+package sign
+
+import (
+	"encoding/json"
+
+	"github.com/okx/threshold-lib/tss"
+)
+
+// 2-party signature has no device number, blame reports the party role
+const (
+	PartyP1 = 1
+	PartyP2 = 2
+)
+
+// blame build BlameError against the counterparty, evidence is json encoded
+func blame(from, round int, check string, evidence ...interface{}) *tss.BlameError {
+	bytes, _ := json.Marshal(evidence)
+	return &tss.BlameError{
+		From:     from,
+		Round:    round,
+		Check:    check,
+		Evidence: string(bytes),
+	}
+}
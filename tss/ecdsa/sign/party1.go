@@ -10,20 +10,23 @@ import (
 	"github.com/okx/threshold-lib/crypto"
 	"github.com/okx/threshold-lib/crypto/commitment"
 	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
 	"github.com/okx/threshold-lib/crypto/paillier"
 	"github.com/okx/threshold-lib/crypto/pedersen"
+	"github.com/okx/threshold-lib/crypto/prehash"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/crypto/zkp"
+	"github.com/okx/threshold-lib/tss/key/bip32"
 )
 
-var (
-	curve = secp256k1.S256()
-)
+// curve default curve, the contexts use the curve of the publicKey
+var curve = secp256k1.S256()
 
 type P1Context struct {
 	sessionID *big.Int
 
 	publicKey *ecdsa.PublicKey
+	group     group.Group
 	paiPriKey *paillier.PrivateKey
 
 	k1      *big.Int
@@ -34,8 +37,16 @@ type P1Context struct {
 	p1_ped  *pedersen.PedersenParameters
 }
 
-// NewP1 2-party signature, P1 init
-func NewP1(publicKey *ecdsa.PublicKey, message string, paiPriKey *paillier.PrivateKey, E_x1 *big.Int, p1_ped *pedersen.PedersenParameters) *P1Context {
+// NewP1 2-party signature, P1 init, message is the hex digest, or the hex message hashed with the optional mode
+func NewP1(publicKey *ecdsa.PublicKey, message string, paiPriKey *paillier.PrivateKey, E_x1 *big.Int, p1_ped *pedersen.PedersenParameters, mode ...prehash.Mode) *P1Context {
+	g := keyGroup(publicKey)
+	if g == nil {
+		return nil
+	}
+	message, err := prehashMessage(message, mode)
+	if err != nil {
+		return nil
+	}
 	msg, err := hex.DecodeString(message)
 	if err != nil {
 		return nil
@@ -45,6 +56,7 @@ func NewP1(publicKey *ecdsa.PublicKey, message string, paiPriKey *paillier.Priva
 
 	p1Context := &P1Context{
 		publicKey: publicKey,
+		group:     g,
 		message:   message,
 		paiPriKey: paiPriKey,
 		sessionID: sessionId,
@@ -54,13 +66,25 @@ func NewP1(publicKey *ecdsa.PublicKey, message string, paiPriKey *paillier.Priva
 	return p1Context
 }
 
+// NewP1WithTssKey P1 init for a bip32 child key, e.g. root.DerivePath("m/0/1"), the child only changes the publicKey,
+// P2 adds the derivation offset to x2, E_x1 is unchanged. tssKey may be watch-only
+func NewP1WithTssKey(tssKey *bip32.TssKey, message string, paiPriKey *paillier.PrivateKey, E_x1 *big.Int, p1_ped *pedersen.PedersenParameters, mode ...prehash.Mode) *P1Context {
+	if tssKey == nil {
+		return nil
+	}
+	return NewP1(childPublicKey(tssKey), message, paiPriKey, E_x1, p1_ped, mode...)
+}
+
 func (p1 *P1Context) Step1() (*commitment.Commitment, error) {
 	if BanSignList.Has(hex.EncodeToString(p1.publicKey.X.Bytes())) {
 		return nil, fmt.Errorf("ecdsa sign forbidden, publicKey " + hex.EncodeToString(p1.publicKey.X.Bytes()))
 	}
 	// random generate k1, k=k1*k2
-	p1.k1 = crypto.RandomNum(curve.N)
-	R1 := curves.ScalarToPoint(curve, p1.k1)
+	k1, R1, err := newNonce(p1.group)
+	if err != nil {
+		return nil, err
+	}
+	p1.k1 = k1
 	cmt := commitment.NewCommitment(p1.sessionID, R1.X, R1.Y)
 	p1.cmtD = &cmt.Msg
 	return &cmt.C, nil
@@ -70,11 +94,21 @@ func (p1 *P1Context) Step2(p2Proof *schnorr.Proof, R2 *curves.ECPoint) (*schnorr
 	// zk schnorr verify k2
 	verify := schnorr.VerifyWithId(p1.sessionID, p2Proof, R2)
 	if !verify {
-		return nil, nil, fmt.Errorf("schnorr verify fail")
+		return nil, nil, blame(PartyP2, 2, "schnorr verify fail", p2Proof, R2)
+	}
+	if curves.GetCurveName(R2.Curve) != curves.GetCurveName(p1.publicKey.Curve) {
+		return nil, nil, blame(PartyP2, 2, "R2 is not on the publicKey curve", p2Proof, R2)
 	}
 	p1.R2 = R2
 	// zk schnorr prove k1
-	R1 := curves.ScalarToPoint(curve, p1.k1)
+	k1, err := p1.group.ScalarFromSecret(p1.k1)
+	if err != nil {
+		return nil, nil, err
+	}
+	R1, err := p1.group.Generator().ScalarMult(k1).ECPoint()
+	if err != nil {
+		return nil, nil, err
+	}
 	proof, err := schnorr.ProveWithId(p1.sessionID, p1.k1, R1)
 	if err != nil {
 		return nil, nil, err
@@ -82,8 +116,25 @@ func (p1 *P1Context) Step2(p2Proof *schnorr.Proof, R2 *curves.ECPoint) (*schnorr
 	return proof, p1.cmtD, nil
 }
 
+// Step3 P1 decrypt s, return the low-s signature (r, s)
 func (p1 *P1Context) Step3(E_k2_h_xr *big.Int, affGProof *zkp.AffGProof) (*big.Int, *big.Int, error) {
-	q := curve.N
+	sig, err := p1.Step3Signature(E_k2_h_xr, affGProof)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sig.R, sig.S, nil
+}
+
+// Step3Signature same as Step3, return the low-s signature with recovery id
+func (p1 *P1Context) Step3Signature(E_k2_h_xr *big.Int, affGProof *zkp.AffGProof) (*Signature, error) {
+	curve := p1.publicKey.Curve
+	q := curve.Params().N
+	if E_k2_h_xr == nil || affGProof == nil || affGProof.X == nil || affGProof.Y == nil || affGProof.Bx == nil || affGProof.By == nil {
+		return nil, blame(PartyP2, 3, "incomplete message", E_k2_h_xr, affGProof)
+	}
+	if curves.GetCurveName(affGProof.X.Curve) != curves.GetCurveName(curve) {
+		return nil, blame(PartyP2, 3, "affine proof is not on the publicKey curve", E_k2_h_xr, affGProof)
+	}
 	statement := &zkp.AffGStatement{
 		N: p1.paiPriKey.N,
 		C: p1.E_x1,
@@ -95,38 +146,49 @@ func (p1 *P1Context) Step3(E_k2_h_xr *big.Int, affGProof *zkp.AffGProof) (*big.I
 	verify := zkp.PaillierAffineVerify(p1.p1_ped, affGProof, statement)
 	if !verify {
 		BanSignList.Add(hex.EncodeToString(p1.publicKey.X.Bytes()))
-		return nil, nil, fmt.Errorf("paillier affine verify fail")
+		return nil, blame(PartyP2, 3, "paillier affine verify fail", E_k2_h_xr, affGProof)
 	}
 
 	// R = k1*k2*G, k = k1*k2
-	Rx, _ := curve.ScalarMult(p1.R2.X, p1.R2.Y, p1.k1.Bytes())
+	Rx, Ry, err := jointR(p1.group, p1.k1, p1.R2)
+	if err != nil {
+		return nil, err
+	}
 	r := new(big.Int).Mod(Rx, q)
 	// paillier Decrypt (h+xr)/k2
 	k2_h_xr, err := p1.paiPriKey.Decrypt(E_k2_h_xr)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	k1, err := p1.group.ScalarFromSecret(p1.k1)
+	if err != nil {
+		return nil, err
+	}
+	k1_1, err := k1.Invert()
+	if err != nil {
+		return nil, err
 	}
-	k1_1 := new(big.Int).ModInverse(p1.k1, q)
-	// s = (h+r*(x1+x2))/(k1*k2)
-	s := new(big.Int).Mod(new(big.Int).Mul(k2_h_xr, k1_1), q)
+	// s = (h+r*(x1+x2))/(k1*k2), k2_h_xr is masked by rho*q and has to be reduced
+	s := p1.group.ScalarFromBigInt(k2_h_xr).Mul(k1_1).BigInt()
 
 	halfOrder := new(big.Int).Rsh(q, 1)
-	if s.Cmp(halfOrder) == 1 {
+	flipped := s.Cmp(halfOrder) == 1
+	if flipped {
 		s.Sub(q, s)
 	}
 	if s.Sign() == 0 {
-		return nil, nil, fmt.Errorf("calculated S is zero")
+		return nil, fmt.Errorf("calculated S is zero")
 	}
 	message, err := hex.DecodeString(p1.message)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	// check ecdsa signature
 	ok := ecdsa.Verify(p1.publicKey, message, r, s)
 	if !ok {
 		// IMPORTANT: If Verify fails, actively disallow signing to prevent attacks described in CVE-2023-33242
 		BanSignList.Add(hex.EncodeToString(p1.publicKey.X.Bytes()))
-		return nil, nil, fmt.Errorf("ecdsa sign verify fail")
+		return nil, blame(PartyP2, 3, "ecdsa sign verify fail", E_k2_h_xr, affGProof)
 	}
-	return r, s, nil
+	return &Signature{R: r, S: s, V: recoveryId(curve, Rx, Ry, flipped), curve: curve}, nil
 }
@@ -2,6 +2,7 @@ package sign
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -9,10 +10,13 @@ import (
 	"github.com/okx/threshold-lib/crypto"
 	"github.com/okx/threshold-lib/crypto/commitment"
 	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
 	"github.com/okx/threshold-lib/crypto/paillier"
 	"github.com/okx/threshold-lib/crypto/pedersen"
+	"github.com/okx/threshold-lib/crypto/prehash"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/crypto/zkp"
+	"github.com/okx/threshold-lib/tss/key/bip32"
 )
 
 type P2Context struct {
@@ -22,14 +26,23 @@ type P2Context struct {
 	E_x1      *big.Int
 	paiPub    *paillier.PublicKey
 	PublicKey *ecdsa.PublicKey
+	group     group.Group
 	message   string
 	k2        *big.Int
 	cmtC      *commitment.Commitment
 	p1_ped    *pedersen.PedersenParameters
 }
 
-// NewP1 2-party signature, P2 init
-func NewP2(bobPri, E_x1 *big.Int, publicKey *ecdsa.PublicKey, paiPub *paillier.PublicKey, message string, p1_ped *pedersen.PedersenParameters) *P2Context {
+// NewP2 2-party signature, P2 init, message and mode as NewP1
+func NewP2(bobPri, E_x1 *big.Int, publicKey *ecdsa.PublicKey, paiPub *paillier.PublicKey, message string, p1_ped *pedersen.PedersenParameters, mode ...prehash.Mode) *P2Context {
+	g := keyGroup(publicKey)
+	if g == nil {
+		return nil
+	}
+	message, err := prehashMessage(message, mode)
+	if err != nil {
+		return nil
+	}
 	msg, err := hex.DecodeString(message)
 	if err != nil {
 		return nil
@@ -42,6 +55,7 @@ func NewP2(bobPri, E_x1 *big.Int, publicKey *ecdsa.PublicKey, paiPub *paillier.P
 		E_x1:      E_x1,
 		paiPub:    paiPub,
 		PublicKey: publicKey,
+		group:     g,
 		message:   message,
 		sessionID: sessionId,
 		p1_ped:    p1_ped,
@@ -49,12 +63,24 @@ func NewP2(bobPri, E_x1 *big.Int, publicKey *ecdsa.PublicKey, paiPub *paillier.P
 	return p2Context
 }
 
+// NewP2WithTssKey P2 init for a bip32 child key derived from the x2 root key, x2 is replaced by
+// x2 + PrivateKeyOffset so x1 + x2 is the child private key
+func NewP2WithTssKey(tssKey *bip32.TssKey, E_x1 *big.Int, paiPub *paillier.PublicKey, message string, p1_ped *pedersen.PedersenParameters, mode ...prehash.Mode) *P2Context {
+	if tssKey == nil || tssKey.ShareI() == nil {
+		return nil
+	}
+	return NewP2(tssKey.ShareI(), E_x1, childPublicKey(tssKey), paiPub, message, p1_ped, mode...)
+}
+
 func (p2 *P2Context) Step1(cmtC *commitment.Commitment) (*schnorr.Proof, *curves.ECPoint, error) {
 	p2.cmtC = cmtC
 
 	// random generate k2, k=k1*k2
-	p2.k2 = crypto.RandomNum(curve.N)
-	R2 := curves.ScalarToPoint(curve, p2.k2)
+	k2, R2, err := newNonce(p2.group)
+	if err != nil {
+		return nil, nil, err
+	}
+	p2.k2 = k2
 	proof, err := schnorr.ProveWithId(p2.sessionID, p2.k2, R2)
 	if err != nil {
 		return nil, nil, err
@@ -64,37 +90,47 @@ func (p2 *P2Context) Step1(cmtC *commitment.Commitment) (*schnorr.Proof, *curves
 
 // Step2 paillier encrypt compute, return E[(h+xr)/k2]
 func (p2 *P2Context) Step2(cmtD *commitment.Witness, p1Proof *schnorr.Proof) (*big.Int, *zkp.AffGProof, error) {
-	q := curve.N
+	curve := p2.PublicKey.Curve
+	q := curve.Params().N
 	// check R1=k1*G commitment
 	commit := commitment.HashCommitment{}
 	commit.C = *p2.cmtC
 	commit.Msg = *cmtD
 	ok, commitD := commit.Open()
-	if !ok {
-		return nil, nil, fmt.Errorf("commitment DeCommit fail")
+	if !ok || len(commitD) != 3 {
+		return nil, nil, blame(PartyP1, 2, "commitment DeCommit fail", cmtD)
 	}
 	if commitD[0].Cmp(p2.sessionID) != 0 {
-		return nil, nil, fmt.Errorf("p2 Step2 commitment sessionId error")
+		return nil, nil, blame(PartyP1, 2, "commitment sessionId error", cmtD)
 	}
 	R1, err := curves.NewECPoint(curve, commitD[1], commitD[2])
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, blame(PartyP1, 2, "invalid R1", cmtD)
 	}
 	verify := schnorr.VerifyWithId(p2.sessionID, p1Proof, R1)
 	if !verify {
-		return nil, nil, fmt.Errorf("schnorr verify fail")
+		return nil, nil, blame(PartyP1, 2, "schnorr verify fail", p1Proof, R1)
 	}
 	// R = k1*k2*G, k = k1*k2
-	Rx, _ := curve.ScalarMult(R1.X, R1.Y, p2.k2.Bytes())
+	Rx, _, err := jointR(p2.group, p2.k2, R1)
+	if err != nil {
+		return nil, nil, err
+	}
 	r := new(big.Int).Mod(Rx, q)
 	bytes, err := hex.DecodeString(p2.message)
 	if err != nil {
 		return nil, nil, err
 	}
-	k2_1 := new(big.Int).ModInverse(p2.k2, q)
+	k2, err := p2.group.ScalarFromSecret(p2.k2)
+	if err != nil {
+		return nil, nil, err
+	}
+	k2_1, err := k2.Invert()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	h := CalculateM(bytes)
-	h = new(big.Int).Mul(h, k2_1) // h/k2
+	h := p2.group.ScalarFromBigInt(CalculateM(bytes, curve)).Mul(k2_1).BigInt() // h/k2
 
 	rho := crypto.RandomNum(new(big.Int).Mul(q, q))
 	rhoq := new(big.Int).Mul(rho, q)
@@ -105,7 +141,7 @@ func (p2 *P2Context) Step2(cmtD *commitment.Witness, p1Proof *schnorr.Proof) (*b
 
 	// s' = (h+r*(x1+x2))/k2 = a * x1 + b
 	// a = r/k2, b = h/k2 + rho * q + r/k2 * x2
-	a := new(big.Int).Mul(r, k2_1)                            // r/k2
+	a := p2.group.ScalarFromBigInt(r).Mul(k2_1).BigInt()      // r/k2
 	b := new(big.Int).Add(h_rhoq, new(big.Int).Mul(a, p2.x2)) // h/k2 + rho*q + r/k2 * x2
 	rnd := crypto.RandomNum(paiPubKey.N)
 
@@ -133,8 +169,76 @@ func (p2 *P2Context) Step2(cmtD *commitment.Witness, p1Proof *schnorr.Proof) (*b
 	return E_k2_h_xr, aff_g_proof, nil
 }
 
-func CalculateM(hash []byte) *big.Int {
-	orderBits := curve.N.BitLen()
+func childPublicKey(tssKey *bip32.TssKey) *ecdsa.PublicKey {
+	pub := tssKey.PublicKey()
+	return &ecdsa.PublicKey{Curve: pub.Curve, X: pub.X, Y: pub.Y}
+}
+
+// keyGroup group of an ecdsa publicKey on a registered weierstrass curve, secp256k1 or p256, nil when unsupported
+func keyGroup(publicKey *ecdsa.PublicKey) group.Group {
+	if publicKey == nil || publicKey.Curve == nil || publicKey.X == nil || publicKey.Y == nil {
+		return nil
+	}
+	name := curves.GetCurveName(publicKey.Curve)
+	if name == "" || name == curves.Ed25519 {
+		return nil
+	}
+	g, err := group.FromCurve(publicKey.Curve)
+	if err != nil {
+		return nil
+	}
+	return g
+}
+
+// newNonce random k and k*G
+func newNonce(g group.Group) (*big.Int, *curves.ECPoint, error) {
+	k, err := g.RandomScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	R, err := g.Generator().ScalarMult(k).ECPoint()
+	if err != nil {
+		return nil, nil, err
+	}
+	return k.BigInt(), R, nil
+}
+
+// jointR R = k*Rj for the other party's nonce point Rj, R = k1*k2*G must not be the identity
+func jointR(g group.Group, k *big.Int, Rj *curves.ECPoint) (*big.Int, *big.Int, error) {
+	if Rj == nil || curves.GetCurveName(Rj.Curve) != g.Name() {
+		return nil, nil, fmt.Errorf("nonce point is not on %s", g.Name())
+	}
+	point, err := g.PointFromAffine(Rj.X, Rj.Y)
+	if err != nil {
+		return nil, nil, err
+	}
+	ks, err := g.ScalarFromSecret(k)
+	if err != nil {
+		return nil, nil, err
+	}
+	R := point.ScalarMult(ks)
+	if R.IsIdentity() {
+		return nil, nil, fmt.Errorf("R is the identity")
+	}
+	Rx, Ry := R.Affine()
+	return Rx, Ry, nil
+}
+
+// prehashMessage hex digest signed by ecdsa, without mode the message is the digest itself
+func prehashMessage(message string, mode []prehash.Mode) (string, error) {
+	if len(mode) == 0 {
+		return message, nil
+	}
+	return prehash.DigestHex(mode[0], message)
+}
+
+// CalculateM hash truncated to the bit length of the curve order, secp256k1 by default
+func CalculateM(hash []byte, ecCurve ...elliptic.Curve) *big.Int {
+	var curve elliptic.Curve = curve
+	if len(ecCurve) > 0 && ecCurve[0] != nil {
+		curve = ecCurve[0]
+	}
+	orderBits := curve.Params().N.BitLen()
 	orderBytes := (orderBits + 7) / 8
 	if len(hash) > orderBytes {
 		hash = hash[:orderBytes]
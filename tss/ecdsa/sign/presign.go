@@ -0,0 +1,363 @@
+package sign
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/paillier"
+	"github.com/okx/threshold-lib/crypto/pedersen"
+	"github.com/okx/threshold-lib/crypto/prehash"
+	"github.com/okx/threshold-lib/crypto/schnorr"
+	"github.com/okx/threshold-lib/crypto/zkp"
+)
+
+// PresignStore records used presignature ids, reusing k leaks the private key.
+// Use must persist the id before it returns nil and fail if the id was already recorded,
+// also by an earlier process, so a presignature restored from storage can't be signed with twice
+type PresignStore interface {
+	Use(id string) error
+}
+
+// MemoryPresignStore PresignStore in memory, only for presignatures that never outlive the process
+type MemoryPresignStore struct {
+	mu   sync.Mutex
+	used BanList
+}
+
+func NewMemoryPresignStore() *MemoryPresignStore {
+	return &MemoryPresignStore{used: make(map[string]struct{})}
+}
+
+func (s *MemoryPresignStore) Use(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.used.Has(id) {
+		return fmt.Errorf("presign %s already used", id)
+	}
+	s.used.Add(id)
+	return nil
+}
+
+// P1Presign P1 one-time presignature, k1 and R2 = k2*G.
+// K1 is secret, keep the presignature encrypted and delete it once NewP1WithPresign returns,
+// the id is recorded in the PresignStore before K1 is used and K1 is zeroed afterwards
+type P1Presign struct {
+	Id        string
+	PublicKey *curves.ECPoint
+	K1        *big.Int
+	R2        *curves.ECPoint
+}
+
+// P2Presign P2 one-time presignature, message independent part of E[(h+xr)/k2].
+// K2Inv, A, B and Rnd are secret, same storage contract as P1Presign, they are zeroed by OnlineStep
+type P2Presign struct {
+	Id        string
+	PublicKey *curves.ECPoint
+	K2Inv     *big.Int // 1/k2
+	A         *big.Int // r/k2
+	B         *big.Int // rho*q + r/k2 * x2
+	E_x1_a    *big.Int // E(x1)^a
+	Rnd       *big.Int // paillier randomness
+	RndN      *big.Int // rnd^N mod N2
+}
+
+type P1PresignContext struct {
+	sessionID *big.Int
+	nonce     *big.Int
+	publicKey *ecdsa.PublicKey
+	group     group.Group
+	k1        *big.Int
+	cmtD      *commitment.Witness
+}
+
+type P2PresignContext struct {
+	sessionID *big.Int
+	x2        *big.Int
+	E_x1      *big.Int
+	paiPub    *paillier.PublicKey
+	publicKey *ecdsa.PublicKey
+	group     group.Group
+	k2        *big.Int
+	cmtC      *commitment.Commitment
+}
+
+// NewP1Presign 2-party presignature, P1 init, run before message is known
+func NewP1Presign(publicKey *ecdsa.PublicKey) *P1PresignContext {
+	g := keyGroup(publicKey)
+	if g == nil {
+		return nil
+	}
+	nonce := crypto.RandomNum(publicKey.Curve.Params().N)
+	return &P1PresignContext{
+		sessionID: crypto.SHA256Int(publicKey.X, publicKey.Y, nonce),
+		nonce:     nonce,
+		publicKey: publicKey,
+		group:     g,
+	}
+}
+
+// Step1 return presign nonce and R1 commitment
+func (p1 *P1PresignContext) Step1() (*big.Int, *commitment.Commitment, error) {
+	if BanSignList.Has(hex.EncodeToString(p1.publicKey.X.Bytes())) {
+		return nil, nil, fmt.Errorf("ecdsa sign forbidden, publicKey " + hex.EncodeToString(p1.publicKey.X.Bytes()))
+	}
+	k1, R1, err := newNonce(p1.group)
+	if err != nil {
+		return nil, nil, err
+	}
+	p1.k1 = k1
+	cmt := commitment.NewCommitment(p1.sessionID, R1.X, R1.Y)
+	p1.cmtD = &cmt.Msg
+	return p1.nonce, &cmt.C, nil
+}
+
+// Step2 verify k2 proof, open R1 commitment, return P1 presignature
+func (p1 *P1PresignContext) Step2(p2Proof *schnorr.Proof, R2 *curves.ECPoint) (*schnorr.Proof, *commitment.Witness, *P1Presign, error) {
+	if p1.k1 == nil {
+		return nil, nil, nil, fmt.Errorf("round error")
+	}
+	if p2Proof == nil || R2 == nil || !schnorr.VerifyWithId(p1.sessionID, p2Proof, R2) {
+		return nil, nil, nil, blame(PartyP2, 2, "schnorr verify fail", p2Proof, R2)
+	}
+	curve := p1.publicKey.Curve
+	if curves.GetCurveName(R2.Curve) != curves.GetCurveName(curve) {
+		return nil, nil, nil, blame(PartyP2, 2, "R2 is not on the publicKey curve", p2Proof, R2)
+	}
+	k1, err := p1.group.ScalarFromSecret(p1.k1)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	R1, err := p1.group.Generator().ScalarMult(k1).ECPoint()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	proof, err := schnorr.ProveWithId(p1.sessionID, p1.k1, R1)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	presign := &P1Presign{
+		Id:        hex.EncodeToString(p1.sessionID.Bytes()),
+		PublicKey: &curves.ECPoint{Curve: curve, X: p1.publicKey.X, Y: p1.publicKey.Y},
+		K1:        p1.k1,
+		R2:        R2,
+	}
+	return proof, p1.cmtD, presign, nil
+}
+
+// NewP2Presign 2-party presignature, P2 init, run before message is known
+func NewP2Presign(bobPri, E_x1 *big.Int, publicKey *ecdsa.PublicKey, paiPub *paillier.PublicKey) *P2PresignContext {
+	g := keyGroup(publicKey)
+	if g == nil {
+		return nil
+	}
+	return &P2PresignContext{
+		x2:        bobPri,
+		E_x1:      E_x1,
+		paiPub:    paiPub,
+		publicKey: publicKey,
+		group:     g,
+	}
+}
+
+// Step1 receive presign nonce and R1 commitment, return k2 proof
+func (p2 *P2PresignContext) Step1(nonce *big.Int, cmtC *commitment.Commitment) (*schnorr.Proof, *curves.ECPoint, error) {
+	if nonce == nil || cmtC == nil {
+		return nil, nil, fmt.Errorf("p2 presign Step1 params error")
+	}
+	// a replayed nonce gives a used id, rejected by the PresignStore in OnlineStep
+	p2.sessionID = crypto.SHA256Int(p2.publicKey.X, p2.publicKey.Y, nonce)
+	p2.cmtC = cmtC
+
+	k2, R2, err := newNonce(p2.group)
+	if err != nil {
+		return nil, nil, err
+	}
+	p2.k2 = k2
+	proof, err := schnorr.ProveWithId(p2.sessionID, p2.k2, R2)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, R2, nil
+}
+
+// Step2 open R1 commitment, precompute paillier exponentiation, return P2 presignature
+func (p2 *P2PresignContext) Step2(cmtD *commitment.Witness, p1Proof *schnorr.Proof) (*P2Presign, error) {
+	if p2.k2 == nil {
+		return nil, fmt.Errorf("round error")
+	}
+	if cmtD == nil || p1Proof == nil {
+		return nil, fmt.Errorf("p2 presign Step2 params error")
+	}
+	curve := p2.publicKey.Curve
+	q := curve.Params().N
+	commit := commitment.HashCommitment{}
+	commit.C = *p2.cmtC
+	commit.Msg = *cmtD
+	ok, commitD := commit.Open()
+	if !ok || len(commitD) != 3 {
+		return nil, blame(PartyP1, 2, "commitment DeCommit fail", cmtD)
+	}
+	if commitD[0].Cmp(p2.sessionID) != 0 {
+		return nil, blame(PartyP1, 2, "commitment sessionId error", cmtD)
+	}
+	R1, err := curves.NewECPoint(curve, commitD[1], commitD[2])
+	if err != nil {
+		return nil, blame(PartyP1, 2, "invalid R1", cmtD)
+	}
+	if !schnorr.VerifyWithId(p2.sessionID, p1Proof, R1) {
+		return nil, blame(PartyP1, 2, "schnorr verify fail", p1Proof, R1)
+	}
+	// R = k1*k2*G, k = k1*k2
+	Rx, _, err := jointR(p2.group, p2.k2, R1)
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Int).Mod(Rx, q)
+	k2, err := p2.group.ScalarFromSecret(p2.k2)
+	if err != nil {
+		return nil, err
+	}
+	k2_1, err := k2.Invert()
+	if err != nil {
+		return nil, err
+	}
+
+	// a = r/k2, b = rho*q + r/k2 * x2, h/k2 is added online
+	rho := crypto.RandomNum(new(big.Int).Mul(q, q))
+	a := p2.group.ScalarFromBigInt(r).Mul(k2_1).BigInt()
+	b := new(big.Int).Add(new(big.Int).Mul(rho, q), new(big.Int).Mul(a, p2.x2))
+
+	paiPubKey := p2.paiPub
+	E_x1_a, err := paiPubKey.HomoMulPlain(p2.E_x1, a)
+	if err != nil {
+		return nil, err
+	}
+	rnd := crypto.RandomNum(paiPubKey.N)
+	rndN := new(big.Int).Exp(rnd, paiPubKey.N, paiPubKey.N2())
+
+	return &P2Presign{
+		Id:        hex.EncodeToString(p2.sessionID.Bytes()),
+		PublicKey: &curves.ECPoint{Curve: curve, X: p2.publicKey.X, Y: p2.publicKey.Y},
+		K2Inv:     k2_1.BigInt(),
+		A:         a,
+		B:         b,
+		E_x1_a:    E_x1_a,
+		Rnd:       rnd,
+		RndN:      rndN,
+	}, nil
+}
+
+// NewP1WithPresign P1 online signature, the returned context only runs Step3
+func NewP1WithPresign(presign *P1Presign, store PresignStore, publicKey *ecdsa.PublicKey, message string, paiPriKey *paillier.PrivateKey, E_x1 *big.Int, p1_ped *pedersen.PedersenParameters, mode ...prehash.Mode) (*P1Context, error) {
+	if presign == nil || presign.K1 == nil || presign.K1.Sign() == 0 || presign.R2 == nil || presign.PublicKey == nil {
+		return nil, fmt.Errorf("invalid presign")
+	}
+	p1 := NewP1(publicKey, message, paiPriKey, E_x1, p1_ped, mode...)
+	if p1 == nil {
+		return nil, fmt.Errorf("invalid message")
+	}
+	if err := consumePresign(store, "p1", presign.Id, presign.PublicKey, publicKey); err != nil {
+		return nil, err
+	}
+	p1.k1 = new(big.Int).Set(presign.K1)
+	p1.R2 = presign.R2
+	zeroInt(presign.K1)
+	presign.K1 = nil
+	return p1, nil
+}
+
+// OnlineStep P2 online signature, return E[(h+xr)/k2] with affine proof, single message to P1
+func (presign *P2Presign) OnlineStep(store PresignStore, E_x1 *big.Int, publicKey *ecdsa.PublicKey, paiPub *paillier.PublicKey, message string, p1_ped *pedersen.PedersenParameters, mode ...prehash.Mode) (*big.Int, *zkp.AffGProof, error) {
+	if presign.K2Inv == nil || presign.K2Inv.Sign() == 0 || presign.A == nil || presign.B == nil || presign.E_x1_a == nil || presign.Rnd == nil || presign.RndN == nil || presign.PublicKey == nil {
+		return nil, nil, fmt.Errorf("invalid presign")
+	}
+	message, err := prehashMessage(message, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+	bytes, err := hex.DecodeString(message)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := consumePresign(store, "p2", presign.Id, presign.PublicKey, publicKey); err != nil {
+		return nil, nil, err
+	}
+	defer presign.zero()
+	curve := presign.PublicKey.Curve
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		return nil, nil, err
+	}
+	// b = h/k2 + rho*q + r/k2 * x2
+	k2_1, err := g.ScalarFromSecret(presign.K2Inv)
+	if err != nil {
+		return nil, nil, err
+	}
+	h := g.ScalarFromBigInt(CalculateM(bytes, curve)).Mul(k2_1)
+	b := new(big.Int).Add(presign.B, h.BigInt())
+
+	N2 := paiPub.N2()
+	a_x1_b, err := paiPub.HomoAddPlain(presign.E_x1_a, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	E_k2_h_xr := new(big.Int).Mod(new(big.Int).Mul(a_x1_b, presign.RndN), N2)
+
+	st := &zkp.AffGStatement{
+		N: paiPub.N,
+		C: E_x1,
+		D: E_k2_h_xr,
+		X: curves.ScalarToPoint(curve, presign.A),
+		Y: curves.ScalarToPoint(curve, b),
+	}
+	wit := &zkp.AffGWitness{
+		X:   presign.A,
+		Y:   b,
+		Rho: presign.Rnd,
+	}
+	aff_g_proof := zkp.PaillierAffineProve(p1_ped, st, wit)
+	return E_k2_h_xr, aff_g_proof, nil
+}
+
+// zero overwrite the secret values, the presignature can't be used again
+func (presign *P2Presign) zero() {
+	for _, x := range []*big.Int{presign.K2Inv, presign.A, presign.B, presign.Rnd} {
+		zeroInt(x)
+	}
+	presign.K2Inv, presign.A, presign.B, presign.Rnd = nil, nil, nil, nil
+}
+
+// consumePresign check presign is bound to the key and record it used in the store
+func consumePresign(store PresignStore, party, id string, pubKey *curves.ECPoint, publicKey *ecdsa.PublicKey) error {
+	if store == nil {
+		return fmt.Errorf("presign store is required")
+	}
+	if publicKey == nil || pubKey.X.Cmp(publicKey.X) != 0 || pubKey.Y.Cmp(publicKey.Y) != 0 {
+		return fmt.Errorf("presign is not bound to the publicKey")
+	}
+	return store.Use(presignKey(party, id))
+}
+
+// zeroInt overwrite the words of a secret big.Int
+func zeroInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
+
+// presignKey P1 and P2 share the presign id, used list is kept per party
+func presignKey(party, id string) string {
+	return party + "_" + id
+}
@@ -2,12 +2,16 @@ package sign
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"math/big"
+	"sync"
 
 	"github.com/okx/threshold-lib/crypto/curves"
 	"github.com/okx/threshold-lib/crypto/paillier"
+	"github.com/okx/threshold-lib/crypto/prehash"
 	"github.com/stretchr/testify/require"
 
 	"testing"
@@ -18,13 +22,27 @@ import (
 	"github.com/okx/threshold-lib/tss/key/dkg"
 )
 
+var (
+	paramsOnce      sync.Once
+	paramsPaiKey    *paillier.PrivateKey
+	paramsPreParams *keygen.PreParamsWithDlnProof
+)
+
+// testParams paillier key and pre params take minutes to generate, one set is shared by the tests
+func testParams(t *testing.T) (*paillier.PrivateKey, *keygen.PreParamsWithDlnProof) {
+	paramsOnce.Do(func() {
+		paramsPaiKey, _, _ = paillier.NewKeyPair(8)
+		paramsPreParams = keygen.GeneratePreParamsWithDlnProof()
+	})
+	require.NotNil(t, paramsPaiKey)
+	return paramsPaiKey, paramsPreParams
+}
+
 func TestEcdsaSign(t *testing.T) {
 	p1Data, p2Data, _ := KeyGen()
 
 	fmt.Println("=========2/2 keygen==========")
-	paiPrivate, _, _ := paillier.NewKeyPair(8)
-
-	p1PreParamsAndProof := keygen.GeneratePreParamsWithDlnProof() // this step should be locally done by P1
+	paiPrivate, p1PreParamsAndProof := testParams(t) // this step should be locally done by P1
 
 	// this step should be locally done by P2. To save time, we assume both setup are the same.
 	p2PreParamsAndProof := &keygen.PreParamsWithDlnProof{
@@ -39,9 +57,19 @@ func TestEcdsaSign(t *testing.T) {
 	fmt.Println(p2SaveData, err)
 
 	fmt.Println("=========bip32==========")
-	tssKey, err := bip32.NewTssKey(p2SaveData.X2, p2Data.PublicKey, p2Data.ChainCode)
-	tssKey, err = tssKey.NewChildKey(996)
-	x2 := tssKey.ShareI()
+	// P2 derives from its share, P1 from the watch-only xpub
+	tssKey, err := bip32.NewTssKey(p2SaveData.X2, p2Data.PublicKey, p2Data.ChainCode, bip32.Standard)
+	require.NoError(t, err)
+	tssKey, err = tssKey.DerivePath("m/996")
+	require.NoError(t, err)
+	xpub, err := bip32.NewTssKey(nil, p1Data.PublicKey, p1Data.ChainCode, bip32.Standard)
+	require.NoError(t, err)
+	xpubStr, err := xpub.Xpub()
+	require.NoError(t, err)
+	watchOnly, err := bip32.NewTssKeyFromXpub(xpubStr)
+	require.NoError(t, err)
+	watchOnly, err = watchOnly.DerivePath("m/996")
+	require.NoError(t, err)
 	pubKey := &ecdsa.PublicKey{Curve: curve, X: tssKey.PublicKey().X, Y: tssKey.PublicKey().Y}
 
 	fmt.Println("=========2/2 sign==========")
@@ -49,8 +77,9 @@ func TestEcdsaSign(t *testing.T) {
 	hash.Write([]byte("hello"))
 	message := hash.Sum(nil)
 
-	p1 := NewP1(pubKey, hex.EncodeToString(message), paiPrivate, E_x1, p1PreParamsAndProof.PedersonParameters())
-	p2 := NewP2(x2, p2SaveData.E_x1, pubKey, p2SaveData.PaiPubKey, hex.EncodeToString(message), p2SaveData.Ped1)
+	p1 := NewP1WithTssKey(watchOnly, hex.EncodeToString(message), paiPrivate, E_x1, p1PreParamsAndProof.PedersonParameters())
+	// P2 hashes the message itself, same digest as P1
+	p2 := NewP2WithTssKey(tssKey, p2SaveData.E_x1, p2SaveData.PaiPubKey, hex.EncodeToString([]byte("hello")), p2SaveData.Ped1, prehash.SHA256)
 
 	commit, err := p1.Step1()
 	require.NoError(t, err)
@@ -64,12 +93,48 @@ func TestEcdsaSign(t *testing.T) {
 	r, s, err := p1.Step3(E_k2_h_xr, affine_proof)
 	require.NoError(t, err)
 	fmt.Println(r, s)
+	sig, err := p1.Step3Signature(E_k2_h_xr, affine_proof)
+	require.NoError(t, err)
+	require.Equal(t, 0, r.Cmp(sig.R))
+	require.Equal(t, 0, s.Cmp(sig.S))
+	fmt.Println(sig.R, sig.S, sig.V)
+	checkSignature(t, pubKey, message, sig)
+}
+
+func checkSignature(t *testing.T, pubKey *ecdsa.PublicKey, hash []byte, sig *Signature) {
+	require.True(t, ecdsa.Verify(pubKey, hash, sig.R, sig.S))
+	require.True(t, sig.S.Cmp(new(big.Int).Rsh(pubKey.Curve.Params().N, 1)) <= 0)
+	compact := sig.Compact()
+	require.Equal(t, 65, len(compact))
+	require.Equal(t, sig.Bytes(), compact[:64])
+	fmt.Println("compact:", hex.EncodeToString(compact))
+
+	recovered, err := sig.RecoverPublicKey(hash)
+	require.NoError(t, err)
+	require.Equal(t, 0, recovered.X.Cmp(pubKey.X))
+	require.Equal(t, 0, recovered.Y.Cmp(pubKey.Y))
+
+	der, err := sig.DER()
+	require.NoError(t, err)
+	fmt.Println("der:", hex.EncodeToString(der))
+	require.True(t, ecdsa.VerifyASN1(pubKey, hash, der))
+
+	// high-s input is normalized, the recovery id is found again
+	highS := new(big.Int).Sub(pubKey.Curve.Params().N, sig.S)
+	normalized, err := NewSignature(pubKey, hash, sig.R, highS)
+	require.NoError(t, err)
+	require.Equal(t, sig.Compact(), normalized.Compact())
 }
 
 func KeyGen() (*tss.KeyStep3Data, *tss.KeyStep3Data, *tss.KeyStep3Data) {
-	setUp1 := dkg.NewSetUp(1, 3, curve)
-	setUp2 := dkg.NewSetUp(2, 3, curve)
-	setUp3 := dkg.NewSetUp(3, 3, curve)
+	return keyGenOnCurve(curve)
+}
+
+func keyGenOnCurve(curve elliptic.Curve) (*tss.KeyStep3Data, *tss.KeyStep3Data, *tss.KeyStep3Data) {
+	sessionId := tss.SessionId("KeyGen")
+	setUp1 := dkg.NewSetUp(sessionId, 1, 2, 3, curve)
+	setUp2 := dkg.NewSetUp(sessionId, 2, 2, 3, curve)
+	setUp3 := dkg.NewSetUp(sessionId, 3, 2, 3, curve)
 
 	msgs1_1, _ := setUp1.DKGStep1()
 	msgs2_1, _ := setUp2.DKGStep1()
@@ -93,3 +158,116 @@ func KeyGen() (*tss.KeyStep3Data, *tss.KeyStep3Data, *tss.KeyStep3Data) {
 
 	return p1SaveData, p2SaveData, p3SaveData
 }
+
+func TestEcdsaSignP256(t *testing.T) {
+	p256 := elliptic.P256()
+	p1Data, p2Data, _ := keyGenOnCurve(p256)
+
+	fmt.Println("=========2/2 keygen p256==========")
+	paiPrivate, p1PreParamsAndProof := testParams(t)
+	p2PreParamsAndProof := &keygen.PreParamsWithDlnProof{
+		Params: p1PreParamsAndProof.Params,
+		Proof:  p1PreParamsAndProof.Proof,
+	}
+	p1Dto, E_x1, err := keygen.P1(p1Data.ShareI, paiPrivate, p1Data.Id, p2Data.Id, p1PreParamsAndProof, p2PreParamsAndProof.PedersonParameters(), p2PreParamsAndProof.Proof, p256)
+	require.NoError(t, err)
+	publicKey, _ := curves.NewECPoint(p256, p2Data.PublicKey.X, p2Data.PublicKey.Y)
+	p2SaveData, err := keygen.P2(p2Data.ShareI, publicKey, p1Dto, p1Data.Id, p2Data.Id, p2PreParamsAndProof.PedersonParameters())
+	require.NoError(t, err)
+	pubKey := &ecdsa.PublicKey{Curve: p256, X: p2Data.PublicKey.X, Y: p2Data.PublicKey.Y}
+
+	fmt.Println("=========2/2 sign p256==========")
+	message := sha256.Sum256([]byte("hello"))
+	p1 := NewP1(pubKey, hex.EncodeToString(message[:]), paiPrivate, E_x1, p1PreParamsAndProof.PedersonParameters())
+	p2 := NewP2(p2SaveData.X2, p2SaveData.E_x1, pubKey, p2SaveData.PaiPubKey, hex.EncodeToString(message[:]), p2SaveData.Ped1)
+
+	commit, err := p1.Step1()
+	require.NoError(t, err)
+	bobProof, R2, err := p2.Step1(commit)
+	require.NoError(t, err)
+	proof, cmtD, err := p1.Step2(bobProof, R2)
+	require.NoError(t, err)
+	E_k2_h_xr, affine_proof, err := p2.Step2(cmtD, proof)
+	require.NoError(t, err)
+	sig, err := p1.Step3Signature(E_k2_h_xr, affine_proof)
+	require.NoError(t, err)
+	require.Equal(t, curves.P256, curves.GetCurveName(sig.Curve()))
+	checkSignature(t, pubKey, message[:], sig)
+
+	// P1 keygen share on the wrong curve doesn't match P2's p256 publicKey
+	p1Dto, _, err = keygen.P1(p1Data.ShareI, paiPrivate, p1Data.Id, p2Data.Id, p1PreParamsAndProof, p2PreParamsAndProof.PedersonParameters(), p2PreParamsAndProof.Proof)
+	require.NoError(t, err)
+	_, err = keygen.P2(p2Data.ShareI, publicKey, p1Dto, p1Data.Id, p2Data.Id, p2PreParamsAndProof.PedersonParameters())
+	require.Error(t, err)
+}
+
+func TestEcdsaPresign(t *testing.T) {
+	p1Data, p2Data, _ := KeyGen()
+
+	fmt.Println("=========2/2 keygen==========")
+	paiPrivate, p1PreParamsAndProof := testParams(t)
+	p2PreParamsAndProof := &keygen.PreParamsWithDlnProof{
+		Params: p1PreParamsAndProof.Params,
+		Proof:  p1PreParamsAndProof.Proof,
+	}
+	p1Dto, E_x1, _ := keygen.P1(p1Data.ShareI, paiPrivate, p1Data.Id, p2Data.Id, p1PreParamsAndProof, p2PreParamsAndProof.PedersonParameters(), p2PreParamsAndProof.Proof)
+	publicKey, _ := curves.NewECPoint(curve, p2Data.PublicKey.X, p2Data.PublicKey.Y)
+	p2SaveData, err := keygen.P2(p2Data.ShareI, publicKey, p1Dto, p1Data.Id, p2Data.Id, p2PreParamsAndProof.PedersonParameters())
+	require.NoError(t, err)
+	pubKey := &ecdsa.PublicKey{Curve: curve, X: p2Data.PublicKey.X, Y: p2Data.PublicKey.Y}
+
+	fmt.Println("=========2/2 presign offline==========")
+	p1 := NewP1Presign(pubKey)
+	p2 := NewP2Presign(p2SaveData.X2, p2SaveData.E_x1, pubKey, p2SaveData.PaiPubKey)
+	nonce, commit, err := p1.Step1()
+	require.NoError(t, err)
+	bobProof, R2, err := p2.Step1(nonce, commit)
+	require.NoError(t, err)
+	proof, cmtD, p1Presign, err := p1.Step2(bobProof, R2)
+	require.NoError(t, err)
+	p2Presign, err := p2.Step2(cmtD, proof)
+	require.NoError(t, err)
+	require.Equal(t, p1Presign.Id, p2Presign.Id)
+
+	fmt.Println("=========2/2 presign online==========")
+	hash := sha256.New()
+	hash.Write([]byte("hello"))
+	message := hex.EncodeToString(hash.Sum(nil))
+
+	store := NewMemoryPresignStore()
+	_, _, err = p2Presign.OnlineStep(nil, p2SaveData.E_x1, pubKey, p2SaveData.PaiPubKey, message, p2SaveData.Ped1)
+	require.Error(t, err)
+	E_k2_h_xr, affine_proof, err := p2Presign.OnlineStep(store, p2SaveData.E_x1, pubKey, p2SaveData.PaiPubKey, message, p2SaveData.Ped1)
+	require.NoError(t, err)
+	p1Online, err := NewP1WithPresign(p1Presign, store, pubKey, message, paiPrivate, E_x1, p1PreParamsAndProof.PedersonParameters())
+	require.NoError(t, err)
+	sig, err := p1Online.Step3Signature(E_k2_h_xr, affine_proof)
+	require.NoError(t, err)
+	messageBytes, _ := hex.DecodeString(message)
+	checkSignature(t, pubKey, messageBytes, sig)
+
+	// presign is single-use, the secrets are zeroed
+	require.Nil(t, p1Presign.K1)
+	require.Nil(t, p2Presign.K2Inv)
+	_, _, err = p2Presign.OnlineStep(store, p2SaveData.E_x1, pubKey, p2SaveData.PaiPubKey, message, p2SaveData.Ped1)
+	require.Error(t, err)
+	_, err = NewP1WithPresign(p1Presign, store, pubKey, message, paiPrivate, E_x1, p1PreParamsAndProof.PedersonParameters())
+	require.Error(t, err)
+
+	// a copy restored from storage is rejected by the store
+	p1 = NewP1Presign(pubKey)
+	p2 = NewP2Presign(p2SaveData.X2, p2SaveData.E_x1, pubKey, p2SaveData.PaiPubKey)
+	nonce, commit, err = p1.Step1()
+	require.NoError(t, err)
+	bobProof, R2, err = p2.Step1(nonce, commit)
+	require.NoError(t, err)
+	_, _, p1Presign, err = p1.Step2(bobProof, R2)
+	require.NoError(t, err)
+	restored := *p1Presign
+	restored.K1 = new(big.Int).Set(p1Presign.K1)
+	_, err = NewP1WithPresign(p1Presign, store, pubKey, message, paiPrivate, E_x1, p1PreParamsAndProof.PedersonParameters())
+	require.NoError(t, err)
+	_, err = NewP1WithPresign(&restored, store, pubKey, message, paiPrivate, E_x1, p1PreParamsAndProof.PedersonParameters())
+	require.Error(t, err)
+	fmt.Println(err)
+}
@@ -0,0 +1,129 @@
+package sign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+)
+
+// Signature ecdsa signature with low-s and recovery id V, secp256k1 unless signed with a p256 key,
+// bit 0 of V is the parity of R.y, bit 1 is set when R.x >= n
+type Signature struct {
+	R *big.Int
+	S *big.Int
+	V byte
+
+	curve elliptic.Curve
+}
+
+// NewSignature normalize s to low-s and find the recovery id of publicKey, for signatures computed elsewhere
+func NewSignature(publicKey *ecdsa.PublicKey, hash []byte, r, s *big.Int) (*Signature, error) {
+	if keyGroup(publicKey) == nil || r == nil || s == nil {
+		return nil, fmt.Errorf("parameter error")
+	}
+	q := publicKey.Curve.Params().N
+	if r.Sign() <= 0 || r.Cmp(q) >= 0 || s.Sign() <= 0 || s.Cmp(q) >= 0 {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	sig := &Signature{R: r, S: s, curve: publicKey.Curve}
+	if s.Cmp(new(big.Int).Rsh(q, 1)) == 1 {
+		sig.S = new(big.Int).Sub(q, s)
+	}
+	for v := byte(0); v < 4; v++ {
+		sig.V = v
+		recovered, err := sig.RecoverPublicKey(hash)
+		if err == nil && recovered.X.Cmp(publicKey.X) == 0 && recovered.Y.Cmp(publicKey.Y) == 0 {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("signature doesn't match the publicKey")
+}
+
+// recoveryId V from the nonce point R = k*G, flipped when s was negated to low-s
+func recoveryId(curve elliptic.Curve, Rx, Ry *big.Int, flipped bool) byte {
+	v := byte(Ry.Bit(0))
+	if flipped {
+		v ^= 1
+	}
+	if Rx.Cmp(curve.Params().N) >= 0 {
+		v |= 2
+	}
+	return v
+}
+
+// Curve curve of the signature, secp256k1 when not set
+func (sig *Signature) Curve() elliptic.Curve {
+	if sig.curve == nil {
+		return curve
+	}
+	return sig.curve
+}
+
+// Bytes 64 bytes r || s, r and s are 32 bytes big endian
+func (sig *Signature) Bytes() []byte {
+	bytes := make([]byte, 64)
+	sig.R.FillBytes(bytes[:32])
+	sig.S.FillBytes(bytes[32:])
+	return bytes
+}
+
+// Compact 65 bytes r || s || v, v is 0 or 1 as used by ethereum, add 27 for legacy transactions and personal_sign
+func (sig *Signature) Compact() []byte {
+	return append(sig.Bytes(), sig.V)
+}
+
+// DER ASN.1 DER encoding SEQUENCE { r INTEGER, s INTEGER } used by bitcoin
+func (sig *Signature) DER() ([]byte, error) {
+	return asn1.Marshal(struct {
+		R *big.Int
+		S *big.Int
+	}{sig.R, sig.S})
+}
+
+// RecoverPublicKey Q = r^-1 * (s*R - e*G), R is lifted from r and V
+func (sig *Signature) RecoverPublicKey(hash []byte) (*ecdsa.PublicKey, error) {
+	curve := sig.Curve()
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+	q := curve.Params().N
+	p := curve.Params().P
+	if sig.V > 3 || sig.R.Sign() <= 0 || sig.R.Cmp(q) >= 0 || sig.S.Sign() <= 0 || sig.S.Cmp(q) >= 0 {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	x := new(big.Int).Set(sig.R)
+	if sig.V&2 != 0 {
+		x.Add(x, q)
+		if x.Cmp(p) >= 0 {
+			return nil, fmt.Errorf("invalid recovery id")
+		}
+	}
+	y, err := curves.LiftX(curve, x)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recovery id")
+	}
+	if y.Bit(0) != uint(sig.V&1) {
+		y.Sub(p, y)
+	}
+	R, err := g.PointFromAffine(x, y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recovery id")
+	}
+	rInv, err := g.ScalarFromBigInt(sig.R).Invert()
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	u1 := g.ScalarFromBigInt(CalculateM(hash, curve)).Negate().Mul(rInv)
+	u2 := g.ScalarFromBigInt(sig.S).Mul(rInv)
+	Q, err := g.Generator().ScalarMult(u1).Add(R.ScalarMult(u2)).ECPoint()
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: Q.X, Y: Q.Y}, nil
+}
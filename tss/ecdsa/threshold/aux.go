@@ -0,0 +1,246 @@
+package threshold
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/paillier"
+	"github.com/okx/threshold-lib/crypto/pedersen"
+	"github.com/okx/threshold-lib/crypto/zkp"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+	"github.com/okx/threshold-lib/tss/ecdsa/keygen"
+)
+
+// AuxStep1Data broadcast paillier public key and pedersen parameters with proofs
+type AuxStep1Data struct {
+	PaiPubKey *paillier.PublicKey
+	Ped       *pedersen.PedersenParameters
+	DlnProof  *zkp.DlnProof
+	BlumProof *zkp.PaillierBlumProof
+}
+
+// AuxStep2Data no small factor proof under receiver's pedersen parameters
+type AuxStep2Data struct {
+	NoSmallFactorProof *zkp.NoSmallFactorProof
+}
+
+// AuxInfo t-of-n signature auxiliary information, save with tss.KeyStep3Data
+type AuxInfo struct {
+	Id         int
+	PaiPriKey  *paillier.PrivateKey
+	Ped        *pedersen.PedersenParameters // own pedersen parameters, others prove to it
+	PaiPubKeys map[int]*paillier.PublicKey
+	Peds       map[int]*pedersen.PedersenParameters
+}
+
+// AuxSetUp run once after dkg among all n participants, exchange paillier keys and pedersen parameters
+type AuxSetUp struct {
+	DeviceNumber int
+	RoundNumber  int
+
+	ids        []int
+	paiPriKey  *paillier.PrivateKey
+	preParams  *keygen.PreParamsWithDlnProof
+	paiPubKeys map[int]*paillier.PublicKey
+	peds       map[int]*pedersen.PedersenParameters
+
+	codec codec.Codec // outgoing round payloads
+}
+
+// NewAuxSetUp paillier key and pre params are time-consuming, generated in advance
+func NewAuxSetUp(deviceNumber int, ids []int, paiPriKey *paillier.PrivateKey, preParams *keygen.PreParamsWithDlnProof) *AuxSetUp {
+	if paiPriKey == nil || preParams == nil || len(ids) < 2 || !containsId(ids, deviceNumber) {
+		panic(fmt.Errorf("NewAuxSetUp params error"))
+	}
+	return &AuxSetUp{
+		DeviceNumber: deviceNumber,
+		RoundNumber:  1,
+		ids:          ids,
+		paiPriKey:    paiPriKey,
+		preParams:    preParams,
+		codec:        codec.JSON,
+	}
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (aux *AuxSetUp) SetCodec(c codec.Codec) *AuxSetUp {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
+	}
+	aux.codec = c
+	return aux
+}
+
+// AuxStep1 p2p send paillier public key, pedersen parameters, dln proof and paillier blum proof
+func (aux *AuxSetUp) AuxStep1() (map[int]*tss.Message, error) {
+	if aux.RoundNumber != 1 {
+		return nil, fmt.Errorf("round error")
+	}
+	paiPriKey := aux.paiPriKey
+	blumProof, err := zkp.PaillierBlumProve(paiPriKey.N, paiPriKey.P, paiPriKey.Q)
+	if err != nil {
+		return nil, fmt.Errorf("fail to generate blum proof due to error [%w]", err)
+	}
+	content := AuxStep1Data{
+		PaiPubKey: &paiPriKey.PublicKey,
+		Ped:       aux.preParams.PedersonParameters(),
+		DlnProof:  aux.preParams.Proof,
+		BlumProof: blumProof,
+	}
+	bytes, err := aux.codec.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	aux.RoundNumber = 2
+
+	out := make(map[int]*tss.Message, len(aux.ids)-1)
+	for _, id := range aux.ids {
+		if id == aux.DeviceNumber {
+			continue
+		}
+		out[id] = &tss.Message{
+			From: aux.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	return out, nil
+}
+
+// AuxStep2 verify paillier key and pedersen parameters, p2p send no small factor proof
+func (aux *AuxSetUp) AuxStep2(msgs []*tss.Message) (map[int]*tss.Message, error) {
+	if aux.RoundNumber != 2 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != len(aux.ids)-1 {
+		return nil, fmt.Errorf("messages number error")
+	}
+	aux.paiPubKeys = make(map[int]*paillier.PublicKey, len(msgs))
+	aux.peds = make(map[int]*pedersen.PedersenParameters, len(msgs))
+	for _, msg := range msgs {
+		if msg.To != aux.DeviceNumber || !containsId(aux.ids, msg.From) || msg.From == aux.DeviceNumber {
+			return nil, fmt.Errorf("message sending error")
+		}
+		if _, ok := aux.paiPubKeys[msg.From]; ok {
+			return nil, fmt.Errorf("message sending error")
+		}
+		var content AuxStep1Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid message", err)
+		}
+		if content.PaiPubKey == nil || content.PaiPubKey.N == nil || content.Ped == nil || content.DlnProof == nil || content.BlumProof == nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid aux data", nil)
+		}
+		// checking paillier keys correct size
+		bitlen := content.PaiPubKey.N.BitLen()
+		if bitlen != paillier.PrimeBits && bitlen != paillier.PrimeBits-1 {
+			return nil, tss.NewBlameError(msg, 2, "invalid paillier keys", nil)
+		}
+		err = zkp.PaillierBlumVerify(content.PaiPubKey.N, content.BlumProof)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 2, "Blum proof verify fail", err)
+		}
+		if !zkp.DlnVerify(content.DlnProof, content.Ped.T, content.Ped.S, content.Ped.Ntilde) {
+			return nil, tss.NewBlameError(msg, 2, "DlnProof verify fail", nil)
+		}
+		aux.paiPubKeys[msg.From] = content.PaiPubKey
+		aux.peds[msg.From] = content.Ped
+	}
+
+	paiPriKey := aux.paiPriKey
+	aux.RoundNumber = 3
+
+	out := make(map[int]*tss.Message, len(aux.ids)-1)
+	for _, id := range aux.ids {
+		if id == aux.DeviceNumber {
+			continue
+		}
+		// proof under receiver's pedersen parameters
+		proof := zkp.NoSmallFactorProve(paiPriKey.N, paiPriKey.P, paiPriKey.Q, noSmallFactorL, aux.peds[id], noSmallFactorParams())
+		bytes, err := aux.codec.Marshal(AuxStep2Data{NoSmallFactorProof: proof})
+		if err != nil {
+			return nil, err
+		}
+		out[id] = &tss.Message{
+			From: aux.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	return out, nil
+}
+
+// AuxStep3 verify no small factor proof, return auxiliary information
+func (aux *AuxSetUp) AuxStep3(msgs []*tss.Message) (*AuxInfo, error) {
+	if aux.RoundNumber != 3 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != len(aux.ids)-1 {
+		return nil, fmt.Errorf("messages number error")
+	}
+	ped := aux.preParams.PedersonParameters()
+	verified := make(map[int]bool, len(msgs))
+	for _, msg := range msgs {
+		if msg.To != aux.DeviceNumber || verified[msg.From] {
+			return nil, fmt.Errorf("message sending error")
+		}
+		paiPubKey, ok := aux.paiPubKeys[msg.From]
+		if !ok {
+			return nil, fmt.Errorf("message sending error")
+		}
+		verified[msg.From] = true
+		var content AuxStep2Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 3, "invalid message", err)
+		}
+		if !verifyNoSmallFactor(content.NoSmallFactorProof, paiPubKey.N, ped) {
+			return nil, tss.NewBlameError(msg, 3, "No small factor verify fail", nil)
+		}
+	}
+	aux.RoundNumber = -1
+
+	paiPubKeys := make(map[int]*paillier.PublicKey, len(aux.ids))
+	peds := make(map[int]*pedersen.PedersenParameters, len(aux.ids))
+	for id, key := range aux.paiPubKeys {
+		paiPubKeys[id] = key
+		peds[id] = aux.peds[id]
+	}
+	paiPubKeys[aux.DeviceNumber] = &aux.paiPriKey.PublicKey
+	peds[aux.DeviceNumber] = ped
+
+	return &AuxInfo{
+		Id:         aux.DeviceNumber,
+		PaiPriKey:  aux.paiPriKey,
+		Ped:        ped,
+		PaiPubKeys: paiPubKeys,
+		Peds:       peds,
+	}, nil
+}
+
+// noSmallFactorL range of the no small factor proof, factors of N are at least 2^-l * sqrt(N)
+const noSmallFactorL = 16
+
+// security parameters of the no small factor proof
+func noSmallFactorParams() *zkp.SecurityParameter {
+	return &zkp.SecurityParameter{
+		Q_bitlen: 64,
+		Epsilon:  128,
+	}
+}
+
+// verifyNoSmallFactor l and the security parameters are fixed here, wider ranges chosen by the prover would let any
+// answer pass the range checks
+func verifyNoSmallFactor(proof *zkp.NoSmallFactorProof, N *big.Int, ped *pedersen.PedersenParameters) bool {
+	if proof == nil || proof.SecurityParams == nil || proof.L != noSmallFactorL || *proof.SecurityParams != *noSmallFactorParams() {
+		return false
+	}
+	for _, v := range []*big.Int{proof.P, proof.Q, proof.A, proof.B, proof.T, proof.Rho, proof.Z1, proof.Z2, proof.W1, proof.W2, proof.V} {
+		if v == nil {
+			return false
+		}
+	}
+	return zkp.NoSmallFactorVerify(N, proof, ped)
+}
@@ -0,0 +1,163 @@
+package threshold
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/prehash"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/crypto/zkp"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// EcdsaSign t-of-n ecdsa signature, GG20/CGGMP style rounds
+// k = sum(ki), gamma = sum(gamma_i), R = (k*gamma)^-1 * gamma*G, s = k*(m + r*x)
+type EcdsaSign struct {
+	DeviceNumber int
+	Threshold    int
+	RoundNumber  int
+	partList     []int // participating signature number, len(partList) >= threshold
+
+	sessionID *big.Int
+	curve     elliptic.Curve // curve of the dkg key, secp256k1 or p256
+	publicKey *ecdsa.PublicKey
+	message   []byte // decoded message hash
+	aux       *AuxInfo
+	wi        *big.Int                // lagrangian interpolation share, x = sum(wi)
+	wiPoints  map[int]*curves.ECPoint // wj*G of each participant
+	h         *curves.ECPoint         // session base point of ki range proof
+
+	ki, gammai *big.Int
+	rhoi       *big.Int                   // paillier randomness of Ki
+	cmtD       map[int]commitment.Witness // Gamma_i decommitment of each receiver
+
+	kMap          map[int]*big.Int // paillier encrypted kj
+	commitmentMap map[int]commitment.Commitment
+	betaSum       *big.Int // -sum(beta'), MtA of gamma_i
+	betaHatSum    *big.Int // -sum(beta_hat'), MtA of wi
+
+	gamma   *curves.ECPoint // sum(gamma_j*G)
+	deltai  *big.Int
+	chii    *big.Int
+	bigR    *curves.ECPoint         // R = delta^-1 * Gamma = k^-1 * G
+	rPoints map[int]*curves.ECPoint // R_j = delta^-1 * Delta_j = kj*R
+	r       *big.Int
+	si      *big.Int
+
+	codec codec.Codec // outgoing round payloads
+}
+
+// NewEcdsaSign work with dkg key data and auxiliary information, message is hex encoded hash,
+// or the hex message hashed with the optional mode.
+// sessionId must be the same for all signers and unique for each signature, see tss.SessionId
+func NewEcdsaSign(sessionId *big.Int, threshold int, partList []int, keyData *tss.KeyStep3Data, aux *AuxInfo, message string, mode ...prehash.Mode) (*EcdsaSign, error) {
+	if sessionId == nil || keyData == nil || aux == nil || keyData.Id != aux.Id || keyData.PublicKey == nil {
+		return nil, fmt.Errorf("NewEcdsaSign params error")
+	}
+	curve := keyData.PublicKey.Curve
+	if name := curves.GetCurveName(curve); name == "" || name == curves.Ed25519 {
+		return nil, fmt.Errorf("NewEcdsaSign unsupported curve")
+	}
+	if threshold < 2 || len(partList) < threshold || !containsId(partList, keyData.Id) {
+		return nil, fmt.Errorf("NewEcdsaSign participants error")
+	}
+	if len(mode) > 0 {
+		var err error
+		if message, err = prehash.DigestHex(mode[0], message); err != nil {
+			return nil, err
+		}
+	}
+	msg, err := hex.DecodeString(message)
+	if err != nil {
+		return nil, err
+	}
+	xList := make([]*big.Int, len(partList))
+	for i, x := range partList {
+		if _, ok := aux.PaiPubKeys[x]; !ok {
+			return nil, fmt.Errorf("missing paillier public key of participant %d", x)
+		}
+		if _, ok := aux.Peds[x]; !ok {
+			return nil, fmt.Errorf("missing pedersen parameters of participant %d", x)
+		}
+		if _, ok := keyData.SharePubKeyMap[x]; !ok {
+			return nil, fmt.Errorf("missing share public key of participant %d", x)
+		}
+		xList[i] = big.NewInt(int64(x))
+	}
+	if !distinct(partList) {
+		return nil, fmt.Errorf("NewEcdsaSign duplicate participants")
+	}
+	// lagrangian interpolation wi, Wj = lambda_j * Xj
+	wi := vss.CalLagrangian(curve, big.NewInt(int64(keyData.Id)), keyData.ShareI, xList)
+	Wi := make(map[int]*curves.ECPoint, len(partList))
+	for _, x := range partList {
+		lambda := vss.CalLagrangian(curve, big.NewInt(int64(x)), big.NewInt(1), xList)
+		Wi[x] = keyData.SharePubKeyMap[x].ScalarMult(lambda)
+	}
+
+	data := new(big.Int).SetBytes(msg)
+	input := []*big.Int{sessionId, keyData.PublicKey.X, keyData.PublicKey.Y, data}
+	for _, x := range partList {
+		input = append(input, big.NewInt(int64(x)))
+	}
+	sessionID := crypto.SHA256Int(input...)
+	H, err := hashToPoint(curve, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EcdsaSign{
+		DeviceNumber: keyData.Id,
+		Threshold:    threshold,
+		RoundNumber:  1,
+		partList:     partList,
+		sessionID:    sessionID,
+		curve:        curve,
+		publicKey:    &ecdsa.PublicKey{Curve: curve, X: keyData.PublicKey.X, Y: keyData.PublicKey.Y},
+		message:      msg,
+		aux:          aux,
+		wi:           wi,
+		wiPoints:     Wi,
+		h:            H,
+		codec:        codec.JSON,
+	}, nil
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (ecdsaSign *EcdsaSign) SetCodec(c codec.Codec) *EcdsaSign {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
+	}
+	ecdsaSign.codec = c
+	return ecdsaSign
+}
+
+// security parameters of enc range proof, ki < q
+func rangeSecurityParams() *zkp.SecurityParameter {
+	return &zkp.SecurityParameter{
+		Q_bitlen: 64,
+		Epsilon:  128,
+	}
+}
+
+// check enc range proof statement and verify, proof is generated under own pedersen parameters
+func (ecdsaSign *EcdsaSign) verifyRangeProof(proof *zkp.GroupElementPaillierEncryptionRangeProof, N, C *big.Int, X, base *curves.ECPoint) bool {
+	if proof == nil || proof.SecurityParams == nil || proof.X == nil || proof.G == nil || proof.Y == nil || proof.N0 == nil || proof.C == nil {
+		return false
+	}
+	expected := rangeSecurityParams()
+	if proof.L != uint(ecdsaSign.curve.Params().N.BitLen()) || *proof.SecurityParams != *expected {
+		return false
+	}
+	if proof.N0.Cmp(N) != 0 || proof.C.Cmp(C) != 0 || !proof.G.Equals(base) || (X != nil && !proof.X.Equals(X)) {
+		return false
+	}
+	return zkp.GroupElementPaillierEncryptionRangeVerify(proof, ecdsaSign.aux.Ped)
+}
@@ -0,0 +1,61 @@
+package threshold
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/zkp"
+	"github.com/okx/threshold-lib/tss"
+)
+
+type Step1Data struct {
+	K      *big.Int // paillier encrypt ki
+	KProof *zkp.GroupElementPaillierEncryptionRangeProof
+	C      commitment.Commitment // Gamma_i commitment
+}
+
+// SignStep1 p2p send encrypted ki with range proof and Gamma_i commitment
+func (ecdsaSign *EcdsaSign) SignStep1() (map[int]*tss.Message, error) {
+	if ecdsaSign.RoundNumber != 1 {
+		return nil, fmt.Errorf("round error")
+	}
+	paiPubKey := &ecdsaSign.aux.PaiPriKey.PublicKey
+	ecdsaSign.ki = crypto.RandomNum(ecdsaSign.curve.Params().N)
+	ecdsaSign.gammai = crypto.RandomNum(ecdsaSign.curve.Params().N)
+	K, rho, err := paiPubKey.Encrypt(ecdsaSign.ki)
+	if err != nil {
+		return nil, err
+	}
+	ecdsaSign.rhoi = rho
+	ecdsaSign.kMap = map[int]*big.Int{ecdsaSign.DeviceNumber: K}
+	Gammai := curves.ScalarToPoint(ecdsaSign.curve, ecdsaSign.gammai)
+	ecdsaSign.cmtD = make(map[int]commitment.Witness, len(ecdsaSign.partList)-1)
+	ecdsaSign.RoundNumber = 2
+
+	kH := ecdsaSign.h.ScalarMult(ecdsaSign.ki)
+	l := uint(ecdsaSign.curve.Params().N.BitLen())
+	out := make(map[int]*tss.Message, len(ecdsaSign.partList)-1)
+	for _, id := range ecdsaSign.partList {
+		if id == ecdsaSign.DeviceNumber {
+			continue
+		}
+		// range proof under receiver's pedersen parameters
+		proof := zkp.NewGroupElementPaillierEncryptionRangeProof(paiPubKey.N, K, ecdsaSign.ki, rho, l, kH, ecdsaSign.h, ecdsaSign.aux.Peds[id], rangeSecurityParams())
+		cmt := commitment.NewCommitment(tss.BindId(ecdsaSign.sessionID, ecdsaSign.DeviceNumber, id), Gammai.X, Gammai.Y)
+		ecdsaSign.cmtD[id] = cmt.Msg
+		data := Step1Data{K: K, KProof: proof, C: cmt.C}
+		bytes, err := ecdsaSign.codec.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = &tss.Message{
+			From: ecdsaSign.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,167 @@
+package threshold
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/paillier"
+	"github.com/okx/threshold-lib/crypto/pedersen"
+	"github.com/okx/threshold-lib/crypto/schnorr"
+	"github.com/okx/threshold-lib/crypto/zkp"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+type Step2Data struct {
+	Witness   commitment.Witness // Gamma_i decommitment
+	Proof     *schnorr.Proof     // gamma_i schnorr proof
+	D         *big.Int           // Enc_j(kj*gamma_i + beta')
+	DProof    *zkp.AffGProof
+	DHat      *big.Int // Enc_j(kj*wi + beta_hat')
+	DHatProof *zkp.AffGProof
+}
+
+// SignStep2 verify Kj range proof, p2p send MtA ciphertexts of gamma_i and wi with affine proof
+func (ecdsaSign *EcdsaSign) SignStep2(msgs []*tss.Message) (map[int]*tss.Message, error) {
+	if ecdsaSign.RoundNumber != 2 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != len(ecdsaSign.partList)-1 {
+		return nil, fmt.Errorf("messages number error")
+	}
+	ecdsaSign.commitmentMap = make(map[int]commitment.Commitment, len(msgs))
+	for _, msg := range msgs {
+		if msg.To != ecdsaSign.DeviceNumber || !ecdsaSign.isPeer(msg.From) {
+			return nil, fmt.Errorf("message sending error")
+		}
+		if _, ok := ecdsaSign.kMap[msg.From]; ok {
+			return nil, fmt.Errorf("message sending error")
+		}
+		var content Step1Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid message", err)
+		}
+		if content.K == nil || content.C == nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid step1 data", nil)
+		}
+		// Kj = Enc_j(kj), kj < 2^(l+epsilon)
+		N := ecdsaSign.aux.PaiPubKeys[msg.From].N
+		if !ecdsaSign.verifyRangeProof(content.KProof, N, content.K, nil, ecdsaSign.h) {
+			return nil, tss.NewBlameError(msg, 2, "enc range proof verify fail", nil)
+		}
+		ecdsaSign.kMap[msg.From] = content.K
+		ecdsaSign.commitmentMap[msg.From] = content.C
+	}
+
+	Gammai := curves.ScalarToPoint(ecdsaSign.curve, ecdsaSign.gammai)
+	Wi := ecdsaSign.wiPoints[ecdsaSign.DeviceNumber]
+
+	q := ecdsaSign.curve.Params().N
+	ecdsaSign.betaSum = big.NewInt(0)
+	ecdsaSign.betaHatSum = big.NewInt(0)
+	out := make(map[int]*tss.Message, len(ecdsaSign.partList)-1)
+	for _, id := range ecdsaSign.partList {
+		if id == ecdsaSign.DeviceNumber {
+			continue
+		}
+		proof, err := schnorr.ProveWithId(tss.BindId(ecdsaSign.sessionID, ecdsaSign.DeviceNumber, id), ecdsaSign.gammai, Gammai)
+		if err != nil {
+			return nil, err
+		}
+		paiPubKey := ecdsaSign.aux.PaiPubKeys[id]
+		ped := ecdsaSign.aux.Peds[id]
+		Kj := ecdsaSign.kMap[id]
+		// MtA: kj*gamma_i = alpha + beta, beta = -beta'
+		D, DProof, beta, err := mta(paiPubKey, ped, Kj, ecdsaSign.gammai, Gammai)
+		if err != nil {
+			return nil, err
+		}
+		// MtA: kj*wi = alpha_hat + beta_hat
+		DHat, DHatProof, betaHat, err := mta(paiPubKey, ped, Kj, ecdsaSign.wi, Wi)
+		if err != nil {
+			return nil, err
+		}
+		ecdsaSign.betaSum = new(big.Int).Mod(new(big.Int).Sub(ecdsaSign.betaSum, beta), q)
+		ecdsaSign.betaHatSum = new(big.Int).Mod(new(big.Int).Sub(ecdsaSign.betaHatSum, betaHat), q)
+
+		data := Step2Data{
+			Witness:   ecdsaSign.cmtD[id],
+			Proof:     proof,
+			D:         D,
+			DProof:    DProof,
+			DHat:      DHat,
+			DHatProof: DHatProof,
+		}
+		bytes, err := ecdsaSign.codec.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = &tss.Message{
+			From: ecdsaSign.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	ecdsaSign.RoundNumber = 3
+	return out, nil
+}
+
+// mta D = C^x * Enc(beta'), affine proof with X = x*G, Y = beta'*G under receiver's pedersen parameters
+func mta(paiPubKey *paillier.PublicKey, ped *pedersen.PedersenParameters, C, x *big.Int, X *curves.ECPoint) (*big.Int, *zkp.AffGProof, *big.Int, error) {
+	betaPrime := crypto.RandomNum(new(big.Int).Lsh(big.NewInt(1), uint(zkp.L1_Aff_G)))
+	rnd, err := crypto.RandomPrimeNum(paiPubKey.N)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	Cx, err := paiPubKey.HomoMulPlain(C, x)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	D, err := paiPubKey.HomoAddPlain(Cx, betaPrime)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	N2 := paiPubKey.N2()
+	D = new(big.Int).Mod(new(big.Int).Mul(D, new(big.Int).Exp(rnd, paiPubKey.N, N2)), N2)
+
+	st := &zkp.AffGStatement{
+		N: paiPubKey.N,
+		C: C,
+		D: D,
+		X: X,
+		Y: curves.ScalarToPoint(X.Curve, betaPrime),
+	}
+	wit := &zkp.AffGWitness{
+		X:   x,
+		Y:   betaPrime,
+		Rho: rnd,
+	}
+	proof := zkp.PaillierAffineProve(ped, st, wit)
+	return D, proof, betaPrime, nil
+}
+
+// verifyMta check affine proof statement under own paillier key and pedersen parameters
+func (ecdsaSign *EcdsaSign) verifyMta(proof *zkp.AffGProof, D *big.Int, X *curves.ECPoint) bool {
+	if proof == nil || D == nil || proof.X == nil || proof.Y == nil || proof.Bx == nil || proof.By == nil {
+		return false
+	}
+	if !proof.X.Equals(X) {
+		return false
+	}
+	st := &zkp.AffGStatement{
+		N: ecdsaSign.aux.PaiPriKey.N,
+		C: ecdsaSign.kMap[ecdsaSign.DeviceNumber],
+		D: D,
+		X: X,
+		Y: proof.Y,
+	}
+	return zkp.PaillierAffineVerify(ecdsaSign.aux.Ped, proof, st)
+}
+
+func (ecdsaSign *EcdsaSign) isPeer(id int) bool {
+	return id != ecdsaSign.DeviceNumber && containsId(ecdsaSign.partList, id)
+}
@@ -0,0 +1,119 @@
+package threshold
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/schnorr"
+	"github.com/okx/threshold-lib/crypto/zkp"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+type Step3Data struct {
+	Delta      *big.Int        // delta_i = ki*gamma_i + sum(alpha + beta)
+	DeltaPoint *curves.ECPoint // Delta_i = ki*Gamma
+	Proof      *zkp.GroupElementPaillierEncryptionRangeProof
+}
+
+// SignStep3 open Gamma_j, verify affine proof, compute delta_i and chi_i
+func (ecdsaSign *EcdsaSign) SignStep3(msgs []*tss.Message) (map[int]*tss.Message, error) {
+	if ecdsaSign.RoundNumber != 3 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != len(ecdsaSign.partList)-1 {
+		return nil, fmt.Errorf("messages number error")
+	}
+	q := ecdsaSign.curve.Params().N
+	paiPriKey := ecdsaSign.aux.PaiPriKey
+	Gamma := curves.ScalarToPoint(ecdsaSign.curve, ecdsaSign.gammai)
+	// delta_i = ki*gamma_i + sum(alpha_j) + sum(beta_j)
+	deltai := new(big.Int).Mul(ecdsaSign.ki, ecdsaSign.gammai)
+	deltai = new(big.Int).Add(deltai, ecdsaSign.betaSum)
+	// chi_i = ki*wi + sum(alpha_hat_j) + sum(beta_hat_j)
+	chii := new(big.Int).Mul(ecdsaSign.ki, ecdsaSign.wi)
+	chii = new(big.Int).Add(chii, ecdsaSign.betaHatSum)
+
+	verified := make(map[int]bool, len(msgs))
+	for _, msg := range msgs {
+		if msg.To != ecdsaSign.DeviceNumber || !ecdsaSign.isPeer(msg.From) || verified[msg.From] {
+			return nil, fmt.Errorf("message sending error")
+		}
+		verified[msg.From] = true
+		var content Step2Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 3, "invalid message", err)
+		}
+		cmt := &commitment.HashCommitment{
+			C:   ecdsaSign.commitmentMap[msg.From],
+			Msg: content.Witness,
+		}
+		bindId := tss.BindId(ecdsaSign.sessionID, msg.From, ecdsaSign.DeviceNumber)
+		ok, D := cmt.Open()
+		if !ok || len(D) != 3 || D[0].Cmp(bindId) != 0 {
+			return nil, tss.NewBlameError(msg, 3, "commitment DeCommit fail", nil)
+		}
+		Gammaj, err := curves.NewECPoint(ecdsaSign.curve, D[1], D[2])
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 3, "invalid Gamma_j", err)
+		}
+		if content.Proof == nil || !schnorr.VerifyWithId(bindId, content.Proof, Gammaj) {
+			return nil, tss.NewBlameError(msg, 3, "schnorr verify fail", nil)
+		}
+		if !ecdsaSign.verifyMta(content.DProof, content.D, Gammaj) {
+			return nil, tss.NewBlameError(msg, 3, "affine proof verify fail", nil)
+		}
+		if !ecdsaSign.verifyMta(content.DHatProof, content.DHat, ecdsaSign.wiPoints[msg.From]) {
+			return nil, tss.NewBlameError(msg, 3, "affine proof verify fail", nil)
+		}
+		alpha, err := paiPriKey.Decrypt(content.D)
+		if err != nil {
+			return nil, err
+		}
+		alphaHat, err := paiPriKey.Decrypt(content.DHat)
+		if err != nil {
+			return nil, err
+		}
+		deltai = new(big.Int).Add(deltai, alpha)
+		chii = new(big.Int).Add(chii, alphaHat)
+		Gamma, err = Gamma.Add(Gammaj)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ecdsaSign.deltai = new(big.Int).Mod(deltai, q)
+	ecdsaSign.chii = new(big.Int).Mod(chii, q)
+	ecdsaSign.gamma = Gamma
+
+	// Delta_i = ki*Gamma, prove Ki encrypts log_Gamma(Delta_i)
+	paiPubKey := &paiPriKey.PublicKey
+	Ki := ecdsaSign.kMap[ecdsaSign.DeviceNumber]
+	DeltaPoint := Gamma.ScalarMult(ecdsaSign.ki)
+	l := uint(q.BitLen())
+	out := make(map[int]*tss.Message, len(ecdsaSign.partList)-1)
+	for _, id := range ecdsaSign.partList {
+		if id == ecdsaSign.DeviceNumber {
+			continue
+		}
+		proof := zkp.NewGroupElementPaillierEncryptionRangeProof(paiPubKey.N, Ki, ecdsaSign.ki, ecdsaSign.rhoi, l, DeltaPoint, Gamma, ecdsaSign.aux.Peds[id], rangeSecurityParams())
+		data := Step3Data{
+			Delta:      ecdsaSign.deltai,
+			DeltaPoint: DeltaPoint,
+			Proof:      proof,
+		}
+		bytes, err := ecdsaSign.codec.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = &tss.Message{
+			From: ecdsaSign.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	ecdsaSign.RoundNumber = 4
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package threshold
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+	"github.com/okx/threshold-lib/tss/ecdsa/sign"
+)
+
+type Step4Data struct {
+	S      *big.Int        // si = ki*m + r*chi_i
+	SPoint *curves.ECPoint // S_i = chi_i*R, checks si in step5
+}
+
+// SignStep4 verify Delta_j, compute R = delta^-1 * Gamma, broadcast partial signature si
+func (ecdsaSign *EcdsaSign) SignStep4(msgs []*tss.Message) (map[int]*tss.Message, error) {
+	if ecdsaSign.RoundNumber != 4 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != len(ecdsaSign.partList)-1 {
+		return nil, fmt.Errorf("messages number error")
+	}
+	q := ecdsaSign.curve.Params().N
+	delta := ecdsaSign.deltai
+	DeltaSum := ecdsaSign.gamma.ScalarMult(ecdsaSign.ki)
+	deltaPoints := map[int]*curves.ECPoint{ecdsaSign.DeviceNumber: DeltaSum}
+	verified := make(map[int]bool, len(msgs))
+	for _, msg := range msgs {
+		if msg.To != ecdsaSign.DeviceNumber || !ecdsaSign.isPeer(msg.From) || verified[msg.From] {
+			return nil, fmt.Errorf("message sending error")
+		}
+		verified[msg.From] = true
+		var content Step3Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 4, "invalid message", err)
+		}
+		if content.Delta == nil || content.DeltaPoint == nil {
+			return nil, tss.NewBlameError(msg, 4, "invalid step3 data", nil)
+		}
+		N := ecdsaSign.aux.PaiPubKeys[msg.From].N
+		if !ecdsaSign.verifyRangeProof(content.Proof, N, ecdsaSign.kMap[msg.From], content.DeltaPoint, ecdsaSign.gamma) {
+			return nil, tss.NewBlameError(msg, 4, "enc range proof verify fail", nil)
+		}
+		deltaPoints[msg.From] = content.DeltaPoint
+		delta = new(big.Int).Add(delta, content.Delta)
+		DeltaSum, err = DeltaSum.Add(content.DeltaPoint)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 4, "invalid Delta_j", err)
+		}
+	}
+	// delta*G = sum(Delta_j) = k*gamma*G
+	delta = new(big.Int).Mod(delta, q)
+	if delta.Sign() == 0 || !curves.ScalarToPoint(ecdsaSign.curve, delta).Equals(DeltaSum) {
+		return nil, fmt.Errorf("delta verify fail")
+	}
+	deltaInv := new(big.Int).ModInverse(delta, q)
+	R := ecdsaSign.gamma.ScalarMult(deltaInv)
+	r := new(big.Int).Mod(R.X, q)
+	if r.Sign() == 0 {
+		return nil, fmt.Errorf("r is zero")
+	}
+	ecdsaSign.r = r
+	ecdsaSign.bigR = R
+	ecdsaSign.rPoints = make(map[int]*curves.ECPoint, len(deltaPoints))
+	for id, Delta := range deltaPoints {
+		ecdsaSign.rPoints[id] = Delta.ScalarMult(deltaInv)
+	}
+
+	m := sign.CalculateM(ecdsaSign.message, ecdsaSign.curve)
+	// si = ki*m + r*chi_i
+	si := new(big.Int).Mul(ecdsaSign.ki, m)
+	si = new(big.Int).Add(si, new(big.Int).Mul(r, ecdsaSign.chii))
+	si = new(big.Int).Mod(si, q)
+	ecdsaSign.si = si
+	chiR := R.ScalarMult(ecdsaSign.chii)
+	if chiR == nil {
+		return nil, fmt.Errorf("chi_i is zero")
+	}
+
+	bytes, err := ecdsaSign.codec.Marshal(Step4Data{S: si, SPoint: chiR})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]*tss.Message, len(ecdsaSign.partList)-1)
+	for _, id := range ecdsaSign.partList {
+		if id == ecdsaSign.DeviceNumber {
+			continue
+		}
+		out[id] = &tss.Message{
+			From: ecdsaSign.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	ecdsaSign.RoundNumber = 5
+	return out, nil
+}
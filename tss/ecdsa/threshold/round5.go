@@ -0,0 +1,86 @@
+package threshold
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+	"github.com/okx/threshold-lib/tss/ecdsa/sign"
+)
+
+// SignStep5 verify each sj*R = m*R_j + r*S_j, s = sum(sj), verify ecdsa signature, return (r, s)
+func (ecdsaSign *EcdsaSign) SignStep5(msgs []*tss.Message) (*big.Int, *big.Int, error) {
+	if ecdsaSign.RoundNumber != 5 {
+		return nil, nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != len(ecdsaSign.partList)-1 {
+		return nil, nil, fmt.Errorf("messages number error")
+	}
+	q := ecdsaSign.curve.Params().N
+	m := sign.CalculateM(ecdsaSign.message, ecdsaSign.curve)
+	s := ecdsaSign.si
+	SSum := ecdsaSign.bigR.ScalarMult(ecdsaSign.chii)
+	verified := make(map[int]bool, len(msgs))
+	for _, msg := range msgs {
+		if msg.To != ecdsaSign.DeviceNumber || !ecdsaSign.isPeer(msg.From) || verified[msg.From] {
+			return nil, nil, fmt.Errorf("message sending error")
+		}
+		verified[msg.From] = true
+		var content Step4Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, nil, tss.NewBlameError(msg, 5, "invalid message", err)
+		}
+		if content.S == nil || content.SPoint == nil || curves.GetCurveName(content.SPoint.Curve) != curves.GetCurveName(ecdsaSign.curve) {
+			return nil, nil, tss.NewBlameError(msg, 5, "invalid step4 data", nil)
+		}
+		if !ecdsaSign.verifyPartial(m, ecdsaSign.rPoints[msg.From], content.SPoint, content.S) {
+			return nil, nil, tss.NewBlameError(msg, 5, "partial signature verify fail", nil)
+		}
+		s = new(big.Int).Add(s, content.S)
+		SSum, err = SSum.Add(content.SPoint)
+		if err != nil {
+			return nil, nil, tss.NewBlameError(msg, 5, "invalid S_j", err)
+		}
+	}
+	// sum(S_j) = k*x*R = X, a wrong S_j chosen to match a wrong sj is caught here
+	if !SSum.Equals(&curves.ECPoint{Curve: ecdsaSign.curve, X: ecdsaSign.publicKey.X, Y: ecdsaSign.publicKey.Y}) {
+		return nil, nil, fmt.Errorf("S_j sum verify fail")
+	}
+	s = new(big.Int).Mod(s, q)
+	// normalize s to low-s
+	halfOrder := new(big.Int).Rsh(q, 1)
+	if s.Cmp(halfOrder) == 1 {
+		s = new(big.Int).Sub(q, s)
+	}
+	if s.Sign() == 0 {
+		return nil, nil, fmt.Errorf("calculated S is zero")
+	}
+	ecdsaSign.RoundNumber = -1
+
+	if !ecdsa.Verify(ecdsaSign.publicKey, ecdsaSign.message, ecdsaSign.r, s) {
+		return nil, nil, fmt.Errorf("ecdsa sign verify fail")
+	}
+	return ecdsaSign.r, s, nil
+}
+
+// verifyPartial sj*R = kj*m*R + r*chi_j*R = m*R_j + r*S_j
+func (ecdsaSign *EcdsaSign) verifyPartial(m *big.Int, Rj, Sj *curves.ECPoint, sj *big.Int) bool {
+	q := ecdsaSign.curve.Params().N
+	if Rj == nil || sj.Sign() <= 0 || sj.Cmp(q) >= 0 {
+		return false
+	}
+	mRj := Rj.ScalarMult(m)
+	rSj := Sj.ScalarMult(ecdsaSign.r)
+	if mRj == nil || rSj == nil {
+		return false
+	}
+	right, err := mRj.Add(rSj)
+	if err != nil {
+		return false
+	}
+	return ecdsaSign.bigR.ScalarMult(sj).Equals(right)
+}
@@ -0,0 +1,165 @@
+package threshold
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/paillier"
+	"github.com/okx/threshold-lib/crypto/zkp"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+	"github.com/okx/threshold-lib/tss/ecdsa/keygen"
+	"github.com/okx/threshold-lib/tss/key/dkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThresholdSign(t *testing.T) {
+	ids := []int{1, 2, 3, 4}
+
+	fmt.Println("=========aux setup==========")
+	// To save time, we assume all participants use the same paillier key and pre params.
+	paiPriKey, _, err := paillier.NewKeyPair(8)
+	require.NoError(t, err)
+	preParams := keygen.GeneratePreParamsWithDlnProof()
+	auxMap := auxSetUp(t, ids, paiPriKey, preParams)
+
+	// l and the security parameters of the no small factor proof are fixed by the verifier
+	proof := zkp.NoSmallFactorProve(paiPriKey.N, paiPriKey.P, paiPriKey.Q, noSmallFactorL, auxMap[2].Ped, noSmallFactorParams())
+	require.True(t, verifyNoSmallFactor(proof, paiPriKey.N, auxMap[2].Ped))
+	proof.SecurityParams = &zkp.SecurityParameter{Q_bitlen: 64, Epsilon: 4096}
+	require.False(t, verifyNoSmallFactor(proof, paiPriKey.N, auxMap[2].Ped))
+	proof = zkp.NoSmallFactorProve(paiPriKey.N, paiPriKey.P, paiPriKey.Q, 1024, auxMap[2].Ped, noSmallFactorParams())
+	require.False(t, verifyNoSmallFactor(proof, paiPriKey.N, auxMap[2].Ped))
+
+	for _, curve := range []elliptic.Curve{secp256k1.S256(), elliptic.P256()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			thresholdSign(t, curve, ids, auxMap)
+		})
+	}
+}
+
+func thresholdSign(t *testing.T, curve elliptic.Curve, ids []int, auxMap map[int]*AuxInfo) {
+	keys := keyGen(t, curve, 3, ids)
+
+	fmt.Println("=========3/4 sign==========")
+	hash := sha256.New()
+	hash.Write([]byte("hello"))
+	message := hex.EncodeToString(hash.Sum(nil))
+
+	var err error
+	sessionId := tss.SessionId("threshold sign " + curve.Params().Name)
+	partList := []int{1, 2, 4}
+	signs := make(map[int]*EcdsaSign, len(partList))
+	for _, id := range partList {
+		signs[id], err = NewEcdsaSign(sessionId, 3, partList, keys[id], auxMap[id], message)
+		require.NoError(t, err)
+	}
+
+	msgs := make(map[int]map[int]*tss.Message, len(partList))
+	for _, id := range partList {
+		msgs[id], err = signs[id].SignStep1()
+		require.NoError(t, err)
+	}
+	for _, step := range []func(*EcdsaSign, []*tss.Message) (map[int]*tss.Message, error){
+		(*EcdsaSign).SignStep2, (*EcdsaSign).SignStep3, (*EcdsaSign).SignStep4,
+	} {
+		next := make(map[int]map[int]*tss.Message, len(partList))
+		for _, id := range partList {
+			next[id], err = step(signs[id], collect(msgs, id))
+			require.NoError(t, err)
+		}
+		msgs = next
+	}
+
+	// a bad partial signature names its sender
+	var content Step4Data
+	require.NoError(t, codec.Unmarshal([]byte(msgs[2][1].Data), &content))
+	content.S = new(big.Int).Add(content.S, big.NewInt(1))
+	bytes, err := codec.JSON.Marshal(content)
+	require.NoError(t, err)
+	bad := *signs[1]
+	in := []*tss.Message{msgs[4][1], {From: 2, To: 1, Data: string(bytes)}}
+	_, _, err = bad.SignStep5(in)
+	culprit, ok := tss.Culprit(err)
+	require.True(t, ok)
+	require.Equal(t, 2, culprit)
+	fmt.Println(err)
+
+	pubKey := &ecdsa.PublicKey{Curve: curve, X: keys[1].PublicKey.X, Y: keys[1].PublicKey.Y}
+	msg, _ := hex.DecodeString(message)
+	for _, id := range partList {
+		r, s, err := signs[id].SignStep5(collect(msgs, id))
+		require.NoError(t, err)
+		require.True(t, ecdsa.Verify(pubKey, msg, r, s))
+		fmt.Println(id, r, s)
+	}
+
+	// fewer than threshold participants
+	_, err = NewEcdsaSign(sessionId, 3, []int{1, 2}, keys[1], auxMap[1], message)
+	require.Error(t, err)
+	// no session id
+	_, err = NewEcdsaSign(tss.SessionId(""), 3, partList, keys[1], auxMap[1], message)
+	require.Error(t, err)
+}
+
+func keyGen(t *testing.T, curve elliptic.Curve, threshold int, ids []int) map[int]*tss.KeyStep3Data {
+	sessionId := tss.SessionId("keyGen")
+	setUps := make(map[int]*dkg.SetupInfo, len(ids))
+	msgs := make(map[int]map[int]*tss.Message, len(ids))
+	var err error
+	for _, id := range ids {
+		setUps[id] = dkg.NewSetUp(sessionId, id, threshold, len(ids), curve)
+		msgs[id], err = setUps[id].DKGStep1()
+		require.NoError(t, err)
+	}
+	next := make(map[int]map[int]*tss.Message, len(ids))
+	for _, id := range ids {
+		next[id], err = setUps[id].DKGStep2(collect(msgs, id))
+		require.NoError(t, err)
+	}
+	keys := make(map[int]*tss.KeyStep3Data, len(ids))
+	for _, id := range ids {
+		keys[id], err = setUps[id].DKGStep3(collect(next, id))
+		require.NoError(t, err)
+	}
+	return keys
+}
+
+func auxSetUp(t *testing.T, ids []int, paiPriKey *paillier.PrivateKey, preParams *keygen.PreParamsWithDlnProof) map[int]*AuxInfo {
+	setUps := make(map[int]*AuxSetUp, len(ids))
+	msgs := make(map[int]map[int]*tss.Message, len(ids))
+	var err error
+	for _, id := range ids {
+		setUps[id] = NewAuxSetUp(id, ids, paiPriKey, preParams)
+		msgs[id], err = setUps[id].AuxStep1()
+		require.NoError(t, err)
+	}
+	next := make(map[int]map[int]*tss.Message, len(ids))
+	for _, id := range ids {
+		next[id], err = setUps[id].AuxStep2(collect(msgs, id))
+		require.NoError(t, err)
+	}
+	auxMap := make(map[int]*AuxInfo, len(ids))
+	for _, id := range ids {
+		auxMap[id], err = setUps[id].AuxStep3(collect(next, id))
+		require.NoError(t, err)
+	}
+	return auxMap
+}
+
+func collect(msgs map[int]map[int]*tss.Message, id int) []*tss.Message {
+	var in []*tss.Message
+	for from, out := range msgs {
+		if from == id {
+			continue
+		}
+		in = append(in, out[id])
+	}
+	return in
+}
@@ -0,0 +1,47 @@
+package threshold
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/curves"
+)
+
+// hashToPoint try-and-increment, nobody knows the discrete log of the result
+func hashToPoint(curve elliptic.Curve, sessionID *big.Int) (*curves.ECPoint, error) {
+	params := curve.Params()
+	for i := int64(0); i < 256; i++ {
+		x := new(big.Int).Mod(crypto.SHA256Int(sessionID, big.NewInt(i)), params.P)
+		y, err := curves.LiftX(curve, x)
+		if err != nil {
+			continue
+		}
+		point, err := curves.NewECPoint(curve, x, y)
+		if err == nil {
+			return point, nil
+		}
+	}
+	return nil, fmt.Errorf("hash to point fail")
+}
+
+func containsId(list []int, id int) bool {
+	for _, v := range list {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func distinct(list []int) bool {
+	seen := make(map[int]bool, len(list))
+	for _, id := range list {
+		if id <= 0 || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
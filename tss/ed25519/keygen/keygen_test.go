@@ -9,10 +9,11 @@ import (
 )
 
 func TestKeyGen(t *testing.T) {
+	sessionId := tss.SessionId("TestKeyGen")
 	curve := edwards.Edwards()
-	setUp1 := dkg.NewSetUp(1, 3, curve)
-	setUp2 := dkg.NewSetUp(2, 3, curve)
-	setUp3 := dkg.NewSetUp(3, 3, curve)
+	setUp1 := dkg.NewSetUp(sessionId, 1, 2, 3, curve)
+	setUp2 := dkg.NewSetUp(sessionId, 2, 2, 3, curve)
+	setUp3 := dkg.NewSetUp(sessionId, 3, 2, 3, curve)
 
 	msgs1_1, _ := setUp1.DKGStep1()
 	msgs2_1, _ := setUp2.DKGStep1()
@@ -1,11 +1,15 @@
 package sign
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/prehash"
 	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss/codec"
+	"github.com/okx/threshold-lib/tss/key/bip32"
 )
 
 var (
@@ -21,14 +25,23 @@ type Ed25519Sign struct {
 	RoundNumber  int
 	ki           *big.Int
 	message      string
+	options      *prehash.Ed25519Options // Ed25519ph or Ed25519ctx, nil for PureEdDSA
+	sessionId    *big.Int                // agreed by all signers, bound into every commitment and proof
 
-	cmtD          commitment.Witness
+	cmtD          map[int]commitment.Witness // commitment opening for each receiver
 	CommitmentMap map[int]commitment.Commitment
+
+	codec codec.Codec // outgoing round payloads
 }
 
-// NewEd25519Sign
-func NewEd25519Sign(deviceNumber, threshold int, partList []int, ShareI *big.Int, PublicKey *edwards.PublicKey, message string) *Ed25519Sign {
-	if len(partList) != threshold {
+// NewEd25519Sign sessionId must be the same for all signers and unique for each signature, see tss.SessionId,
+// options select Ed25519ph or Ed25519ctx, PureEdDSA by default
+func NewEd25519Sign(sessionId *big.Int, deviceNumber, threshold int, partList []int, ShareI *big.Int, PublicKey *edwards.PublicKey, message string,
+	options ...*prehash.Ed25519Options) *Ed25519Sign {
+	if sessionId == nil || len(partList) != threshold {
+		return nil
+	}
+	if _, _, err := prehash.Ed25519Message(firstOptions(options), nil); err != nil {
 		return nil
 	}
 	xList := make([]*big.Int, len(partList))
@@ -45,7 +58,42 @@ func NewEd25519Sign(deviceNumber, threshold int, partList []int, ShareI *big.Int
 		partList:     partList,
 		PublicKey:    PublicKey,
 		message:      message,
+		options:      firstOptions(options),
+		sessionId:    sessionId,
 		RoundNumber:  1,
+		codec:        codec.JSON,
+	}
+	return ed25519
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (ed25519 *Ed25519Sign) SetCodec(c codec.Codec) *Ed25519Sign {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
 	}
+	ed25519.codec = c
 	return ed25519
 }
+
+// NewEd25519SignWithKey sign for a bip32 child key, e.g. root.DerivePath("m/0/1") of every signer's own share,
+// the derivation offset is already added to each share, the lagrangian coefficients sum to 1
+func NewEd25519SignWithKey(sessionId *big.Int, deviceNumber, threshold int, partList []int, tssKey *bip32.Ed25519TssKey, message string,
+	options ...*prehash.Ed25519Options) *Ed25519Sign {
+	if tssKey == nil || tssKey.ShareI() == nil {
+		return nil
+	}
+	return NewEd25519Sign(sessionId, deviceNumber, threshold, partList, tssKey.ShareI(), tssKey.ToEd25519PublicKey(), message, options...)
+}
+
+// isPeer another signer of partList
+func (ed25519 *Ed25519Sign) isPeer(id int) bool {
+	if id == ed25519.DeviceNumber {
+		return false
+	}
+	for _, x := range ed25519.partList {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/key/bip32"
 	"github.com/okx/threshold-lib/tss/key/dkg"
+	"github.com/stretchr/testify/require"
 	"math/big"
 	"testing"
 )
@@ -27,11 +29,105 @@ func TestEd25519(t *testing.T) {
 	}
 }
 
+func TestEd25519ChildKey(t *testing.T) {
+	p1Data, _, p3Data := keyGen(curve)
+	message := []byte("hello child key")
+	sessionId := tss.SessionId("TestEd25519ChildKey")
+	partList := []int{1, 3}
+
+	signers := make(map[int]*Ed25519Sign)
+	var childPublicKey *edwards.PublicKey
+	for _, data := range []*tss.KeyStep3Data{p1Data, p3Data} {
+		root, err := bip32.NewEd25519TssKey(data.ShareI, data.PublicKey, data.ChainCode, bip32.Ed25519Bip32)
+		require.NoError(t, err)
+		child, err := root.DerivePath("m/44/501/0")
+		require.NoError(t, err)
+		childPublicKey = child.ToEd25519PublicKey()
+		signers[data.Id] = NewEd25519SignWithKey(sessionId, data.Id, 2, partList, child, hex.EncodeToString(message))
+		require.NotNil(t, signers[data.Id])
+	}
+
+	step1 := make(map[int]map[int]*tss.Message)
+	for id, signer := range signers {
+		out, err := signer.SignStep1()
+		require.NoError(t, err)
+		step1[id] = out
+	}
+	step2 := make(map[int]map[int]*tss.Message)
+	step2[1], _ = signers[1].SignStep2([]*tss.Message{step1[3][1]})
+	step2[3], _ = signers[3].SignStep2([]*tss.Message{step1[1][3]})
+	si_1, r, err := signers[1].SignStep3([]*tss.Message{step2[3][1]})
+	require.NoError(t, err)
+	si_3, _, err := signers[3].SignStep3([]*tss.Message{step2[1][3]})
+	require.NoError(t, err)
+
+	s := new(big.Int).Mod(new(big.Int).Add(si_1, si_3), curve.Params().N)
+	require.True(t, edwards.NewSignature(r, s).Verify(message, childPublicKey))
+	require.False(t, edwards.NewSignature(r, s).Verify(message, edwards.NewPublicKey(p1Data.PublicKey.X, p1Data.PublicKey.Y)))
+}
+
+func TestEd25519Senders(t *testing.T) {
+	p1Data, p2Data, p3Data := keyGen(curve)
+	publicKey := edwards.NewPublicKey(p1Data.PublicKey.X, p1Data.PublicKey.Y)
+	message := hex.EncodeToString([]byte("hello senders"))
+	sessionId := tss.SessionId("TestEd25519Senders")
+	partList := []int{1, 2, 3}
+	signers := make(map[int]*Ed25519Sign)
+	for _, data := range []*tss.KeyStep3Data{p1Data, p2Data, p3Data} {
+		signers[data.Id] = NewEd25519Sign(sessionId, data.Id, 3, partList, data.ShareI, publicKey, message)
+	}
+	step1 := make(map[int]map[int]*tss.Message)
+	for id, signer := range signers {
+		out, err := signer.SignStep1()
+		require.NoError(t, err)
+		step1[id] = out
+	}
+
+	// duplicate, stranger and self senders
+	stranger := *step1[3][1]
+	stranger.From = 4
+	self := *step1[3][1]
+	self.From = 1
+	for _, msgs := range [][]*tss.Message{{step1[2][1], step1[2][1]}, {step1[2][1], &stranger}, {step1[2][1], &self}} {
+		_, err := signers[1].SignStep2(msgs)
+		require.Error(t, err)
+	}
+	step2 := make(map[int]map[int]*tss.Message)
+	for id, signer := range signers {
+		var in []*tss.Message
+		for from, out := range step1 {
+			if from != id {
+				in = append(in, out[id])
+			}
+		}
+		out, err := signer.SignStep2(in)
+		require.NoError(t, err)
+		step2[id] = out
+	}
+	_, _, err := signers[1].SignStep3([]*tss.Message{step2[2][1], step2[2][1]})
+	require.Error(t, err)
+
+	// step 2 messages bound to another receiver or sender name the sender
+	forwarded := *step2[2][1]
+	forwarded.To = 3
+	_, _, err = signers[3].SignStep3([]*tss.Message{&forwarded, step2[1][3]})
+	culprit, ok := tss.Culprit(err)
+	require.True(t, ok)
+	require.Equal(t, 2, culprit)
+	forged := *step2[1][2]
+	forged.From = 3
+	_, _, err = signers[2].SignStep3([]*tss.Message{&forged, step2[1][2]})
+	culprit, ok = tss.Culprit(err)
+	require.True(t, ok)
+	require.Equal(t, 3, culprit)
+}
+
 func sign_p1_p2(p1Data, p2Data *tss.KeyStep3Data, publicKey *edwards.PublicKey, message []byte) {
+	sessionId := tss.SessionId("sign_p1_p2")
 	fmt.Println("=========sign_p1_p2========")
 	partList := []int{1, 2}
-	p1 := NewEd25519Sign(1, 2, partList, p1Data.ShareI, publicKey, hex.EncodeToString(message))
-	p2 := NewEd25519Sign(2, 2, partList, p2Data.ShareI, publicKey, hex.EncodeToString(message))
+	p1 := NewEd25519Sign(sessionId, 1, 2, partList, p1Data.ShareI, publicKey, hex.EncodeToString(message))
+	p2 := NewEd25519Sign(sessionId, 2, 2, partList, p2Data.ShareI, publicKey, hex.EncodeToString(message))
 
 	p1Step1, _ := p1.SignStep1()
 	p2Step1, _ := p2.SignStep1()
@@ -51,10 +147,11 @@ func sign_p1_p2(p1Data, p2Data *tss.KeyStep3Data, publicKey *edwards.PublicKey,
 }
 
 func sign_p1_p3(p1Data, p3Data *tss.KeyStep3Data, publicKey *edwards.PublicKey, message []byte) {
+	sessionId := tss.SessionId("sign_p1_p3")
 	fmt.Println("=========sign_p1_p3========")
 	partList := []int{1, 3}
-	p1 := NewEd25519Sign(1, 2, partList, p1Data.ShareI, publicKey, hex.EncodeToString(message))
-	p3 := NewEd25519Sign(3, 2, partList, p3Data.ShareI, publicKey, hex.EncodeToString(message))
+	p1 := NewEd25519Sign(sessionId, 1, 2, partList, p1Data.ShareI, publicKey, hex.EncodeToString(message))
+	p3 := NewEd25519Sign(sessionId, 3, 2, partList, p3Data.ShareI, publicKey, hex.EncodeToString(message))
 
 	p1Step1, _ := p1.SignStep1()
 	p3Step1, _ := p3.SignStep1()
@@ -74,10 +171,11 @@ func sign_p1_p3(p1Data, p3Data *tss.KeyStep3Data, publicKey *edwards.PublicKey,
 }
 
 func sign_p2_p3(p2Data, p3Data *tss.KeyStep3Data, publicKey *edwards.PublicKey, message []byte) {
+	sessionId := tss.SessionId("sign_p2_p3")
 	fmt.Println("=========sign_p2_p3========")
 	partList := []int{2, 3}
-	p2 := NewEd25519Sign(2, 2, partList, p2Data.ShareI, publicKey, hex.EncodeToString(message))
-	p3 := NewEd25519Sign(3, 2, partList, p3Data.ShareI, publicKey, hex.EncodeToString(message))
+	p2 := NewEd25519Sign(sessionId, 2, 2, partList, p2Data.ShareI, publicKey, hex.EncodeToString(message))
+	p3 := NewEd25519Sign(sessionId, 3, 2, partList, p3Data.ShareI, publicKey, hex.EncodeToString(message))
 
 	p2Step1, _ := p2.SignStep1()
 	p3Step1, _ := p3.SignStep1()
@@ -97,9 +195,10 @@ func sign_p2_p3(p2Data, p3Data *tss.KeyStep3Data, publicKey *edwards.PublicKey,
 }
 
 func keyGen(curve elliptic.Curve) (*tss.KeyStep3Data, *tss.KeyStep3Data, *tss.KeyStep3Data) {
-	setUp1 := dkg.NewSetUp(1, 3, curve)
-	setUp2 := dkg.NewSetUp(2, 3, curve)
-	setUp3 := dkg.NewSetUp(3, 3, curve)
+	sessionId := tss.SessionId("keyGen")
+	setUp1 := dkg.NewSetUp(sessionId, 1, 2, 3, curve)
+	setUp2 := dkg.NewSetUp(sessionId, 2, 2, 3, curve)
+	setUp3 := dkg.NewSetUp(sessionId, 3, 2, 3, curve)
 
 	msgs1_1, _ := setUp1.DKGStep1()
 	msgs2_1, _ := setUp2.DKGStep1()
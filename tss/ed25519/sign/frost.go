@@ -0,0 +1,142 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/prehash"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/frost"
+)
+
+// FROST(Ed25519, SHA-512) of RFC 9591, H2 is the RFC 8032 challenge, signatures verify with crypto/ed25519
+var frostContext = []byte("FROST-ED25519-SHA512-v1")
+
+// NonceCommitment public commitment of a nonce pair, Di = di*G, Ei = ei*G
+type NonceCommitment = frost.NonceCommitment
+
+// Nonces secret nonce pair, used for exactly one signature
+type Nonces = frost.Nonces
+
+type FrostStep1Data = frost.Step1Data
+
+type FrostStep2Data = frost.Step2Data
+
+// ciphersuite FROST(Ed25519, SHA-512), dom is the dom2 prefix of Ed25519ph and Ed25519ctx, nil for PureEdDSA,
+// it is bound by the binding factors through H4 and by the challenge as RFC 8032 does
+func ciphersuite(dom []byte) *frost.Ciphersuite {
+	return &frost.Ciphersuite{
+		Group: group.Ed25519(),
+		Hash: func(tag string, data ...[]byte) []byte {
+			if tag == "msg" && dom != nil {
+				data = append([][]byte{dom}, data...)
+			}
+			return frostHash(tag, data...)
+		},
+		EncodeKey: func(publicKey group.Point) []byte {
+			return publicKey.Bytes()
+		},
+		Challenge: func(R, publicKey group.Point, message []byte) group.Scalar {
+			return challenge(R, publicKey, dom, message)
+		},
+	}
+}
+
+// Preprocess generate count nonce pairs before the message is known,
+// publish the commitments to the other signers or the coordinator, keep the nonces secret
+func Preprocess(keyData *tss.KeyStep3Data, count int) ([]*Nonces, error) {
+	if keyData == nil {
+		return nil, fmt.Errorf("parameter error")
+	}
+	return ciphersuite(nil).Preprocess(keyData.Id, keyData.ShareI, count)
+}
+
+// Aggregate verify every signature share zi and output the 64 bytes RFC 8032 signature R || z,
+// a wrong share is reported as tss.BlameError of the signer, options select Ed25519ph or Ed25519ctx
+func Aggregate(publicKey *curves.ECPoint, sharePubKeyMap map[int]*curves.ECPoint, message []byte,
+	commitments []*NonceCommitment, shares map[int]*big.Int, options ...*prehash.Ed25519Options) ([]byte, error) {
+	dom, msg, err := prehash.Ed25519Message(firstOptions(options), message)
+	if err != nil {
+		return nil, err
+	}
+	R, z, err := ciphersuite(dom).Aggregate(publicKey, sharePubKeyMap, msg, commitments, shares)
+	if err != nil {
+		return nil, err
+	}
+	return signature(publicKey, R, z, dom, msg)
+}
+
+// signature R || z, checked against publicKey
+func signature(publicKey, R *curves.ECPoint, z *big.Int, dom, message []byte) ([]byte, error) {
+	signature := append(serializePoint(R), bigIntToEncodedBytes(z)[:]...)
+	if !verify(publicKey, R, z, dom, message, signature) {
+		return nil, fmt.Errorf("signature verify fail, signers are fewer than threshold")
+	}
+	return signature, nil
+}
+
+// verify crypto/ed25519 checks PureEdDSA signatures, the other variants are checked by z*G = R + c*A
+func verify(publicKey, R *curves.ECPoint, z *big.Int, dom, message, signature []byte) bool {
+	if dom == nil {
+		return ed25519.Verify(serializePoint(publicKey), message, signature)
+	}
+	g := group.Ed25519()
+	A, err := g.PointFromAffine(publicKey.X, publicKey.Y)
+	if err != nil {
+		return false
+	}
+	Rp, err := g.PointFromAffine(R.X, R.Y)
+	if err != nil {
+		return false
+	}
+	c := challenge(Rp, A, dom, message)
+	return g.Generator().ScalarMult(g.ScalarFromBigInt(z)).Equal(Rp.Add(A.ScalarMult(c)))
+}
+
+func firstOptions(options []*prehash.Ed25519Options) *prehash.Ed25519Options {
+	if len(options) == 0 {
+		return nil
+	}
+	return options[0]
+}
+
+// challenge H2(R || PK || msg) = SHA512(dom2 || R || PK || msg) mod L, same as RFC 8032
+func challenge(R, publicKey group.Point, dom, message []byte) group.Scalar {
+	h := sha512.New()
+	h.Write(dom)
+	h.Write(R.Bytes())
+	h.Write(publicKey.Bytes())
+	h.Write(message)
+	c, _ := group.Ed25519().ScalarFromUniformBytes(h.Sum(nil))
+	return c
+}
+
+// nonceFromRandom H3(random || secret), nonce_generate of RFC 9591 with the random bytes given
+func nonceFromRandom(random []byte, secret *big.Int) (*big.Int, error) {
+	suite := ciphersuite(nil)
+	s, err := suite.Share(secret)
+	if err != nil {
+		return nil, err
+	}
+	return suite.NonceFromRandom(random, s).BigInt(), nil
+}
+
+// frostHash SHA512(contextString || tag || data)
+func frostHash(tag string, data ...[]byte) []byte {
+	h := sha512.New()
+	h.Write(frostContext)
+	h.Write([]byte(tag))
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func serializePoint(point *curves.ECPoint) []byte {
+	return edwards.NewPublicKey(point.X, point.Y).Serialize()
+}
@@ -0,0 +1,14 @@
+package sign
+
+import (
+	"github.com/okx/threshold-lib/tss"
+)
+
+// SignStep3 receive signature shares, aggregate the 64 bytes RFC 8032 signature
+func (frost *FrostSign) SignStep3(msgs []*tss.Message) ([]byte, error) {
+	R, z, err := frost.Signer.SignStep3(msgs)
+	if err != nil {
+		return nil, err
+	}
+	return signature(frost.publicKey, R, z, frost.dom, frost.message)
+}
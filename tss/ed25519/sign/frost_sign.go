@@ -0,0 +1,57 @@
+package sign
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/prehash"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+	"github.com/okx/threshold-lib/tss/frost"
+)
+
+// FrostSign FROST signing among partList, one round to exchange nonce commitments,
+// one round to exchange signature shares, then every signer can aggregate the signature
+type FrostSign struct {
+	*frost.Signer
+
+	publicKey *curves.ECPoint
+	dom       []byte // dom2 prefix of Ed25519ph and Ed25519ctx
+	message   []byte // M' of RFC 8032
+}
+
+// NewFrostSign keyData is the dkg output, nonces come from Preprocess and are consumed by this signature,
+// options select Ed25519ph or Ed25519ctx, PureEdDSA by default
+func NewFrostSign(keyData *tss.KeyStep3Data, partList []int, message string, nonces *Nonces, options ...*prehash.Ed25519Options) *FrostSign {
+	if keyData == nil {
+		return nil
+	}
+	bytes, err := hex.DecodeString(message)
+	if err != nil {
+		return nil
+	}
+	dom, msg, err := prehash.Ed25519Message(firstOptions(options), bytes)
+	if err != nil {
+		return nil
+	}
+	signer, err := frost.NewSigner(ciphersuite(dom), keyData.Id, keyData.ShareI, keyData.PublicKey, keyData.SharePubKeyMap, partList, msg, nonces)
+	if err != nil {
+		return nil
+	}
+	return &FrostSign{
+		Signer:    signer,
+		publicKey: keyData.PublicKey,
+		dom:       dom,
+		message:   msg,
+	}
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (frost *FrostSign) SetCodec(c codec.Codec) *FrostSign {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
+	}
+	frost.Signer.SetCodec(c)
+	return frost
+}
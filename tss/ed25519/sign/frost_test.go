@@ -0,0 +1,205 @@
+package sign
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/prehash"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrost(t *testing.T) {
+	p1Data, p2Data, p3Data := keyGen(curve)
+	keys := map[int]*tss.KeyStep3Data{1: p1Data, 2: p2Data, 3: p3Data}
+	publicKey := ed25519.PublicKey(serializePoint(p1Data.PublicKey))
+
+	// preprocessing, done before the message is known
+	nonces := make(map[int][]*Nonces)
+	for id, keyData := range keys {
+		list, err := Preprocess(keyData, 3)
+		require.NoError(t, err)
+		nonces[id] = list
+	}
+
+	message := []byte("hello frost")
+	for n, partList := range [][]int{{1, 2}, {1, 3}, {2, 3}} {
+		signatures := frostSign(t, keys, nonces, n, partList, message)
+		for _, signature := range signatures {
+			require.Equal(t, 64, len(signature))
+			require.True(t, ed25519.Verify(publicKey, message, signature))
+		}
+		fmt.Println("frost", partList, hex.EncodeToString(signatures[0]))
+	}
+}
+
+func TestFrostBlame(t *testing.T) {
+	p1Data, p2Data, p3Data := keyGen(curve)
+	keys := map[int]*tss.KeyStep3Data{1: p1Data, 2: p2Data, 3: p3Data}
+	message := hex.EncodeToString([]byte("hello frost"))
+
+	nonces1, err := Preprocess(p1Data, 1)
+	require.NoError(t, err)
+	nonces3, err := Preprocess(p3Data, 1)
+	require.NoError(t, err)
+	p1 := NewFrostSign(keys[1], []int{1, 3}, message, nonces1[0])
+	p3 := NewFrostSign(keys[3], []int{1, 3}, message, nonces3[0])
+
+	p1Step1, err := p1.SignStep1()
+	require.NoError(t, err)
+	p3Step1, err := p3.SignStep1()
+	require.NoError(t, err)
+	p1Step2, err := p1.SignStep2([]*tss.Message{p3Step1[1]})
+	require.NoError(t, err)
+	_, err = p3.SignStep2([]*tss.Message{p1Step1[3]})
+	require.NoError(t, err)
+
+	// nonces can't be used twice
+	again := NewFrostSign(keys[1], []int{1, 3}, message, nonces1[0])
+	_, err = again.SignStep1()
+	require.NoError(t, err)
+	_, err = again.SignStep2([]*tss.Message{p3Step1[1]})
+	require.Error(t, err)
+
+	// party 1 sends a wrong share
+	bytes, err := codec.JSON.Marshal(FrostStep2Data{Zi: new(big.Int).Add(big.NewInt(1), p1.Share())})
+	require.NoError(t, err)
+	require.NotEqual(t, p1Step2[3].Data, string(bytes))
+	bad := &tss.Message{From: 1, To: 3, Data: string(bytes)}
+	_, err = p3.SignStep3([]*tss.Message{bad})
+	fmt.Println(err)
+	culprit, ok := tss.Culprit(err)
+	require.True(t, ok)
+	require.Equal(t, 1, culprit)
+}
+
+func TestFrostPrehash(t *testing.T) {
+	p1Data, p2Data, p3Data := keyGen(curve)
+	keys := map[int]*tss.KeyStep3Data{1: p1Data, 2: p2Data, 3: p3Data}
+	publicKey := ed25519.PublicKey(serializePoint(p1Data.PublicKey))
+	nonces := make(map[int][]*Nonces)
+	for id, keyData := range keys {
+		list, err := Preprocess(keyData, 3)
+		require.NoError(t, err)
+		nonces[id] = list
+	}
+
+	message := []byte("hello frost")
+	variants := []struct {
+		options *prehash.Ed25519Options
+		verify  *ed25519.Options
+	}{
+		{&prehash.Ed25519Options{Mode: prehash.Ed25519ph}, &ed25519.Options{Hash: crypto.SHA512}},
+		{&prehash.Ed25519Options{Mode: prehash.Ed25519ph, Context: []byte("ph")}, &ed25519.Options{Hash: crypto.SHA512, Context: "ph"}},
+		{&prehash.Ed25519Options{Mode: prehash.Ed25519ctx, Context: []byte("ctx")}, &ed25519.Options{Context: "ctx"}},
+	}
+	for n, v := range variants {
+		signatures := frostSign(t, keys, nonces, n, []int{1, 3}, message, v.options)
+		digest := sha512.Sum512(message)
+		signed := message
+		if v.verify.Hash == crypto.SHA512 {
+			signed = digest[:]
+		}
+		for _, signature := range signatures {
+			require.NoError(t, ed25519.VerifyWithOptions(publicKey, signed, signature, v.verify))
+			require.False(t, ed25519.Verify(publicKey, message, signature))
+		}
+		fmt.Println(v.options.Mode, hex.EncodeToString(signatures[0]))
+	}
+	require.Nil(t, NewFrostSign(keys[1], []int{1, 3}, hex.EncodeToString(message), nonces[1][0], &prehash.Ed25519Options{Mode: prehash.Ed25519ctx}))
+}
+
+// TestFrostRFC9591 FROST(Ed25519, SHA-512) vector of RFC 9591 Appendix E.1, 2 of 3, key inputs, nonce generation and commitments.
+// Binding factors, group commitment and signature shares of the appendix are not included, they could not be checked
+// against the published text
+func TestFrostRFC9591(t *testing.T) {
+	scalar := func(s string) *big.Int {
+		b, err := hex.DecodeString(s)
+		require.NoError(t, err)
+		return encodedBytesToBigInt(copyBytes(b))
+	}
+	point := func(p *curves.ECPoint) string {
+		return hex.EncodeToString(serializePoint(p))
+	}
+	random := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		require.NoError(t, err)
+		return b
+	}
+
+	secret := scalar("7b1c33d3f5291d85de664833beb1ad469f7fb6025a0ec78b3a790c6e13a98304")
+	coefficient := scalar("178199860edd8c62f5212ee91eff1295d0d670ab4ed4506866bae57e7030b204")
+	require.Equal(t, "15d21ccd7ee42959562fc8aa63224c8851fb3ec85a3faf66040d380fb9738673", point(curves.ScalarToPoint(curve, secret)))
+
+	// participant shares f(i) = secret + coefficient*i
+	shares := map[int]string{
+		1: "929dcc590407aae7d388761cddb0c0db6f5627aea8e217f4a033f2ec83d93509",
+		2: "a91e66e012e4364ac9aaa405fcafd370402d9859f7b6685c07eed76bf409e80d",
+		3: "d3cb090a075eb154e82fdb4b3cb507f110040905468bb9c46da8bdea643a9a02",
+	}
+	for i, share := range shares {
+		fi := new(big.Int).Mul(coefficient, big.NewInt(int64(i)))
+		fi.Add(fi, secret).Mod(fi, curve.N)
+		require.Equal(t, 0, fi.Cmp(scalar(share)))
+	}
+
+	// P1 nonces from hiding_nonce_randomness and binding_nonce_randomness
+	share1 := scalar(shares[1])
+	hiding, err := nonceFromRandom(random("0fd2e39e111cdc266f6c0f4d0fd45c947761f1f5d3cb583dfcb9bbaf8d4c9fec"), share1)
+	require.NoError(t, err)
+	binding, err := nonceFromRandom(random("69cd85f631d5f7f2721ed5e40519b1366f340a87c2f6856363dbdcda348a7501"), share1)
+	require.NoError(t, err)
+	require.Equal(t, 0, hiding.Cmp(scalar("812d6104142944d5a55924de6d49940956206909f2acaeedecda2b726e630407")))
+	require.Equal(t, "b5aa8ab305882a6fc69cbee9327e5a45e54c08af61ae77cb8207be3d2ce13de3", point(curves.ScalarToPoint(curve, hiding)))
+	require.Equal(t, "67e98ab55aa310c3120418e5050c9cf76cf387cb20ac9e4b6fdb6f82a469f932", point(curves.ScalarToPoint(curve, binding)))
+
+	// P3 binding nonce commitment
+	binding3 := scalar("243d71944d929063bc51205714ae3c2218bd3451d0214dfb5aeec2a90c35180d")
+	require.Equal(t, "7487bc41a6e712eea2f2af24681b58b1cf1da278ea11fe4e8b78398965f13552", point(curves.ScalarToPoint(curve, binding3)))
+}
+
+// frostSign run one signature among partList with the n-th preprocessed nonces
+func frostSign(t *testing.T, keys map[int]*tss.KeyStep3Data, nonces map[int][]*Nonces, n int, partList []int, message []byte,
+	options ...*prehash.Ed25519Options) [][]byte {
+	signers := make(map[int]*FrostSign)
+	for _, id := range partList {
+		signers[id] = NewFrostSign(keys[id], partList, hex.EncodeToString(message), nonces[id][n], options...)
+		require.NotNil(t, signers[id])
+	}
+	step1 := make(map[int]map[int]*tss.Message)
+	for id, signer := range signers {
+		out, err := signer.SignStep1()
+		require.NoError(t, err)
+		step1[id] = out
+	}
+	step2 := make(map[int]map[int]*tss.Message)
+	for id, signer := range signers {
+		out, err := signer.SignStep2(receive(step1, id))
+		require.NoError(t, err)
+		step2[id] = out
+	}
+	var signatures [][]byte
+	for id, signer := range signers {
+		signature, err := signer.SignStep3(receive(step2, id))
+		require.NoError(t, err)
+		signatures = append(signatures, signature)
+	}
+	return signatures
+}
+
+func receive(msgs map[int]map[int]*tss.Message, to int) []*tss.Message {
+	var in []*tss.Message
+	for from, out := range msgs {
+		if from != to {
+			in = append(in, out[to])
+		}
+	}
+	return in
+}
@@ -1,7 +1,6 @@
 package sign
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/okx/threshold-lib/crypto"
 	"github.com/okx/threshold-lib/crypto/commitment"
@@ -20,9 +19,7 @@ func (ed25519 *Ed25519Sign) SignStep1() (map[int]*tss.Message, error) {
 	}
 	ed25519.ki = crypto.RandomNum(curve.N)
 	Ri := curves.ScalarToPoint(curve, ed25519.ki)
-	// Ri commitment
-	cmt := commitment.NewCommitment(Ri.X, Ri.Y)
-	ed25519.cmtD = cmt.Msg
+	ed25519.cmtD = make(map[int]commitment.Witness, ed25519.Threshold-1)
 	ed25519.RoundNumber = 2
 
 	out := make(map[int]*tss.Message, ed25519.Threshold-1)
@@ -30,9 +27,12 @@ func (ed25519 *Ed25519Sign) SignStep1() (map[int]*tss.Message, error) {
 		if i == ed25519.DeviceNumber {
 			continue
 		}
+		// Ri commitment, bound to session and receiver
+		cmt := commitment.NewCommitment(tss.BindId(ed25519.sessionId, ed25519.DeviceNumber, i), Ri.X, Ri.Y)
+		ed25519.cmtD[i] = cmt.Msg
 		// p2p send message
 		data := Step1Data{C: cmt.C}
-		bytes, err := json.Marshal(data)
+		bytes, err := ed25519.codec.Marshal(data)
 		if err != nil {
 			return nil, err
 		}
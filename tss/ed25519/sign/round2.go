@@ -1,12 +1,12 @@
 package sign
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/okx/threshold-lib/crypto/commitment"
 	"github.com/okx/threshold-lib/crypto/curves"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
 type Step2Data struct {
@@ -25,22 +25,24 @@ func (ed25519 *Ed25519Sign) SignStep2(msgs []*tss.Message) (map[int]*tss.Message
 	// received step1 message from others
 	ed25519.CommitmentMap = make(map[int]commitment.Commitment, len(msgs))
 	for _, msg := range msgs {
-		if msg.To != ed25519.DeviceNumber {
+		if msg.To != ed25519.DeviceNumber || !ed25519.isPeer(msg.From) {
+			return nil, fmt.Errorf("message sending error")
+		}
+		// one message per signer, a replayed message can't stand in for a missing one
+		if _, ok := ed25519.CommitmentMap[msg.From]; ok {
 			return nil, fmt.Errorf("message sending error")
 		}
 		var content Step1Data
-		err := json.Unmarshal([]byte(msg.Data), &content)
+		err := codec.Unmarshal([]byte(msg.Data), &content)
 		if err != nil {
-			return nil, err
+			return nil, tss.NewBlameError(msg, 2, "invalid message", err)
+		}
+		if content.C == nil {
+			return nil, tss.NewBlameError(msg, 2, "missing commitment", nil)
 		}
 		ed25519.CommitmentMap[msg.From] = content.C
 	}
-	// zk schnorr prove ki
 	uiG := curves.ScalarToPoint(curve, ed25519.ki)
-	proof, err := schnorr.Prove(ed25519.ki, uiG)
-	if err != nil {
-		return nil, err
-	}
 	ed25519.RoundNumber = 3
 
 	out := make(map[int]*tss.Message, ed25519.Threshold-1)
@@ -48,11 +50,16 @@ func (ed25519 *Ed25519Sign) SignStep2(msgs []*tss.Message) (map[int]*tss.Message
 		if i == ed25519.DeviceNumber {
 			continue
 		}
+		// zk schnorr prove ki, bound to session and receiver
+		proof, err := schnorr.ProveWithId(tss.BindId(ed25519.sessionId, ed25519.DeviceNumber, i), ed25519.ki, uiG)
+		if err != nil {
+			return nil, err
+		}
 		data := Step2Data{
-			Witness: ed25519.cmtD,
+			Witness: ed25519.cmtD[i],
 			Proof:   proof,
 		}
-		bytes, err := json.Marshal(data)
+		bytes, err := ed25519.codec.Marshal(data)
 		if err != nil {
 			return nil, err
 		}
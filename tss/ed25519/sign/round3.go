@@ -3,7 +3,6 @@ package sign
 import (
 	"crypto/sha512"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -11,8 +10,10 @@ import (
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/okx/threshold-lib/crypto/commitment"
 	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/prehash"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
 // SignStep3  calculate R, si = ri + h * xi
@@ -26,31 +27,40 @@ func (ed25519 *Ed25519Sign) SignStep3(msgs []*tss.Message) (*big.Int, *big.Int,
 	}
 	// R = sum(Ri)
 	R := curves.ScalarToPoint(curve, ed25519.ki)
+	verified := make(map[int]bool, len(msgs))
 	for _, msg := range msgs {
-		if msg.To != ed25519.DeviceNumber {
+		if msg.To != ed25519.DeviceNumber || !ed25519.isPeer(msg.From) || verified[msg.From] {
+			return nil, nil, fmt.Errorf("message sending error")
+		}
+		verified[msg.From] = true
+		// the proof is checked against the binding of a signer who committed in step 2
+		if _, ok := ed25519.CommitmentMap[msg.From]; !ok {
 			return nil, nil, fmt.Errorf("message sending error")
 		}
 		var data Step2Data
-		err := json.Unmarshal([]byte(msg.Data), &data)
+		err := codec.Unmarshal([]byte(msg.Data), &data)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, tss.NewBlameError(msg, 3, "invalid message", err)
 		}
 		// check Ri commitment
 		commit := commitment.HashCommitment{}
 		commit.C = ed25519.CommitmentMap[msg.From]
 		commit.Msg = data.Witness
 		ok, DeC := commit.Open()
-		if !ok {
-			return nil, nil, fmt.Errorf("commitment DeCommit fail")
+		if !ok || len(DeC) != 3 {
+			return nil, nil, tss.NewBlameError(msg, 3, "commitment DeCommit fail", nil)
+		}
+		bindId := tss.BindId(ed25519.sessionId, msg.From, ed25519.DeviceNumber)
+		if DeC[0].Cmp(bindId) != 0 {
+			return nil, nil, tss.NewBlameError(msg, 3, "commitment sessionId error", nil)
 		}
-		Rj, err := curves.NewECPoint(curve, DeC[0], DeC[1])
+		Rj, err := curves.NewECPoint(curve, DeC[1], DeC[2])
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, tss.NewBlameError(msg, 3, "invalid Rj", err)
 		}
 		// ki schnorr verify, Rj = kj*G
-		verify := schnorr.Verify(data.Proof, Rj)
-		if !verify {
-			return nil, nil, fmt.Errorf("schnorr verify fail")
+		if data.Proof == nil || !schnorr.VerifyWithId(bindId, data.Proof, Rj) {
+			return nil, nil, tss.NewBlameError(msg, 3, "schnorr verify fail", nil)
 		}
 		R, err = R.Add(Rj)
 		if err != nil {
@@ -63,12 +73,17 @@ func (ed25519 *Ed25519Sign) SignStep3(msgs []*tss.Message) (*big.Int, *big.Int,
 	if err != nil {
 		return nil, nil, err
 	}
-	// h = hash512(R || Pub || M)
+	dom, message, err := prehash.Ed25519Message(ed25519.options, bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	// h = hash512(dom2 || R || Pub || M)
 	h := sha512.New()
 	h.Reset()
+	h.Write(dom)
 	h.Write(RR.Serialize())
 	h.Write(ed25519.PublicKey.Serialize())
-	h.Write(bytes)
+	h.Write(message)
 
 	var lambda [64]byte
 	h.Sum(lambda[:0])
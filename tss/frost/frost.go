@@ -0,0 +1,353 @@
+package frost
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+)
+
+// Ciphersuite group and hash functions of a FROST ciphersuite, RFC 9591 section 6
+type Ciphersuite struct {
+	Group group.Group
+	// Hash H1 "rho", H3 "nonce", H4 "msg" and H5 "com", H1 and H3 digests are reduced by ScalarFromUniformBytes
+	Hash func(tag string, data ...[]byte) []byte
+	// EncodeKey group publicKey in the binding factor input
+	EncodeKey func(publicKey group.Point) []byte
+	// Challenge c of the group commitment R, H2 for RFC 9591 ciphersuites
+	Challenge func(R, publicKey group.Point, message []byte) group.Scalar
+	// EvenR signatures carry R with even Y, the nonces are negated when the group commitment has odd Y (BIP340)
+	EvenR bool
+}
+
+// NonceCommitment public commitment of a nonce pair, Di = di*G, Ei = ei*G
+type NonceCommitment struct {
+	Id      int
+	Hiding  *curves.ECPoint // Di
+	Binding *curves.ECPoint // Ei
+}
+
+// Nonces secret nonce pair, used for exactly one signature
+type Nonces struct {
+	hiding     group.Scalar
+	binding    group.Scalar
+	Commitment *NonceCommitment
+}
+
+// Used the nonces were consumed by a signature share
+func (nonces *Nonces) Used() bool {
+	return nonces.hiding == nil || nonces.binding == nil
+}
+
+// Preprocess generate count nonce pairs of the signer id before the message is known,
+// publish the commitments to the other signers or the coordinator, keep the nonces secret
+func (cs *Ciphersuite) Preprocess(id int, shareI *big.Int, count int) ([]*Nonces, error) {
+	if id <= 0 || shareI == nil || count < 1 {
+		return nil, fmt.Errorf("parameter error")
+	}
+	secret, err := cs.Share(shareI)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Nonces, count)
+	for i := range list {
+		hiding, err := cs.nonceGenerate(secret)
+		if err != nil {
+			return nil, err
+		}
+		binding, err := cs.nonceGenerate(secret)
+		if err != nil {
+			return nil, err
+		}
+		Di, err := cs.Group.Generator().ScalarMult(hiding).ECPoint()
+		if err != nil {
+			return nil, err
+		}
+		Ei, err := cs.Group.Generator().ScalarMult(binding).ECPoint()
+		if err != nil {
+			return nil, err
+		}
+		list[i] = &Nonces{
+			hiding:     hiding,
+			binding:    binding,
+			Commitment: &NonceCommitment{Id: id, Hiding: Di, Binding: Ei},
+		}
+	}
+	return list, nil
+}
+
+// NonceFromRandom H3(random || secret), nonce_generate of RFC 9591 with the random bytes given
+func (cs *Ciphersuite) NonceFromRandom(random []byte, secret group.Scalar) group.Scalar {
+	return cs.hashToScalar("nonce", random, secret.Bytes())
+}
+
+// nonceGenerate H3(random || secret), hedged against a weak random source
+func (cs *Ciphersuite) nonceGenerate(secret group.Scalar) (group.Scalar, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	k := cs.NonceFromRandom(random, secret)
+	if k.IsZero() {
+		return nil, fmt.Errorf("nonce generate error")
+	}
+	return k, nil
+}
+
+// Sign signature share zi = di + ei*rhoi + λi*xi*c of the signer id, ±(di + ei*rhoi) for EvenR,
+// the nonces are consumed, commitments hold one commitment of each signer
+func (cs *Ciphersuite) Sign(nonces *Nonces, id int, shareI *big.Int, publicKey *curves.ECPoint, message []byte,
+	commitments []*NonceCommitment) (*big.Int, error) {
+	if nonces == nil || nonces.Used() {
+		return nil, fmt.Errorf("nonces already used")
+	}
+	if shareI == nil || publicKey == nil {
+		return nil, fmt.Errorf("parameter error")
+	}
+	commitments, err := cs.SortCommitments(commitments)
+	if err != nil {
+		return nil, err
+	}
+	own := false
+	for _, cmt := range commitments {
+		own = own || (cmt.Id == id && cmt.Equals(nonces.Commitment))
+	}
+	if !own {
+		return nil, fmt.Errorf("own commitment is missing")
+	}
+	R, rhoMap, err := cs.groupCommitment(publicKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	rho := rhoMap[id]
+	c, err := cs.challenge(R, publicKey, message)
+	if err != nil {
+		return nil, err
+	}
+	k := nonces.hiding.Add(nonces.binding.Mul(rho))
+	if cs.EvenR && oddY(R) {
+		k = k.Negate()
+	}
+	// λi*xi
+	wi, err := cs.Group.ScalarFromSecret(vss.CalLagrangian(cs.Group.Curve(), big.NewInt(int64(id)), shareI, ids(commitments)))
+	if err != nil {
+		return nil, err
+	}
+	zi := k.Add(wi.Mul(c))
+	// never sign twice with the same nonces
+	nonces.hiding, nonces.binding = nil, nil
+	return zi.BigInt(), nil
+}
+
+// Aggregate verify every signature share zi and output the group commitment R and z = sum(zi),
+// R has even Y for EvenR, a wrong share is reported as tss.BlameError of the signer
+func (cs *Ciphersuite) Aggregate(publicKey *curves.ECPoint, sharePubKeyMap map[int]*curves.ECPoint, message []byte,
+	commitments []*NonceCommitment, shares map[int]*big.Int) (*curves.ECPoint, *big.Int, error) {
+	if publicKey == nil || len(commitments) != len(shares) {
+		return nil, nil, fmt.Errorf("parameter error")
+	}
+	commitments, err := cs.SortCommitments(commitments)
+	if err != nil {
+		return nil, nil, err
+	}
+	R, rhoMap, err := cs.groupCommitment(publicKey, message, commitments)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := cs.challenge(R, publicKey, message)
+	if err != nil {
+		return nil, nil, err
+	}
+	negate := cs.EvenR && oddY(R)
+
+	idList := ids(commitments)
+	z := cs.Group.NewScalar()
+	for _, cmt := range commitments {
+		zi, ok := shares[cmt.Id]
+		if !ok || zi == nil {
+			return nil, nil, fmt.Errorf("missing signature share of %d", cmt.Id)
+		}
+		Xi, err := cs.point(sharePubKeyMap[cmt.Id])
+		if err != nil {
+			return nil, nil, fmt.Errorf("missing share publicKey of %d", cmt.Id)
+		}
+		// zi*G = ±(Di + rhoi*Ei) + c*λi*Xi
+		Ri, err := cs.commitmentShare(cmt, rhoMap[cmt.Id])
+		if err != nil {
+			return nil, nil, err
+		}
+		if negate {
+			Ri = Ri.Negate()
+		}
+		lambda := cs.Group.ScalarFromBigInt(vss.CalLagrangian(cs.Group.Curve(), big.NewInt(int64(cmt.Id)), big.NewInt(1), idList))
+		expected := Ri.Add(Xi.ScalarMult(c.Mul(lambda)))
+		if zi.Sign() < 0 || zi.Cmp(cs.Group.Order()) >= 0 || !cs.Group.Generator().ScalarMult(cs.Group.ScalarFromBigInt(zi)).Equal(expected) {
+			return nil, nil, &tss.BlameError{From: cmt.Id, Round: 3, Check: "invalid signature share", Evidence: zi.String()}
+		}
+		z = z.Add(cs.Group.ScalarFromBigInt(zi))
+	}
+	if negate {
+		R = R.Negate()
+	}
+	Rpoint, err := R.ECPoint()
+	if err != nil {
+		return nil, nil, err
+	}
+	return Rpoint, z.BigInt(), nil
+}
+
+// Share key share as a scalar, key files of older versions hold shares not reduced modulo the order
+func (cs *Ciphersuite) Share(shareI *big.Int) (group.Scalar, error) {
+	if shareI.Cmp(cs.Group.Order()) >= 0 {
+		shareI = new(big.Int).Mod(shareI, cs.Group.Order())
+	}
+	return cs.Group.ScalarFromSecret(shareI)
+}
+
+// SortCommitments sort by id, ids are unique and points on the curve
+func (cs *Ciphersuite) SortCommitments(commitments []*NonceCommitment) ([]*NonceCommitment, error) {
+	list := make([]*NonceCommitment, len(commitments))
+	for i, cmt := range commitments {
+		if err := cs.CheckCommitment(cmt); err != nil {
+			return nil, err
+		}
+		list[i] = cmt
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Id < list[j].Id
+	})
+	for i, cmt := range list {
+		if cmt.Id <= 0 || (i > 0 && list[i-1].Id == cmt.Id) {
+			return nil, fmt.Errorf("invalid commitment list")
+		}
+	}
+	return list, nil
+}
+
+// CheckCommitment both points are on the curve of the ciphersuite
+func (cs *Ciphersuite) CheckCommitment(cmt *NonceCommitment) error {
+	if cmt == nil || cmt.Hiding == nil || cmt.Binding == nil {
+		return fmt.Errorf("invalid commitment")
+	}
+	cmt.Hiding.SetCurve(cs.Group.Curve())
+	cmt.Binding.SetCurve(cs.Group.Curve())
+	if !cmt.Hiding.IsOnCurve() || !cmt.Binding.IsOnCurve() {
+		return fmt.Errorf("invalid commitment of %d", cmt.Id)
+	}
+	return nil
+}
+
+// Equals same id and points
+func (cmt *NonceCommitment) Equals(other *NonceCommitment) bool {
+	return other != nil && cmt.Id == other.Id && cmt.Hiding.Equals(other.Hiding) && cmt.Binding.Equals(other.Binding)
+}
+
+// groupCommitment R = sum(Di + rhoi*Ei), binding factor rhoi = H1(PK || H4(msg) || H5(commitments) || i)
+func (cs *Ciphersuite) groupCommitment(publicKey *curves.ECPoint, message []byte, commitments []*NonceCommitment) (group.Point, map[int]group.Scalar, error) {
+	PK, err := cs.point(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	var encoded []byte
+	for _, cmt := range commitments {
+		Di, err := cs.point(cmt.Hiding)
+		if err != nil {
+			return nil, nil, err
+		}
+		Ei, err := cs.point(cmt.Binding)
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded = append(encoded, cs.identifier(cmt.Id)...)
+		encoded = append(encoded, Di.Bytes()...)
+		encoded = append(encoded, Ei.Bytes()...)
+	}
+	var prefix []byte
+	prefix = append(prefix, cs.EncodeKey(PK)...)
+	prefix = append(prefix, cs.Hash("msg", message)...)
+	prefix = append(prefix, cs.Hash("com", encoded)...)
+
+	rhoMap := make(map[int]group.Scalar, len(commitments))
+	R := cs.Group.Identity()
+	for _, cmt := range commitments {
+		rho := cs.hashToScalar("rho", prefix, cs.identifier(cmt.Id))
+		rhoMap[cmt.Id] = rho
+		Ri, err := cs.commitmentShare(cmt, rho)
+		if err != nil {
+			return nil, nil, err
+		}
+		R = R.Add(Ri)
+	}
+	if R.IsIdentity() {
+		return nil, nil, fmt.Errorf("group commitment is the identity")
+	}
+	return R, rhoMap, nil
+}
+
+// commitmentShare Di + rhoi*Ei
+func (cs *Ciphersuite) commitmentShare(cmt *NonceCommitment, rho group.Scalar) (group.Point, error) {
+	Di, err := cs.point(cmt.Hiding)
+	if err != nil {
+		return nil, err
+	}
+	Ei, err := cs.point(cmt.Binding)
+	if err != nil {
+		return nil, err
+	}
+	return Di.Add(Ei.ScalarMult(rho)), nil
+}
+
+// challenge c of the group commitment R, taken with even Y for EvenR
+func (cs *Ciphersuite) challenge(R group.Point, publicKey *curves.ECPoint, message []byte) (group.Scalar, error) {
+	PK, err := cs.point(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if cs.EvenR && oddY(R) {
+		R = R.Negate()
+	}
+	return cs.Challenge(R, PK, message), nil
+}
+
+// hashToScalar H1 and H3
+func (cs *Ciphersuite) hashToScalar(tag string, data ...[]byte) group.Scalar {
+	k, err := cs.Group.ScalarFromUniformBytes(cs.Hash(tag, data...))
+	if err != nil {
+		panic(fmt.Errorf("frost: digest length of the ciphersuite: %v", err))
+	}
+	return k
+}
+
+// identifier scalar encoding of a signer id
+func (cs *Ciphersuite) identifier(id int) []byte {
+	return cs.Group.ScalarFromBigInt(big.NewInt(int64(id))).Bytes()
+}
+
+// point key or commitment point in the group of the ciphersuite, not in the group of its own curve instance
+func (cs *Ciphersuite) point(p *curves.ECPoint) (group.Point, error) {
+	if p == nil || p.X == nil || p.Y == nil {
+		return nil, fmt.Errorf("point is nil")
+	}
+	if curves.GetCurveName(p.Curve) != cs.Group.Name() {
+		return nil, fmt.Errorf("point is not on %s", cs.Group.Name())
+	}
+	return cs.Group.PointFromAffine(p.X, p.Y)
+}
+
+func oddY(p group.Point) bool {
+	_, y := p.Affine()
+	return y.Bit(0) == 1
+}
+
+func ids(commitments []*NonceCommitment) []*big.Int {
+	list := make([]*big.Int, len(commitments))
+	for i, cmt := range commitments {
+		list[i] = big.NewInt(int64(cmt.Id))
+	}
+	return list
+}
@@ -0,0 +1,116 @@
+package frost
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"math/big"
+	"testing"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/stretchr/testify/require"
+)
+
+// testSuite plain schnorr c = H("chal" || R || PK || m)
+func testSuite(g group.Group, newHash func() hash.Hash, evenR bool) *Ciphersuite {
+	digest := func(data ...[]byte) []byte {
+		h := newHash()
+		for _, d := range data {
+			h.Write(d)
+		}
+		return h.Sum(nil)
+	}
+	return &Ciphersuite{
+		Group: g,
+		Hash: func(tag string, data ...[]byte) []byte {
+			return digest(append([][]byte{[]byte(tag)}, data...)...)
+		},
+		EncodeKey: func(publicKey group.Point) []byte {
+			return publicKey.Bytes()
+		},
+		Challenge: func(R, publicKey group.Point, message []byte) group.Scalar {
+			c, _ := g.ScalarFromUniformBytes(digest([]byte("chal"), R.Bytes(), publicKey.Bytes(), message))
+			return c
+		},
+		EvenR: evenR,
+	}
+}
+
+// deal 2 of 3 shares of a random secret
+func deal(t *testing.T, g group.Group) (*curves.ECPoint, map[int]*big.Int, map[int]*curves.ECPoint) {
+	secret, err := rand.Int(rand.Reader, g.Order())
+	require.NoError(t, err)
+	feldman, err := vss.NewFeldman(2, 3, g.Curve())
+	require.NoError(t, err)
+	verifiers, shares, err := feldman.Evaluate(secret)
+	require.NoError(t, err)
+	shareMap := make(map[int]*big.Int)
+	pubKeyMap := make(map[int]*curves.ECPoint)
+	for _, share := range shares {
+		id := int(share.Id.Int64())
+		shareMap[id] = share.Y
+		pubKeyMap[id] = curves.ScalarToPoint(g.Curve(), share.Y)
+	}
+	return verifiers[0], shareMap, pubKeyMap
+}
+
+func TestCiphersuite(t *testing.T) {
+	message := []byte("hello frost")
+	for _, suite := range []*Ciphersuite{testSuite(group.Ed25519(), sha512.New, false), testSuite(group.Secp256k1(), sha256.New, true)} {
+		g := suite.Group
+		publicKey, shares, pubKeyMap := deal(t, g)
+		// shares of older key files are not reduced
+		legacy, err := suite.Share(new(big.Int).Add(shares[1], g.Order()))
+		require.NoError(t, err)
+		reduced, err := suite.Share(shares[1])
+		require.NoError(t, err)
+		require.True(t, legacy.Equal(reduced))
+		for _, partList := range [][]int{{1, 2}, {1, 3}, {2, 3}, {1, 2, 3}} {
+			nonces := make(map[int]*Nonces)
+			var commitments []*NonceCommitment
+			for _, id := range partList {
+				list, err := suite.Preprocess(id, shares[id], 1)
+				require.NoError(t, err)
+				nonces[id] = list[0]
+				commitments = append(commitments, list[0].Commitment)
+			}
+			zMap := make(map[int]*big.Int)
+			for _, id := range partList {
+				zi, err := suite.Sign(nonces[id], id, shares[id], publicKey, message, commitments)
+				require.NoError(t, err)
+				require.True(t, nonces[id].Used())
+				zMap[id] = zi
+			}
+			R, z, err := suite.Aggregate(publicKey, pubKeyMap, message, commitments, zMap)
+			require.NoError(t, err)
+
+			// z*G = R + c*PK
+			Rp, err := group.FromECPoint(R)
+			require.NoError(t, err)
+			PK, err := group.FromECPoint(publicKey)
+			require.NoError(t, err)
+			if suite.EvenR {
+				require.False(t, oddY(Rp))
+			}
+			c := suite.Challenge(Rp, PK, message)
+			require.True(t, g.Generator().ScalarMult(g.ScalarFromBigInt(z)).Equal(Rp.Add(PK.ScalarMult(c))))
+
+			// nonces are single use
+			_, err = suite.Sign(nonces[partList[0]], partList[0], shares[partList[0]], publicKey, message, commitments)
+			require.Error(t, err)
+
+			// a wrong share blames its signer
+			zMap[partList[1]] = new(big.Int).Mod(new(big.Int).Add(zMap[partList[1]], big.NewInt(1)), g.Order())
+			_, _, err = suite.Aggregate(publicKey, pubKeyMap, message, commitments, zMap)
+			culprit, ok := tss.Culprit(err)
+			require.True(t, ok)
+			require.Equal(t, partList[1], culprit)
+		}
+		fmt.Println("ciphersuite", curves.GetCurveName(g.Curve()), "ok")
+	}
+}
@@ -0,0 +1,32 @@
+package frost
+
+import (
+	"fmt"
+
+	"github.com/okx/threshold-lib/tss"
+)
+
+// SignStep1 p2p send the nonce commitment chosen for this signature
+func (signer *Signer) SignStep1() (map[int]*tss.Message, error) {
+	if signer.RoundNumber != 1 {
+		return nil, fmt.Errorf("round error")
+	}
+	signer.RoundNumber = 2
+
+	bytes, err := signer.codec.Marshal(Step1Data{Commitment: signer.nonces.Commitment})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]*tss.Message, len(signer.partList)-1)
+	for _, i := range signer.partList {
+		if i == signer.DeviceNumber {
+			continue
+		}
+		out[i] = &tss.Message{
+			From: signer.DeviceNumber,
+			To:   i,
+			Data: string(bytes),
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package frost
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// SignStep2 receive nonce commitments, p2p send the signature share, see Ciphersuite.Sign
+func (signer *Signer) SignStep2(msgs []*tss.Message) (map[int]*tss.Message, error) {
+	if signer.RoundNumber != 2 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != len(signer.partList)-1 {
+		return nil, fmt.Errorf("messages number error")
+	}
+	if signer.nonces.Used() {
+		return nil, fmt.Errorf("nonces already used")
+	}
+	received := make(map[int]bool, len(msgs))
+	commitments := []*NonceCommitment{signer.nonces.Commitment}
+	for _, msg := range msgs {
+		if msg.To != signer.DeviceNumber {
+			return nil, fmt.Errorf("message sending error")
+		}
+		if msg.From == signer.DeviceNumber || !signer.isSigner(msg.From) || received[msg.From] {
+			return nil, tss.NewBlameError(msg, 2, "unexpected sender", nil)
+		}
+		received[msg.From] = true
+		var content Step1Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid message", err)
+		}
+		cmt := content.Commitment
+		if cmt == nil || cmt.Id != msg.From {
+			return nil, tss.NewBlameError(msg, 2, "invalid commitment", nil)
+		}
+		if err := signer.suite.CheckCommitment(cmt); err != nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid commitment", err)
+		}
+		commitments = append(commitments, cmt)
+	}
+	commitments, err := signer.suite.SortCommitments(commitments)
+	if err != nil {
+		return nil, err
+	}
+	zi, err := signer.suite.Sign(signer.nonces, signer.DeviceNumber, signer.shareI, signer.publicKey, signer.message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	signer.commitments = commitments
+	signer.shares = map[int]*big.Int{signer.DeviceNumber: zi}
+	signer.RoundNumber = 3
+
+	bytes, err := signer.codec.Marshal(Step2Data{Zi: zi})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]*tss.Message, len(signer.partList)-1)
+	for _, i := range signer.partList {
+		if i == signer.DeviceNumber {
+			continue
+		}
+		out[i] = &tss.Message{
+			From: signer.DeviceNumber,
+			To:   i,
+			Data: string(bytes),
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package frost
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// SignStep3 receive signature shares, verify them and output R and z of the signature, see Ciphersuite.Aggregate
+func (signer *Signer) SignStep3(msgs []*tss.Message) (*curves.ECPoint, *big.Int, error) {
+	if signer.RoundNumber != 3 {
+		return nil, nil, fmt.Errorf("round error")
+	}
+	signer.RoundNumber = -1
+	if len(msgs) != len(signer.partList)-1 {
+		return nil, nil, fmt.Errorf("messages number error")
+	}
+	for _, msg := range msgs {
+		if msg.To != signer.DeviceNumber {
+			return nil, nil, fmt.Errorf("message sending error")
+		}
+		if _, ok := signer.shares[msg.From]; ok || !signer.isSigner(msg.From) {
+			return nil, nil, tss.NewBlameError(msg, 3, "unexpected sender", nil)
+		}
+		var content Step2Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, nil, tss.NewBlameError(msg, 3, "invalid message", err)
+		}
+		if content.Zi == nil {
+			return nil, nil, tss.NewBlameError(msg, 3, "missing signature share", nil)
+		}
+		signer.shares[msg.From] = content.Zi
+	}
+	return signer.suite.Aggregate(signer.publicKey, signer.sharePubKeyMap, signer.message, signer.commitments, signer.shares)
+}
+
+// Share own signature share, set by SignStep2
+func (signer *Signer) Share() *big.Int {
+	return signer.shares[signer.DeviceNumber]
+}
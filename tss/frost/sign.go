@@ -0,0 +1,89 @@
+package frost
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// Signer FROST signing among partList, one round to exchange nonce commitments,
+// one round to exchange signature shares, then every signer can aggregate the signature
+type Signer struct {
+	DeviceNumber int
+	RoundNumber  int
+
+	suite          *Ciphersuite
+	partList       []int // participating signature number, at least threshold
+	shareI         *big.Int
+	publicKey      *curves.ECPoint
+	sharePubKeyMap map[int]*curves.ECPoint
+	message        []byte
+	nonces         *Nonces
+
+	commitments []*NonceCommitment
+	shares      map[int]*big.Int
+
+	codec codec.Codec // outgoing round payloads
+}
+
+type Step1Data struct {
+	Commitment *NonceCommitment
+}
+
+type Step2Data struct {
+	Zi *big.Int
+}
+
+// NewSigner nonces come from Preprocess of the same suite and are consumed by this signature
+func NewSigner(suite *Ciphersuite, id int, shareI *big.Int, publicKey *curves.ECPoint, sharePubKeyMap map[int]*curves.ECPoint,
+	partList []int, message []byte, nonces *Nonces) (*Signer, error) {
+	if suite == nil || shareI == nil || publicKey == nil || len(partList) < 2 {
+		return nil, fmt.Errorf("parameter error")
+	}
+	if nonces == nil || nonces.Commitment == nil || nonces.Commitment.Id != id {
+		return nil, fmt.Errorf("nonces of another signer")
+	}
+	seen := make(map[int]bool, len(partList))
+	for _, i := range partList {
+		if seen[i] || sharePubKeyMap[i] == nil {
+			return nil, fmt.Errorf("partList error")
+		}
+		seen[i] = true
+	}
+	if !seen[id] {
+		return nil, fmt.Errorf("signer is not in partList")
+	}
+	return &Signer{
+		DeviceNumber:   id,
+		RoundNumber:    1,
+		suite:          suite,
+		partList:       partList,
+		shareI:         shareI,
+		publicKey:      publicKey,
+		sharePubKeyMap: sharePubKeyMap,
+		message:        message,
+		nonces:         nonces,
+		codec:          codec.JSON,
+	}, nil
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (signer *Signer) SetCodec(c codec.Codec) *Signer {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
+	}
+	signer.codec = c
+	return signer
+}
+
+// isSigner id is in partList
+func (signer *Signer) isSigner(id int) bool {
+	for _, i := range signer.partList {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,292 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/tss"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Version of the backup format
+const Version = 1
+
+// DefaultKDF scrypt parameters of new backups
+var DefaultKDF = KDFParams{Name: "scrypt", N: 1 << 15, R: 8, P: 1}
+
+// KDFParams passphrase key derivation
+type KDFParams struct {
+	Name    string
+	Salt    []byte
+	N, R, P int
+}
+
+// limits of scrypt params read from a backup, the header is not authenticated before the key is derived,
+// same N limit as the keystore, memory 128*N*R stays within 1GB
+const (
+	maxKDFN      = 1 << 20
+	maxKDFMemory = 1 << 30
+	maxKDFP      = 16
+)
+
+// valid scrypt name, salt and params within limits, N is a power of 2
+func (kdf *KDFParams) valid() bool {
+	if kdf.Name != "scrypt" || len(kdf.Salt) == 0 {
+		return false
+	}
+	if kdf.N <= 1 || kdf.N > maxKDFN || kdf.N&(kdf.N-1) != 0 {
+		return false
+	}
+	if kdf.R <= 0 || kdf.R > maxKDFMemory/(128*kdf.N) || kdf.P <= 0 || kdf.P > maxKDFP {
+		return false
+	}
+	return true
+}
+
+// KitParams recovery kits, Threshold of Total kits open the backup without the passphrase
+type KitParams struct {
+	Threshold int
+	Total     int
+	Verifiers []*curves.ECPoint // feldman verifiers of the data key on secp256k1, a kit is checked against them
+}
+
+// Header metadata readable without the passphrase, authenticated as associated data of the payload
+type Header struct {
+	Version   int
+	Curve     string
+	PartyId   int
+	PublicKey *curves.ECPoint
+	ChainCode string
+	KDF       *KDFParams // nil if the backup can only be opened with kits
+	Kits      *KitParams // nil without recovery kits
+}
+
+// Backup encrypted share material of one party, the payload is encrypted with a random data key using AES-256-GCM,
+// the data key is wrapped with the scrypt key of the passphrase and optionally split into recovery kits.
+// Checksum is sha256 of everything else, Verify detects corruption without decryption, only decryption authenticates
+type Backup struct {
+	Header
+	WrappedKey []byte // nonce || AES-256-GCM(passphrase key, data key)
+	Ciphertext []byte // nonce || AES-256-GCM(data key, payload), header is the associated data
+	Checksum   string
+}
+
+// payload plaintext of a backup
+type payload struct {
+	Key   *tss.KeyStep3Data
+	Extra json.RawMessage `json:",omitempty"`
+}
+
+// Export encrypt key and optional extra share material of the same party, e.g. keygen.P2SaveData,
+// passphrase may be empty when kits are given, kits split the data key into kits[0].Threshold of kits[0].Total
+func Export(key *tss.KeyStep3Data, extra interface{}, passphrase []byte, kits ...KitParams) (*Backup, []*Kit, error) {
+	if key == nil || key.ShareI == nil || key.PublicKey == nil {
+		return nil, nil, fmt.Errorf("Export key is incomplete")
+	}
+	if len(passphrase) == 0 && len(kits) == 0 {
+		return nil, nil, fmt.Errorf("Export needs a passphrase or recovery kits")
+	}
+	if len(kits) > 1 {
+		return nil, nil, fmt.Errorf("Export params error")
+	}
+	curve := curves.GetCurveName(key.PublicKey.Curve)
+	if curve == "" {
+		return nil, nil, fmt.Errorf("Export unsupported curve")
+	}
+
+	// data key, a nonzero secp256k1 scalar so it can be split with feldman vss
+	dataKey, err := group.Secp256k1().RandomScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	b := &Backup{
+		Header: Header{
+			Version:   Version,
+			Curve:     curve,
+			PartyId:   key.Id,
+			PublicKey: key.PublicKey,
+			ChainCode: key.ChainCode,
+		},
+	}
+	var kitList []*Kit
+	if len(kits) == 1 {
+		b.Kits, kitList, err = split(dataKey, kits[0].Threshold, kits[0].Total)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(passphrase) > 0 {
+		kdf := DefaultKDF
+		kdf.Salt = make([]byte, 32)
+		if _, err := rand.Read(kdf.Salt); err != nil {
+			return nil, nil, err
+		}
+		b.KDF = &kdf
+		kek, err := deriveKey(b.KDF, passphrase)
+		if err != nil {
+			return nil, nil, err
+		}
+		b.WrappedKey, err = seal(kek, dataKey.Bytes(), []byte("threshold-lib backup data key"))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	plaintext := payload{Key: key}
+	if extra != nil {
+		plaintext.Extra, err = json.Marshal(extra)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	bytes, err := json.Marshal(plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+	header, err := json.Marshal(b.Header)
+	if err != nil {
+		return nil, nil, err
+	}
+	b.Ciphertext, err = seal(dataKey.Bytes(), bytes, header)
+	if err != nil {
+		return nil, nil, err
+	}
+	b.Checksum, err = b.checksum()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, kit := range kitList {
+		kit.BackupId = b.Checksum
+	}
+	return b, kitList, nil
+}
+
+// Verify integrity and metadata of the backup without decryption
+func (b *Backup) Verify() error {
+	if b.Version != Version {
+		return fmt.Errorf("unsupported backup version %d", b.Version)
+	}
+	checksum, err := b.checksum()
+	if err != nil {
+		return err
+	}
+	if checksum != b.Checksum {
+		return fmt.Errorf("backup checksum mismatch")
+	}
+	if b.PartyId <= 0 || b.PublicKey == nil || curves.GetCurveName(b.PublicKey.Curve) != b.Curve {
+		return fmt.Errorf("invalid backup metadata")
+	}
+	if b.KDF == nil && b.Kits == nil {
+		return fmt.Errorf("backup can't be opened")
+	}
+	if b.KDF != nil && (!b.KDF.valid() || len(b.WrappedKey) == 0) {
+		return fmt.Errorf("invalid backup kdf")
+	}
+	if b.Kits != nil && (b.Kits.Threshold < 2 || b.Kits.Total < b.Kits.Threshold || len(b.Kits.Verifiers) != b.Kits.Threshold) {
+		return fmt.Errorf("invalid backup kits")
+	}
+	return nil
+}
+
+// Open decrypt with the passphrase, extra receives the extra share material if not nil
+func (b *Backup) Open(passphrase []byte, extra interface{}) (*tss.KeyStep3Data, error) {
+	if err := b.Verify(); err != nil {
+		return nil, err
+	}
+	if b.KDF == nil {
+		return nil, fmt.Errorf("backup has no passphrase, open it with kits")
+	}
+	kek, err := deriveKey(b.KDF, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := open(kek, b.WrappedKey, []byte("threshold-lib backup data key"))
+	if err != nil {
+		return nil, fmt.Errorf("wrong passphrase or corrupted backup")
+	}
+	return b.decrypt(dataKey, extra)
+}
+
+// decrypt payload with the data key
+func (b *Backup) decrypt(dataKey []byte, extra interface{}) (*tss.KeyStep3Data, error) {
+	header, err := json.Marshal(b.Header)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := open(dataKey, b.Ciphertext, header)
+	if err != nil {
+		return nil, fmt.Errorf("backup decryption fail")
+	}
+	var plaintext payload
+	if err := json.Unmarshal(bytes, &plaintext); err != nil {
+		return nil, err
+	}
+	key := plaintext.Key
+	if key == nil || key.Id != b.PartyId || key.PublicKey == nil || !key.PublicKey.Equals(b.PublicKey) {
+		return nil, fmt.Errorf("backup payload doesn't match the metadata")
+	}
+	if extra != nil && len(plaintext.Extra) > 0 {
+		if err := json.Unmarshal(plaintext.Extra, extra); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// checksum sha256 of the backup without the checksum
+func (b *Backup) checksum() (string, error) {
+	c := *b
+	c.Checksum = ""
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func deriveKey(kdf *KDFParams, passphrase []byte) ([]byte, error) {
+	if !kdf.valid() {
+		return nil, fmt.Errorf("unsupported kdf %s or params out of range", kdf.Name)
+	}
+	return scrypt.Key(passphrase, kdf.Salt, kdf.N, kdf.R, kdf.P, 32)
+}
+
+// seal nonce || AES-256-GCM ciphertext
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], additionalData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
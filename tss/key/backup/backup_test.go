@@ -0,0 +1,182 @@
+package backup
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/key/dealer"
+	"github.com/stretchr/testify/require"
+)
+
+type extraData struct {
+	X2 *big.Int
+}
+
+// importKeys import privateKey and run the mandatory refresh, key shares indexed by id
+func importKeys(t *testing.T, curve elliptic.Curve, privateKey *big.Int, threshold, total int) map[int]*tss.KeyStep3Data {
+	data, err := dealer.Import(curve, privateKey, threshold, total, nil)
+	require.NoError(t, err)
+	sessionId := tss.SessionId("refresh imported key")
+	infos := make(map[int]*dealer.Refresh, total)
+	for id, key := range data.Keys {
+		infos[id], err = key.Refresh(sessionId, data.Verifiers, curves.ScalarToPoint(curve, privateKey))
+		require.NoError(t, err)
+	}
+	msgs := make(map[int]map[int]*tss.Message, total)
+	for id, info := range infos {
+		msgs[id], err = info.DKGStep1()
+		require.NoError(t, err)
+	}
+	next := make(map[int]map[int]*tss.Message, total)
+	for id, info := range infos {
+		next[id], err = info.DKGStep2(collect(msgs, id))
+		require.NoError(t, err)
+	}
+	keys := make(map[int]*tss.KeyStep3Data, total)
+	for id, info := range infos {
+		keys[id], err = info.DKGStep3(collect(next, id))
+		require.NoError(t, err)
+	}
+	return keys
+}
+
+func collect(msgs map[int]map[int]*tss.Message, id int) []*tss.Message {
+	var in []*tss.Message
+	for _, out := range msgs {
+		if msg, ok := out[id]; ok {
+			in = append(in, msg)
+		}
+	}
+	return in
+}
+
+// roundTrip backup through its json form
+func roundTrip(t *testing.T, b *Backup) *Backup {
+	bytes, err := json.Marshal(b)
+	require.NoError(t, err)
+	decoded := &Backup{}
+	require.NoError(t, json.Unmarshal(bytes, decoded))
+	return decoded
+}
+
+func TestBackup(t *testing.T) {
+	key := importKeys(t, secp256k1.S256(), big.NewInt(12345), 2, 3)[2]
+	passphrase := []byte("correct horse battery staple")
+
+	b, kits, err := Export(key, &extraData{X2: big.NewInt(7)}, passphrase)
+	require.NoError(t, err)
+	require.Nil(t, kits)
+	bytes, _ := json.Marshal(b)
+	fmt.Println(string(bytes))
+
+	b = roundTrip(t, b)
+	require.NoError(t, b.Verify())
+	require.Equal(t, "secp256k1", b.Curve)
+	require.Equal(t, 2, b.PartyId)
+	require.True(t, b.PublicKey.Equals(key.PublicKey))
+	require.Equal(t, key.ChainCode, b.ChainCode)
+
+	var extra extraData
+	opened, err := b.Open(passphrase, &extra)
+	require.NoError(t, err)
+	require.Equal(t, 0, opened.ShareI.Cmp(key.ShareI))
+	require.Equal(t, len(key.SharePubKeyMap), len(opened.SharePubKeyMap))
+	require.Equal(t, int64(7), extra.X2.Int64())
+
+	_, err = b.Open([]byte("wrong"), nil)
+	require.Error(t, err)
+	_, err = b.OpenWithKits(nil, nil)
+	require.Error(t, err)
+
+	// corruption is found without the passphrase
+	corrupted := roundTrip(t, b)
+	corrupted.Ciphertext[len(corrupted.Ciphertext)-1] ^= 1
+	require.Error(t, corrupted.Verify())
+
+	// metadata changed together with the checksum, decryption fails
+	changed := roundTrip(t, b)
+	changed.PartyId = 3
+	changed.Checksum, _ = changed.checksum()
+	require.NoError(t, changed.Verify())
+	_, err = changed.Open(passphrase, nil)
+	require.Error(t, err)
+
+	_, _, err = Export(key, nil, nil)
+	require.Error(t, err)
+}
+
+// scrypt params come from the unauthenticated header, oversized ones are rejected before any key derivation
+func TestBackupKDFLimits(t *testing.T) {
+	key := importKeys(t, secp256k1.S256(), big.NewInt(12345), 2, 3)[1]
+	passphrase := []byte("correct horse battery staple")
+	b, _, err := Export(key, nil, passphrase)
+	require.NoError(t, err)
+
+	for _, kdf := range []KDFParams{
+		{N: 1 << 30, R: 8, P: 1},
+		{N: 1 << 21, R: 1, P: 1},
+		{N: 1 << 20, R: 9, P: 1},
+		{N: 1<<15 + 1, R: 8, P: 1},
+		{N: 1 << 15, R: 8, P: 1 << 20},
+		{N: 1 << 15, R: 0, P: 1},
+	} {
+		crafted := roundTrip(t, b)
+		crafted.KDF.N, crafted.KDF.R, crafted.KDF.P = kdf.N, kdf.R, kdf.P
+		crafted.Checksum, _ = crafted.checksum()
+		require.Error(t, crafted.Verify(), "%+v", kdf)
+		_, err = crafted.Open(passphrase, nil)
+		require.Error(t, err)
+	}
+}
+
+func TestKits(t *testing.T) {
+	key := importKeys(t, edwards.Edwards(), big.NewInt(12345), 2, 3)[1]
+
+	// kits only
+	b, kits, err := Export(key, nil, nil, KitParams{Threshold: 2, Total: 3})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(kits))
+	b = roundTrip(t, b)
+	require.NoError(t, b.Verify())
+	require.Nil(t, b.KDF)
+	for _, kit := range kits {
+		require.NoError(t, b.VerifyKit(kit))
+	}
+	opened, err := b.OpenWithKits([]*Kit{kits[2], kits[0]}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, opened.ShareI.Cmp(key.ShareI))
+	require.Equal(t, "ed25519", b.Curve)
+
+	_, err = b.OpenWithKits(kits[:1], nil)
+	require.Error(t, err)
+	_, err = b.OpenWithKits([]*Kit{kits[1], kits[1]}, nil)
+	require.Error(t, err)
+	_, err = b.Open([]byte("any"), nil)
+	require.Error(t, err)
+
+	bad := *kits[1]
+	bad.Share = &vss.Share{Id: kits[1].Share.Id, Y: new(big.Int).Add(kits[1].Share.Y, big.NewInt(1))}
+	require.Error(t, b.VerifyKit(&bad))
+
+	// passphrase and kits, kits of another backup don't fit
+	passphrase := []byte("passphrase")
+	b2, kits2, err := Export(key, nil, passphrase, KitParams{Threshold: 3, Total: 5})
+	require.NoError(t, err)
+	require.Error(t, b2.VerifyKit(kits[0]))
+	_, err = b2.Open(passphrase, nil)
+	require.NoError(t, err)
+	opened, err = b2.OpenWithKits(kits2[1:4], nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, opened.ShareI.Cmp(key.ShareI))
+
+	_, _, err = Export(key, nil, nil, KitParams{Threshold: 1, Total: 3})
+	require.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package backup
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+)
+
+// Kit recovery kit, one feldman share of the data key of the backup BackupId
+type Kit struct {
+	Version   int
+	BackupId  string // checksum of the backup
+	Threshold int
+	Share     *vss.Share
+}
+
+// split data key into threshold-of-total kits
+func split(dataKey group.Scalar, threshold, total int) (*KitParams, []*Kit, error) {
+	g := group.Secp256k1()
+	feldman, err := vss.NewFeldman(threshold, total, g.Curve())
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]int, total)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	verifiers, shares, err := feldman.Deal(dataKey, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	params := &KitParams{Threshold: threshold, Total: total, Verifiers: make([]*curves.ECPoint, len(verifiers))}
+	for i, v := range verifiers {
+		params.Verifiers[i], err = v.ECPoint()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	kits := make([]*Kit, total)
+	for i, share := range shares {
+		kits[i] = &Kit{Version: Version, Threshold: threshold, Share: share}
+	}
+	return params, kits, nil
+}
+
+// VerifyKit check a kit belongs to the backup, without the other kits
+func (b *Backup) VerifyKit(kit *Kit) error {
+	if err := b.Verify(); err != nil {
+		return err
+	}
+	if b.Kits == nil {
+		return fmt.Errorf("backup has no recovery kits")
+	}
+	if kit == nil || kit.Version != Version || kit.BackupId != b.Checksum || kit.Threshold != b.Kits.Threshold || kit.Share == nil {
+		return fmt.Errorf("kit doesn't belong to the backup")
+	}
+	if kit.Share.Id == nil || kit.Share.Id.Sign() <= 0 || kit.Share.Id.Cmp(big.NewInt(int64(b.Kits.Total))) > 0 {
+		return fmt.Errorf("invalid kit id")
+	}
+	feldman, err := vss.NewFeldman(b.Kits.Threshold, b.Kits.Total, group.Secp256k1().Curve())
+	if err != nil {
+		return err
+	}
+	ok, err := feldman.Verify(kit.Share, b.Kits.Verifiers)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("kit %d verify fail", kit.Share.Id)
+	}
+	return nil
+}
+
+// OpenWithKits decrypt with at least threshold kits, extra receives the extra share material if not nil
+func (b *Backup) OpenWithKits(kits []*Kit, extra interface{}) (*tss.KeyStep3Data, error) {
+	if b.Kits == nil {
+		return nil, fmt.Errorf("backup has no recovery kits")
+	}
+	if len(kits) < b.Kits.Threshold {
+		return nil, fmt.Errorf("%d of %d kits needed", b.Kits.Threshold, b.Kits.Total)
+	}
+	seen := make(map[int64]bool, len(kits))
+	shares := make([]*vss.Share, len(kits))
+	for i, kit := range kits {
+		if err := b.VerifyKit(kit); err != nil {
+			return nil, err
+		}
+		if seen[kit.Share.Id.Int64()] {
+			return nil, fmt.Errorf("duplicate kit %d", kit.Share.Id)
+		}
+		seen[kit.Share.Id.Int64()] = true
+		shares[i] = kit.Share
+	}
+	g := group.Secp256k1()
+	dataKey := vss.RecoverSecret(g.Curve(), shares)
+	key, err := g.ScalarFromSecret(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return b.decrypt(key.Bytes(), extra)
+}
@@ -0,0 +1,84 @@
+package bip32
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+)
+
+// Ed25519Scheme Ed25519TssKey非硬化派生方案
+type Ed25519Scheme int
+
+const (
+	// Ed25519Legacy HMAC-SHA512(ed25519Label | chaincode | publicKey.X | childIdx)，兼容已有派生结果
+	Ed25519Legacy Ed25519Scheme = iota
+	// Ed25519Bip32 Khovratovich–Law BIP32-Ed25519（Cardano使用）的非硬化公钥派生
+	Ed25519Bip32
+)
+
+// extendedPublicKeyLen BIP32-Ed25519扩展公钥 publicKey(32) || chaincode(32)
+const extendedPublicKeyLen = 64
+
+// NewEd25519TssKeyFromExtendedPublicKey 由BIP32-Ed25519扩展公钥创建只读密钥，没有密钥分片
+func NewEd25519TssKeyFromExtendedPublicKey(xpub []byte) (*Ed25519TssKey, error) {
+	if len(xpub) != extendedPublicKeyLen {
+		return nil, fmt.Errorf("extended publicKey must be %d bytes", extendedPublicKeyLen)
+	}
+	publicKey, err := edwards.ParsePubKey(xpub[:32])
+	if err != nil {
+		return nil, err
+	}
+	point := &curves.ECPoint{Curve: publicKey.Curve, X: publicKey.X, Y: publicKey.Y}
+	return NewEd25519TssKey(nil, point, hex.EncodeToString(xpub[32:]), Ed25519Bip32)
+}
+
+// ExtendedPublicKey BIP32-Ed25519扩展公钥 publicKey || chaincode
+func (tssKey *Ed25519TssKey) ExtendedPublicKey() ([]byte, error) {
+	if len(tssKey.chaincode) != 32 {
+		return nil, fmt.Errorf("chaincode must be 32 bytes")
+	}
+	xpub := make([]byte, 0, extendedPublicKeyLen)
+	xpub = append(xpub, tssKey.ToEd25519PublicKey().Serialize()...)
+	return append(xpub, tssKey.chaincode...), nil
+}
+
+// Scheme 非硬化派生方案
+func (tssKey *Ed25519TssKey) Scheme() Ed25519Scheme {
+	return tssKey.scheme
+}
+
+// calBip32Ed25519Offset Z = HMAC-SHA512(chaincode, 0x02 | A | LE32(i))，偏移量为8*ZL，ZL为Z前28字节的小端整数，
+// 子链码为HMAC-SHA512(chaincode, 0x03 | A | LE32(i))的后32字节，返回值与childKey的intermediary格式一致
+func calBip32Ed25519Offset(publicKey, chaincode []byte, childIdx uint32) ([]byte, error) {
+	index := make([]byte, 4)
+	binary.LittleEndian.PutUint32(index, childIdx)
+
+	z := hmac.New(sha512.New, chaincode)
+	z.Write([]byte{0x02})
+	z.Write(publicKey)
+	z.Write(index)
+	zl := reverse(z.Sum(nil)[:28])
+	offset := new(big.Int).Lsh(new(big.Int).SetBytes(zl), 3)
+
+	c := hmac.New(sha512.New, chaincode)
+	c.Write([]byte{0x03})
+	c.Write(publicKey)
+	c.Write(index)
+
+	intermediary := offset.FillBytes(make([]byte, 32))
+	return append(intermediary, c.Sum(nil)[32:]...), nil
+}
+
+func reverse(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
@@ -0,0 +1,171 @@
+package bip32
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// bip32Ed25519PrivateChild 论文中的非硬化私钥派生 kL' = 8*ZL + kL，kL为小端整数
+func bip32Ed25519PrivateChild(kL, A, c []byte, i uint32) ([]byte, []byte) {
+	index := make([]byte, 4)
+	binary.LittleEndian.PutUint32(index, i)
+	mac := func(prefix byte) []byte {
+		h := hmac.New(sha512.New, c)
+		h.Write(append(append([]byte{prefix}, A...), index...))
+		return h.Sum(nil)
+	}
+	zl := new(big.Int).SetBytes(reverse(mac(0x02)[:28]))
+	child := new(big.Int).Add(new(big.Int).Lsh(zl, 3), new(big.Int).SetBytes(reverse(kL)))
+	return reverse(child.FillBytes(make([]byte, 32))), mac(0x03)[32:]
+}
+
+// bip32Ed25519HardenedChild 论文中的硬化私钥派生，xprv = kL || kR || chaincode，只用于从已发布向量推到账户层
+func bip32Ed25519HardenedChild(xprv []byte, i uint32) []byte {
+	index := make([]byte, 4)
+	binary.LittleEndian.PutUint32(index, i)
+	mac := func(prefix byte) []byte {
+		h := hmac.New(sha512.New, xprv[64:])
+		h.Write([]byte{prefix})
+		h.Write(xprv[:64])
+		h.Write(index)
+		return h.Sum(nil)
+	}
+	z := mac(0x00)
+	kL := new(big.Int).Add(new(big.Int).Lsh(new(big.Int).SetBytes(reverse(z[:28])), 3), new(big.Int).SetBytes(reverse(xprv[:32])))
+	kR := new(big.Int).Add(new(big.Int).SetBytes(reverse(z[32:])), new(big.Int).SetBytes(reverse(xprv[32:64])))
+	kR.Mod(kR, new(big.Int).Lsh(big.NewInt(1), 256))
+	child := append(reverse(kL.FillBytes(make([]byte, 32))), reverse(kR.FillBytes(make([]byte, 32)))...)
+	return append(child, mac(0x01)[32:]...)
+}
+
+// icarusRoot Cardano Icarus根扩展私钥 PBKDF2-HMAC-SHA512("", entropy, 4096, 96)，kL按论文clamp
+func icarusRoot(entropy []byte) []byte {
+	xprv := pbkdf2.Key(nil, entropy, 4096, 96, sha512.New)
+	xprv[0] &= 0xf8
+	xprv[31] &= 0x1f
+	xprv[31] |= 0x40
+	return xprv
+}
+
+// 已发布向量：rust ed25519-bip32 (Cardano) 的 D1 与 D1/0H，校验上面的硬化派生
+func TestBip32Ed25519HardenedVector(t *testing.T) {
+	d1, _ := hex.DecodeString("f8a29231ee38d6c5bf715d5bac21c750577aa3798b22d79d65bf97d6fadea15a" +
+		"dcd1ee1abdf78bd4be64731a12deb94d3671784112eb6f364b871851fd1c9a24" +
+		"7384db9ad6003bbd08b3b1ddc0d07a597293ff85e961bf252b331262eddfad0d")
+	d1h0 := "60d399da83ef80d8d4f8d223239efdc2b8fef387e1b5219137ffb4e8fbdea15a" +
+		"dc9366b7d003af37c11396de9a83734e30e05e851efa32745c9cd7b42712c890" +
+		"608763770eddf77248ab652984b21b849760d1da74a6f5bd633ce41adceef07a"
+	require.Equal(t, d1h0, hex.EncodeToString(bip32Ed25519HardenedChild(d1, 0x80000000)))
+}
+
+// 已发布向量：CIP-19 地址测试向量，助记词 "test walk nut penalty hip pave soap entry language right filter choice"，
+// m/1852'/1815'/0'/0/0 支付公钥的blake2b-224哈希为 9493315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e，
+// 最后两层 /0/0 由Ed25519TssKey和只读扩展公钥做非硬化派生
+func TestBip32Ed25519Cip19Vector(t *testing.T) {
+	curve := edwards.Edwards()
+	entropy, _ := hex.DecodeString("df9ed25ed146bf43336a5d7cf7395994")
+	account := icarusRoot(entropy)
+	for _, i := range []uint32{1852, 1815, 0} {
+		account = bip32Ed25519HardenedChild(account, 0x80000000|i)
+	}
+	x := new(big.Int).SetBytes(reverse(account[:32]))
+	tssKey, err := NewEd25519TssKey(new(big.Int).Mod(x, curve.Params().N), curves.ScalarToPoint(curve, x), hex.EncodeToString(account[64:]), Ed25519Bip32)
+	require.NoError(t, err)
+	xpub, err := tssKey.ExtendedPublicKey()
+	require.NoError(t, err)
+	watchOnly, err := NewEd25519TssKeyFromExtendedPublicKey(xpub)
+	require.NoError(t, err)
+
+	for _, key := range []*Ed25519TssKey{tssKey, watchOnly} {
+		child, err := key.DeriveChildKeys([]uint32{0, 0})
+		require.NoError(t, err)
+		h, _ := blake2b.New(28, nil)
+		h.Write(child.ToEd25519PublicKey().Serialize())
+		require.Equal(t, "9493315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e", hex.EncodeToString(h.Sum(nil)))
+	}
+}
+
+func TestBip32Ed25519(t *testing.T) {
+	curve := edwards.Edwards()
+	// 根扩展私钥的kL（已clamp）和chaincode
+	kL, _ := hex.DecodeString("f8a29231ee38d6c5bf715d5bac21c750577aa3798b22d79d65bf97d6fadea15a")
+	c, _ := hex.DecodeString("7384db9ad6003bbd08b3b1ddc0d07a597293ff85e961bf252b331262eddfad0d")
+	x := new(big.Int).SetBytes(reverse(kL))
+	root := curves.ScalarToPoint(curve, x)
+
+	tssKey, err := NewEd25519TssKey(new(big.Int).Mod(x, curve.Params().N), root, hex.EncodeToString(c), Ed25519Bip32)
+	require.NoError(t, err)
+	xpub, err := tssKey.ExtendedPublicKey()
+	require.NoError(t, err)
+	watchOnly, err := NewEd25519TssKeyFromExtendedPublicKey(xpub)
+	require.NoError(t, err)
+	require.Equal(t, Ed25519Bip32, watchOnly.Scheme())
+
+	// m/0/1/2147483647 与私钥派生一致
+	childKL, childC := kL, c
+	A := xpub[:32]
+	key, watchKey := tssKey, watchOnly
+	for _, i := range []uint32{0, 1, 0x7fffffff} {
+		childKL, childC = bip32Ed25519PrivateChild(childKL, A, childC, i)
+		key, err = key.NewChildKey(i)
+		require.NoError(t, err)
+		watchKey, err = watchKey.NewChildKey(i)
+		require.NoError(t, err)
+
+		expected := curves.ScalarToPoint(curve, new(big.Int).SetBytes(reverse(childKL)))
+		require.True(t, expected.Equals(key.PublicKey()))
+		require.True(t, expected.Equals(watchKey.PublicKey()))
+		require.Equal(t, childC, watchKey.Chaincode())
+		require.Equal(t, new(big.Int).Mod(new(big.Int).SetBytes(reverse(childKL)), curve.Params().N), key.ShareI())
+		A = watchKey.ToEd25519PublicKey().Serialize()
+	}
+
+	// 旧方案结果不变
+	legacy, err := NewEd25519TssKey(nil, root, hex.EncodeToString(c))
+	require.NoError(t, err)
+	legacyChild, err := legacy.NewChildKey(0)
+	require.NoError(t, err)
+	bip32Child, err := watchOnly.NewChildKey(0)
+	require.NoError(t, err)
+	require.False(t, legacyChild.PublicKey().Equals(bip32Child.PublicKey()))
+
+	_, err = NewEd25519TssKeyFromExtendedPublicKey(xpub[:32])
+	require.Error(t, err)
+	_, err = NewEd25519TssKey(nil, root, "0123", Ed25519Bip32)
+	require.Error(t, err)
+}
+
+func TestBip32Ed25519Shares(t *testing.T) {
+	keys := dkgKeys(t, edwards.Edwards())
+	path := []uint32{44, 1815, 0}
+	children := make([]*Ed25519TssKey, 4)
+	for i := 1; i <= 3; i++ {
+		tssKey, err := NewEd25519TssKey(keys[i].ShareI, keys[i].PublicKey, keys[i].ChainCode, Ed25519Bip32)
+		require.NoError(t, err)
+		children[i], err = tssKey.DeriveChildKeys(path)
+		require.NoError(t, err)
+		require.True(t, children[i].PublicKey().Equals(children[1].PublicKey()))
+	}
+
+	// 任意2方的子分片恢复出的私钥对应子公钥
+	curve := edwards.Edwards()
+	xList := []*big.Int{big.NewInt(1), big.NewInt(3)}
+	sum := big.NewInt(0)
+	for _, i := range []int{1, 3} {
+		li := vss.CalLagrangian(curve, big.NewInt(int64(i)), children[i].ShareI(), xList)
+		sum.Add(sum, li)
+	}
+	sum.Mod(sum, curve.Params().N)
+	require.True(t, curves.ScalarToPoint(curve, sum).Equals(children[1].PublicKey()))
+}
@@ -20,10 +20,11 @@ type Ed25519TssKey struct {
 	publicKey    *curves.ECPoint // publicKey
 	chaincode    []byte
 	offsetSonPri *big.Int // child private key share offset, accumulative
+	scheme       Ed25519Scheme
 }
 
-// NewEd25519TssKey 创建新的Ed25519 TSS密钥，shareI是可选的
-func NewEd25519TssKey(shareI *big.Int, publicKey *curves.ECPoint, chaincode string) (*Ed25519TssKey, error) {
+// NewEd25519TssKey 创建新的Ed25519 TSS密钥，shareI是可选的，scheme默认为Ed25519Legacy
+func NewEd25519TssKey(shareI *big.Int, publicKey *curves.ECPoint, chaincode string, scheme ...Ed25519Scheme) (*Ed25519TssKey, error) {
 	chainBytes, err := hex.DecodeString(chaincode)
 	if err != nil {
 		return nil, err
@@ -43,23 +44,57 @@ func NewEd25519TssKey(shareI *big.Int, publicKey *curves.ECPoint, chaincode stri
 		chaincode:    chainBytes,
 		offsetSonPri: big.NewInt(0),
 	}
+	if len(scheme) > 0 {
+		tssKey.scheme = scheme[0]
+	}
+	if tssKey.scheme == Ed25519Bip32 && len(chainBytes) != 32 {
+		return nil, fmt.Errorf("BIP32-Ed25519 chaincode must be 32 bytes")
+	}
 	return tssKey, nil
 }
 
-// NewChildKey 类似BIP32的非硬化派生，专门为Ed25519设计
+// NewChildKey 非硬化派生，Ed25519Bip32时与BIP32-Ed25519的公钥派生结果一致
 func (tssKey *Ed25519TssKey) NewChildKey(childIdx uint32) (*Ed25519TssKey, error) {
 	if childIdx >= uint32(0x80000000) { // 2^31
-		return nil, fmt.Errorf("hardened derivation is unsupported")
+		return nil, fmt.Errorf("hardened derivation is unsupported by NewChildKey, see NewHardenedSetUp")
 	}
 
-	curve := tssKey.publicKey.Curve
-	intermediary, err := calEd25519PrivateOffset(tssKey.publicKey.X.Bytes(), tssKey.chaincode, childIdx)
+	var intermediary []byte
+	var err error
+	if tssKey.scheme == Ed25519Bip32 {
+		intermediary, err = calBip32Ed25519Offset(tssKey.ToEd25519PublicKey().Serialize(), tssKey.chaincode, childIdx)
+	} else {
+		intermediary, err = calEd25519PrivateOffset(tssKey.publicKey.X.Bytes(), tssKey.chaincode, childIdx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tssKey.childKey(intermediary)
+}
+
+// NewHardenedSetUp 硬化派生childIdx >= 2^31，需要至少t个参与方联合计算，sharePubKeyMap为根密钥的DKG结果
+func (tssKey *Ed25519TssKey) NewHardenedSetUp(sessionId *big.Int, deviceNumber int, participants []int, sharePubKeyMap map[int]*curves.ECPoint, childIdx uint32) (*HardenedSetUp, error) {
+	return newHardenedSetUp(sessionId, deviceNumber, participants, sharePubKeyMap, tssKey.shareI, tssKey.offsetSonPri, tssKey.publicKey, tssKey.chaincode, childIdx)
+}
+
+// NewHardenedChildKey 硬化派生子密钥，secret为HardenedSetUp.Step2的输出
+func (tssKey *Ed25519TssKey) NewHardenedChildKey(childIdx uint32, secret *curves.ECPoint) (*Ed25519TssKey, error) {
+	if childIdx < uint32(0x80000000) {
+		return nil, fmt.Errorf("childIdx is not hardened")
+	}
+	intermediary, err := calHardenedOffset(secret, tssKey.publicKey, tssKey.chaincode, childIdx)
 	if err != nil {
 		return nil, err
 	}
+	return tssKey.childKey(intermediary)
+}
+
+// childKey 使用intermediary[:32]作为偏移量，intermediary[32:]作为子链码
+func (tssKey *Ed25519TssKey) childKey(intermediary []byte) (*Ed25519TssKey, error) {
+	curve := tssKey.publicKey.Curve
 
 	// 验证Ed25519密钥
-	err = validateEd25519PrivateKey(intermediary[:32])
+	err := validateEd25519PrivateKey(intermediary[:32])
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +124,7 @@ func (tssKey *Ed25519TssKey) NewChildKey(childIdx uint32) (*Ed25519TssKey, error
 		publicKey:    ecPoint,
 		chaincode:    intermediary[32:],
 		offsetSonPri: offsetSonPri,
+		scheme:       tssKey.scheme,
 	}
 	return tss, nil
 }
@@ -0,0 +1,282 @@
+package bip32
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/schnorr"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+var hardenedLabel = []byte("Hardened key share derivation:\n")
+
+// HardenedSetUp joint hardened derivation, one round among t participants.
+//
+// Standard BIP32 hardened derivation HMAC-SHA512(chaincode, 0x00 | x | childIdx) needs the private key in the clear,
+// instead each participant i sends Di = xi*H with a dleq proof against its share publicKey, H is hashed to the curve
+// from publicKey | chaincode | childIdx, so D = sum(λi*Di) = x*H is only known to the key holders.
+// The child offset is HMAC-SHA512(label | chaincode | D | publicKey | childIdx), like hardened derivation the child key
+// can't be computed from the parent publicKey and chaincode, but it differs from the BIP32 hardened child.
+type HardenedSetUp struct {
+	DeviceNumber int
+	RoundNumber  int
+
+	sessionId      *big.Int
+	participants   []int
+	group          group.Group
+	sharePubKeyMap map[int]group.Point // share publicKey of the parent key
+	shareI         *big.Int
+	publicKey      *curves.ECPoint
+	chaincode      []byte
+	childIdx       uint32
+	point          group.Point // H
+
+	codec codec.Codec // outgoing round payloads
+}
+
+type HardenedStep1Data struct {
+	D     *curves.ECPoint // xi*H
+	Proof *schnorr.DleqProof
+}
+
+// newHardenedSetUp shareI is the parent key share, sharePubKeyMap comes from dkg, offset is the accumulated offset of the parent key
+func newHardenedSetUp(sessionId *big.Int, deviceNumber int, participants []int, sharePubKeyMap map[int]*curves.ECPoint,
+	shareI, offset *big.Int, publicKey *curves.ECPoint, chaincode []byte, childIdx uint32) (*HardenedSetUp, error) {
+	if childIdx < uint32(0x80000000) {
+		return nil, fmt.Errorf("childIdx is not hardened")
+	}
+	if sessionId == nil || len(participants) < 2 || sharePubKeyMap == nil || sharePubKeyMap[deviceNumber] == nil {
+		return nil, fmt.Errorf("parameter error")
+	}
+	curve := publicKey.Curve
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := g.PointFromAffine(publicKey.X, publicKey.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	// share publicKeys move with the accumulated offset, like the key shares did
+	offsetG := g.Generator().ScalarMult(g.ScalarFromBigInt(offset))
+	pubKeyMap := make(map[int]group.Point, len(sharePubKeyMap))
+	for id, X := range sharePubKeyMap {
+		if X == nil {
+			continue
+		}
+		point, err := g.PointFromAffine(X.X, X.Y)
+		if err != nil {
+			return nil, fmt.Errorf("share publicKey of %d: %w", id, err)
+		}
+		pubKeyMap[id] = point.Add(offsetG)
+	}
+
+	ids := append([]int{}, participants...)
+	sort.Ints(ids)
+	for i, id := range ids {
+		if pubKeyMap[id] == nil || (i > 0 && ids[i-1] == id) {
+			return nil, fmt.Errorf("participants error")
+		}
+	}
+	// fewer than t participants can't interpolate the publicKey
+	if !lagrangeSum(g, ids, pubKeyMap).Equal(pub) {
+		return nil, fmt.Errorf("participants are fewer than threshold")
+	}
+
+	// H = hash to the group of label | publicKey | chaincode | childIdx
+	var domain []byte
+	domain = append(domain, hardenedLabel...)
+	domain = append(domain, pub.Bytes()...)
+	domain = append(domain, chaincode...)
+	domain = append(domain, uint32Bytes(childIdx)...)
+	// key files of older versions hold shares not reduced modulo the order
+	if shareI != nil && shareI.Cmp(curve.Params().N) >= 0 {
+		shareI = new(big.Int).Mod(shareI, curve.Params().N)
+	}
+	return &HardenedSetUp{
+		DeviceNumber:   deviceNumber,
+		RoundNumber:    1,
+		sessionId:      sessionId,
+		participants:   ids,
+		group:          g,
+		sharePubKeyMap: pubKeyMap,
+		shareI:         shareI,
+		publicKey:      publicKey,
+		chaincode:      chaincode,
+		childIdx:       childIdx,
+		point:          group.HashToPoint(g, domain),
+		codec:          codec.JSON,
+	}, nil
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (setUp *HardenedSetUp) SetCodec(c codec.Codec) *HardenedSetUp {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
+	}
+	setUp.codec = c
+	return setUp
+}
+
+// isParticipant parties outside participants only receive Di
+func (setUp *HardenedSetUp) isParticipant(id int) bool {
+	for _, participant := range setUp.participants {
+		if participant == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Step1 participants send Di to every other party, parties outside participants send nothing
+func (setUp *HardenedSetUp) Step1() (map[int]*tss.Message, error) {
+	if setUp.RoundNumber != 1 {
+		return nil, fmt.Errorf("round error")
+	}
+	setUp.RoundNumber = 2
+	if !setUp.isParticipant(setUp.DeviceNumber) {
+		return nil, nil
+	}
+	if setUp.shareI == nil {
+		return nil, fmt.Errorf("key share is required")
+	}
+	X, err := setUp.sharePubKeyMap[setUp.DeviceNumber].ECPoint()
+	if err != nil {
+		return nil, err
+	}
+	H, err := setUp.point.ECPoint()
+	if err != nil {
+		return nil, err
+	}
+	D, err := setUp.point.ScalarMult(setUp.group.ScalarFromBigInt(setUp.shareI)).ECPoint()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[int]*tss.Message, len(setUp.sharePubKeyMap)-1)
+	for id := range setUp.sharePubKeyMap {
+		if id == setUp.DeviceNumber {
+			continue
+		}
+		proof, err := schnorr.ProveDleqWithId(tss.BindId(setUp.sessionId, setUp.DeviceNumber, id), setUp.shareI, X, H, D)
+		if err != nil {
+			return nil, err
+		}
+		bytes, err := setUp.codec.Marshal(HardenedStep1Data{D: D, Proof: proof})
+		if err != nil {
+			return nil, err
+		}
+		out[id] = &tss.Message{
+			From: setUp.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	return out, nil
+}
+
+// Step2 receive Di from the other participants, return D = x*H for NewHardenedChildKey
+func (setUp *HardenedSetUp) Step2(msgs []*tss.Message) (*curves.ECPoint, error) {
+	if setUp.RoundNumber != 2 {
+		return nil, fmt.Errorf("round error")
+	}
+	expected := len(setUp.participants)
+	if setUp.isParticipant(setUp.DeviceNumber) {
+		expected--
+	}
+	if len(msgs) != expected {
+		return nil, fmt.Errorf("messages number error")
+	}
+
+	H, err := setUp.point.ECPoint()
+	if err != nil {
+		return nil, err
+	}
+	dMap := make(map[int]group.Point, len(setUp.participants))
+	if setUp.isParticipant(setUp.DeviceNumber) {
+		dMap[setUp.DeviceNumber] = setUp.point.ScalarMult(setUp.group.ScalarFromBigInt(setUp.shareI))
+	}
+	for _, msg := range msgs {
+		if msg.To != setUp.DeviceNumber {
+			return nil, fmt.Errorf("message sending error")
+		}
+		if !setUp.isParticipant(msg.From) || dMap[msg.From] != nil {
+			return nil, tss.NewBlameError(msg, 2, "unexpected sender", nil)
+		}
+		var content HardenedStep1Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid message", err)
+		}
+		if content.D == nil || content.Proof == nil || content.Proof.R1 == nil || content.Proof.R2 == nil {
+			return nil, tss.NewBlameError(msg, 2, "incomplete message", nil)
+		}
+		curve := setUp.publicKey.Curve
+		content.D.SetCurve(curve)
+		content.Proof.R1.SetCurve(curve)
+		content.Proof.R2.SetCurve(curve)
+		D, err := setUp.group.PointFromAffine(content.D.X, content.D.Y)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid D", err)
+		}
+		X, err := setUp.sharePubKeyMap[msg.From].ECPoint()
+		if err != nil {
+			return nil, err
+		}
+		if !schnorr.VerifyDleqWithId(tss.BindId(setUp.sessionId, msg.From, setUp.DeviceNumber), content.Proof, X, H, content.D) {
+			return nil, tss.NewBlameError(msg, 2, "dleq proof verify fail", nil)
+		}
+		dMap[msg.From] = D
+	}
+	setUp.RoundNumber = 3
+	return lagrangeSum(setUp.group, setUp.participants, dMap).ECPoint()
+}
+
+// lagrangeSum sum(λi*Pi) over ids
+func lagrangeSum(g group.Group, ids []int, points map[int]group.Point) group.Point {
+	xList := make([]*big.Int, len(ids))
+	for i, id := range ids {
+		xList[i] = big.NewInt(int64(id))
+	}
+	sum := g.Identity()
+	for i, id := range ids {
+		lambda := vss.CalLagrangian(g.Curve(), xList[i], big.NewInt(1), xList)
+		sum = sum.Add(points[id].ScalarMult(g.ScalarFromBigInt(lambda)))
+	}
+	return sum
+}
+
+// calHardenedOffset HMAC-SHA512(label | chaincode | D | publicKey | childIdx)
+func calHardenedOffset(secret, publicKey *curves.ECPoint, chaincode []byte, childIdx uint32) ([]byte, error) {
+	if secret == nil || !secret.IsOnCurve() || curves.GetCurveName(secret.Curve) != curves.GetCurveName(publicKey.Curve) {
+		return nil, fmt.Errorf("invalid hardened secret")
+	}
+	hash := hmac.New(sha512.New, hardenedLabel)
+	var data []byte
+	data = append(data, chaincode...)
+	data = append(data, serializePoint(secret)...)
+	data = append(data, serializePoint(publicKey)...)
+	data = append(data, uint32Bytes(childIdx)...)
+	_, err := hash.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+func serializePoint(point *curves.ECPoint) []byte {
+	if curves.GetCurveName(point.Curve) == curves.Ed25519 {
+		return (&edwards.PublicKey{Curve: point.Curve, X: point.X, Y: point.Y}).SerializeCompressed()
+	}
+	return (&secp256k1.PublicKey{Curve: point.Curve, X: point.X, Y: point.Y}).SerializeCompressed()
+}
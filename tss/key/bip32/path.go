@@ -0,0 +1,64 @@
+package bip32
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const hardenedOffset = uint32(0x80000000)
+
+// ParsePath parse a derivation path like m/44'/0/1, hardened indexes are marked with ' or h
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("path must start with m: %s", path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+		index, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path index %s: %v", part, err)
+		}
+		if hardened {
+			index += uint64(hardenedOffset)
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
+
+// DeriveChildKeys non-hardened derivation of every index in path
+func (tssKey *TssKey) DeriveChildKeys(path []uint32) (*TssKey, error) {
+	current := tssKey
+	var err error
+	for _, childIdx := range path {
+		current, err = current.NewChildKey(childIdx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive child key at index %d: %v", childIdx, err)
+		}
+	}
+	return current, nil
+}
+
+// DerivePath non-hardened derivation of a path like m/0/1
+func (tssKey *TssKey) DerivePath(path string) (*TssKey, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return tssKey.DeriveChildKeys(indexes)
+}
+
+// DerivePath 按m/0/1形式的路径做非硬化派生
+func (tssKey *Ed25519TssKey) DerivePath(path string) (*Ed25519TssKey, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return tssKey.DeriveChildKeys(indexes)
+}
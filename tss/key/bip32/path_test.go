@@ -0,0 +1,44 @@
+package bip32
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	indexes, err := ParsePath("m/44'/60h/0/7")
+	require.NoError(t, err)
+	require.Equal(t, []uint32{0x8000002c, 0x8000003c, 0, 7}, indexes)
+
+	indexes, err = ParsePath("m")
+	require.NoError(t, err)
+	require.Empty(t, indexes)
+
+	for _, path := range []string{"", "0/1", "m/x", "m/2147483648", "m//1"} {
+		_, err = ParsePath(path)
+		require.Error(t, err, path)
+	}
+}
+
+func TestDerivePath(t *testing.T) {
+	curve := secp256k1.S256()
+	x := crypto.RandomNum(curve.N)
+	tssKey, err := NewTssKey(x, curves.ScalarToPoint(curve, x), hex.EncodeToString(make([]byte, 32)))
+	require.NoError(t, err)
+
+	child, err := tssKey.DerivePath("m/0/1")
+	require.NoError(t, err)
+	step, _ := tssKey.NewChildKey(0)
+	step, _ = step.NewChildKey(1)
+	require.True(t, step.PublicKey().Equals(child.PublicKey()))
+	require.Equal(t, step.ShareI(), child.ShareI())
+
+	// hardened indexes need NewHardenedSetUp
+	_, err = tssKey.DerivePath("m/0'")
+	require.Error(t, err)
+}
@@ -15,16 +15,31 @@ import (
 
 var label = []byte("Key share derivation:\n")
 
+// Scheme non-hardened derivation of TssKey
+type Scheme int
+
+const (
+	// Legacy HMAC-SHA512(label | chaincode | publicKey.X | childIdx), keeps the children of existing keys
+	Legacy Scheme = iota
+	// Standard BIP32 CKDpub HMAC-SHA512(chaincode, serP(publicKey) | ser32(childIdx)), same children as xpub wallets
+	Standard
+)
+
 // support secp256k1 derived, not support ed25519
 type TssKey struct {
 	shareI       *big.Int        // key share
 	publicKey    *curves.ECPoint // publicKey
 	chaincode    []byte
 	offsetSonPri *big.Int // child private key share offset, accumulative
+	scheme       Scheme
+
+	depth             byte
+	parentFingerprint []byte
+	childNumber       uint32
 }
 
-// NewTssKey shareI is optional
-func NewTssKey(shareI *big.Int, publicKey *curves.ECPoint, chaincode string) (*TssKey, error) {
+// NewTssKey shareI is optional, scheme defaults to Legacy, Standard needs a 32 bytes chaincode
+func NewTssKey(shareI *big.Int, publicKey *curves.ECPoint, chaincode string, scheme ...Scheme) (*TssKey, error) {
 	chainBytes, err := hex.DecodeString(chaincode)
 	if err != nil {
 		return nil, err
@@ -32,28 +47,72 @@ func NewTssKey(shareI *big.Int, publicKey *curves.ECPoint, chaincode string) (*T
 	if publicKey == nil || chaincode == "" {
 		return nil, fmt.Errorf("parameter error")
 	}
+	s := Legacy
+	if len(scheme) > 0 {
+		s = scheme[0]
+	}
+	if s != Legacy && s != Standard {
+		return nil, fmt.Errorf("unknown derivation scheme %d", s)
+	}
+	if s == Standard && len(chainBytes) != 32 {
+		return nil, fmt.Errorf("chaincode must be 32 bytes")
+	}
 	tssKey := &TssKey{
 		shareI:       shareI,
 		publicKey:    publicKey,
 		chaincode:    chainBytes,
 		offsetSonPri: big.NewInt(0),
+		scheme:       s,
+
+		parentFingerprint: make([]byte, 4),
 	}
 	return tssKey, nil
 }
 
-// NewChildKey like bip32 non-hardened derivation
+// NewChildKey non-hardened derivation of the key scheme, BIP32 CKDpub for Standard
 func (tssKey *TssKey) NewChildKey(childIdx uint32) (*TssKey, error) {
 	if childIdx >= uint32(0x80000000) { // 2^31
-		return nil, fmt.Errorf("hardened derivation is unsupported")
+		return nil, fmt.Errorf("hardened derivation is unsupported by NewChildKey, see NewHardenedSetUp")
 	}
-	curve := tssKey.publicKey.Curve
-	intermediary, err := calPrivateOffset(tssKey.publicKey.X.Bytes(), tssKey.chaincode, childIdx)
+	var intermediary []byte
+	var err error
+	if tssKey.scheme == Standard {
+		intermediary, err = calBip32Offset(compressed(tssKey.publicKey), tssKey.chaincode, childIdx)
+	} else {
+		intermediary, err = calPrivateOffset(tssKey.publicKey.X.Bytes(), tssKey.chaincode, childIdx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tssKey.childKey(intermediary, childIdx)
+}
+
+// NewHardenedSetUp start hardened derivation of childIdx >= 2^31, sharePubKeyMap is the dkg output of the root key,
+// at least t participants, parties outside participants only receive messages
+func (tssKey *TssKey) NewHardenedSetUp(sessionId *big.Int, deviceNumber int, participants []int, sharePubKeyMap map[int]*curves.ECPoint, childIdx uint32) (*HardenedSetUp, error) {
+	return newHardenedSetUp(sessionId, deviceNumber, participants, sharePubKeyMap, tssKey.shareI, tssKey.offsetSonPri, tssKey.publicKey, tssKey.chaincode, childIdx)
+}
+
+// NewHardenedChildKey hardened child of childIdx, secret is the output of HardenedSetUp.Step2
+func (tssKey *TssKey) NewHardenedChildKey(childIdx uint32, secret *curves.ECPoint) (*TssKey, error) {
+	if childIdx < uint32(0x80000000) {
+		return nil, fmt.Errorf("childIdx is not hardened")
+	}
+	intermediary, err := calHardenedOffset(secret, tssKey.publicKey, tssKey.chaincode, childIdx)
 	if err != nil {
 		return nil, err
 	}
+	return tssKey.childKey(intermediary, childIdx)
+}
 
+// childKey apply offset intermediary[:32], intermediary[32:] is the child chaincode
+func (tssKey *TssKey) childKey(intermediary []byte, childIdx uint32) (*TssKey, error) {
+	curve := tssKey.publicKey.Curve
+	if tssKey.depth == maxDepth {
+		return nil, fmt.Errorf("derivation depth exceeds %d", maxDepth)
+	}
 	// Validate key
-	err = validatePrivateKey(intermediary[:32])
+	err := validatePrivateKey(intermediary[:32])
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +135,11 @@ func (tssKey *TssKey) NewChildKey(childIdx uint32) (*TssKey, error) {
 		publicKey:    ecPoint,
 		chaincode:    intermediary[32:],
 		offsetSonPri: offsetSonPri,
+		scheme:       tssKey.scheme,
+
+		depth:             tssKey.depth + 1,
+		parentFingerprint: tssKey.fingerprint(),
+		childNumber:       childIdx,
 	}
 	return tss, nil
 }
@@ -95,6 +159,11 @@ func (tssKey *TssKey) PublicKey() *curves.ECPoint {
 	return tssKey.publicKey
 }
 
+// Scheme non-hardened derivation scheme
+func (tssKey *TssKey) Scheme() Scheme {
+	return tssKey.scheme
+}
+
 // calPrivateOffset HMAC-SHA512(label | chaincode | publicKey | childIdx)
 func calPrivateOffset(publicKey, chaincode []byte, childIdx uint32) ([]byte, error) {
 	hash := hmac.New(sha512.New, label)
@@ -109,6 +178,20 @@ func calPrivateOffset(publicKey, chaincode []byte, childIdx uint32) ([]byte, err
 	return hash.Sum(nil), nil
 }
 
+// calBip32Offset BIP32 CKDpub I = HMAC-SHA512(chaincode, serP(publicKey) | ser32(childIdx)),
+// IL is the offset and IR the child chaincode
+func calBip32Offset(publicKey, chaincode []byte, childIdx uint32) ([]byte, error) {
+	hash := hmac.New(sha512.New, chaincode)
+	var data []byte
+	data = append(data, publicKey...)
+	data = append(data, uint32Bytes(childIdx)...)
+	_, err := hash.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
 func validatePrivateKey(key []byte) error {
 	if fmt.Sprintf("%x", key) == "0000000000000000000000000000000000000000000000000000000000000000" || //if the key is zero
 		bytes.Compare(key, secp256k1.S256().N.Bytes()) >= 0 || //or is outside of the curve
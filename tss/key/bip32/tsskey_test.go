@@ -1,14 +1,20 @@
 package bip32
 
 import (
+	"crypto/elliptic"
 	"encoding/hex"
 	"fmt"
 	"math/big"
 	"testing"
 
+	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/decred/dcrd/dcrec/secp256k1/v2"
 	"github.com/okx/threshold-lib/crypto"
 	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/key/dkg"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTssKey(t *testing.T) {
@@ -45,3 +51,130 @@ func TestTssKey_cmp(t *testing.T) {
 	fmt.Println(tssKey.publicKey)
 	fmt.Println(X_new)
 }
+
+// dkgKeys 2-of-3 dkg, index is device number
+func dkgKeys(t *testing.T, curve elliptic.Curve) []*tss.KeyStep3Data {
+	sessionId := tss.SessionId("dkgKeys")
+	setUps := make([]*dkg.SetupInfo, 4)
+	msgs1 := make([]map[int]*tss.Message, 4)
+	for i := 1; i <= 3; i++ {
+		setUps[i] = dkg.NewSetUp(sessionId, i, 2, 3, curve)
+		out, err := setUps[i].DKGStep1()
+		require.NoError(t, err)
+		msgs1[i] = out
+	}
+	collect := func(msgs []map[int]*tss.Message, to int) []*tss.Message {
+		var in []*tss.Message
+		for from := 1; from < len(msgs); from++ {
+			if msg, ok := msgs[from][to]; ok {
+				in = append(in, msg)
+			}
+		}
+		return in
+	}
+	msgs2 := make([]map[int]*tss.Message, 4)
+	for i := 1; i <= 3; i++ {
+		out, err := setUps[i].DKGStep2(collect(msgs1, i))
+		require.NoError(t, err)
+		msgs2[i] = out
+	}
+	keys := make([]*tss.KeyStep3Data, 4)
+	for i := 1; i <= 3; i++ {
+		data, err := setUps[i].DKGStep3(collect(msgs2, i))
+		require.NoError(t, err)
+		keys[i] = data
+	}
+	return keys
+}
+
+func TestHardenedChildKey(t *testing.T) {
+	keys := dkgKeys(t, secp256k1.S256())
+	sessionId := tss.SessionId("TestHardenedChildKey")
+	childIdx := uint32(0x80000000 + 44)
+	participants := []int{1, 3}
+
+	// m/0/44'
+	parents := make([]*TssKey, 4)
+	setUps := make([]*HardenedSetUp, 4)
+	msgs := make([]map[int]*tss.Message, 4)
+	for i := 1; i <= 3; i++ {
+		tssKey, err := NewTssKey(keys[i].ShareI, keys[i].PublicKey, keys[i].ChainCode)
+		require.NoError(t, err)
+		parents[i], err = tssKey.NewChildKey(0)
+		require.NoError(t, err)
+		setUps[i], err = parents[i].NewHardenedSetUp(sessionId, i, participants, keys[i].SharePubKeyMap, childIdx)
+		require.NoError(t, err)
+		msgs[i], err = setUps[i].Step1()
+		require.NoError(t, err)
+	}
+	// party 2 doesn't take part, but receives D
+	require.Nil(t, msgs[2])
+
+	children := make([]*TssKey, 4)
+	for i := 1; i <= 3; i++ {
+		var in []*tss.Message
+		for _, from := range participants {
+			if from != i {
+				in = append(in, msgs[from][i])
+			}
+		}
+		secret, err := setUps[i].Step2(in)
+		require.NoError(t, err)
+		children[i], err = parents[i].NewHardenedChildKey(childIdx, secret)
+		require.NoError(t, err)
+	}
+	require.True(t, children[1].PublicKey().Equals(children[2].PublicKey()))
+	require.True(t, children[1].PublicKey().Equals(children[3].PublicKey()))
+	require.False(t, children[1].PublicKey().Equals(parents[1].PublicKey()))
+	fmt.Println("hardened child publicKey: ", children[1].PublicKey())
+
+	// any 2 child shares recover the child private key
+	curve := secp256k1.S256()
+	x := vss.RecoverSecret(curve, []*vss.Share{
+		{Id: big.NewInt(1), Y: children[1].ShareI()},
+		{Id: big.NewInt(2), Y: children[2].ShareI()},
+	})
+	require.True(t, curves.ScalarToPoint(curve, x).Equals(children[1].PublicKey()))
+
+	// the public key alone can't derive hardened children
+	_, err := parents[1].NewChildKey(childIdx)
+	require.Error(t, err)
+
+	// one participant is fewer than threshold
+	_, err = parents[1].NewHardenedSetUp(sessionId, 1, []int{1}, keys[1].SharePubKeyMap, childIdx)
+	require.Error(t, err)
+}
+
+func TestHardenedChildKeyBlame(t *testing.T) {
+	keys := dkgKeys(t, edwards.Edwards())
+	sessionId := tss.SessionId("TestHardenedChildKeyBlame")
+	childIdx := uint32(0x80000000)
+
+	setUps := make([]*HardenedSetUp, 4)
+	msgs := make([]map[int]*tss.Message, 4)
+	for i := 1; i <= 2; i++ {
+		tssKey, err := NewEd25519TssKey(keys[i].ShareI, keys[i].PublicKey, keys[i].ChainCode)
+		require.NoError(t, err)
+		setUps[i], err = tssKey.NewHardenedSetUp(sessionId, i, []int{1, 2}, keys[i].SharePubKeyMap, childIdx)
+		require.NoError(t, err)
+		msgs[i], err = setUps[i].Step1()
+		require.NoError(t, err)
+	}
+	secret, err := setUps[1].Step2([]*tss.Message{msgs[2][1]})
+	require.NoError(t, err)
+	require.True(t, secret.IsOnCurve())
+
+	// party 2 replays the message sent to party 1 into party 3
+	tssKey, err := NewEd25519TssKey(keys[3].ShareI, keys[3].PublicKey, keys[3].ChainCode)
+	require.NoError(t, err)
+	setUp3, err := tssKey.NewHardenedSetUp(sessionId, 3, []int{1, 2}, keys[3].SharePubKeyMap, childIdx)
+	require.NoError(t, err)
+	_, err = setUp3.Step1()
+	require.NoError(t, err)
+	replay := &tss.Message{From: 2, To: 3, Data: msgs[2][1].Data}
+	_, err = setUp3.Step2([]*tss.Message{msgs[1][3], replay})
+	fmt.Println(err)
+	culprit, ok := tss.Culprit(err)
+	require.True(t, ok)
+	require.Equal(t, 2, culprit)
+}
@@ -0,0 +1,120 @@
+package bip32
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/base58"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// BIP32 extended public key version bytes
+var (
+	XpubVersion = []byte{0x04, 0x88, 0xb2, 0x1e}
+	TpubVersion = []byte{0x04, 0x35, 0x87, 0xcf}
+)
+
+const (
+	maxDepth   = 255
+	xpubLength = 78
+)
+
+// Xpub BIP32 extended public key: version || depth || parent fingerprint || child number || chaincode || publicKey,
+// version defaults to XpubVersion. Only Standard keys have an xpub, their non-hardened children are BIP32 CKDpub
+// and any watch-only wallet derives the same addresses, Legacy children differ so a Legacy key is refused
+func (tssKey *TssKey) Xpub(version ...[]byte) (string, error) {
+	if tssKey.scheme != Standard {
+		return "", fmt.Errorf("xpub needs the Standard derivation scheme")
+	}
+	v := XpubVersion
+	if len(version) > 0 {
+		v = version[0]
+	}
+	if len(v) != 4 {
+		return "", fmt.Errorf("version must be 4 bytes")
+	}
+	if len(tssKey.chaincode) != 32 {
+		return "", fmt.Errorf("chaincode must be 32 bytes")
+	}
+	data := make([]byte, 0, xpubLength)
+	data = append(data, v...)
+	data = append(data, tssKey.depth)
+	data = append(data, tssKey.parentFingerprint...)
+	data = append(data, uint32Bytes(tssKey.childNumber)...)
+	data = append(data, tssKey.chaincode...)
+	data = append(data, compressed(tssKey.publicKey)...)
+	return base58.CheckEncode(data), nil
+}
+
+// NewTssKeyFromXpub watch-only Standard TssKey without key share, only non-hardened children can be derived
+func NewTssKeyFromXpub(xpub string) (*TssKey, error) {
+	data, err := base58.CheckDecode(xpub)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != xpubLength {
+		return nil, fmt.Errorf("xpub must be %d bytes", xpubLength)
+	}
+	if !bytes.Equal(data[:4], XpubVersion) && !bytes.Equal(data[:4], TpubVersion) {
+		return nil, fmt.Errorf("unknown xpub version %x", data[:4])
+	}
+	depth := data[4]
+	parentFingerprint := data[5:9]
+	childNumber := binary.BigEndian.Uint32(data[9:13])
+	if depth == 0 && (!bytes.Equal(parentFingerprint, make([]byte, 4)) || childNumber != 0) {
+		return nil, fmt.Errorf("invalid master xpub")
+	}
+	pubKey := data[45:78]
+	if pubKey[0] != 0x02 && pubKey[0] != 0x03 {
+		return nil, fmt.Errorf("invalid xpub publicKey")
+	}
+	publicKey, err := curves.EcdsaPubKeyToPoint(hex.EncodeToString(pubKey))
+	if err != nil {
+		return nil, err
+	}
+	tssKey, err := NewTssKey(nil, publicKey, hex.EncodeToString(data[13:45]), Standard)
+	if err != nil {
+		return nil, err
+	}
+	tssKey.depth = depth
+	tssKey.parentFingerprint = append([]byte{}, parentFingerprint...)
+	tssKey.childNumber = childNumber
+	return tssKey, nil
+}
+
+// Depth number of derivations from the dkg key
+func (tssKey *TssKey) Depth() byte {
+	return tssKey.depth
+}
+
+// ChildNumber index of this key in its parent, 0 for the dkg key
+func (tssKey *TssKey) ChildNumber() uint32 {
+	return tssKey.childNumber
+}
+
+// ParentFingerprint first 4 bytes of the parent hash160, zero for the dkg key
+func (tssKey *TssKey) ParentFingerprint() []byte {
+	return tssKey.parentFingerprint
+}
+
+// Chaincode child chaincode
+func (tssKey *TssKey) Chaincode() []byte {
+	return tssKey.chaincode
+}
+
+// fingerprint first 4 bytes of hash160(compressed publicKey)
+func (tssKey *TssKey) fingerprint() []byte {
+	digest := sha256.Sum256(compressed(tssKey.publicKey))
+	h := ripemd160.New()
+	h.Write(digest[:])
+	return h.Sum(nil)[:4]
+}
+
+func compressed(publicKey *curves.ECPoint) []byte {
+	return secp256k1.NewPublicKey(publicKey.X, publicKey.Y).SerializeCompressed()
+}
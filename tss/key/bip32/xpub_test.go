@@ -0,0 +1,151 @@
+package bip32
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/base58"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXpub(t *testing.T) {
+	// BIP32 test vector 1, master key m
+	publicKey, err := curves.EcdsaPubKeyToPoint("0339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2")
+	require.NoError(t, err)
+	tssKey, err := NewTssKey(nil, publicKey, "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508", Standard)
+	require.NoError(t, err)
+	xpub, err := tssKey.Xpub()
+	require.NoError(t, err)
+	require.Equal(t, "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8", xpub)
+
+	watchOnly, err := NewTssKeyFromXpub(xpub)
+	require.NoError(t, err)
+	require.True(t, watchOnly.PublicKey().Equals(publicKey))
+	require.Nil(t, watchOnly.ShareI())
+
+	tpub, err := tssKey.Xpub(TpubVersion)
+	require.NoError(t, err)
+	require.Equal(t, "tpub", tpub[:4])
+
+	// legacy children differ from CKDpub, a legacy key has no xpub
+	legacy, err := NewTssKey(nil, publicKey, "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508")
+	require.NoError(t, err)
+	_, err = legacy.Xpub()
+	require.Error(t, err)
+	_, err = NewTssKey(nil, publicKey, "873dff81", Standard)
+	require.Error(t, err)
+}
+
+// BIP32 test vector 1, non-hardened steps derived from the published parent xpub
+func TestXpubVector1(t *testing.T) {
+	for _, v := range []struct {
+		parent   string
+		childIdx uint32
+		child    string
+	}{
+		// m/0H -> m/0H/1
+		{"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw", 1,
+			"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ"},
+		// m/0H/1/2H -> m/0H/1/2H/2
+		{"xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5", 2,
+			"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV"},
+		// m/0H/1/2H/2 -> m/0H/1/2H/2/1000000000
+		{"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV", 1000000000,
+			"xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy"},
+	} {
+		parent, err := NewTssKeyFromXpub(v.parent)
+		require.NoError(t, err)
+		child, err := parent.NewChildKey(v.childIdx)
+		require.NoError(t, err)
+		xpub, err := child.Xpub()
+		require.NoError(t, err)
+		require.Equal(t, v.child, xpub)
+	}
+}
+
+// ckdPriv BIP32 CKDpriv of a non-hardened child, k and chaincode of the parent
+func ckdPriv(k *big.Int, chaincode []byte, childIdx uint32) (*big.Int, []byte) {
+	publicKey := curves.ScalarToPoint(secp256k1.S256(), k)
+	mac := hmac.New(sha512.New, chaincode)
+	mac.Write(compressed(publicKey))
+	mac.Write(uint32Bytes(childIdx))
+	I := mac.Sum(nil)
+	child := new(big.Int).Add(k, new(big.Int).SetBytes(I[:32]))
+	return child.Mod(child, secp256k1.S256().N), I[32:]
+}
+
+// BIP32 test vector 1 master xprv, m/0 and m/0/1 of the key holder and the watch-only key match CKDpriv
+func TestXpubVector1Child(t *testing.T) {
+	data, err := base58.CheckDecode("xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi")
+	require.NoError(t, err)
+	k, chaincode := new(big.Int).SetBytes(data[46:78]), data[13:45]
+	publicKey := curves.ScalarToPoint(secp256k1.S256(), k)
+	tssKey, err := NewTssKey(k, publicKey, hex.EncodeToString(chaincode), Standard)
+	require.NoError(t, err)
+	watchOnly, err := NewTssKeyFromXpub("xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8")
+	require.NoError(t, err)
+
+	for _, childIdx := range []uint32{0, 1} {
+		k, chaincode = ckdPriv(k, chaincode, childIdx)
+		tssKey, err = tssKey.NewChildKey(childIdx)
+		require.NoError(t, err)
+		watchOnly, err = watchOnly.NewChildKey(childIdx)
+		require.NoError(t, err)
+
+		require.Equal(t, 0, k.Cmp(tssKey.ShareI()))
+		require.True(t, curves.ScalarToPoint(secp256k1.S256(), k).Equals(watchOnly.PublicKey()))
+		require.Equal(t, chaincode, watchOnly.Chaincode())
+		xpub, err := tssKey.Xpub()
+		require.NoError(t, err)
+		watchXpub, err := watchOnly.Xpub()
+		require.NoError(t, err)
+		require.Equal(t, xpub, watchXpub)
+	}
+}
+
+func TestXpubChild(t *testing.T) {
+	keys := dkgKeys(t, secp256k1.S256())
+	tssKey, err := NewTssKey(keys[1].ShareI, keys[1].PublicKey, keys[1].ChainCode, Standard)
+	require.NoError(t, err)
+	xpub, err := tssKey.Xpub()
+	require.NoError(t, err)
+	watchOnly, err := NewTssKeyFromXpub(xpub)
+	require.NoError(t, err)
+
+	// watch-only derivation matches the share holder
+	child, err := tssKey.NewChildKey(44)
+	require.NoError(t, err)
+	child, err = child.NewChildKey(1)
+	require.NoError(t, err)
+	watchChild, err := watchOnly.NewChildKey(44)
+	require.NoError(t, err)
+	watchChild, err = watchChild.NewChildKey(1)
+	require.NoError(t, err)
+	require.True(t, child.PublicKey().Equals(watchChild.PublicKey()))
+
+	childXpub, err := child.Xpub()
+	require.NoError(t, err)
+	watchXpub, err := watchChild.Xpub()
+	require.NoError(t, err)
+	require.Equal(t, childXpub, watchXpub)
+
+	parsed, err := NewTssKeyFromXpub(childXpub)
+	require.NoError(t, err)
+	require.Equal(t, byte(2), parsed.Depth())
+	require.Equal(t, uint32(1), parsed.ChildNumber())
+	require.Equal(t, watchChild.ParentFingerprint(), parsed.ParentFingerprint())
+	require.Equal(t, child.Chaincode(), parsed.Chaincode())
+
+	// a bad checksum and a private key version are rejected
+	_, err = NewTssKeyFromXpub(childXpub[:len(childXpub)-1] + "1")
+	require.Error(t, err)
+	xprv, err := child.Xpub([]byte{0x04, 0x88, 0xad, 0xe4})
+	require.NoError(t, err)
+	_, err = NewTssKeyFromXpub(xprv)
+	require.Error(t, err)
+}
@@ -0,0 +1,240 @@
+package dealer
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+	"github.com/okx/threshold-lib/tss/key/dkg"
+	"github.com/okx/threshold-lib/tss/key/reshare"
+)
+
+// ImportData output of the trusted dealer, Keys[id] goes privately to participant id, Verifiers are public
+type ImportData struct {
+	Keys      map[int]*DealtKey
+	Verifiers []*curves.ECPoint // feldman verifiers, Verifiers[0] is the public key
+}
+
+// DealtKey key share dealt to participant id, the dealer knows it, so it is no tss.KeyStep3Data,
+// the only way to a usable key share is DealtKey.Refresh run by all participants
+type DealtKey struct {
+	key *tss.KeyStep3Data
+}
+
+// dealtKeyJSON json form of a DealtKey, unlike a key file so it can't be loaded as one
+type dealtKeyJSON struct {
+	Dealt *tss.KeyStep3Data `json:"dealt"`
+}
+
+// Refresh mandatory refresh of an imported key, same rounds as reshare.RefreshInfo,
+// DKGStep3 outputs the key share with the chaincode of the import
+type Refresh struct {
+	*reshare.RefreshInfo
+	chainCode string
+}
+
+// Import split an existing private key into threshold-of-total key shares for participants 1..total,
+// chainCode is 32 bytes, a random one is generated if it is nil.
+// the dealer sees the key and every share, the dealt keys are only usable after the refresh among all participants,
+// so they are useless to anyone who saw them, the original key must still be destroyed by its owner
+func Import(curve elliptic.Curve, privateKey *big.Int, threshold, total int, chainCode []byte) (*ImportData, error) {
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+	if privateKey == nil {
+		return nil, fmt.Errorf("invalid private key")
+	}
+	x, err := g.ScalarFromSecret(privateKey)
+	if err != nil || x.IsZero() {
+		return nil, fmt.Errorf("invalid private key")
+	}
+	if chainCode == nil {
+		chainCode = make([]byte, 32)
+		if _, err := rand.Read(chainCode); err != nil {
+			return nil, err
+		}
+	}
+	if len(chainCode) != 32 {
+		return nil, fmt.Errorf("chaincode must be 32 bytes")
+	}
+	feldman, err := vss.NewFeldman(threshold, total, curve)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, total)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	verifiers, shares, err := feldman.Deal(x, ids)
+	if err != nil {
+		return nil, err
+	}
+	sharePubKeyMap, err := dkg.SharePubKeys(verifiers, ids)
+	if err != nil {
+		return nil, err
+	}
+	ecVerifiers := make([]*curves.ECPoint, len(verifiers))
+	for i, v := range verifiers {
+		ecVerifiers[i], err = v.ECPoint()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data := &ImportData{
+		Keys:      make(map[int]*DealtKey, total),
+		Verifiers: ecVerifiers,
+	}
+	for i, id := range ids {
+		data.Keys[id] = &DealtKey{key: &tss.KeyStep3Data{
+			Id:             id,
+			ShareI:         shares[i].Y,
+			PublicKey:      ecVerifiers[0],
+			ChainCode:      hex.EncodeToString(chainCode),
+			SharePubKeyMap: sharePubKeyMap,
+		}}
+	}
+	return data, nil
+}
+
+// Id participant id of the dealt key
+func (key *DealtKey) Id() int {
+	return key.key.Id
+}
+
+// PublicKey public key of the imported key
+func (key *DealtKey) PublicKey() *curves.ECPoint {
+	return key.key.PublicKey
+}
+
+func (key *DealtKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&dealtKeyJSON{Dealt: key.key})
+}
+
+func (key *DealtKey) UnmarshalJSON(data []byte) error {
+	var raw dealtKeyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Dealt == nil {
+		return fmt.Errorf("not a dealt key")
+	}
+	key.key = raw.Dealt
+	return nil
+}
+
+// Verify participant checks the dealt key against the feldman verifiers,
+// publicKey is the expected public key of the imported key, e.g. from its address
+func (key *DealtKey) Verify(verifiers []*curves.ECPoint, publicKey *curves.ECPoint) error {
+	if key == nil || key.key == nil {
+		return fmt.Errorf("Verify params error")
+	}
+	return verifyKey(key.key, verifiers, publicKey)
+}
+
+// Refresh verify the dealt key and start the refresh among all participants 1..total, every one of them contributes,
+// so the new shares are unknown to the dealer, run DKGStep1-3 of the returned Refresh
+func (key *DealtKey) Refresh(sessionId *big.Int, verifiers []*curves.ECPoint, publicKey *curves.ECPoint) (*Refresh, error) {
+	if err := key.Verify(verifiers, publicKey); err != nil {
+		return nil, err
+	}
+	total := len(key.key.SharePubKeyMap)
+	devoteList := make([]int, total)
+	for i := range devoteList {
+		devoteList[i] = i + 1
+		if _, ok := key.key.SharePubKeyMap[i+1]; !ok {
+			return nil, fmt.Errorf("share public key of %d not found", i+1)
+		}
+	}
+	info := reshare.NewRefresh(sessionId, key.key.Id, len(verifiers), total, devoteList, key.key.ShareI, key.key.PublicKey)
+	return &Refresh{RefreshInfo: info, chainCode: key.key.ChainCode}, nil
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, see reshare.RefreshInfo.SetCodec
+func (r *Refresh) SetCodec(c codec.Codec) *Refresh {
+	r.RefreshInfo.SetCodec(c)
+	return r
+}
+
+// DKGStep3 return the refreshed key share, the chaincode of the import doesn't change with refresh
+func (r *Refresh) DKGStep3(msgs []*tss.Message) (*tss.KeyStep3Data, error) {
+	data, err := r.RefreshInfo.DKGStep3(msgs)
+	if err != nil {
+		return nil, err
+	}
+	data.ChainCode = r.chainCode
+	return data, nil
+}
+
+// verifyKey check the dealt key against the feldman verifiers and the expected public key
+func verifyKey(key *tss.KeyStep3Data, verifiers []*curves.ECPoint, publicKey *curves.ECPoint) error {
+	if key.ShareI == nil || key.PublicKey == nil || len(verifiers) < 2 || publicKey == nil {
+		return fmt.Errorf("Verify params error")
+	}
+	if !key.PublicKey.Equals(publicKey) || !verifiers[0].Equals(publicKey) {
+		return fmt.Errorf("public key mismatch")
+	}
+	feldman, err := vss.NewFeldman(len(verifiers), len(key.SharePubKeyMap), publicKey.Curve)
+	if err != nil {
+		return err
+	}
+	ok, err := feldman.Verify(&vss.Share{Id: big.NewInt(int64(key.Id)), Y: key.ShareI}, verifiers)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("feldman verify fail")
+	}
+	// every share public key lies on the committed polynomial
+	g, _ := group.FromCurve(publicKey.Curve)
+	points := make([]group.Point, len(verifiers))
+	for i, v := range verifiers {
+		points[i], err = g.PointFromAffine(v.X, v.Y)
+		if err != nil {
+			return err
+		}
+	}
+	ids := make([]int, 0, len(key.SharePubKeyMap))
+	for id := range key.SharePubKeyMap {
+		ids = append(ids, id)
+	}
+	expected, err := dkg.SharePubKeys(points, ids)
+	if err != nil {
+		return err
+	}
+	for id, X := range key.SharePubKeyMap {
+		if !expected[id].Equals(X) {
+			return fmt.Errorf("share public key of %d mismatch", id)
+		}
+	}
+	if _, ok := key.SharePubKeyMap[key.Id]; !ok {
+		return fmt.Errorf("share public key of %d not found", key.Id)
+	}
+	return nil
+}
+
+// Ed25519PrivateKey secret scalar of a RFC 8032 private key seed, clamped sha512(seed)[:32] little-endian mod l
+func Ed25519PrivateKey(seed []byte) (*big.Int, error) {
+	if len(seed) != 32 {
+		return nil, fmt.Errorf("ed25519 seed must be 32 bytes")
+	}
+	h := sha512.Sum512(seed)
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	scalar := make([]byte, 32)
+	for i := range scalar {
+		scalar[i] = h[31-i]
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(scalar), group.Ed25519().Order()), nil
+}
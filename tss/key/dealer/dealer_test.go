@@ -0,0 +1,149 @@
+package dealer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/stretchr/testify/require"
+)
+
+// refresh the mandatory refresh of the dealt keys, all participants contribute, keys indexed by id
+func refresh(t *testing.T, data *ImportData, publicKey *curves.ECPoint) map[int]*tss.KeyStep3Data {
+	sessionId := tss.SessionId("refresh imported key")
+	total := len(data.Keys)
+	infos := make(map[int]*Refresh, total)
+	for id, key := range data.Keys {
+		info, err := key.Refresh(sessionId, data.Verifiers, publicKey)
+		require.NoError(t, err)
+		infos[id] = info
+	}
+	msgs := make(map[int]map[int]*tss.Message, total)
+	for id, info := range infos {
+		out, err := info.DKGStep1()
+		require.NoError(t, err)
+		msgs[id] = out
+	}
+	next := make(map[int]map[int]*tss.Message, total)
+	for id, info := range infos {
+		out, err := info.DKGStep2(collect(msgs, id))
+		require.NoError(t, err)
+		next[id] = out
+	}
+	refreshed := make(map[int]*tss.KeyStep3Data, total)
+	for id, info := range infos {
+		key, err := info.DKGStep3(collect(next, id))
+		require.NoError(t, err)
+		refreshed[id] = key
+	}
+	return refreshed
+}
+
+func collect(msgs map[int]map[int]*tss.Message, id int) []*tss.Message {
+	var in []*tss.Message
+	for _, out := range msgs {
+		if msg, ok := out[id]; ok {
+			in = append(in, msg)
+		}
+	}
+	return in
+}
+
+func recoverKey(keys ...*tss.KeyStep3Data) *big.Int {
+	shares := make([]*vss.Share, len(keys))
+	for i, key := range keys {
+		shares[i] = &vss.Share{Id: big.NewInt(int64(key.Id)), Y: key.ShareI}
+	}
+	return vss.RecoverSecret(keys[0].PublicKey.Curve, shares)
+}
+
+func TestImport(t *testing.T) {
+	curve := secp256k1.S256()
+	privateKey := crypto.RandomNum(curve.N)
+	publicKey := curves.ScalarToPoint(curve, privateKey)
+	chainCode, _ := hex.DecodeString("873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508")
+
+	data, err := Import(curve, privateKey, 2, 3, chainCode)
+	require.NoError(t, err)
+	for id, key := range data.Keys {
+		fmt.Println("import", id, key.key.SharePubKeyMap[id])
+		require.Equal(t, id, key.Id())
+		require.True(t, key.PublicKey().Equals(publicKey))
+		require.NoError(t, key.Verify(data.Verifiers, publicKey))
+	}
+	require.Equal(t, 0, recoverKey(data.Keys[1].key, data.Keys[3].key).Cmp(privateKey))
+
+	// dealt keys are usable only through the refresh, which makes them useless
+	keys := refresh(t, data, publicKey)
+	for id, key := range keys {
+		require.True(t, key.PublicKey.Equals(publicKey))
+		require.Equal(t, hex.EncodeToString(chainCode), key.ChainCode)
+		require.NotEqual(t, 0, key.ShareI.Cmp(data.Keys[id].key.ShareI))
+	}
+	require.Equal(t, 0, recoverKey(keys[1], keys[2]).Cmp(privateKey))
+	require.NotEqual(t, 0, recoverKey(data.Keys[1].key, keys[2]).Cmp(privateKey))
+
+	// a dealt key doesn't load as a key file
+	bytes, err := json.Marshal(data.Keys[2])
+	require.NoError(t, err)
+	fmt.Println(string(bytes))
+	loaded := &tss.KeyStep3Data{}
+	require.NoError(t, json.Unmarshal(bytes, loaded))
+	require.Nil(t, loaded.ShareI)
+	decoded := &DealtKey{}
+	require.NoError(t, json.Unmarshal(bytes, decoded))
+	require.NoError(t, decoded.Verify(data.Verifiers, publicKey))
+	keyBytes, _ := json.Marshal(keys[2])
+	require.Error(t, json.Unmarshal(keyBytes, &DealtKey{}))
+
+	// wrong share or wrong expected key, no refresh starts
+	bad := &DealtKey{key: &tss.KeyStep3Data{}}
+	*bad.key = *data.Keys[2].key
+	bad.key.ShareI = new(big.Int).Add(bad.key.ShareI, big.NewInt(1))
+	require.Error(t, bad.Verify(data.Verifiers, publicKey))
+	_, err = bad.Refresh(tss.SessionId("refresh imported key"), data.Verifiers, publicKey)
+	require.Error(t, err)
+	wrongKey := curves.ScalarToPoint(curve, big.NewInt(2))
+	require.Error(t, data.Keys[2].Verify(data.Verifiers, wrongKey))
+	_, err = data.Keys[2].Refresh(tss.SessionId("refresh imported key"), data.Verifiers, wrongKey)
+	require.Error(t, err)
+
+	_, err = Import(curve, curve.N, 2, 3, nil)
+	require.Error(t, err)
+	_, err = Import(curve, privateKey, 2, 3, chainCode[:16])
+	require.Error(t, err)
+	_, err = Import(curve, privateKey, 4, 3, nil)
+	require.Error(t, err)
+}
+
+func TestImportEd25519(t *testing.T) {
+	// RFC8032 test 1
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	privateKey, err := Ed25519PrivateKey(seed)
+	require.NoError(t, err)
+	g := group.Ed25519()
+	require.Equal(t, "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		hex.EncodeToString(g.Generator().ScalarMult(g.ScalarFromBigInt(privateKey)).Bytes()))
+
+	curve := edwards.Edwards()
+	publicKey := curves.ScalarToPoint(curve, privateKey)
+	data, err := Import(curve, privateKey, 3, 4, nil)
+	require.NoError(t, err)
+	keys := refresh(t, data, publicKey)
+	for _, key := range keys {
+		require.Equal(t, 64, len(key.ChainCode))
+	}
+	require.Equal(t, 0, recoverKey(keys[1], keys[2], keys[4]).Cmp(privateKey))
+
+	_, err = Ed25519PrivateKey(seed[:31])
+	require.Error(t, err)
+}
@@ -7,38 +7,62 @@ import (
 
 	"github.com/okx/threshold-lib/crypto/commitment"
 	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
 	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
 type SetupInfo struct {
 	DeviceNumber int // device id， start 1
-	Threshold    int //  2/n, fixed 2
+	Threshold    int // t/n, minimum number of shares to recover the key
 	Total        int // number of participants
 	RoundNumber  int
 
-	ui        *big.Int
+	sessionId *big.Int // agreed by all participants, bound into every commitment and proof
+	ui        group.Scalar
 	shareI    *big.Int // key share
 	publicKey *curves.ECPoint
 	curve     elliptic.Curve
+	group     group.Group
 	chaincode *big.Int // for non-hardened derivation, unchangeable
 
-	verifiers     []*curves.ECPoint
+	verifiers     []group.Point
 	secretShares  []*vss.Share
-	deC           *commitment.Witness
+	deC           map[int]*commitment.Witness // commitment opening for each receiver
 	commitmentMap map[int]commitment.Commitment
+
+	codec codec.Codec // outgoing round payloads
 }
 
-func NewSetUp(deviceNumber, total int, curve elliptic.Curve) *SetupInfo {
-	if total < 2 || deviceNumber > total || deviceNumber <= 0 {
+// NewSetUp threshold t, 2 <= t <= total, feldman polynomial degree is t-1
+// sessionId must be the same for all participants and unique for each dkg, see tss.SessionId
+func NewSetUp(sessionId *big.Int, deviceNumber, threshold, total int, curve elliptic.Curve) *SetupInfo {
+	if sessionId == nil || total < 2 || deviceNumber > total || deviceNumber <= 0 || threshold < 2 || threshold > total {
 		panic(fmt.Errorf("NewSetUp params error"))
 	}
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		panic(fmt.Errorf("NewSetUp unsupported curve: %v", err))
+	}
 	info := &SetupInfo{
 		DeviceNumber: deviceNumber,
-		Threshold:    2,
+		Threshold:    threshold,
 		Total:        total,
 		RoundNumber:  1,
+		sessionId:    sessionId,
 		curve:        curve,
+		group:        g,
+		codec:        codec.JSON,
+	}
+	return info
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (info *SetupInfo) SetCodec(c codec.Codec) *SetupInfo {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
 	}
+	info.codec = c
 	return info
 }
 
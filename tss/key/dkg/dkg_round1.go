@@ -1,7 +1,6 @@
 package dkg
 
 import (
-	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -17,13 +16,16 @@ func (info *SetupInfo) DKGStep1() (map[int]*tss.Message, error) {
 		return nil, fmt.Errorf("round error")
 	}
 	// random generate ui, private key = sum(ui)
-	ui := crypto.RandomNum(info.curve.Params().N)
+	ui, err := info.group.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
 	feldman, err := vss.NewFeldman(info.Threshold, info.Total, info.curve)
 	if err != nil {
 		return nil, err
 	}
 	// verifiers [a0*G, a1*G, ...], shares [fi(1), fi(2), ...]
-	verifiers, shares, err := feldman.Evaluate(ui)
+	verifiers, shares, err := feldman.Deal(ui, info.Ids())
 	if err != nil {
 		return nil, err
 	}
@@ -34,12 +36,12 @@ func (info *SetupInfo) DKGStep1() (map[int]*tss.Message, error) {
 	var input []*big.Int
 	input = append(input, chaincode)
 	for i := 0; i < len(verifiers); i++ {
-		input = append(input, verifiers[i].X, verifiers[i].Y)
+		x, y := verifiers[i].Affine()
+		input = append(input, x, y)
 	}
-	hashCommitment := commitment.NewCommitment(input...)
 
 	info.ui = ui
-	info.deC = &hashCommitment.Msg
+	info.deC = make(map[int]*commitment.Witness, info.Total-1)
 	info.secretShares = shares
 	info.verifiers = verifiers
 	info.chaincode = chaincode
@@ -51,9 +53,11 @@ func (info *SetupInfo) DKGStep1() (map[int]*tss.Message, error) {
 			continue
 		}
 		// each message send p2p, not broadcast
-		// step1: verifiers commitment
+		// step1: verifiers commitment, bound to session and receiver
+		hashCommitment := commitment.NewCommitment(append([]*big.Int{tss.BindId(info.sessionId, info.DeviceNumber, id)}, input...)...)
+		info.deC[id] = &hashCommitment.Msg
 		content := tss.KeyStep1Data{C: &hashCommitment.C}
-		bytes, err := json.Marshal(content)
+		bytes, err := info.codec.Marshal(content)
 		if err != nil {
 			return nil, err
 		}
@@ -1,12 +1,11 @@
 package dkg
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/okx/threshold-lib/crypto/commitment"
-	"github.com/okx/threshold-lib/crypto/curves"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
 // DKGStep2 receive first step message and execute second step
@@ -19,20 +18,25 @@ func (info *SetupInfo) DKGStep2(msgs []*tss.Message) (map[int]*tss.Message, erro
 	}
 	info.commitmentMap = make(map[int]commitment.Commitment, len(msgs))
 	for _, msg := range msgs {
-		if msg.To != info.DeviceNumber {
+		if msg.To != info.DeviceNumber || msg.From <= 0 || msg.From > info.Total || msg.From == info.DeviceNumber {
+			return nil, fmt.Errorf("message sending error")
+		}
+		// one message per participant, a replayed message can't stand in for a missing one
+		if _, ok := info.commitmentMap[msg.From]; ok {
 			return nil, fmt.Errorf("message sending error")
 		}
 		var content tss.KeyStep1Data
-		err := json.Unmarshal([]byte(msg.Data), &content)
+		err := codec.Unmarshal([]byte(msg.Data), &content)
 		if err != nil {
-			return nil, err
+			return nil, tss.NewBlameError(msg, 2, "invalid message", err)
+		}
+		if content.C == nil {
+			return nil, tss.NewBlameError(msg, 2, "missing commitment", nil)
 		}
 		info.commitmentMap[msg.From] = *content.C
 	}
 
-	// compute zkSchnorr prove for ui
-	uiG := curves.ScalarToPoint(info.curve, info.ui)
-	proof, err := schnorr.Prove(info.ui, uiG)
+	uiG, err := info.group.Generator().ScalarMult(info.ui).ECPoint()
 	if err != nil {
 		return nil, err
 	}
@@ -43,13 +47,18 @@ func (info *SetupInfo) DKGStep2(msgs []*tss.Message) (map[int]*tss.Message, erro
 		if id == info.DeviceNumber {
 			continue
 		}
+		// compute zkSchnorr prove for ui, bound to session and receiver
+		proof, err := schnorr.ProveWithId(tss.BindId(info.sessionId, info.DeviceNumber, id), info.ui.BigInt(), uiG)
+		if err != nil {
+			return nil, err
+		}
 		// step2: commitment data、secretShares and schnorr proof for ui
 		content := tss.KeyStep2Data{
-			Witness: info.deC,
+			Witness: info.deC[id],
 			Share:   info.secretShares[id-1],
 			Proof:   proof,
 		}
-		bytes, err := json.Marshal(content)
+		bytes, err := info.codec.Marshal(content)
 		if err != nil {
 			return nil, err
 		}
@@ -3,15 +3,16 @@ package dkg
 import (
 	"crypto/elliptic"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"math/big"
 
 	"github.com/okx/threshold-lib/crypto/commitment"
 	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/crypto/vss"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
 // DKGStep3 receive second step message and execute dkg finish
@@ -25,117 +26,160 @@ func (info *SetupInfo) DKGStep3(msgs []*tss.Message) (*tss.KeyStep3Data, error)
 	}
 
 	curve := info.curve
+	g := info.group
 	feldman, err := vss.NewFeldman(info.Threshold, info.Total, curve)
 	if err != nil {
 		return nil, err
 	}
 
-	verifiers := make(map[int][]*curves.ECPoint, len(msgs))
+	verifiers := make(map[int][]group.Point, len(msgs))
 	verifiers[info.DeviceNumber] = info.verifiers
 	chaincode := info.chaincode
-	xi := info.secretShares[info.DeviceNumber-1]
+	xi, err := g.ScalarFromSecret(info.secretShares[info.DeviceNumber-1].Y)
+	if err != nil {
+		return nil, err
+	}
 	for _, msg := range msgs {
 		if msg.To != info.DeviceNumber {
 			return nil, fmt.Errorf("message sending error")
 		}
+		// only participants who committed in step2, once each
+		if _, ok := info.commitmentMap[msg.From]; !ok {
+			return nil, fmt.Errorf("message sending error")
+		}
+		if _, ok := verifiers[msg.From]; ok {
+			return nil, fmt.Errorf("message sending error")
+		}
 		var data tss.KeyStep2Data
-		err := json.Unmarshal([]byte(msg.Data), &data)
+		err := codec.Unmarshal([]byte(msg.Data), &data)
 		if err != nil {
-			return nil, err
+			return nil, tss.NewBlameError(msg, 3, "invalid message", err)
+		}
+		if data.Witness == nil || data.Share == nil || data.Share.Y == nil || data.Proof == nil {
+			return nil, tss.NewBlameError(msg, 3, "incomplete message", nil)
+		}
+		if data.Share.Id == nil || data.Share.Id.Cmp(big.NewInt(int64(info.DeviceNumber))) != 0 {
+			return nil, tss.NewBlameError(msg, 3, "invalid share", nil)
 		}
 		// check verifiers commitment
 		hashCommit := commitment.HashCommitment{}
 		hashCommit.C = info.commitmentMap[msg.From]
 		hashCommit.Msg = *data.Witness
 		ok, D := hashCommit.Open()
-		if !ok {
-			return nil, fmt.Errorf("commitment DeCommit fail")
+		if !ok || len(D) < 2 {
+			return nil, tss.NewBlameError(msg, 3, "commitment DeCommit fail", nil)
+		}
+		bindId := tss.BindId(info.sessionId, msg.From, info.DeviceNumber)
+		if D[0].Cmp(bindId) != 0 {
+			return nil, tss.NewBlameError(msg, 3, "commitment sessionId error", nil)
 		}
 		//  actual chaincode = sum(chaincode)
-		chaincode = new(big.Int).Add(chaincode, D[0])
-		verifiers[msg.From], err = UnmarshalVerifiers(curve, D[1:], info.Threshold)
+		chaincode = new(big.Int).Add(chaincode, D[1])
+		verifiers[msg.From], err = UnmarshalVerifiers(g, D[2:], info.Threshold)
 		if err != nil {
-			return nil, err
+			return nil, tss.NewBlameError(msg, 3, "invalid verifiers", err)
 		}
 
 		// feldman verify
-		if ok, err := feldman.Verify(data.Share, verifiers[msg.From]); !ok {
-			if err != nil {
-				return nil, err
-			} else {
-				return nil, fmt.Errorf("invalid share for participant  ")
-			}
-		}
-		xi.Y = new(big.Int).Add(xi.Y, data.Share.Y)
+		if ok, err := feldman.VerifyPoints(data.Share, verifiers[msg.From]); !ok {
+			return nil, tss.NewBlameError(msg, 3, "invalid share", err)
+		}
+		yj, err := g.ScalarFromSecret(data.Share.Y)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 3, "invalid share", err)
+		}
+		xi = xi.Add(yj)
 
-		ujPoint := verifiers[msg.From][0]
-		point, err := curves.NewECPoint(curve, ujPoint.X, ujPoint.Y)
+		// uj*G must not be the identity, schnorr proof of knowledge for uj
+		point, err := verifiers[msg.From][0].ECPoint()
 		if err != nil {
-			return nil, err
+			return nil, tss.NewBlameError(msg, 3, "invalid ui*G", err)
 		}
-		// schnorr verify for ui
-		verify := schnorr.Verify(data.Proof, point)
+		// schnorr verify
+		verify := schnorr.VerifyWithId(bindId, data.Proof, point)
 		if !verify {
-			return nil, fmt.Errorf("schnorr verify fail")
-		}
-	}
-
-	v := make([]*curves.ECPoint, info.Threshold)
-	for j := 0; j < info.Threshold; j++ {
-		v[j] = curves.ScalarToPoint(curve, big.NewInt(0))
-
-		for _, verifier := range verifiers {
-			v[j], err = v[j].Add(verifier[j])
-			if err != nil {
-				return nil, err
-			}
+			return nil, tss.NewBlameError(msg, 3, "schnorr verify fail", nil)
 		}
 	}
 
-	sharePubKeyMap := make(map[int]*curves.ECPoint, info.Threshold)
-	for k := 1; k <= info.Total; k++ {
-		Yi := v[0]
-		tmp := big.NewInt(1)
-		for i := 1; i < info.Threshold; i++ {
-			tmp = tmp.Mul(tmp, big.NewInt(int64(k)))
-			point := v[i]
-			point = point.ScalarMult(tmp)
-			Yi, err = Yi.Add(point)
-			if err != nil {
-				return nil, err
-			}
-		}
-		sharePubKeyMap[k] = Yi
+	v := SumVerifiers(g, verifiers, info.Threshold)
+	// Yk = v0 + k*v1 + ... + k^(t-1)*v(t-1)
+	sharePubKeyMap, err := SharePubKeys(v, info.Ids())
+	if err != nil {
+		return nil, err
 	}
 	// check share publicKey
-	xiG := curves.ScalarToPoint(curve, xi.Y)
-	if !sharePubKeyMap[info.DeviceNumber].Equals(xiG) {
+	xiG, err := g.Generator().ScalarMult(xi).ECPoint()
+	if err != nil || !sharePubKeyMap[info.DeviceNumber].Equals(xiG) {
 		return nil, fmt.Errorf("public key calculation error")
 	}
-	info.shareI = xi.Y
-	info.publicKey = v[0]
+	publicKey, err := v[0].ECPoint()
+	if err != nil {
+		return nil, fmt.Errorf("public key is the identity")
+	}
+	info.shareI = xi.BigInt()
+	info.publicKey = publicKey
 
 	content := &tss.KeyStep3Data{
 		Id:             info.DeviceNumber,
 		ShareI:         info.shareI,
 		PublicKey:      info.publicKey,
-		ChainCode:      hex.EncodeToString(chaincode.Bytes()),
+		ChainCode:      hex.EncodeToString(chaincodeBytes(chaincode, curve)),
 		SharePubKeyMap: sharePubKeyMap,
 	}
 	return content, nil
 }
 
-func UnmarshalVerifiers(curve elliptic.Curve, msg []*big.Int, threshold int) ([]*curves.ECPoint, error) {
+// chaincodeBytes sum(chaincode) mod N as 32 bytes, the fixed width BIP32 xpub chaincode.
+// sum(chaincode).Bytes() is 33 bytes when the sum passes 2^256 and shorter with leading zeros, such a key has no xpub.
+// only new keys are affected, a stored ChainCode is used as it is
+func chaincodeBytes(chaincode *big.Int, curve elliptic.Curve) []byte {
+	return new(big.Int).Mod(chaincode, curve.Params().N).FillBytes(make([]byte, 32))
+}
+
+// UnmarshalVerifiers threshold verifiers from committed (X, Y) pairs, (0, 0) is the identity
+func UnmarshalVerifiers(g group.Group, msg []*big.Int, threshold int) ([]group.Point, error) {
 	if len(msg) != (threshold * 2) {
 		return nil, fmt.Errorf("invalid number of verifier shares")
 	}
-	verifiers := make([]*curves.ECPoint, threshold)
+	verifiers := make([]group.Point, threshold)
 	for k := 0; k < threshold; k++ {
-		verifiers[k] = &curves.ECPoint{
-			Curve: curve,
-			X:     msg[2*k],
-			Y:     msg[2*k+1],
+		point, err := g.PointFromAffine(msg[2*k], msg[2*k+1])
+		if err != nil {
+			return nil, err
 		}
+		verifiers[k] = point
 	}
 	return verifiers, nil
 }
+
+// SumVerifiers v[j] = sum of every dealer's j-th verifier
+func SumVerifiers(g group.Group, verifiers map[int][]group.Point, threshold int) []group.Point {
+	v := make([]group.Point, threshold)
+	for j := 0; j < threshold; j++ {
+		v[j] = g.Identity()
+		for _, verifier := range verifiers {
+			v[j] = v[j].Add(verifier[j])
+		}
+	}
+	return v
+}
+
+// SharePubKeys Yk = v0 + k*v1 + ... + k^(t-1)*v(t-1) for each id
+func SharePubKeys(v []group.Point, ids []int) (map[int]*curves.ECPoint, error) {
+	g := v[0].Group()
+	sharePubKeyMap := make(map[int]*curves.ECPoint, len(ids))
+	for _, k := range ids {
+		x := g.ScalarFromBigInt(big.NewInt(int64(k)))
+		Yk := g.Identity()
+		for i := len(v) - 1; i >= 0; i-- {
+			Yk = Yk.ScalarMult(x).Add(v[i])
+		}
+		point, err := Yk.ECPoint()
+		if err != nil {
+			return nil, fmt.Errorf("share publicKey of %d is the identity", k)
+		}
+		sharePubKeyMap[k] = point
+	}
+	return sharePubKeyMap, nil
+}
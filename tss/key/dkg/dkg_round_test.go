@@ -1,18 +1,25 @@
 package dkg
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/vss"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
 func TestKeyGen(t *testing.T) {
+	sessionId := tss.SessionId("TestKeyGen")
 	curve := secp256k1.S256() // edwards.Edwards()
-	setUp1 := NewSetUp(1, 3, curve)
-	setUp2 := NewSetUp(2, 3, curve)
-	setUp3 := NewSetUp(3, 3, curve)
+	setUp1 := NewSetUp(sessionId, 1, 2, 3, curve)
+	setUp2 := NewSetUp(sessionId, 2, 2, 3, curve)
+	setUp3 := NewSetUp(sessionId, 3, 2, 3, curve)
 
 	msgs1_1, _ := setUp1.DKGStep1()
 	msgs2_1, _ := setUp2.DKGStep1()
@@ -40,11 +47,12 @@ func TestKeyGen(t *testing.T) {
 
 }
 func TestKeyGen2_4(t *testing.T) {
+	sessionId := tss.SessionId("TestKeyGen2_4")
 	curve := secp256k1.S256() // edwards.Edwards()
-	setUp1 := NewSetUp(1, 4, curve)
-	setUp2 := NewSetUp(2, 4, curve)
-	setUp3 := NewSetUp(3, 4, curve)
-	setUp4 := NewSetUp(4, 4, curve)
+	setUp1 := NewSetUp(sessionId, 1, 2, 4, curve)
+	setUp2 := NewSetUp(sessionId, 2, 2, 4, curve)
+	setUp3 := NewSetUp(sessionId, 3, 2, 4, curve)
+	setUp4 := NewSetUp(sessionId, 4, 2, 4, curve)
 
 	msgs1_1, _ := setUp1.DKGStep1()
 	msgs2_1, _ := setUp2.DKGStep1()
@@ -87,4 +95,309 @@ func TestKeyGen2_4(t *testing.T) {
 	fmt.Println("setUp2", p2SaveData, p2SaveData.PublicKey)
 	fmt.Println("setUp3", p3SaveData, p3SaveData.PublicKey)
 	fmt.Println("setUp4", p4SaveData, p4SaveData.PublicKey)
-}
\ No newline at end of file
+}
+func TestKeyGen3_5(t *testing.T) {
+	testKeyGenThreshold(t, 3, 5)
+}
+
+func TestKeyGen4_7(t *testing.T) {
+	testKeyGenThreshold(t, 4, 7)
+}
+
+// testKeyGenThreshold run t-of-n dkg, any t shares recover the private key, t-1 shares do not
+func testKeyGenThreshold(t *testing.T, threshold, total int) {
+	sessionId := tss.SessionId("testKeyGenThreshold")
+	curve := secp256k1.S256()
+	setUps := make([]*SetupInfo, total+1)
+	for i := 1; i <= total; i++ {
+		setUps[i] = NewSetUp(sessionId, i, threshold, total, curve)
+	}
+
+	msgs1 := make([]map[int]*tss.Message, total+1)
+	for i := 1; i <= total; i++ {
+		out, err := setUps[i].DKGStep1()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs1[i] = out
+	}
+	msgs2 := make([]map[int]*tss.Message, total+1)
+	for i := 1; i <= total; i++ {
+		out, err := setUps[i].DKGStep2(collect(msgs1, i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs2[i] = out
+	}
+	saveData := make([]*tss.KeyStep3Data, total+1)
+	for i := 1; i <= total; i++ {
+		data, err := setUps[i].DKGStep3(collect(msgs2, i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		saveData[i] = data
+	}
+
+	for i := 1; i <= total; i++ {
+		if !saveData[i].PublicKey.Equals(saveData[1].PublicKey) || saveData[i].ChainCode != saveData[1].ChainCode {
+			t.Fatalf("party %d public key mismatch", i)
+		}
+		if len(saveData[i].ChainCode) != 64 {
+			t.Fatalf("party %d chaincode is not 32 bytes", i)
+		}
+		if len(saveData[i].SharePubKeyMap) != total {
+			t.Fatalf("party %d share public key map size %d", i, len(saveData[i].SharePubKeyMap))
+		}
+		for k := 1; k <= total; k++ {
+			xkG := curves.ScalarToPoint(curve, saveData[k].ShareI)
+			if !saveData[i].SharePubKeyMap[k].Equals(xkG) {
+				t.Fatalf("party %d share public key %d mismatch", i, k)
+			}
+		}
+	}
+
+	// use the last t parties
+	shares := make([]*vss.Share, 0, threshold)
+	for i := total - threshold + 1; i <= total; i++ {
+		shares = append(shares, &vss.Share{Id: big.NewInt(int64(i)), Y: saveData[i].ShareI})
+	}
+	secret := vss.RecoverSecret(curve, shares)
+	if !curves.ScalarToPoint(curve, secret).Equals(saveData[1].PublicKey) {
+		t.Fatal("t shares recover wrong private key")
+	}
+	secret = vss.RecoverSecret(curve, shares[1:])
+	if curves.ScalarToPoint(curve, secret).Equals(saveData[1].PublicKey) {
+		t.Fatal("t-1 shares recover private key")
+	}
+	fmt.Println("publicKey", saveData[1].PublicKey)
+}
+
+// collect messages sent to party id
+func collect(msgs []map[int]*tss.Message, id int) []*tss.Message {
+	var in []*tss.Message
+	for from := 1; from < len(msgs); from++ {
+		if from == id {
+			continue
+		}
+		in = append(in, msgs[from][id])
+	}
+	return in
+}
+
+func TestKeyGenBlame(t *testing.T) {
+	sessionId := tss.SessionId("TestKeyGenBlame")
+	curve := secp256k1.S256()
+	total := 3
+	setUps := make([]*SetupInfo, total+1)
+	for i := 1; i <= total; i++ {
+		setUps[i] = NewSetUp(sessionId, i, 2, total, curve)
+	}
+	msgs1 := make([]map[int]*tss.Message, total+1)
+	for i := 1; i <= total; i++ {
+		msgs1[i], _ = setUps[i].DKGStep1()
+	}
+	msgs2 := make([]map[int]*tss.Message, total+1)
+	for i := 1; i <= total; i++ {
+		msgs2[i], _ = setUps[i].DKGStep2(collect(msgs1, i))
+	}
+
+	// participant 3 sends an invalid share to participant 1
+	var content tss.KeyStep2Data
+	if err := json.Unmarshal([]byte(msgs2[3][1].Data), &content); err != nil {
+		t.Fatal(err)
+	}
+	content.Share.Y = new(big.Int).Add(content.Share.Y, big.NewInt(1))
+	bytes, _ := json.Marshal(content)
+	msgs2[3][1].Data = string(bytes)
+
+	_, err := setUps[1].DKGStep3(collect(msgs2, 1))
+	fmt.Println(err)
+	var blame *tss.BlameError
+	if !errors.As(err, &blame) || blame.From != 3 || blame.Round != 3 || blame.Evidence != msgs2[3][1].Data {
+		t.Fatalf("expected blame for participant 3, got %v", err)
+	}
+	if culprit, ok := tss.Culprit(err); !ok || culprit != 3 {
+		t.Fatalf("culprit %d", culprit)
+	}
+	// other participants are not affected
+	if _, err = setUps[2].DKGStep3(collect(msgs2, 2)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeyGenBinaryCodec(t *testing.T) {
+	sessionId := tss.SessionId("TestKeyGenBinaryCodec")
+	curve := secp256k1.S256()
+	total := 3
+	setUps := make([]*SetupInfo, total+1)
+	for i := 1; i < total; i++ {
+		setUps[i] = NewSetUp(sessionId, i, 2, total, curve).SetCodec(codec.Binary)
+	}
+	// the last party keeps JSON, receivers accept both
+	setUps[total] = NewSetUp(sessionId, total, 2, total, curve)
+
+	// round 1 through the binary transport format
+	transport := func(out map[int]*tss.Message) map[int]*tss.Message {
+		received := make(map[int]*tss.Message, len(out))
+		for id, msg := range out {
+			bytes, err := msg.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			received[id] = new(tss.Message)
+			if err := received[id].UnmarshalBinary(bytes); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return received
+	}
+	// round 2 through a JSON transport, binary payloads must survive it
+	jsonTransport := func(out map[int]*tss.Message) map[int]*tss.Message {
+		bytes, err := json.Marshal(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		received := make(map[int]*tss.Message, len(out))
+		if err := json.Unmarshal(bytes, &received); err != nil {
+			t.Fatal(err)
+		}
+		for id, msg := range out {
+			if received[id].Data != msg.Data || received[id].From != msg.From || received[id].To != msg.To {
+				t.Fatalf("message to %d changed by the JSON transport", id)
+			}
+		}
+		return received
+	}
+	msgs1 := make([]map[int]*tss.Message, total+1)
+	for i := 1; i <= total; i++ {
+		out, err := setUps[i].DKGStep1()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs1[i] = transport(out)
+	}
+	msgs2 := make([]map[int]*tss.Message, total+1)
+	for i := 1; i <= total; i++ {
+		out, err := setUps[i].DKGStep2(collect(msgs1, i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if codec.IsBinary([]byte(out[i%total+1].Data)) != (i < total) {
+			t.Fatal("round 2 message is not encoded with the party codec")
+		}
+		msgs2[i] = jsonTransport(out)
+	}
+	saveData, err := setUps[1].DKGStep3(collect(msgs2, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// saved key round trip
+	bytes, err := codec.Binary.Marshal(saveData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded tss.KeyStep3Data
+	if err := codec.Unmarshal(bytes, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ShareI.Cmp(saveData.ShareI) != 0 || !loaded.PublicKey.Equals(saveData.PublicKey) || loaded.ChainCode != saveData.ChainCode {
+		t.Fatal("saved key mismatch")
+	}
+	for id, point := range saveData.SharePubKeyMap {
+		if !loaded.SharePubKeyMap[id].Equals(point) {
+			t.Fatalf("share public key %d mismatch", id)
+		}
+	}
+	fmt.Println("saved key", len(bytes), "bytes")
+}
+
+func TestKeyGenSessionBinding(t *testing.T) {
+	curve := secp256k1.S256()
+	total := 3
+	newSession := func(sessionId *big.Int) ([]*SetupInfo, []map[int]*tss.Message) {
+		setUps := make([]*SetupInfo, total+1)
+		msgs1 := make([]map[int]*tss.Message, total+1)
+		for i := 1; i <= total; i++ {
+			setUps[i] = NewSetUp(sessionId, i, 2, total, curve)
+			msgs1[i], _ = setUps[i].DKGStep1()
+		}
+		return setUps, msgs1
+	}
+	step2 := func(setUps []*SetupInfo, msgs1 []map[int]*tss.Message) []map[int]*tss.Message {
+		msgs2 := make([]map[int]*tss.Message, total+1)
+		for i := 1; i <= total; i++ {
+			msgs2[i], _ = setUps[i].DKGStep2(collect(msgs1, i))
+		}
+		return msgs2
+	}
+
+	// messages of participant 3 replayed from another session
+	setUpsA, msgs1A := newSession(tss.SessionId("session-a"))
+	setUpsB, msgs1B := newSession(tss.SessionId("session-b"))
+	msgs2B := step2(setUpsB, msgs1B)
+	msgs1A[3][1] = msgs1B[3][1]
+	msgs2A := step2(setUpsA, msgs1A)
+	msgs2A[3][1] = msgs2B[3][1]
+	_, err := setUpsA[1].DKGStep3(collect(msgs2A, 1))
+	fmt.Println(err)
+	var blame *tss.BlameError
+	if !errors.As(err, &blame) || blame.From != 3 || blame.Check != "commitment sessionId error" {
+		t.Fatalf("expected session blame for participant 3, got %v", err)
+	}
+
+	// the same session still works for other participants
+	if _, err = setUpsA[2].DKGStep3(collect(msgs2A, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	// an empty session id is rejected
+	defer func() {
+		if recover() == nil {
+			t.Fatal("empty session id accepted")
+		}
+	}()
+	NewSetUp(tss.SessionId(""), 1, 2, total, curve)
+}
+
+func TestKeyGenReplay(t *testing.T) {
+	sessionId := tss.SessionId("TestKeyGenReplay")
+	curve := secp256k1.S256()
+	total := 3
+	setUps := make([]*SetupInfo, total+1)
+	for i := 1; i <= total; i++ {
+		setUps[i] = NewSetUp(sessionId, i, 2, total, curve)
+	}
+	msgs1 := make([]map[int]*tss.Message, total+1)
+	for i := 1; i <= total; i++ {
+		msgs1[i], _ = setUps[i].DKGStep1()
+	}
+
+	// message of participant 2 replayed in place of participant 3
+	if _, err := setUps[1].DKGStep2([]*tss.Message{msgs1[2][1], msgs1[2][1]}); err == nil {
+		t.Fatal("duplicate sender accepted")
+	}
+	forged := *msgs1[2][1]
+	forged.From = 4
+	if _, err := setUps[1].DKGStep2([]*tss.Message{msgs1[2][1], &forged}); err == nil {
+		t.Fatal("sender out of range accepted")
+	}
+	forged.From = 1
+	if _, err := setUps[1].DKGStep2([]*tss.Message{msgs1[2][1], &forged}); err == nil {
+		t.Fatal("own id accepted")
+	}
+
+	msgs2 := make([]map[int]*tss.Message, total+1)
+	for i := 1; i <= total; i++ {
+		var err error
+		if msgs2[i], err = setUps[i].DKGStep2(collect(msgs1, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := setUps[1].DKGStep3([]*tss.Message{msgs2[2][1], msgs2[2][1]}); err == nil {
+		t.Fatal("duplicate sender accepted")
+	}
+	if _, err := setUps[1].DKGStep3(collect(msgs2, 1)); err != nil {
+		t.Fatal(err)
+	}
+}
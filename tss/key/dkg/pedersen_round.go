@@ -0,0 +1,139 @@
+package dkg
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/schnorr"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// PedersenSetupInfo dkg of Gennaro, Jarecki, Krawczyk and Rabin, shares are dealt with pedersen vss so nothing about
+// the key is public until the qualified set is fixed, an invalid share is resolved by a complaint round instead of aborting:
+//
+//	step1: pedersen commitments a_k*G + b_k*H and the share pair f(j), f'(j)
+//	step2: complaints against dealers whose share pair doesn't match the commitments
+//	step3: accused dealers reveal the complainers' share pairs
+//	step4: dealers with threshold or more complaints, or an invalid reveal, are disqualified,
+//	       qualified dealers send feldman verifiers a_k*G with a schnorr proof
+//	step5: key share is the sum of the qualified dealers' shares
+//
+// commitments, complaints and reveals must reach every participant unchanged, the transport has to provide broadcast
+type PedersenSetupInfo struct {
+	DeviceNumber int // device id， start 1
+	Threshold    int // t/n, minimum number of shares to recover the key
+	Total        int // number of participants
+	RoundNumber  int
+
+	sessionId *big.Int
+	curve     elliptic.Curve
+	group     group.Group
+	pedersen  *vss.Pedersen
+	ui        group.Scalar
+	chaincode *big.Int
+
+	deal        *vss.PedersenDeal
+	deC         map[int]*commitment.Witness // chaincode commitment opening for each receiver
+	commitments map[int][]group.Point       // pedersen commitments of each dealer
+	chaincodeC  map[int]commitment.Commitment
+	shares      map[int]*vss.Share // share pair received from each dealer
+	blinds      map[int]*vss.Share
+	complaints  map[int][]int // complainer -> accused dealers
+	qualified   []int
+	xi          group.Scalar
+
+	codec codec.Codec // outgoing round payloads
+}
+
+// PedersenStep1Data Commitments are the same for every receiver, Share and Blind are private
+type PedersenStep1Data struct {
+	Commitments []*curves.ECPoint      // a_k*G + b_k*H
+	C           *commitment.Commitment // chaincode commitment
+	Share       *vss.Share             // f(j)
+	Blind       *vss.Share             // f'(j)
+}
+
+// PedersenStep2Data dealers accused by the sender, broadcast
+type PedersenStep2Data struct {
+	Complaints []int
+}
+
+// PedersenStep3Data share pairs of the complainers opened by the accused dealer, broadcast
+type PedersenStep3Data struct {
+	Shares map[int]*vss.Share
+	Blinds map[int]*vss.Share
+}
+
+// PedersenStep4Data feldman verifiers of a qualified dealer
+type PedersenStep4Data struct {
+	Verifiers []*curves.ECPoint // a_k*G
+	Witness   *commitment.Witness
+	Proof     *schnorr.Proof // a_0 schnorr proof
+}
+
+// NewPedersenSetUp same parameters as NewSetUp, sessionId must be unique for each dkg
+func NewPedersenSetUp(sessionId *big.Int, deviceNumber, threshold, total int, curve elliptic.Curve) *PedersenSetupInfo {
+	if sessionId == nil || total < 2 || deviceNumber > total || deviceNumber <= 0 || threshold < 2 || threshold > total {
+		panic(fmt.Errorf("NewPedersenSetUp params error"))
+	}
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		panic(fmt.Errorf("NewPedersenSetUp unsupported curve: %v", err))
+	}
+	pedersen, err := vss.NewPedersen(threshold, total, curve, PedersenH(g))
+	if err != nil {
+		panic(err)
+	}
+	return &PedersenSetupInfo{
+		DeviceNumber: deviceNumber,
+		Threshold:    threshold,
+		Total:        total,
+		RoundNumber:  1,
+		sessionId:    sessionId,
+		curve:        curve,
+		group:        g,
+		pedersen:     pedersen,
+		codec:        codec.JSON,
+	}
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (info *PedersenSetupInfo) SetCodec(c codec.Codec) *PedersenSetupInfo {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
+	}
+	info.codec = c
+	return info
+}
+
+// PedersenH second generator of the pedersen commitments, hashed so log_G(H) is unknown
+func PedersenH(g group.Group) group.Point {
+	return group.HashToPoint(g, []byte("threshold-lib pedersen dkg "+g.Name()))
+}
+
+func (info *PedersenSetupInfo) Ids() []int {
+	var ids []int
+	for i := 1; i <= info.Total; i++ {
+		ids = append(ids, i)
+	}
+	return ids
+}
+
+// Qualified dealers contributing to the key, known after step4
+func (info *PedersenSetupInfo) Qualified() []int {
+	return append([]int{}, info.qualified...)
+}
+
+func (info *PedersenSetupInfo) isQualified(id int) bool {
+	for _, q := range info.qualified {
+		if q == id {
+			return true
+		}
+	}
+	return false
+}
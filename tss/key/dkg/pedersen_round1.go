@@ -0,0 +1,76 @@
+package dkg
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto"
+	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+)
+
+// DKGStep1 pedersen dealing of a random ui, p2p send the commitments and each receiver's share pair
+func (info *PedersenSetupInfo) DKGStep1() (map[int]*tss.Message, error) {
+	if info.RoundNumber != 1 {
+		return nil, fmt.Errorf("round error")
+	}
+	ui, err := info.group.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	deal, err := info.pedersen.Deal(ui, info.Ids())
+	if err != nil {
+		return nil, err
+	}
+	commitments := make([]*curves.ECPoint, len(deal.Commitments))
+	for i, C := range deal.Commitments {
+		commitments[i], err = C.ECPoint()
+		if err != nil {
+			return nil, err
+		}
+	}
+	chaincode := crypto.RandomNum(info.curve.Params().N)
+
+	info.ui = ui
+	info.deal = deal
+	info.chaincode = chaincode
+	info.deC = make(map[int]*commitment.Witness, info.Total-1)
+	info.RoundNumber = 2
+
+	out := make(map[int]*tss.Message, info.Total-1)
+	for _, id := range info.Ids() {
+		if id == info.DeviceNumber {
+			continue
+		}
+		// chaincode commitment bound to session and receiver, opened by qualified dealers in step4
+		hashCommitment := commitment.NewCommitment(tss.BindId(info.sessionId, info.DeviceNumber, id), chaincode)
+		info.deC[id] = &hashCommitment.Msg
+		content := PedersenStep1Data{
+			Commitments: commitments,
+			C:           &hashCommitment.C,
+			Share:       deal.Shares[id-1],
+			Blind:       deal.Blinds[id-1],
+		}
+		bytes, err := info.codec.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = &tss.Message{
+			From: info.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	return out, nil
+}
+
+// shareOf share pair dealt to id is valid for the dealer's commitments
+func (info *PedersenSetupInfo) shareOf(dealer, id int, share, blind *vss.Share) bool {
+	if share == nil || blind == nil || share.Id == nil || share.Id.Cmp(big.NewInt(int64(id))) != 0 {
+		return false
+	}
+	ok, err := info.pedersen.Verify(share, blind, info.commitments[dealer])
+	return err == nil && ok
+}
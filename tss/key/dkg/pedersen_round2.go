@@ -0,0 +1,89 @@
+package dkg
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// DKGStep2 verify the received share pairs, broadcast complaints against dealers whose pair doesn't match
+func (info *PedersenSetupInfo) DKGStep2(msgs []*tss.Message) (map[int]*tss.Message, error) {
+	if info.RoundNumber != 2 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != (info.Total - 1) {
+		return nil, fmt.Errorf("messages number error")
+	}
+	g := info.group
+	info.commitments = map[int][]group.Point{info.DeviceNumber: info.deal.Commitments}
+	info.chaincodeC = make(map[int]commitment.Commitment, len(msgs))
+	info.shares = map[int]*vss.Share{info.DeviceNumber: info.deal.Shares[info.DeviceNumber-1]}
+	info.blinds = map[int]*vss.Share{info.DeviceNumber: info.deal.Blinds[info.DeviceNumber-1]}
+	var complaints []int
+	for _, msg := range msgs {
+		if msg.To != info.DeviceNumber || msg.From <= 0 || msg.From > info.Total {
+			return nil, fmt.Errorf("message sending error")
+		}
+		if _, ok := info.commitments[msg.From]; ok {
+			return nil, fmt.Errorf("message sending error")
+		}
+		var content PedersenStep1Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid message", err)
+		}
+		// the broadcast part must be usable by everyone, a private share pair can be disputed
+		if content.C == nil || len(content.Commitments) != info.Threshold {
+			return nil, tss.NewBlameError(msg, 2, "invalid commitments", nil)
+		}
+		commitments := make([]group.Point, len(content.Commitments))
+		for k, C := range content.Commitments {
+			if C == nil || curves.GetCurveName(C.Curve) != g.Name() {
+				return nil, tss.NewBlameError(msg, 2, "invalid commitments", nil)
+			}
+			commitments[k], err = g.PointFromAffine(C.X, C.Y)
+			if err != nil {
+				return nil, tss.NewBlameError(msg, 2, "invalid commitments", err)
+			}
+		}
+		info.commitments[msg.From] = commitments
+		info.chaincodeC[msg.From] = *content.C
+
+		if !info.shareOf(msg.From, info.DeviceNumber, content.Share, content.Blind) {
+			complaints = append(complaints, msg.From)
+			continue
+		}
+		info.shares[msg.From] = content.Share
+		info.blinds[msg.From] = content.Blind
+	}
+	sort.Ints(complaints)
+	info.complaints = map[int][]int{info.DeviceNumber: complaints}
+	info.RoundNumber = 3
+	return info.broadcast(PedersenStep2Data{Complaints: complaints})
+}
+
+// broadcast the same content to every other participant
+func (info *PedersenSetupInfo) broadcast(content interface{}) (map[int]*tss.Message, error) {
+	bytes, err := info.codec.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]*tss.Message, info.Total-1)
+	for _, id := range info.Ids() {
+		if id == info.DeviceNumber {
+			continue
+		}
+		out[id] = &tss.Message{
+			From: info.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+package dkg
+
+import (
+	"fmt"
+
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// DKGStep3 collect the complaints, broadcast the share pairs of everyone who complained against this dealer
+func (info *PedersenSetupInfo) DKGStep3(msgs []*tss.Message) (map[int]*tss.Message, error) {
+	if info.RoundNumber != 3 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != (info.Total - 1) {
+		return nil, fmt.Errorf("messages number error")
+	}
+	for _, msg := range msgs {
+		if msg.To != info.DeviceNumber || msg.From <= 0 || msg.From > info.Total {
+			return nil, fmt.Errorf("message sending error")
+		}
+		if _, ok := info.complaints[msg.From]; ok {
+			return nil, fmt.Errorf("message sending error")
+		}
+		var content PedersenStep2Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 3, "invalid message", err)
+		}
+		// sorted distinct dealer ids, nobody accuses itself
+		for i, id := range content.Complaints {
+			if id <= 0 || id > info.Total || id == msg.From || (i > 0 && id <= content.Complaints[i-1]) {
+				return nil, tss.NewBlameError(msg, 3, "invalid complaints", nil)
+			}
+		}
+		info.complaints[msg.From] = content.Complaints
+	}
+
+	reveal := PedersenStep3Data{
+		Shares: make(map[int]*vss.Share),
+		Blinds: make(map[int]*vss.Share),
+	}
+	for _, complainer := range info.accusers(info.DeviceNumber) {
+		reveal.Shares[complainer] = info.deal.Shares[complainer-1]
+		reveal.Blinds[complainer] = info.deal.Blinds[complainer-1]
+	}
+	info.RoundNumber = 4
+	return info.broadcast(reveal)
+}
+
+// accusers participants complaining against dealer, ascending
+func (info *PedersenSetupInfo) accusers(dealer int) []int {
+	var ids []int
+	for _, id := range info.Ids() {
+		for _, accused := range info.complaints[id] {
+			if accused == dealer {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	return ids
+}
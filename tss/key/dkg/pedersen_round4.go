@@ -0,0 +1,118 @@
+package dkg
+
+import (
+	"fmt"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/schnorr"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// DKGStep4 fix the qualified set from the reveals, send the feldman verifiers of this dealer if it is qualified
+// a dealer is disqualified with threshold or more complaints, a revealed pair would then leak its secret,
+// or when a revealed share pair doesn't match its commitments
+func (info *PedersenSetupInfo) DKGStep4(msgs []*tss.Message) (map[int]*tss.Message, error) {
+	if info.RoundNumber != 4 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != (info.Total - 1) {
+		return nil, fmt.Errorf("messages number error")
+	}
+	reveals := make(map[int]*PedersenStep3Data, info.Total)
+	for _, msg := range msgs {
+		if msg.To != info.DeviceNumber || msg.From <= 0 || msg.From > info.Total || msg.From == info.DeviceNumber {
+			return nil, fmt.Errorf("message sending error")
+		}
+		if _, ok := reveals[msg.From]; ok {
+			return nil, fmt.Errorf("message sending error")
+		}
+		// everyone sees the same broadcast, an unreadable reveal disqualifies an accused dealer
+		content := &PedersenStep3Data{}
+		if err := codec.Unmarshal([]byte(msg.Data), content); err != nil {
+			content = &PedersenStep3Data{}
+		}
+		reveals[msg.From] = content
+	}
+
+	info.qualified = nil
+	for _, dealer := range info.Ids() {
+		accusers := info.accusers(dealer)
+		if len(accusers) >= info.Threshold {
+			continue
+		}
+		// own pairs are valid
+		valid := true
+		for _, complainer := range accusers {
+			if dealer == info.DeviceNumber {
+				break
+			}
+			share, blind := reveals[dealer].Shares[complainer], reveals[dealer].Blinds[complainer]
+			if !info.shareOf(dealer, complainer, share, blind) {
+				valid = false
+				break
+			}
+			// the complainer takes the revealed pair
+			if complainer == info.DeviceNumber {
+				info.shares[dealer], info.blinds[dealer] = share, blind
+			}
+		}
+		if valid {
+			info.qualified = append(info.qualified, dealer)
+		}
+	}
+	if len(info.qualified) == 0 {
+		return nil, fmt.Errorf("no qualified dealer")
+	}
+
+	// key share, sum of the qualified dealers' shares
+	g := info.group
+	xi := g.NewScalar()
+	for _, dealer := range info.qualified {
+		// verified shares, reduced
+		yj, err := g.ScalarFromSecret(info.shares[dealer].Y)
+		if err != nil {
+			return nil, err
+		}
+		xi = xi.Add(yj)
+	}
+	info.xi = xi
+	info.RoundNumber = 5
+
+	if !info.isQualified(info.DeviceNumber) {
+		return info.broadcast(PedersenStep4Data{})
+	}
+	verifiers := make([]*curves.ECPoint, len(info.deal.Verifiers))
+	for k, A := range info.deal.Verifiers {
+		var err error
+		verifiers[k], err = A.ECPoint()
+		if err != nil {
+			return nil, err
+		}
+	}
+	out := make(map[int]*tss.Message, info.Total-1)
+	for _, id := range info.Ids() {
+		if id == info.DeviceNumber {
+			continue
+		}
+		proof, err := schnorr.ProveWithId(tss.BindId(info.sessionId, info.DeviceNumber, id), info.ui.BigInt(), verifiers[0])
+		if err != nil {
+			return nil, err
+		}
+		content := PedersenStep4Data{
+			Verifiers: verifiers,
+			Witness:   info.deC[id],
+			Proof:     proof,
+		}
+		bytes, err := info.codec.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = &tss.Message{
+			From: info.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	return out, nil
+}
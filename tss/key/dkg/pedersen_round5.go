@@ -0,0 +1,110 @@
+package dkg
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/commitment"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/schnorr"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// DKGStep5 check the qualified dealers' feldman verifiers against the shares and finish, messages of disqualified
+// dealers are ignored. A qualified dealer whose verifiers don't match is blamed, its contribution can't be dropped
+// after the qualified set is fixed without biasing the key, so the dkg is run again without it
+func (info *PedersenSetupInfo) DKGStep5(msgs []*tss.Message) (*tss.KeyStep3Data, error) {
+	if info.RoundNumber != 5 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != (info.Total - 1) {
+		return nil, fmt.Errorf("messages number error")
+	}
+	g := info.group
+	feldman, err := vss.NewFeldman(info.Threshold, info.Total, info.curve)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiers := make(map[int][]group.Point, len(info.qualified))
+	chaincode := big.NewInt(0)
+	if info.isQualified(info.DeviceNumber) {
+		verifiers[info.DeviceNumber] = info.deal.Verifiers
+		chaincode.Set(info.chaincode)
+	}
+	seen := make(map[int]bool, len(msgs))
+	for _, msg := range msgs {
+		if msg.To != info.DeviceNumber || msg.From <= 0 || msg.From > info.Total || msg.From == info.DeviceNumber || seen[msg.From] {
+			return nil, fmt.Errorf("message sending error")
+		}
+		seen[msg.From] = true
+		if !info.isQualified(msg.From) {
+			continue
+		}
+		var content PedersenStep4Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 5, "invalid message", err)
+		}
+		if content.Witness == nil || content.Proof == nil || len(content.Verifiers) != info.Threshold {
+			return nil, tss.NewBlameError(msg, 5, "incomplete message", nil)
+		}
+		// chaincode opening
+		hashCommit := commitment.HashCommitment{}
+		hashCommit.C = info.chaincodeC[msg.From]
+		hashCommit.Msg = *content.Witness
+		ok, D := hashCommit.Open()
+		if !ok || len(D) != 2 {
+			return nil, tss.NewBlameError(msg, 5, "commitment DeCommit fail", nil)
+		}
+		bindId := tss.BindId(info.sessionId, msg.From, info.DeviceNumber)
+		if D[0].Cmp(bindId) != 0 {
+			return nil, tss.NewBlameError(msg, 5, "commitment sessionId error", nil)
+		}
+		chaincode = new(big.Int).Add(chaincode, D[1])
+
+		points := make([]group.Point, len(content.Verifiers))
+		for k, A := range content.Verifiers {
+			if A == nil || curves.GetCurveName(A.Curve) != g.Name() {
+				return nil, tss.NewBlameError(msg, 5, "invalid verifiers", nil)
+			}
+			points[k], err = g.PointFromAffine(A.X, A.Y)
+			if err != nil {
+				return nil, tss.NewBlameError(msg, 5, "invalid verifiers", err)
+			}
+		}
+		// the accepted share was checked against the pedersen commitments, a_k*G must explain it too
+		if ok, err := feldman.VerifyPoints(info.shares[msg.From], points); !ok {
+			return nil, tss.NewBlameError(msg, 5, "feldman verify fail", err)
+		}
+		if !schnorr.VerifyWithId(bindId, content.Proof, content.Verifiers[0]) {
+			return nil, tss.NewBlameError(msg, 5, "schnorr verify fail", nil)
+		}
+		verifiers[msg.From] = points
+	}
+
+	v := SumVerifiers(g, verifiers, info.Threshold)
+	sharePubKeyMap, err := SharePubKeys(v, info.Ids())
+	if err != nil {
+		return nil, err
+	}
+	xiG, err := g.Generator().ScalarMult(info.xi).ECPoint()
+	if err != nil || !sharePubKeyMap[info.DeviceNumber].Equals(xiG) {
+		return nil, fmt.Errorf("public key calculation error")
+	}
+	publicKey, err := v[0].ECPoint()
+	if err != nil {
+		return nil, fmt.Errorf("public key is the identity")
+	}
+	return &tss.KeyStep3Data{
+		Id:             info.DeviceNumber,
+		ShareI:         info.xi.BigInt(),
+		PublicKey:      publicKey,
+		ChainCode:      hex.EncodeToString(chaincodeBytes(chaincode, info.curve)),
+		SharePubKeyMap: sharePubKeyMap,
+	}, nil
+}
@@ -0,0 +1,186 @@
+package dkg
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/stretchr/testify/require"
+)
+
+// pedersenKeyGen run all five steps, tamper may change messages of a step before they are delivered,
+// returns the key of every honest participant
+func pedersenKeyGen(t *testing.T, threshold, total int, curve elliptic.Curve, tamper func(step int, msgs []map[int]*tss.Message), honest func(id int) bool) ([]*PedersenSetupInfo, []*tss.KeyStep3Data) {
+	sessionId := tss.SessionId(fmt.Sprintf("pedersenKeyGen %d %d", threshold, total))
+	setUps := make([]*PedersenSetupInfo, total+1)
+	for i := 1; i <= total; i++ {
+		setUps[i] = NewPedersenSetUp(sessionId, i, threshold, total, curve)
+	}
+	msgs := make([]map[int]*tss.Message, total+1)
+	var err error
+	for i := 1; i <= total; i++ {
+		msgs[i], err = setUps[i].DKGStep1()
+		require.NoError(t, err)
+	}
+	steps := []func(info *PedersenSetupInfo, in []*tss.Message) (map[int]*tss.Message, error){
+		(*PedersenSetupInfo).DKGStep2,
+		(*PedersenSetupInfo).DKGStep3,
+		(*PedersenSetupInfo).DKGStep4,
+	}
+	for s, step := range steps {
+		tamper(s+1, msgs)
+		next := make([]map[int]*tss.Message, total+1)
+		for i := 1; i <= total; i++ {
+			next[i], err = step(setUps[i], collect(msgs, i))
+			require.NoError(t, err)
+		}
+		msgs = next
+	}
+	tamper(4, msgs)
+	saveData := make([]*tss.KeyStep3Data, total+1)
+	for i := 1; i <= total; i++ {
+		if !honest(i) {
+			continue
+		}
+		saveData[i], err = setUps[i].DKGStep5(collect(msgs, i))
+		require.NoError(t, err)
+	}
+	return setUps, saveData
+}
+
+// checkPedersenKey same key for the honest participants, t honest shares recover it
+func checkPedersenKey(t *testing.T, curve elliptic.Curve, threshold int, saveData []*tss.KeyStep3Data) {
+	var first *tss.KeyStep3Data
+	var shares []*vss.Share
+	for i, data := range saveData {
+		if data == nil {
+			continue
+		}
+		if first == nil {
+			first = data
+		}
+		require.True(t, data.PublicKey.Equals(first.PublicKey))
+		require.Equal(t, first.ChainCode, data.ChainCode)
+		require.True(t, first.SharePubKeyMap[i].Equals(curves.ScalarToPoint(curve, data.ShareI)))
+		shares = append(shares, &vss.Share{Id: big.NewInt(int64(i)), Y: data.ShareI})
+	}
+	secret := vss.RecoverSecret(curve, shares[:threshold])
+	require.True(t, curves.ScalarToPoint(curve, secret).Equals(first.PublicKey))
+	fmt.Println("publicKey", first.PublicKey)
+}
+
+func noTamper(int, []map[int]*tss.Message) {}
+
+func allHonest(int) bool { return true }
+
+func TestPedersenKeyGen(t *testing.T) {
+	for _, curve := range []elliptic.Curve{secp256k1.S256(), edwards.Edwards()} {
+		setUps, saveData := pedersenKeyGen(t, 3, 5, curve, noTamper, allHonest)
+		require.Equal(t, []int{1, 2, 3, 4, 5}, setUps[1].Qualified())
+		checkPedersenKey(t, curve, 3, saveData)
+	}
+}
+
+// tamperShare dealer sends a wrong share to receiver in step1
+func tamperShare(t *testing.T, msgs []map[int]*tss.Message, dealer, receiver int) {
+	var content PedersenStep1Data
+	require.NoError(t, json.Unmarshal([]byte(msgs[dealer][receiver].Data), &content))
+	content.Share.Y = new(big.Int).Add(content.Share.Y, big.NewInt(1))
+	bytes, err := json.Marshal(content)
+	require.NoError(t, err)
+	msgs[dealer][receiver].Data = string(bytes)
+}
+
+func TestPedersenKeyGenComplaint(t *testing.T) {
+	curve := secp256k1.S256()
+	// dealer 2 sends a wrong share to 1, 1 complains and 2 reveals the right pair, 2 stays qualified
+	setUps, saveData := pedersenKeyGen(t, 2, 4, curve, func(step int, msgs []map[int]*tss.Message) {
+		if step == 1 {
+			tamperShare(t, msgs, 2, 1)
+		}
+	}, allHonest)
+	require.Equal(t, []int{1, 2, 3, 4}, setUps[3].Qualified())
+	checkPedersenKey(t, curve, 2, saveData)
+}
+
+func TestPedersenKeyGenDisqualify(t *testing.T) {
+	curve := secp256k1.S256()
+	honest := func(id int) bool { return id != 2 }
+
+	// dealer 2 sends a wrong share to 1 and reveals a wrong pair
+	setUps, saveData := pedersenKeyGen(t, 2, 4, curve, func(step int, msgs []map[int]*tss.Message) {
+		switch step {
+		case 1:
+			tamperShare(t, msgs, 2, 1)
+		case 3:
+			var content PedersenStep3Data
+			require.NoError(t, json.Unmarshal([]byte(msgs[2][1].Data), &content))
+			content.Shares[1].Y = new(big.Int).Add(content.Shares[1].Y, big.NewInt(1))
+			bytes, _ := json.Marshal(content)
+			for _, msg := range msgs[2] {
+				msg.Data = string(bytes)
+			}
+		}
+	}, honest)
+	for _, id := range []int{1, 3, 4} {
+		require.Equal(t, []int{1, 3, 4}, setUps[id].Qualified())
+	}
+	checkPedersenKey(t, curve, 2, saveData)
+
+	// dealer 2 sends wrong shares to threshold participants, disqualified without looking at the reveals
+	setUps, saveData = pedersenKeyGen(t, 2, 4, curve, func(step int, msgs []map[int]*tss.Message) {
+		if step == 1 {
+			tamperShare(t, msgs, 2, 1)
+			tamperShare(t, msgs, 2, 3)
+		}
+	}, honest)
+	require.Equal(t, []int{1, 3, 4}, setUps[4].Qualified())
+	checkPedersenKey(t, curve, 2, saveData)
+}
+
+func TestPedersenKeyGenBlame(t *testing.T) {
+	curve := secp256k1.S256()
+	total := 3
+	sessionId := tss.SessionId("TestPedersenKeyGenBlame")
+	setUps := make([]*PedersenSetupInfo, total+1)
+	for i := 1; i <= total; i++ {
+		setUps[i] = NewPedersenSetUp(sessionId, i, 2, total, curve)
+	}
+	msgs := make([]map[int]*tss.Message, total+1)
+	for i := 1; i <= total; i++ {
+		msgs[i], _ = setUps[i].DKGStep1()
+	}
+	for _, step := range []func(info *PedersenSetupInfo, in []*tss.Message) (map[int]*tss.Message, error){
+		(*PedersenSetupInfo).DKGStep2, (*PedersenSetupInfo).DKGStep3, (*PedersenSetupInfo).DKGStep4,
+	} {
+		next := make([]map[int]*tss.Message, total+1)
+		for i := 1; i <= total; i++ {
+			next[i], _ = step(setUps[i], collect(msgs, i))
+		}
+		msgs = next
+	}
+
+	// qualified dealer 3 sends feldman verifiers of another polynomial
+	var content PedersenStep4Data
+	require.NoError(t, json.Unmarshal([]byte(msgs[3][1].Data), &content))
+	content.Verifiers[1] = curves.ScalarToPoint(curve, big.NewInt(5))
+	bytes, _ := json.Marshal(content)
+	msgs[3][1].Data = string(bytes)
+
+	_, err := setUps[1].DKGStep5(collect(msgs, 1))
+	fmt.Println(err)
+	var blame *tss.BlameError
+	require.True(t, errors.As(err, &blame))
+	require.Equal(t, 3, blame.From)
+	require.Equal(t, 5, blame.Round)
+	_, err = setUps[2].DKGStep5(collect(msgs, 2))
+	require.NoError(t, err)
+}
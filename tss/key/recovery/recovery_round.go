@@ -0,0 +1,182 @@
+package recovery
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// RecoveryInfo enrollment of a lost key share, at least threshold helpers re-create ShareI of the target device,
+// nobody learns the secret key or another share:
+//
+//	step1: helper j splits its contribution lambda_j(target)*x_j into random pieces, one for each helper,
+//	       sends a piece to every helper and the commitments piece*G of all pieces to everyone
+//	step2: helper k sums the pieces it holds and sends the sum to the target
+//	step3: target adds the sums, ShareI*G must match SharePubKeyMap[target]
+//
+// commitments of step1 must reach every participant unchanged, the transport has to provide broadcast
+type RecoveryInfo struct {
+	DeviceNumber int
+	Threshold    int
+	RoundNumber  int
+
+	curve          elliptic.Curve
+	group          group.Group
+	target         int
+	helpers        []int
+	shareI         *big.Int
+	publicKey      *curves.ECPoint
+	chainCode      string
+	sharePubKeyMap map[int]*curves.ECPoint
+
+	pieces      map[int]group.Scalar        // pieces held by this helper, dealer -> piece
+	commitments map[int]map[int]group.Point // dealer -> helper -> piece*G
+
+	codec codec.Codec // outgoing round payloads
+}
+
+// RecoverStep1Data Commitments are the same for every receiver, Piece is private to the receiving helper,
+// the target gets the public key data instead
+type RecoverStep1Data struct {
+	Commitments    map[int]*curves.ECPoint // piece*G for each helper
+	Piece          *big.Int
+	ChainCode      string
+	SharePubKeyMap map[int]*curves.ECPoint
+}
+
+// RecoverStep2Data sum of the pieces held by the helper
+type RecoverStep2Data struct {
+	Sum *big.Int
+}
+
+// NewHelper data is the key of a helper, helpers are distinct devices holding a share, at least threshold of them
+func NewHelper(threshold, target int, helpers []int, data *tss.KeyStep3Data) *RecoveryInfo {
+	if data == nil || data.ShareI == nil || data.PublicKey == nil || data.SharePubKeyMap == nil {
+		panic(fmt.Errorf("NewHelper key data error"))
+	}
+	info := newRecovery(data.Id, threshold, target, helpers, data.PublicKey)
+	if !contains(helpers, data.Id) {
+		panic(fmt.Errorf("NewHelper device not in helpers"))
+	}
+	info.shareI = data.ShareI
+	info.chainCode = data.ChainCode
+	info.sharePubKeyMap = data.SharePubKeyMap
+	return info
+}
+
+// NewTarget device target lost its share, publicKey is the known threshold public key,
+// chaincode and share public keys are taken from the helpers
+func NewTarget(threshold, target int, helpers []int, publicKey *curves.ECPoint) *RecoveryInfo {
+	if publicKey == nil {
+		panic(fmt.Errorf("NewTarget params error"))
+	}
+	return newRecovery(target, threshold, target, helpers, publicKey)
+}
+
+func newRecovery(deviceNumber, threshold, target int, helpers []int, publicKey *curves.ECPoint) *RecoveryInfo {
+	if threshold < 2 || len(helpers) < threshold || target <= 0 {
+		panic(fmt.Errorf("NewRecovery params error"))
+	}
+	seen := make(map[int]bool, len(helpers))
+	for _, id := range helpers {
+		if id <= 0 || id == target || seen[id] {
+			panic(fmt.Errorf("NewRecovery duplicate or invalid ids"))
+		}
+		seen[id] = true
+	}
+	g, err := group.FromCurve(publicKey.Curve)
+	if err != nil {
+		panic(fmt.Errorf("NewRecovery unsupported curve: %v", err))
+	}
+	return &RecoveryInfo{
+		DeviceNumber: deviceNumber,
+		Threshold:    threshold,
+		RoundNumber:  1,
+		curve:        publicKey.Curve,
+		group:        g,
+		target:       target,
+		helpers:      helpers,
+		publicKey:    publicKey,
+		codec:        codec.JSON,
+	}
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (info *RecoveryInfo) SetCodec(c codec.Codec) *RecoveryInfo {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
+	}
+	info.codec = c
+	return info
+}
+
+// Helpers devices re-creating the share
+func (info *RecoveryInfo) Helpers() []int {
+	return info.helpers
+}
+
+func (info *RecoveryInfo) isTarget() bool {
+	return info.DeviceNumber == info.target
+}
+
+// lambda lagrangian coefficient of helper j at point at
+func (info *RecoveryInfo) lambda(j, at int) group.Scalar {
+	ints := make([]*big.Int, len(info.helpers))
+	for i, id := range info.helpers {
+		ints[i] = big.NewInt(int64(id))
+	}
+	w := vss.CalLagrangianAt(info.curve, big.NewInt(int64(j)), big.NewInt(1), big.NewInt(int64(at)), ints)
+	return info.group.ScalarFromBigInt(w)
+}
+
+// checkCommitments pieces of dealer j add up to lambda_j(target)*X_j
+func (info *RecoveryInfo) checkCommitments(j int, commitments map[int]group.Point) bool {
+	Xj, ok := info.sharePubKeyMap[j]
+	if !ok || Xj == nil {
+		return false
+	}
+	X, err := info.group.PointFromAffine(Xj.X, Xj.Y)
+	if err != nil {
+		return false
+	}
+	sum := info.group.Identity()
+	for _, k := range info.helpers {
+		sum = sum.Add(commitments[k])
+	}
+	return sum.Equal(X.ScalarMult(info.lambda(j, info.target)))
+}
+
+// unmarshalCommitments one commitment on the curve for each helper
+func (info *RecoveryInfo) unmarshalCommitments(msg map[int]*curves.ECPoint) (map[int]group.Point, error) {
+	if len(msg) != len(info.helpers) {
+		return nil, fmt.Errorf("commitments number error")
+	}
+	commitments := make(map[int]group.Point, len(msg))
+	for _, k := range info.helpers {
+		C, ok := msg[k]
+		if !ok || C == nil || curves.GetCurveName(C.Curve) != info.group.Name() {
+			return nil, fmt.Errorf("invalid commitment")
+		}
+		point, err := info.group.PointFromAffine(C.X, C.Y)
+		if err != nil {
+			return nil, err
+		}
+		commitments[k] = point
+	}
+	return commitments, nil
+}
+
+func contains(list []int, id int) bool {
+	for _, v := range list {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package recovery
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/tss"
+)
+
+// RecoverStep1 helper splits lambda_j(target)*x_j into random pieces, target has nothing to send
+func (info *RecoveryInfo) RecoverStep1() (map[int]*tss.Message, error) {
+	if info.RoundNumber != 1 {
+		return nil, fmt.Errorf("round error")
+	}
+	info.RoundNumber = 2
+	if info.isTarget() {
+		return map[int]*tss.Message{}, nil
+	}
+
+	g := info.group
+	// x_j*G must match the published share public key, a stale share would poison the recovery
+	// key files of older versions hold shares not reduced modulo the order
+	shareI := info.shareI
+	if shareI.Cmp(g.Order()) >= 0 {
+		shareI = new(big.Int).Mod(shareI, g.Order())
+	}
+	xj, err := g.ScalarFromSecret(shareI)
+	if err != nil {
+		return nil, err
+	}
+	xjG, err := g.Generator().ScalarMult(xj).ECPoint()
+	if err != nil || !xjG.Equals(info.sharePubKeyMap[info.DeviceNumber]) {
+		return nil, fmt.Errorf("share public key mismatch")
+	}
+	delta := xj.Mul(info.lambda(info.DeviceNumber, info.target))
+
+	// random pieces for the other helpers, own piece makes the sum delta
+	pieces := make(map[int]group.Scalar, len(info.helpers))
+	own := delta
+	for _, k := range info.helpers {
+		if k == info.DeviceNumber {
+			continue
+		}
+		piece, err := g.RandomScalar()
+		if err != nil {
+			return nil, err
+		}
+		pieces[k] = piece
+		own = own.Sub(piece)
+	}
+	pieces[info.DeviceNumber] = own
+
+	commitments := make(map[int]group.Point, len(pieces))
+	ecCommitments := make(map[int]*curves.ECPoint, len(pieces))
+	for k, piece := range pieces {
+		commitments[k] = g.Generator().ScalarMult(piece)
+		// a zero piece has probability 1/n
+		ecCommitments[k], err = commitments[k].ECPoint()
+		if err != nil {
+			return nil, err
+		}
+	}
+	info.pieces = map[int]group.Scalar{info.DeviceNumber: own}
+	info.commitments = map[int]map[int]group.Point{info.DeviceNumber: commitments}
+
+	out := make(map[int]*tss.Message, len(info.helpers))
+	for _, id := range append([]int{info.target}, info.helpers...) {
+		if id == info.DeviceNumber {
+			continue
+		}
+		content := RecoverStep1Data{Commitments: ecCommitments}
+		if id == info.target {
+			content.ChainCode = info.chainCode
+			content.SharePubKeyMap = info.sharePubKeyMap
+		} else {
+			content.Piece = pieces[id].BigInt()
+		}
+		bytes, err := info.codec.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = &tss.Message{
+			From: info.DeviceNumber,
+			To:   id,
+			Data: string(bytes),
+		}
+	}
+	return out, nil
+}
+
+// fromHelper message is from another helper to this device
+func (info *RecoveryInfo) fromHelper(msg *tss.Message, received map[int]bool) bool {
+	if msg.To != info.DeviceNumber || msg.From == info.DeviceNumber || !contains(info.helpers, msg.From) || received[msg.From] {
+		return false
+	}
+	received[msg.From] = true
+	return true
+}
+
+// expectedMessages every other helper sends one message to each participant
+func (info *RecoveryInfo) expectedMessages() int {
+	if info.isTarget() {
+		return len(info.helpers)
+	}
+	return len(info.helpers) - 1
+}
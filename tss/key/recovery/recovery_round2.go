@@ -0,0 +1,145 @@
+package recovery
+
+import (
+	"fmt"
+
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// RecoverStep2 helper checks its pieces against the commitments and sends their sum to the target,
+// target checks the public key data of the helpers, nothing to send
+func (info *RecoveryInfo) RecoverStep2(msgs []*tss.Message) (map[int]*tss.Message, error) {
+	if info.RoundNumber != 2 {
+		return nil, fmt.Errorf("round error")
+	}
+	if len(msgs) != info.expectedMessages() {
+		return nil, fmt.Errorf("messages number error")
+	}
+	if info.commitments == nil {
+		info.commitments = make(map[int]map[int]group.Point, len(info.helpers))
+	}
+	received := make(map[int]bool, len(msgs))
+	contents := make(map[int]*RecoverStep1Data, len(msgs))
+	for _, msg := range msgs {
+		if !info.fromHelper(msg, received) {
+			return nil, fmt.Errorf("message sending error")
+		}
+		content := &RecoverStep1Data{}
+		err := codec.Unmarshal([]byte(msg.Data), content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid message", err)
+		}
+		contents[msg.From] = content
+	}
+
+	if info.isTarget() {
+		if err := info.publicData(contents); err != nil {
+			return nil, err
+		}
+	}
+	for _, msg := range msgs {
+		content := contents[msg.From]
+		commitments, err := info.unmarshalCommitments(content.Commitments)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid commitments", err)
+		}
+		if !info.checkCommitments(msg.From, commitments) {
+			return nil, tss.NewBlameError(msg, 2, "commitments sum error", nil)
+		}
+		info.commitments[msg.From] = commitments
+		if info.isTarget() {
+			continue
+		}
+		if content.Piece == nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid piece", nil)
+		}
+		piece, err := info.group.ScalarFromSecret(content.Piece)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 2, "invalid piece", err)
+		}
+		if !info.group.Generator().ScalarMult(piece).Equal(commitments[info.DeviceNumber]) {
+			return nil, tss.NewBlameError(msg, 2, "piece verify fail", nil)
+		}
+		info.pieces[msg.From] = piece
+	}
+	info.RoundNumber = 3
+	if info.isTarget() {
+		return map[int]*tss.Message{}, nil
+	}
+
+	sum := info.group.NewScalar()
+	for _, piece := range info.pieces {
+		sum = sum.Add(piece)
+	}
+	bytes, err := info.codec.Marshal(RecoverStep2Data{Sum: sum.BigInt()})
+	if err != nil {
+		return nil, err
+	}
+	out := map[int]*tss.Message{
+		info.target: {
+			From: info.DeviceNumber,
+			To:   info.target,
+			Data: string(bytes),
+		},
+	}
+	return out, nil
+}
+
+// publicData helpers must agree on chaincode and share public keys, the share public keys of the helpers
+// interpolate to the known public key and to every other share public key
+func (info *RecoveryInfo) publicData(contents map[int]*RecoverStep1Data) error {
+	first := contents[info.helpers[0]]
+	for _, content := range contents {
+		if content.ChainCode != first.ChainCode || len(content.SharePubKeyMap) != len(first.SharePubKeyMap) {
+			return fmt.Errorf("helpers disagree on public key data")
+		}
+		for id, X := range first.SharePubKeyMap {
+			if X == nil || !X.Equals(content.SharePubKeyMap[id]) {
+				return fmt.Errorf("helpers disagree on public key data")
+			}
+		}
+	}
+	if _, ok := first.SharePubKeyMap[info.target]; !ok {
+		return fmt.Errorf("share public key of %d not found", info.target)
+	}
+
+	g := info.group
+	points := make(map[int]group.Point, len(info.helpers))
+	for _, j := range info.helpers {
+		X, ok := first.SharePubKeyMap[j]
+		if !ok || curves.GetCurveName(X.Curve) != g.Name() {
+			return fmt.Errorf("share public key of %d not found", j)
+		}
+		point, err := g.PointFromAffine(X.X, X.Y)
+		if err != nil {
+			return err
+		}
+		points[j] = point
+	}
+	interpolate := func(at int) group.Point {
+		sum := g.Identity()
+		for _, j := range info.helpers {
+			sum = sum.Add(points[j].ScalarMult(info.lambda(j, at)))
+		}
+		return sum
+	}
+	publicKey, err := interpolate(0).ECPoint()
+	if err != nil || !publicKey.Equals(info.publicKey) {
+		return fmt.Errorf("share public keys do not match the public key")
+	}
+	for id, X := range first.SharePubKeyMap {
+		if id <= 0 {
+			return fmt.Errorf("share public keys do not match the public key")
+		}
+		point, err := interpolate(id).ECPoint()
+		if err != nil || !point.Equals(X) {
+			return fmt.Errorf("share public keys do not match the public key")
+		}
+	}
+	info.chainCode = first.ChainCode
+	info.sharePubKeyMap = first.SharePubKeyMap
+	return nil
+}
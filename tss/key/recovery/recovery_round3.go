@@ -0,0 +1,65 @@
+package recovery
+
+import (
+	"fmt"
+
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
+)
+
+// RecoverStep3 target adds the sums of the helpers, return the recovered key share
+func (info *RecoveryInfo) RecoverStep3(msgs []*tss.Message) (*tss.KeyStep3Data, error) {
+	if info.RoundNumber != 3 {
+		return nil, fmt.Errorf("round error")
+	}
+	if !info.isTarget() {
+		return nil, fmt.Errorf("device is not the recovery target")
+	}
+	if len(msgs) != info.expectedMessages() {
+		return nil, fmt.Errorf("messages number error")
+	}
+	g := info.group
+	received := make(map[int]bool, len(msgs))
+	xi := g.NewScalar()
+	for _, msg := range msgs {
+		if !info.fromHelper(msg, received) {
+			return nil, fmt.Errorf("message sending error")
+		}
+		var content RecoverStep2Data
+		err := codec.Unmarshal([]byte(msg.Data), &content)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 3, "invalid message", err)
+		}
+		if content.Sum == nil {
+			return nil, tss.NewBlameError(msg, 3, "invalid sum", nil)
+		}
+		sum, err := g.ScalarFromSecret(content.Sum)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 3, "invalid sum", err)
+		}
+		// sum of the pieces committed for this helper
+		expected := g.Identity()
+		for _, j := range info.helpers {
+			expected = expected.Add(info.commitments[j][msg.From])
+		}
+		if !g.Generator().ScalarMult(sum).Equal(expected) {
+			return nil, tss.NewBlameError(msg, 3, "sum verify fail", nil)
+		}
+		xi = xi.Add(sum)
+	}
+
+	xiG, err := g.Generator().ScalarMult(xi).ECPoint()
+	if err != nil || !xiG.Equals(info.sharePubKeyMap[info.DeviceNumber]) {
+		return nil, fmt.Errorf("recovered share does not match the share public key")
+	}
+	info.shareI = xi.BigInt()
+
+	content := &tss.KeyStep3Data{
+		Id:             info.DeviceNumber,
+		ShareI:         info.shareI,
+		PublicKey:      info.publicKey,
+		ChainCode:      info.chainCode,
+		SharePubKeyMap: info.sharePubKeyMap,
+	}
+	return content, nil
+}
@@ -0,0 +1,155 @@
+package recovery
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/key/dkg"
+	"github.com/stretchr/testify/require"
+)
+
+// keyGen threshold-of-total dkg, keys indexed by device id
+func keyGen(t *testing.T, threshold, total int, curve elliptic.Curve) map[int]*tss.KeyStep3Data {
+	sessionId := tss.SessionId(fmt.Sprintf("keyGen %d %d", threshold, total))
+	setUps := make(map[int]*dkg.SetupInfo, total)
+	for i := 1; i <= total; i++ {
+		setUps[i] = dkg.NewSetUp(sessionId, i, threshold, total, curve)
+	}
+	msgs := make(map[int]map[int]*tss.Message, total)
+	for i, setUp := range setUps {
+		out, err := setUp.DKGStep1()
+		require.NoError(t, err)
+		msgs[i] = out
+	}
+	next := make(map[int]map[int]*tss.Message, total)
+	for i, setUp := range setUps {
+		out, err := setUp.DKGStep2(collect(msgs, i))
+		require.NoError(t, err)
+		next[i] = out
+	}
+	keys := make(map[int]*tss.KeyStep3Data, total)
+	for i, setUp := range setUps {
+		data, err := setUp.DKGStep3(collect(next, i))
+		require.NoError(t, err)
+		keys[i] = data
+	}
+	return keys
+}
+
+// collect messages sent to device id
+func collect(msgs map[int]map[int]*tss.Message, id int) []*tss.Message {
+	var in []*tss.Message
+	for _, out := range msgs {
+		if msg, ok := out[id]; ok {
+			in = append(in, msg)
+		}
+	}
+	return in
+}
+
+// recoverShare run the recovery of target with helpers, tamper may change messages of a step before delivery
+func recoverShare(t *testing.T, keys map[int]*tss.KeyStep3Data, threshold, target int, helpers []int, tamper func(step int, msgs map[int]map[int]*tss.Message)) (*tss.KeyStep3Data, error) {
+	infos := map[int]*RecoveryInfo{target: NewTarget(threshold, target, helpers, keys[target].PublicKey)}
+	for _, id := range helpers {
+		infos[id] = NewHelper(threshold, target, helpers, keys[id])
+	}
+	msgs := make(map[int]map[int]*tss.Message, len(infos))
+	for id, info := range infos {
+		out, err := info.RecoverStep1()
+		require.NoError(t, err)
+		msgs[id] = out
+	}
+	tamper(1, msgs)
+	next := make(map[int]map[int]*tss.Message, len(infos))
+	for id, info := range infos {
+		out, err := info.RecoverStep2(collect(msgs, id))
+		if err != nil {
+			return nil, err
+		}
+		next[id] = out
+	}
+	tamper(2, next)
+	return infos[target].RecoverStep3(collect(next, target))
+}
+
+func noTamper(int, map[int]map[int]*tss.Message) {}
+
+func TestRecover(t *testing.T) {
+	keys := keyGen(t, 2, 3, secp256k1.S256())
+	data, err := recoverShare(t, keys, 2, 2, []int{1, 3}, noTamper)
+	require.NoError(t, err)
+	fmt.Println("recovered", data.Id, data.ShareI)
+	require.Equal(t, 0, data.ShareI.Cmp(keys[2].ShareI))
+	require.True(t, data.PublicKey.Equals(keys[2].PublicKey))
+	require.Equal(t, keys[2].ChainCode, data.ChainCode)
+	require.Equal(t, len(keys[2].SharePubKeyMap), len(data.SharePubKeyMap))
+
+	keys = keyGen(t, 3, 5, edwards.Edwards())
+	data, err = recoverShare(t, keys, 3, 4, []int{5, 1, 2}, noTamper)
+	require.NoError(t, err)
+	require.Equal(t, 0, data.ShareI.Cmp(keys[4].ShareI))
+	// more helpers than threshold
+	data, err = recoverShare(t, keys, 3, 1, []int{2, 3, 4, 5}, noTamper)
+	require.NoError(t, err)
+	require.Equal(t, 0, data.ShareI.Cmp(keys[1].ShareI))
+}
+
+func TestRecoverBlame(t *testing.T) {
+	keys := keyGen(t, 2, 4, secp256k1.S256())
+	helpers := []int{1, 3}
+	var blame *tss.BlameError
+
+	// helper 3 sends a piece not matching its commitments
+	_, err := recoverShare(t, keys, 2, 2, helpers, func(step int, msgs map[int]map[int]*tss.Message) {
+		if step != 1 {
+			return
+		}
+		var content RecoverStep1Data
+		require.NoError(t, json.Unmarshal([]byte(msgs[3][1].Data), &content))
+		content.Piece = new(big.Int).Add(content.Piece, big.NewInt(1))
+		bytes, _ := json.Marshal(content)
+		msgs[3][1].Data = string(bytes)
+	})
+	fmt.Println(err)
+	require.True(t, errors.As(err, &blame))
+	require.Equal(t, 3, blame.From)
+	require.Equal(t, 2, blame.Round)
+
+	// helper 1 sends a wrong sum to the target
+	_, err = recoverShare(t, keys, 2, 2, helpers, func(step int, msgs map[int]map[int]*tss.Message) {
+		if step != 2 {
+			return
+		}
+		var content RecoverStep2Data
+		require.NoError(t, json.Unmarshal([]byte(msgs[1][2].Data), &content))
+		content.Sum = new(big.Int).Add(content.Sum, big.NewInt(1))
+		bytes, _ := json.Marshal(content)
+		msgs[1][2].Data = string(bytes)
+	})
+	fmt.Println(err)
+	require.True(t, errors.As(err, &blame))
+	require.Equal(t, 1, blame.From)
+	require.Equal(t, 3, blame.Round)
+
+	// helpers can't hand the target another key
+	other := keyGen(t, 2, 4, secp256k1.S256())
+	keys[2] = &tss.KeyStep3Data{Id: 2, PublicKey: other[2].PublicKey}
+	_, err = recoverShare(t, keys, 2, 2, helpers, noTamper)
+	fmt.Println(err)
+	require.Error(t, err)
+
+	// helper with a share not matching its share public key
+	require.Panics(t, func() { NewTarget(2, 2, []int{1, 2}, keys[1].PublicKey) })
+	keys = keyGen(t, 2, 3, secp256k1.S256())
+	stale := *keys[1]
+	stale.ShareI = new(big.Int).Add(stale.ShareI, big.NewInt(1))
+	_, err = NewHelper(2, 2, helpers, &stale).RecoverStep1()
+	require.Error(t, err)
+}
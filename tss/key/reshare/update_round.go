@@ -7,56 +7,174 @@ import (
 
 	"github.com/okx/threshold-lib/crypto/commitment"
 	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
 	"github.com/okx/threshold-lib/crypto/vss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
 type RefreshInfo struct {
 	DeviceNumber int
-	Threshold    int // 2/n
-	Total        int
+	Threshold    int // new committee t'/n'
+	Total        int // new committee size n'
 	RoundNumber  int
 
+	sessionId  *big.Int // agreed by all participants, bound into every commitment and proof
 	curve      elliptic.Curve
-	devoteList [2]int // 2 contributors reset the key share
-	ui         *big.Int
+	group      group.Group
+	devoteList []int // old committee contributors, at least old threshold
+	newList    []int // new committee ids, new shares are evaluated at these ids
+	ui         group.Scalar
 	shareI     *big.Int
 	publicKey  *curves.ECPoint
 
-	verifiers     []*curves.ECPoint
-	secretShares  []*vss.Share
-	deC           *commitment.Witness
+	verifiers     []group.Point
+	secretShares  map[int]*vss.Share
+	deC           map[int]*commitment.Witness // commitment opening for each receiver
 	commitmentMap map[int]commitment.Commitment
+
+	codec codec.Codec // outgoing round payloads
 }
 
-// NewRefresh the process is consistent with dkg
-func NewRefresh(deviceNumber, total int, devoteList [2]int, ShareI *big.Int, PublicKey *curves.ECPoint) *RefreshInfo {
-	if total < 2 || deviceNumber > total || deviceNumber <= 0 {
+// NewRefresh reset key shares of the same committee 1..total, the process is consistent with dkg
+func NewRefresh(sessionId *big.Int, deviceNumber, threshold, total int, devoteList []int, ShareI *big.Int, PublicKey *curves.ECPoint) *RefreshInfo {
+	if total < 2 {
 		panic(fmt.Errorf("NewRefresh params error"))
 	}
+	ids := make([]int, total)
+	for i := 0; i < total; i++ {
+		ids[i] = i + 1
+	}
+	return NewReshare(sessionId, deviceNumber, threshold, ids, devoteList, threshold, ids, ShareI, PublicKey)
+}
+
+// NewReshare move the key from old committee (t, n) to new committee (t', n'), members may be disjoint.
+// oldThreshold, oldList: t and the ids of the old committee
+// devoteList: old committee ids holding ShareI, at least t of them
+// newList: new committee ids, threshold t' <= len(newList)
+// a device in devoteList but not in newList only deals, it gets no new share and should drop ShareI
+// sessionId must be the same for all participants and unique for each resharing, see tss.SessionId
+func NewReshare(sessionId *big.Int, deviceNumber, oldThreshold int, oldList, devoteList []int, threshold int, newList []int, ShareI *big.Int, PublicKey *curves.ECPoint) *RefreshInfo {
+	if sessionId == nil || deviceNumber <= 0 || PublicKey == nil {
+		panic(fmt.Errorf("NewReshare params error"))
+	}
+	if !distinctIds(oldList) || !distinctIds(devoteList) || !distinctIds(newList) {
+		panic(fmt.Errorf("NewReshare duplicate or invalid ids"))
+	}
+	if oldThreshold < 2 || oldThreshold > len(oldList) {
+		panic(fmt.Errorf("NewReshare old threshold %d invalid for %d parties", oldThreshold, len(oldList)))
+	}
+	if threshold < 2 || threshold > len(newList) {
+		panic(fmt.Errorf("NewReshare new threshold %d invalid for %d parties", threshold, len(newList)))
+	}
+	// fewer than t contributors would reshare a different secret, only caught by the final public key check
+	if len(devoteList) < oldThreshold {
+		panic(fmt.Errorf("NewReshare need at least %d contributors, got %d", oldThreshold, len(devoteList)))
+	}
+	for _, id := range devoteList {
+		if !contains(oldList, id) {
+			panic(fmt.Errorf("NewReshare contributor %d not in old committee", id))
+		}
+	}
+	isDevote, isNew := contains(devoteList, deviceNumber), contains(newList, deviceNumber)
+	if !isDevote && !isNew {
+		panic(fmt.Errorf("NewReshare device not in committee"))
+	}
 	curve := PublicKey.Curve
+	g, err := group.FromCurve(curve)
+	if err != nil {
+		panic(fmt.Errorf("NewReshare unsupported curve: %v", err))
+	}
 	info := &RefreshInfo{
 		DeviceNumber: deviceNumber,
-		Threshold:    2,
-		Total:        total,
+		Threshold:    threshold,
+		Total:        len(newList),
 		RoundNumber:  1,
 		devoteList:   devoteList,
+		newList:      newList,
+		sessionId:    sessionId,
 		publicKey:    PublicKey,
 		curve:        curve,
+		group:        g,
+		codec:        codec.JSON,
 	}
 
-	if deviceNumber == devoteList[0] || deviceNumber == devoteList[1] {
-		ints := []*big.Int{big.NewInt(int64(devoteList[0])), big.NewInt(int64(devoteList[1]))}
-		info.ui = vss.CalLagrangian(curve, big.NewInt(int64(deviceNumber)), ShareI, ints)
+	if isDevote {
+		if ShareI == nil {
+			panic(fmt.Errorf("NewReshare contributor share is nil"))
+		}
+		ints := make([]*big.Int, len(devoteList))
+		for i, id := range devoteList {
+			ints[i] = big.NewInt(int64(id))
+		}
+		ui, err := g.ScalarFromSecret(vss.CalLagrangian(curve, big.NewInt(int64(deviceNumber)), ShareI, ints))
+		if err != nil {
+			panic(fmt.Errorf("NewReshare contributor share error: %v", err))
+		}
+		info.ui = ui
 	} else {
-		info.ui = big.NewInt(0)
+		// new member contributes zero, only re-randomizes
+		info.ui = g.NewScalar()
+	}
+	return info
+}
+
+// SetCodec encode the outgoing round payloads with c instead of JSON, received payloads of both formats are accepted
+func (info *RefreshInfo) SetCodec(c codec.Codec) *RefreshInfo {
+	if c == nil {
+		panic(fmt.Errorf("SetCodec codec is nil"))
 	}
+	info.codec = c
 	return info
 }
 
+// Ids new committee ids, receivers of the new shares
 func (info *RefreshInfo) Ids() []int {
-	var ids []int
-	for i := 1; i <= info.Total; i++ {
-		ids = append(ids, i)
+	return info.newList
+}
+
+// Participants all devices taking part in resharing, devoteList ∪ newList
+func (info *RefreshInfo) Participants() []int {
+	ids := append([]int{}, info.devoteList...)
+	for _, id := range info.newList {
+		if !contains(ids, id) {
+			ids = append(ids, id)
+		}
 	}
 	return ids
 }
+
+func (info *RefreshInfo) isDevote(id int) bool {
+	return contains(info.devoteList, id)
+}
+
+func (info *RefreshInfo) isReceiver() bool {
+	return contains(info.newList, info.DeviceNumber)
+}
+
+// expected number of messages in step2 and step3, contributors outside new committee receive nothing
+func (info *RefreshInfo) expectedMessages() int {
+	if !info.isReceiver() {
+		return 0
+	}
+	return len(info.Participants()) - 1
+}
+
+func contains(list []int, id int) bool {
+	for _, v := range list {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func distinctIds(list []int) bool {
+	seen := make(map[int]bool, len(list))
+	for _, id := range list {
+		if id <= 0 || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
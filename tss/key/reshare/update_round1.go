@@ -1,7 +1,6 @@
 package reshare
 
 import (
-	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -10,7 +9,7 @@ import (
 	"github.com/okx/threshold-lib/tss"
 )
 
-// DKGStep1
+// DKGStep1 p2p send verifiers commitment to the new committee
 func (info *RefreshInfo) DKGStep1() (map[int]*tss.Message, error) {
 	if info.RoundNumber != 1 {
 		return nil, fmt.Errorf("round error")
@@ -19,31 +18,36 @@ func (info *RefreshInfo) DKGStep1() (map[int]*tss.Message, error) {
 	if err != nil {
 		return nil, err
 	}
-	// ui calculated from previous share
-	verifiers, shares, err := feldman.Evaluate(info.ui)
+	// ui calculated from previous share, new shares evaluated at new committee ids
+	verifiers, shares, err := feldman.Deal(info.ui, info.Ids())
 	if err != nil {
 		return nil, err
 	}
 
-	// compute verifiers commitment， no chaincode
+	// compute verifiers commitment， no chaincode, the identity 0*G is committed as (0, 0)
 	var input []*big.Int
 	for i := 0; i < len(verifiers); i++ {
-		input = append(input, verifiers[i].X, verifiers[i].Y)
+		x, y := verifiers[i].Affine()
+		input = append(input, x, y)
+	}
+	info.deC = make(map[int]*commitment.Witness, len(info.Ids()))
+	info.secretShares = make(map[int]*vss.Share, len(shares))
+	for i, id := range info.Ids() {
+		info.secretShares[id] = shares[i]
 	}
-	hashCommitment := commitment.NewCommitment(input...)
-
-	info.deC = &hashCommitment.Msg
-	info.secretShares = shares
 	info.verifiers = verifiers
 	info.RoundNumber = 2
 
-	out := make(map[int]*tss.Message, info.Total-1)
+	out := make(map[int]*tss.Message, info.Total)
 	for _, id := range info.Ids() {
 		if id == info.DeviceNumber {
 			continue
 		}
+		// bound to session and receiver
+		hashCommitment := commitment.NewCommitment(append([]*big.Int{tss.BindId(info.sessionId, info.DeviceNumber, id)}, input...)...)
+		info.deC[id] = &hashCommitment.Msg
 		content := tss.KeyStep1Data{C: &hashCommitment.C}
-		bytes, err := json.Marshal(content)
+		bytes, err := info.codec.Marshal(content)
 		if err != nil {
 			return nil, err
 		}
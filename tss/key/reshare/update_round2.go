@@ -1,53 +1,66 @@
 package reshare
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/okx/threshold-lib/crypto/commitment"
-	"github.com/okx/threshold-lib/crypto/curves"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
 )
 
-// DKGStep2 same as dkg step2
+// DKGStep2 same as dkg step2, shares are sent to the new committee
 func (info *RefreshInfo) DKGStep2(msgs []*tss.Message) (map[int]*tss.Message, error) {
 	if info.RoundNumber != 2 {
 		return nil, fmt.Errorf("round error")
 	}
-	if len(msgs) != (info.Total - 1) {
+	if len(msgs) != info.expectedMessages() {
 		return nil, fmt.Errorf("messages number error")
 	}
 	info.commitmentMap = make(map[int]commitment.Commitment, len(msgs))
 	for _, msg := range msgs {
-		if msg.To != info.DeviceNumber {
+		if msg.To != info.DeviceNumber || !contains(info.Participants(), msg.From) {
+			return nil, fmt.Errorf("message sending error")
+		}
+		if _, ok := info.commitmentMap[msg.From]; ok || msg.From == info.DeviceNumber {
 			return nil, fmt.Errorf("message sending error")
 		}
 		var content tss.KeyStep1Data
-		err := json.Unmarshal([]byte(msg.Data), &content)
+		err := codec.Unmarshal([]byte(msg.Data), &content)
 		if err != nil {
-			return nil, err
+			return nil, tss.NewBlameError(msg, 2, "invalid message", err)
+		}
+		if content.C == nil {
+			return nil, tss.NewBlameError(msg, 2, "missing commitment", nil)
 		}
 		info.commitmentMap[msg.From] = *content.C
 	}
 
-	uiG := curves.ScalarToPoint(info.curve, info.ui)
-	proof, err := schnorr.Prove(info.ui, uiG)
-	if err != nil {
-		return nil, err
-	}
+	uiG := info.group.Generator().ScalarMult(info.ui)
 	info.RoundNumber = 3
 
-	out := make(map[int]*tss.Message, info.Total-1)
+	out := make(map[int]*tss.Message, info.Total)
 	for _, id := range info.Ids() {
 		if id == info.DeviceNumber {
 			continue
 		}
+		// zero contribution of a new member has nothing to prove
+		var proof *schnorr.Proof
+		if !uiG.IsIdentity() {
+			point, err := uiG.ECPoint()
+			if err != nil {
+				return nil, err
+			}
+			proof, err = schnorr.ProveWithId(tss.BindId(info.sessionId, info.DeviceNumber, id), info.ui.BigInt(), point)
+			if err != nil {
+				return nil, err
+			}
+		}
 		content := tss.KeyStep2Data{
-			Witness: info.deC,
-			Share:   info.secretShares[id-1],
+			Witness: info.deC[id],
+			Share:   info.secretShares[id],
 			Proof:   proof,
 		}
-		bytes, err := json.Marshal(content)
+		bytes, err := info.codec.Marshal(content)
 		if err != nil {
 			return nil, err
 		}
@@ -1,116 +1,127 @@
 package reshare
 
 import (
-	"encoding/json"
 	"fmt"
 	"math/big"
 
 	"github.com/okx/threshold-lib/crypto/commitment"
-	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/group"
 	"github.com/okx/threshold-lib/crypto/schnorr"
 	"github.com/okx/threshold-lib/crypto/vss"
 	"github.com/okx/threshold-lib/tss"
+	"github.com/okx/threshold-lib/tss/codec"
 	"github.com/okx/threshold-lib/tss/key/dkg"
 )
 
 // DKGStep3 return new key share information except chaincode
+// only new committee members finish the protocol, v0 = sum(ui*G) proves the publicKey is unchanged
 func (info *RefreshInfo) DKGStep3(msgs []*tss.Message) (*tss.KeyStep3Data, error) {
 	if info.RoundNumber != 3 {
 		return nil, fmt.Errorf("round error")
 	}
-	if len(msgs) != (info.Total - 1) {
+	if !info.isReceiver() {
+		return nil, fmt.Errorf("device not in new committee")
+	}
+	if len(msgs) != info.expectedMessages() {
 		return nil, fmt.Errorf("messages number error")
 	}
 
 	curve := info.curve
+	g := info.group
 	feldman, err := vss.NewFeldman(info.Threshold, info.Total, curve)
 	if err != nil {
 		return nil, err
 	}
 
-	verifiers := make(map[int][]*curves.ECPoint, len(msgs))
+	verifiers := make(map[int][]group.Point, len(msgs))
 	verifiers[info.DeviceNumber] = info.verifiers
-	xi := info.secretShares[info.DeviceNumber-1]
+	xi, err := g.ScalarFromSecret(info.secretShares[info.DeviceNumber].Y)
+	if err != nil {
+		return nil, err
+	}
 	for _, msg := range msgs {
 		if msg.To != info.DeviceNumber {
 			return nil, fmt.Errorf("message sending error")
 		}
+		if _, ok := info.commitmentMap[msg.From]; !ok {
+			return nil, fmt.Errorf("message sending error")
+		}
+		if _, ok := verifiers[msg.From]; ok {
+			return nil, fmt.Errorf("message sending error")
+		}
 		var content tss.KeyStep2Data
-		err := json.Unmarshal([]byte(msg.Data), &content)
+		err := codec.Unmarshal([]byte(msg.Data), &content)
 		if err != nil {
-			return nil, err
+			return nil, tss.NewBlameError(msg, 3, "invalid message", err)
+		}
+		if content.Witness == nil {
+			return nil, tss.NewBlameError(msg, 3, "incomplete message", nil)
 		}
 		hashCommit := commitment.HashCommitment{}
 		hashCommit.C = info.commitmentMap[msg.From]
 		hashCommit.Msg = *content.Witness
 		ok, D := hashCommit.Open()
-		if !ok {
-			return nil, fmt.Errorf("commitment DeCommit fail")
+		if !ok || len(D) < 1 {
+			return nil, tss.NewBlameError(msg, 3, "commitment DeCommit fail", nil)
+		}
+		bindId := tss.BindId(info.sessionId, msg.From, info.DeviceNumber)
+		if D[0].Cmp(bindId) != 0 {
+			return nil, tss.NewBlameError(msg, 3, "commitment sessionId error", nil)
 		}
 
-		verifiers[msg.From], err = dkg.UnmarshalVerifiers(curve, D, info.Threshold)
-		if ok, err := feldman.Verify(content.Share, verifiers[msg.From]); !ok {
-			if err != nil {
-				return nil, err
-			} else {
-				return nil, fmt.Errorf("invalid share for participant  ")
-			}
+		verifiers[msg.From], err = dkg.UnmarshalVerifiers(g, D[1:], info.Threshold)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 3, "invalid verifiers", err)
+		}
+		if content.Share == nil || content.Share.Id == nil || content.Share.Y == nil || content.Share.Id.Cmp(big.NewInt(int64(info.DeviceNumber))) != 0 {
+			return nil, tss.NewBlameError(msg, 3, "invalid share", nil)
+		}
+		if ok, err := feldman.VerifyPoints(content.Share, verifiers[msg.From]); !ok {
+			return nil, tss.NewBlameError(msg, 3, "invalid share", err)
 		}
-		xi.Y = new(big.Int).Add(xi.Y, content.Share.Y)
+		yj, err := g.ScalarFromSecret(content.Share.Y)
+		if err != nil {
+			return nil, tss.NewBlameError(msg, 3, "invalid share", err)
+		}
+		xi = xi.Add(yj)
 
+		// only members outside devoteList contribute zero, their ui*G is the identity
 		ujPoint := verifiers[msg.From][0]
-		// filter 0*G
-		if ujPoint.X.Cmp(big.NewInt(0)) == 0 || ujPoint.Y.Cmp(big.NewInt(0)) == 0 {
+		if ujPoint.IsIdentity() {
+			if info.isDevote(msg.From) {
+				return nil, tss.NewBlameError(msg, 3, "invalid contribution", nil)
+			}
 			continue
 		}
-		point, err := curves.NewECPoint(curve, ujPoint.X, ujPoint.Y)
+		if !info.isDevote(msg.From) {
+			return nil, tss.NewBlameError(msg, 3, "invalid contribution", nil)
+		}
+		point, err := ujPoint.ECPoint()
 		if err != nil {
-			return nil, err
+			return nil, tss.NewBlameError(msg, 3, "invalid ui*G", err)
 		}
-		verify := schnorr.Verify(content.Proof, point)
-		if !verify {
-			return nil, fmt.Errorf("schnorr verify fail")
+		if content.Proof == nil || !schnorr.VerifyWithId(bindId, content.Proof, point) {
+			return nil, tss.NewBlameError(msg, 3, "schnorr verify fail", nil)
 		}
 	}
 
-	v := make([]*curves.ECPoint, info.Threshold)
-	for j := 0; j < info.Threshold; j++ {
-		v[j] = curves.ScalarToPoint(curve, big.NewInt(0))
-
-		for _, verifier := range verifiers {
-			if !verifier[j].IsOnCurve() {
-				continue
-			}
-			v[j], err = v[j].Add(verifier[j])
-			if err != nil {
-				return nil, err
-			}
-		}
-	}
-
-	sharePubKeyMap := make(map[int]*curves.ECPoint, info.Threshold)
-	for k := 1; k <= info.Total; k++ {
-		Yi := v[0]
-		tmp := big.NewInt(1)
-		for i := 1; i < info.Threshold; i++ {
-			tmp = tmp.Mul(tmp, big.NewInt(int64(k)))
-			point := v[i]
-			point = point.ScalarMult(tmp)
-			Yi, err = Yi.Add(point)
-		}
-		sharePubKeyMap[k] = Yi
+	v := dkg.SumVerifiers(g, verifiers, info.Threshold)
+	sharePubKeyMap, err := dkg.SharePubKeys(v, info.Ids())
+	if err != nil {
+		return nil, err
 	}
-	xiG := curves.ScalarToPoint(curve, xi.Y)
-	if !sharePubKeyMap[info.DeviceNumber].Equals(xiG) {
+	xiG, err := g.Generator().ScalarMult(xi).ECPoint()
+	if err != nil || !sharePubKeyMap[info.DeviceNumber].Equals(xiG) {
 		return nil, fmt.Errorf("public key calculation error")
 	}
 	// update publicKey is equals previous publicKey?
-	if !v[0].Equals(info.publicKey) {
+	publicKey, err := v[0].ECPoint()
+	if err != nil || !publicKey.Equals(info.publicKey) {
 		return nil, fmt.Errorf("public key recalculation error")
 	}
 
-	info.shareI = xi.Y
-	info.publicKey = v[0]
+	info.shareI = xi.BigInt()
+	info.publicKey = publicKey
 
 	content := &tss.KeyStep3Data{
 		Id:             info.DeviceNumber,
@@ -3,22 +3,28 @@ package reshare
 import (
 	"crypto/elliptic"
 	"fmt"
+	"math/big"
+	"testing"
+
 	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/okx/threshold-lib/crypto/curves"
+	"github.com/okx/threshold-lib/crypto/vss"
 	"github.com/okx/threshold-lib/tss"
 	"github.com/okx/threshold-lib/tss/key/dkg"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRefresh(t *testing.T) {
+	sessionId := tss.SessionId("TestRefresh")
 	// curve := edwards.Edwards()
 	curve := secp256k1.S256()
 	p1Data, p2Data, p3Data := KeyGen(curve)
 	// Reset private key share by 1, 3
-	devoteList := [2]int{1, 3}
+	devoteList := []int{1, 3}
 
-	refresh1 := NewRefresh(1, 3, devoteList, p1Data.ShareI, p1Data.PublicKey)
-	refresh2 := NewRefresh(2, 3, devoteList, nil, p2Data.PublicKey)
-	refresh3 := NewRefresh(3, 3, devoteList, p3Data.ShareI, p3Data.PublicKey)
+	refresh1 := NewRefresh(sessionId, 1, 2, 3, devoteList, p1Data.ShareI, p1Data.PublicKey)
+	refresh2 := NewRefresh(sessionId, 2, 2, 3, devoteList, nil, p2Data.PublicKey)
+	refresh3 := NewRefresh(sessionId, 3, 2, 3, devoteList, p3Data.ShareI, p3Data.PublicKey)
 
 	msgs1_1, _ := refresh1.DKGStep1()
 	msgs2_1, _ := refresh2.DKGStep1()
@@ -46,10 +52,117 @@ func TestRefresh(t *testing.T) {
 
 }
 
+// 2-of-3 {1,2,3} -> 3-of-5 {4,5,6,7,8}, disjoint members
+func TestReshareDisjoint(t *testing.T) {
+	curve := secp256k1.S256()
+	p1Data, _, p3Data := KeyGen(curve)
+	shares := map[int]*big.Int{1: p1Data.ShareI, 3: p3Data.ShareI}
+	testReshare(t, p1Data.PublicKey, shares, []int{1, 3}, []int{4, 5, 6, 7, 8}, 3)
+}
+
+// 2-of-3 {1,2,3} -> 3-of-4 {2,3,4,5}, device 1 leaves, device 2 keeps its id
+func TestReshareOverlap(t *testing.T) {
+	curve := secp256k1.S256()
+	p1Data, p2Data, _ := KeyGen(curve)
+	shares := map[int]*big.Int{1: p1Data.ShareI, 2: p2Data.ShareI}
+	testReshare(t, p1Data.PublicKey, shares, []int{1, 2}, []int{2, 3, 4, 5}, 3)
+}
+
+func TestReshareParams(t *testing.T) {
+	sessionId := tss.SessionId("TestReshareParams")
+	curve := secp256k1.S256()
+	p1Data, _, _ := KeyGen(curve)
+	old := []int{1, 2, 3}
+	// fewer contributors than the old threshold
+	require.Panics(t, func() { NewReshare(sessionId, 1, 3, old, []int{1, 2}, 2, []int{4, 5}, p1Data.ShareI, p1Data.PublicKey) })
+	// contributor outside the old committee
+	require.Panics(t, func() { NewReshare(sessionId, 1, 2, old, []int{1, 4}, 2, []int{4, 5}, p1Data.ShareI, p1Data.PublicKey) })
+	// new threshold above new committee size
+	require.Panics(t, func() { NewReshare(sessionId, 1, 2, old, []int{1, 2}, 3, []int{4, 5}, p1Data.ShareI, p1Data.PublicKey) })
+	// invalid new id
+	require.Panics(t, func() { NewReshare(sessionId, 1, 2, old, []int{1, 2}, 2, []int{0, 5}, p1Data.ShareI, p1Data.PublicKey) })
+	// refresh contributor outside 1..total
+	require.Panics(t, func() { NewRefresh(sessionId, 1, 2, 3, []int{1, 4}, p1Data.ShareI, p1Data.PublicKey) })
+	require.Panics(t, func() { NewRefresh(sessionId, 1, 3, 3, []int{1, 2}, p1Data.ShareI, p1Data.PublicKey) })
+	require.NotPanics(t, func() { NewReshare(sessionId, 1, 2, old, []int{1, 2}, 2, []int{4, 5}, p1Data.ShareI, p1Data.PublicKey) })
+}
+
+func testReshare(t *testing.T, publicKey *curves.ECPoint, shares map[int]*big.Int, devoteList, newList []int, threshold int) {
+	sessionId := tss.SessionId("testReshare")
+	infos := make(map[int]*RefreshInfo)
+	for _, id := range append(append([]int{}, devoteList...), newList...) {
+		if _, ok := infos[id]; !ok {
+			infos[id] = NewReshare(sessionId, id, 2, []int{1, 2, 3}, devoteList, threshold, newList, shares[id], publicKey)
+		}
+	}
+	msgs1 := make(map[int]map[int]*tss.Message)
+	for id, info := range infos {
+		out, err := info.DKGStep1()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs1[id] = out
+	}
+	msgs2 := make(map[int]map[int]*tss.Message)
+	for id, info := range infos {
+		out, err := info.DKGStep2(collect(msgs1, id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs2[id] = out
+	}
+	saveData := make(map[int]*tss.KeyStep3Data)
+	for id, info := range infos {
+		data, err := info.DKGStep3(collect(msgs2, id))
+		if !info.isReceiver() {
+			if err == nil {
+				t.Fatalf("device %d outside new committee finished", id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !data.PublicKey.Equals(publicKey) || len(data.SharePubKeyMap) != len(newList) {
+			t.Fatalf("device %d reshare result error", id)
+		}
+		saveData[id] = data
+	}
+
+	var newShares []*vss.Share
+	for _, id := range newList[:threshold] {
+		newShares = append(newShares, &vss.Share{Id: big.NewInt(int64(id)), Y: saveData[id].ShareI})
+	}
+	secret := vss.RecoverSecret(publicKey.Curve, newShares)
+	if !curves.ScalarToPoint(publicKey.Curve, secret).Equals(publicKey) {
+		t.Fatal("new committee recover wrong private key")
+	}
+	secret = vss.RecoverSecret(publicKey.Curve, newShares[1:])
+	if curves.ScalarToPoint(publicKey.Curve, secret).Equals(publicKey) {
+		t.Fatal("t'-1 shares recover private key")
+	}
+	fmt.Println("reshare", devoteList, "->", newList, publicKey)
+}
+
+// collect messages sent to device id
+func collect(msgs map[int]map[int]*tss.Message, id int) []*tss.Message {
+	var in []*tss.Message
+	for from, out := range msgs {
+		if from == id {
+			continue
+		}
+		if msg, ok := out[id]; ok {
+			in = append(in, msg)
+		}
+	}
+	return in
+}
+
 func KeyGen(curve elliptic.Curve) (*tss.KeyStep3Data, *tss.KeyStep3Data, *tss.KeyStep3Data) {
-	setUp1 := dkg.NewSetUp(1, 3, curve)
-	setUp2 := dkg.NewSetUp(2, 3, curve)
-	setUp3 := dkg.NewSetUp(3, 3, curve)
+	sessionId := tss.SessionId("KeyGen")
+	setUp1 := dkg.NewSetUp(sessionId, 1, 2, 3, curve)
+	setUp2 := dkg.NewSetUp(sessionId, 2, 2, 3, curve)
+	setUp3 := dkg.NewSetUp(sessionId, 3, 2, 3, curve)
 
 	msgs1_1, _ := setUp1.DKGStep1()
 	msgs2_1, _ := setUp2.DKGStep1()